@@ -0,0 +1,383 @@
+// Package atlas renders schema definitions as Atlas HCL.
+//
+// The output is a declarative schema that Atlas (atlasgo.io) can use as the
+// desired state for migrations, so teams can baseline an existing database
+// and manage it with Atlas from then on.
+//
+// Basic usage:
+//
+//	output, err := atlas.Generate(s)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("schema.hcl", output, 0644)
+package atlas
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lucasefe/dbml/ddl"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Generate converts a Schema into Atlas HCL.
+// The output declares schemas, then enums, then tables with their columns,
+// primary keys, foreign keys, and indexes.
+func Generate(s *schema.Schema) ([]byte, error) {
+	g := newGenerator(s)
+
+	var builder strings.Builder
+
+	for _, name := range g.schemaNames() {
+		builder.WriteString(fmt.Sprintf("schema %q {\n}\n\n", name))
+	}
+
+	for _, enum := range g.enums {
+		g.writeEnum(&builder, enum)
+	}
+
+	for _, table := range g.tables {
+		g.writeTable(&builder, table)
+	}
+
+	return []byte(strings.TrimRight(builder.String(), "\n") + "\n"), nil
+}
+
+// GenerateString is a convenience wrapper that returns the HCL as a string.
+func GenerateString(s *schema.Schema) (string, error) {
+	result, err := Generate(s)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+type generator struct {
+	tables []schema.Table
+	enums  []schema.Enum
+	// ambiguous holds table names used in more than one schema. Atlas
+	// requires those tables to be declared and referenced with a schema
+	// qualifier.
+	ambiguous map[string]bool
+	// enumRefs maps the database spelling of an enum type to its HCL reference.
+	enumRefs map[string]string
+}
+
+func newGenerator(s *schema.Schema) *generator {
+	g := &generator{
+		ambiguous: make(map[string]bool),
+		enumRefs:  make(map[string]string),
+	}
+
+	g.tables = make([]schema.Table, len(s.Tables))
+	copy(g.tables, s.Tables)
+	sort.Slice(g.tables, func(i, j int) bool {
+		if g.tables[i].Schema != g.tables[j].Schema {
+			return g.tables[i].Schema < g.tables[j].Schema
+		}
+		return g.tables[i].Name < g.tables[j].Name
+	})
+
+	g.enums = make([]schema.Enum, len(s.Enums))
+	copy(g.enums, s.Enums)
+	sort.Slice(g.enums, func(i, j int) bool {
+		if g.enums[i].Schema != g.enums[j].Schema {
+			return g.enums[i].Schema < g.enums[j].Schema
+		}
+		return g.enums[i].Name < g.enums[j].Name
+	})
+
+	seen := make(map[string]string)
+	for _, table := range g.tables {
+		if other, ok := seen[table.Name]; ok && other != table.Schema {
+			g.ambiguous[table.Name] = true
+		}
+		seen[table.Name] = table.Schema
+	}
+
+	for _, enum := range g.enums {
+		ref := "enum" + attr(enum.Name)
+		g.enumRefs[enum.Schema+"."+enum.Name] = ref
+		g.enumRefs[ddl.QualifiedName(enum.Name, enum.Schema)] = ref
+		if enum.Schema == "public" || enum.Schema == "" {
+			g.enumRefs[enum.Name] = ref
+		}
+	}
+
+	return g
+}
+
+// schemaNames returns every schema that contains a table or enum. Atlas
+// requires each one to be declared, including "public".
+func (g *generator) schemaNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name == "" {
+			name = "public"
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, table := range g.tables {
+		add(table.Schema)
+	}
+	for _, enum := range g.enums {
+		add(enum.Schema)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func schemaRef(name string) string {
+	if name == "" {
+		name = "public"
+	}
+	return "schema" + attr(name)
+}
+
+// tableLabel returns the block labels for a table declaration.
+func (g *generator) tableLabel(tableName, schemaName string) string {
+	if g.ambiguous[tableName] {
+		return fmt.Sprintf("%q %q", schemaName, tableName)
+	}
+	return strconv.Quote(tableName)
+}
+
+// tableRef returns the HCL expression referring to a table.
+func (g *generator) tableRef(tableName, schemaName string) string {
+	if g.ambiguous[tableName] {
+		return "table" + attr(schemaName) + attr(tableName)
+	}
+	return "table" + attr(tableName)
+}
+
+func (g *generator) writeEnum(builder *strings.Builder, enum schema.Enum) {
+	values := make([]string, len(enum.Values))
+	for i, value := range enum.Values {
+		values[i] = strconv.Quote(value)
+	}
+
+	builder.WriteString(fmt.Sprintf("enum %q {\n", enum.Name))
+	builder.WriteString(fmt.Sprintf("  schema = %s\n", schemaRef(enum.Schema)))
+	builder.WriteString(fmt.Sprintf("  values = [%s]\n", strings.Join(values, ", ")))
+	builder.WriteString("}\n\n")
+}
+
+func (g *generator) writeTable(builder *strings.Builder, table schema.Table) {
+	builder.WriteString(fmt.Sprintf("table %s {\n", g.tableLabel(table.Name, table.Schema)))
+	builder.WriteString(fmt.Sprintf("  schema = %s\n", schemaRef(table.Schema)))
+
+	for _, column := range table.Columns {
+		g.writeColumn(builder, column)
+	}
+
+	if len(table.PrimaryKeys) > 0 {
+		builder.WriteString("  primary_key {\n")
+		builder.WriteString(fmt.Sprintf("    columns = [%s]\n", columnRefs("column", table.PrimaryKeys)))
+		builder.WriteString("  }\n")
+	}
+
+	for _, ref := range table.References {
+		builder.WriteString(fmt.Sprintf("  foreign_key %q {\n", foreignKeyName(ref)))
+		builder.WriteString(fmt.Sprintf("    columns     = [%s]\n", columnRefs("column", ref.FromColumns)))
+		builder.WriteString(fmt.Sprintf("    ref_columns = [%s]\n", columnRefs(g.tableRef(ref.ToTable, ref.ToSchema)+".column", ref.ToColumns)))
+		builder.WriteString(fmt.Sprintf("    on_update   = %s\n", referentialAction(ref.OnUpdate)))
+		builder.WriteString(fmt.Sprintf("    on_delete   = %s\n", referentialAction(ref.OnDelete)))
+		builder.WriteString("  }\n")
+	}
+
+	indexes := make([]schema.Index, len(table.Indexes))
+	copy(indexes, table.Indexes)
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	for _, index := range indexes {
+		builder.WriteString(fmt.Sprintf("  index %q {\n", index.Name))
+		if index.Unique {
+			builder.WriteString("    unique  = true\n")
+		}
+		if atlasIndexTypes[index.Type] {
+			builder.WriteString(fmt.Sprintf("    type    = %s\n", strings.ToUpper(index.Type)))
+		}
+		if hasExpression(index.Columns) {
+			for _, column := range index.Columns {
+				builder.WriteString("    on {\n")
+				if strings.HasPrefix(column, "`") {
+					builder.WriteString(fmt.Sprintf("      expr = %q\n", strings.Trim(column, "`")))
+				} else {
+					builder.WriteString(fmt.Sprintf("      column = column%s\n", attr(column)))
+				}
+				builder.WriteString("    }\n")
+			}
+		} else {
+			builder.WriteString(fmt.Sprintf("    columns = [%s]\n", columnRefs("column", index.Columns)))
+		}
+		if len(index.Include) > 0 {
+			builder.WriteString(fmt.Sprintf("    include = [%s]\n", columnRefs("column", index.Include)))
+		}
+		if index.Where != "" {
+			builder.WriteString(fmt.Sprintf("    where   = %q\n", index.Where))
+		}
+		builder.WriteString("  }\n")
+	}
+
+	builder.WriteString("}\n\n")
+}
+
+func (g *generator) writeColumn(builder *strings.Builder, column schema.Column) {
+	isSequence := column.AutoIncrement && column.Identity == ""
+
+	builder.WriteString(fmt.Sprintf("  column %q {\n", column.Name))
+	builder.WriteString(fmt.Sprintf("    null = %t\n", column.Nullable && !column.IsPrimaryKey))
+	builder.WriteString(fmt.Sprintf("    type = %s\n", g.columnType(column, isSequence)))
+	if column.DefaultValue != nil && !isSequence {
+		builder.WriteString(fmt.Sprintf("    default = %s\n", defaultValue(*column.DefaultValue)))
+	}
+	if column.Identity != "" {
+		builder.WriteString("    identity {\n")
+		builder.WriteString(fmt.Sprintf("      generated = %s\n", strings.ReplaceAll(column.Identity, " ", "_")))
+		builder.WriteString("    }\n")
+	}
+	builder.WriteString("  }\n")
+}
+
+// atlasTypes maps PostgreSQL type names as spelled by format_type to the
+// names Atlas uses in HCL.
+var atlasTypes = map[string]string{
+	"character varying":           "varchar",
+	"character":                   "char",
+	"double precision":            "double_precision",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"bit varying":                 "varbit",
+}
+
+// atlasIndexTypes are the index access methods Atlas can declare; indexes
+// using other methods, such as pgvector's hnsw, are declared as btree.
+var atlasIndexTypes = map[string]bool{
+	"hash":   true,
+	"gin":    true,
+	"gist":   true,
+	"brin":   true,
+	"spgist": true,
+}
+
+var (
+	simpleType    = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\(\d+(,\s*\d+)?\))?$`)
+	precisionType = regexp.MustCompile(`^(timestamp|time)\((\d+)\) (with|without) time zone$`)
+)
+
+func (g *generator) columnType(column schema.Column, isSequence bool) string {
+	typ := column.DatabaseType
+	if typ == "" {
+		typ = ddl.PostgresType(column.Type)
+	}
+
+	if ref, ok := g.enumRefs[typ]; ok {
+		return ref
+	}
+
+	if isSequence {
+		switch typ {
+		case "bigint", "int8":
+			return "bigserial"
+		case "smallint", "int2":
+			return "smallserial"
+		default:
+			return "serial"
+		}
+	}
+
+	if m := precisionType.FindStringSubmatch(typ); m != nil {
+		name := m[1]
+		if m[3] == "with" {
+			name += "tz"
+		}
+		return fmt.Sprintf("%s(%s)", name, m[2])
+	}
+
+	base, suffix := typ, ""
+	if i := strings.Index(typ, "("); i >= 0 {
+		base, suffix = typ[:i], typ[i:]
+	}
+	if name, ok := atlasTypes[base]; ok {
+		typ = name + suffix
+	}
+
+	if simpleType.MatchString(typ) {
+		return strings.ReplaceAll(typ, ", ", ",")
+	}
+	return fmt.Sprintf("sql(%q)", typ)
+}
+
+var (
+	numberLiteral = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	stringLiteral = regexp.MustCompile(`^'((?:[^']|'')*)'(::[\w ]+)?$`)
+)
+
+// defaultValue renders a column default. Literals become HCL values and
+// everything else is passed through as a raw SQL expression.
+func defaultValue(value string) string {
+	lower := strings.ToLower(value)
+	switch {
+	case lower == "true" || lower == "false":
+		return lower
+	case numberLiteral.MatchString(value):
+		return value
+	}
+	if m := stringLiteral.FindStringSubmatch(value); m != nil {
+		return strconv.Quote(strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return fmt.Sprintf("sql(%q)", value)
+}
+
+func referentialAction(action string) string {
+	if action == "" {
+		action = "NO ACTION"
+	}
+	return strings.ReplaceAll(strings.ToUpper(action), " ", "_")
+}
+
+// foreignKeyName returns PostgreSQL's default constraint name for a foreign
+// key, since introspection does not record constraint names.
+func foreignKeyName(ref schema.Reference) string {
+	return fmt.Sprintf("%s_%s_fkey", ref.FromTable, strings.Join(ref.FromColumns, "_"))
+}
+
+func columnRefs(prefix string, columns []string) string {
+	refs := make([]string, len(columns))
+	for i, column := range columns {
+		refs[i] = prefix + attr(column)
+	}
+	return strings.Join(refs, ", ")
+}
+
+func hasExpression(columns []string) bool {
+	for _, column := range columns {
+		if strings.HasPrefix(column, "`") {
+			return true
+		}
+	}
+	return false
+}
+
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// attr returns the traversal step selecting name in a reference expression.
+// Names that are not valid HCL identifiers use index syntax instead.
+func attr(name string) string {
+	if plainIdentifier.MatchString(name) {
+		return "." + name
+	}
+	return fmt.Sprintf("[%q]", name)
+}
@@ -0,0 +1,132 @@
+package atlas
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerate(t *testing.T) {
+	seq := "nextval('users_id_seq'::regclass)"
+	now := "now()"
+	active := "'active'::character varying"
+	s := &schema.Schema{
+		Enums: []schema.Enum{{Name: "role", Schema: "public", Values: []string{"admin", "member"}}},
+		Tables: []schema.Table{
+			{
+				Name:   "users",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "id", Type: "int", IsPrimaryKey: true, DefaultValue: &seq, AutoIncrement: true, DatabaseType: "integer"},
+					{Name: "email", Type: "varchar(255)", DatabaseType: "character varying(255)"},
+					{Name: "status", Type: "varchar(20)", Nullable: true, DefaultValue: &active, DatabaseType: "character varying(20)"},
+					{Name: "role", Type: "role", DatabaseType: "role"},
+					{Name: "tags", Type: "text[]", Nullable: true, DatabaseType: "text[]"},
+					{Name: "created_at", Type: "timestamptz", DefaultValue: &now, DatabaseType: "timestamp(3) with time zone"},
+				},
+				PrimaryKeys: []string{"id"},
+				Indexes: []schema.Index{
+					{Name: "users_email_key", Columns: []string{"email"}, Unique: true},
+					{Name: "users_status_idx", Columns: []string{"status"}, Include: []string{"email", "role"}},
+				},
+			},
+			{
+				Name:   "posts",
+				Schema: "blog",
+				Columns: []schema.Column{
+					{Name: "id", Type: "bigint", IsPrimaryKey: true, Identity: "BY DEFAULT", AutoIncrement: true},
+					{Name: "author_id", Type: "int"},
+				},
+				PrimaryKeys: []string{"id"},
+				References: []schema.Reference{
+					{FromTable: "posts", FromSchema: "blog", FromColumns: []string{"author_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}, OnDelete: "CASCADE"},
+				},
+			},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	expected := []string{
+		"schema \"blog\" {\n}",
+		"schema \"public\" {\n}",
+		"enum \"role\" {\n  schema = schema.public\n  values = [\"admin\", \"member\"]\n}",
+		"table \"users\" {\n  schema = schema.public\n",
+		"  column \"id\" {\n    null = false\n    type = serial\n  }",
+		"  column \"email\" {\n    null = false\n    type = varchar(255)\n  }",
+		"    type = varchar(20)\n    default = \"active\"\n",
+		"    type = enum.role\n",
+		"    type = sql(\"text[]\")\n",
+		"    type = timestamptz(3)\n    default = sql(\"now()\")\n",
+		"  primary_key {\n    columns = [column.id]\n  }",
+		"  index \"users_email_key\" {\n    unique  = true\n    columns = [column.email]\n  }",
+		"  index \"users_status_idx\" {\n    columns = [column.status]\n    include = [column.email, column.role]\n  }",
+		"table \"posts\" {\n  schema = schema.blog\n",
+		"    type = bigint\n    identity {\n      generated = BY_DEFAULT\n    }\n",
+		"  foreign_key \"posts_author_id_fkey\" {\n    columns     = [column.author_id]\n    ref_columns = [table.users.column.id]\n    on_update   = NO_ACTION\n    on_delete   = CASCADE\n  }",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain:\n%s\n\nGot:\n%s", e, output)
+		}
+	}
+
+	if strings.Index(output, "table \"posts\"") > strings.Index(output, "table \"users\"") {
+		t.Errorf("Expected tables sorted by schema then name")
+	}
+}
+
+func TestGenerateQualifiesAmbiguousTables(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{
+				Name:    "users",
+				Schema:  "auth",
+				Columns: []schema.Column{{Name: "user id", Type: "int"}},
+				References: []schema.Reference{
+					{FromTable: "users", FromSchema: "auth", FromColumns: []string{"user id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	expected := []string{
+		"table \"auth\" \"users\" {",
+		"table \"public\" \"users\" {",
+		"columns     = [column[\"user id\"]]",
+		"ref_columns = [table.public.users.column.id]",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, output)
+		}
+	}
+}
+
+func TestDefaultValue(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"0", "0"},
+		{"-1.5", "-1.5"},
+		{"true", "true"},
+		{"'it''s'::text", `"it's"`},
+		{"CURRENT_TIMESTAMP", `sql("CURRENT_TIMESTAMP")`},
+	}
+
+	for _, tt := range tests {
+		if result := defaultValue(tt.input); result != tt.expected {
+			t.Errorf("defaultValue(%q) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+}
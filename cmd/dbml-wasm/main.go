@@ -0,0 +1,70 @@
+//go:build js && wasm
+
+// Command dbml-wasm exposes the schema converters to JavaScript.
+//
+// Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o dbml.wasm ./cmd/dbml-wasm
+//
+// and load it with the wasm_exec.js shim shipped with Go. Once running, it
+// registers a global "dbml" object:
+//
+//	dbml.convert(input, from, to) // from: "json" | "dbml" | "sql"; to: "json" | "dbml" | "sql" | "mermaid"
+//	dbml.generate(json)           // shorthand for convert(json, "json", "dbml")
+//	dbml.parse(dbml)              // shorthand for convert(dbml, "dbml", "json")
+//
+// Every function returns an object {output, error}; error is null on success.
+package main
+
+import (
+	"fmt"
+	"syscall/js"
+
+	"github.com/lucasefe/dbml/convert"
+)
+
+func main() {
+	js.Global().Set("dbml", js.ValueOf(map[string]interface{}{
+		"convert":  js.FuncOf(convertFunc),
+		"generate": js.FuncOf(fixed(convert.JSON, convert.DBML)),
+		"parse":    js.FuncOf(fixed(convert.DBML, convert.JSON)),
+	}))
+
+	// Keep the Go runtime alive so the callbacks remain usable.
+	select {}
+}
+
+func convertFunc(this js.Value, args []js.Value) interface{} {
+	if len(args) != 3 {
+		return result("", fmt.Errorf("convert expects 3 arguments (input, from, to), got %d", len(args)))
+	}
+
+	from, err := convert.ParseFormat(args[1].String())
+	if err != nil {
+		return result("", err)
+	}
+	to, err := convert.ParseFormat(args[2].String())
+	if err != nil {
+		return result("", err)
+	}
+
+	output, err := convert.Convert([]byte(args[0].String()), from, to)
+	return result(string(output), err)
+}
+
+func fixed(from, to convert.Format) func(js.Value, []js.Value) interface{} {
+	return func(this js.Value, args []js.Value) interface{} {
+		if len(args) != 1 {
+			return result("", fmt.Errorf("expected 1 argument, got %d", len(args)))
+		}
+		output, err := convert.Convert([]byte(args[0].String()), from, to)
+		return result(string(output), err)
+	}
+}
+
+func result(output string, err error) map[string]interface{} {
+	if err != nil {
+		return map[string]interface{}{"output": nil, "error": err.Error()}
+	}
+	return map[string]interface{}{"output": output, "error": nil}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lucasefe/dbml/introspect"
+	"github.com/lucasefe/dbml/pipeline"
+	"github.com/lucasefe/dbml/render"
+	"github.com/lucasefe/dbml/schema"
+	"github.com/lucasefe/dbml/sink"
+)
+
+// agentManifest is uploaded as manifest.json next to the artifacts.
+type agentManifest struct {
+	Fingerprint string    `json:"fingerprint"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+}
+
+func runAgent(args []string) {
+	fs := flag.NewFlagSet("agent", flag.ExitOnError)
+	target := fs.String("target", "", "Storage URI to upload to, e.g. s3://bucket/prefix (can also use DBML_AGENT_TARGET env var)")
+	formats := fs.String("formats", "dbml,json", "Comma-separated output formats to upload")
+	interval := fs.Duration("interval", 15*time.Minute, "How often to introspect the database")
+	once := fs.Bool("once", false, "Upload once and exit, e.g. from a Kubernetes CronJob")
+
+	config := parseFlags(fs, args)
+	handleCommonFlags(&config, printAgentUsage)
+
+	if *target == "" {
+		*target = os.Getenv("DBML_AGENT_TARGET")
+	}
+	if *target == "" {
+		log.Fatal("A storage target is required: use --target or DBML_AGENT_TARGET")
+	}
+	if !*once && *interval <= 0 {
+		log.Fatalf("Invalid interval %s: must be positive", *interval)
+	}
+
+	formatList := splitList(*formats)
+	if len(formatList) == 0 {
+		log.Fatal("At least one format is required")
+	}
+	names := make(map[string]string)
+	for _, format := range formatList {
+		if _, ok := render.Lookup(format); !ok {
+			log.Fatalf("Unsupported format for agent: %s", format)
+		}
+		name := sink.FileName(format)
+		if other, ok := names[name]; ok {
+			log.Fatalf("Formats %s and %s would both upload %s", other, format, name)
+		}
+		names[name] = format
+	}
+
+	dst, err := sink.Open(*target)
+	if err != nil {
+		log.Fatalf("Failed to open storage target: %v", err)
+	}
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open database connection: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := introspectOptions(config)
+	p := buildPipeline(config)
+
+	if *once {
+		s, err := introspect.Database(db, opts...)
+		if err != nil {
+			log.Fatalf("Failed to introspect database: %v", err)
+		}
+		if err := uploadArtifacts(ctx, dst, s, formatList, config, p); err != nil {
+			log.Fatalf("Failed to upload artifacts: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Uploaded %d artifacts to %s (fingerprint %s)\n", len(formatList), *target, s.Fingerprint())
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Uploading to %s every %s\n", *target, *interval)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	var uploaded string
+	for {
+		s, err := introspect.Database(db, opts...)
+		if err != nil {
+			// Keep running through transient failures such as restarts
+			log.Printf("Failed to introspect database: %v", err)
+		} else if fingerprint := s.Fingerprint(); fingerprint != uploaded {
+			if err := uploadArtifacts(ctx, dst, s, formatList, config, p); err != nil {
+				log.Printf("Failed to upload artifacts: %v", err)
+			} else {
+				fmt.Fprintf(os.Stderr, "Uploaded %d artifacts (fingerprint %s)\n", len(formatList), fingerprint)
+				uploaded = fingerprint
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// uploadArtifacts renders the schema in each format and uploads the results,
+// followed by a manifest listing them. The manifest goes last so readers
+// never see it point at files from an older run.
+func uploadArtifacts(ctx context.Context, dst sink.Sink, s *schema.Schema, formats []string, config Config, p *pipeline.Pipeline) error {
+	fingerprint := s.Fingerprint()
+
+	s, err := p.Schema(s)
+	if err != nil {
+		return err
+	}
+
+	manifest := agentManifest{
+		Fingerprint: fingerprint,
+		GeneratedAt: time.Now().UTC(),
+		Files:       []string{},
+	}
+	for _, format := range formats {
+		content, err := renderFormat(s, format, config)
+		if err == nil {
+			content, err = p.Output(content, format)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate %s: %w", format, err)
+		}
+
+		name := sink.FileName(format)
+		if err := dst.Write(ctx, name, content); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, name)
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return dst.Write(ctx, "manifest.json", append(content, '\n'))
+}
+
+func printAgentUsage() {
+	fmt.Printf(`dbml agent - Keep schema docs current in object storage
+
+Introspects the database and uploads the generated artifacts, plus a
+manifest.json describing them, to S3, Google Cloud Storage, or Azure Blob
+Storage (or to a local directory or HTTP URL ending in "/"). Runs until interrupted, uploading again whenever the schema changes,
+or once with --once for use from a scheduler such as a Kubernetes CronJob.
+
+USAGE:
+    dbml agent --target <URI> [OPTIONS]
+
+OPTIONS:
+    --target <URI>                 Storage target: s3://bucket/prefix, gs://bucket/prefix,
+                                   azblob://account/container/prefix, a directory,
+                                   or an HTTP URL ending in "/"
+    --formats <FORMATS>            Comma-separated formats to upload (default: dbml,json)
+    --interval <DURATION>          How often to introspect, e.g. 5m (default: 15m)
+    --once                         Upload once and exit
+    -url, --url <URL>              PostgreSQL connection URL
+    -s, --schemas <SCHEMAS>        Comma-separated schemas to include (default: public)
+    -x, --exclude-tables <TABLES>  Comma-separated tables to exclude
+    -a, --all-schemas              Include all non-system schemas
+    -h, --help                     Show help
+
+ENVIRONMENT VARIABLES:
+    DATABASE_URL                   PostgreSQL connection URL
+    DBML_AGENT_TARGET              Storage target URI
+    AWS_ACCESS_KEY_ID              S3 access key
+    AWS_SECRET_ACCESS_KEY          S3 secret key
+    AWS_SESSION_TOKEN              S3 session token, for temporary credentials
+    AWS_REGION                     S3 region (default: us-east-1)
+    AWS_ENDPOINT_URL               S3-compatible endpoint, e.g. MinIO
+    GOOGLE_OAUTH_ACCESS_TOKEN      GCS access token (default: GCE metadata server)
+    AZURE_STORAGE_SAS_TOKEN        Azure Blob SAS token with write permission
+
+EXAMPLES:
+    # Upload DBML, JSON, and a diagram once
+    dbml agent --target s3://docs/schemas/prod --formats dbml,json,svg --once
+
+    # Keep a bucket current, checking every five minutes
+    dbml agent --target gs://docs/schemas/prod --interval 5m
+
+`)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lucasefe/dbml/graph"
+	"github.com/lucasefe/dbml/introspect"
+)
+
+func runAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	top := fs.Int("top", 10, "Number of hub tables to list (0 for all)")
+	jsonOutput := fs.Bool("json", false, "Print the analysis as JSON")
+
+	config := parseFlags(fs, args)
+	handleCommonFlags(&config, printAnalyzeUsage)
+
+	s, err := introspect.FromConnectionString(config.DatabaseURL, introspectOptions(config)...)
+	if err != nil {
+		log.Fatalf("Failed to introspect database: %v", err)
+	}
+
+	analysis := graph.Analyze(s, *top)
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(analysis); err != nil {
+			log.Fatalf("Failed to encode analysis: %v", err)
+		}
+		return
+	}
+
+	if err := analysis.WriteText(os.Stdout); err != nil {
+		log.Fatalf("Failed to write analysis: %v", err)
+	}
+}
+
+func printAnalyzeUsage() {
+	fmt.Printf(`dbml analyze - Analyze the relationships between tables
+
+Reports orphan tables (no foreign keys in or out), foreign key cycles, hub
+tables by number of relationships, and connected components.
+
+USAGE:
+    dbml analyze [OPTIONS]
+
+OPTIONS:
+    --top <N>                      Number of hub tables to list, 0 for all (default: 10)
+    --json                         Print the analysis as JSON
+    -url, --url <URL>              PostgreSQL connection URL
+    -s, --schemas <SCHEMAS>        Comma-separated schemas to include (default: public)
+    -x, --exclude-tables <TABLES>  Comma-separated tables to exclude
+    -a, --all-schemas              Include all non-system schemas
+    -h, --help                     Show help
+
+ENVIRONMENT VARIABLES:
+    DATABASE_URL                   PostgreSQL connection URL
+
+`)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lucasefe/dbml/introspect"
+	"github.com/lucasefe/dbml/schema"
+)
+
+func runChecksum(args []string) {
+	fs := flag.NewFlagSet("checksum", flag.ExitOnError)
+	ignoreComments := fs.Bool("ignore-comments", false, "Leave comments and comment annotations out of the checksum")
+	expect := fs.String("expect", "", "Exit with status 1 if the checksum differs from this one")
+
+	config := parseFlags(fs, args)
+	handleCommonFlags(&config, printChecksumUsage)
+
+	opts := introspectOptions(config)
+	var s *schema.Schema
+	if config.AllDatabases {
+		databases, err := introspect.Server(config.DatabaseURL, opts...)
+		if err != nil {
+			log.Fatalf("Failed to introspect server: %v", err)
+		}
+		s = schema.MergeDatabases(databases)
+	} else {
+		var err error
+		s, err = introspect.FromConnectionString(config.DatabaseURL, opts...)
+		if err != nil {
+			log.Fatalf("Failed to introspect database: %v", err)
+		}
+	}
+
+	// Renames, aliases, and redaction change what is published, so they
+	// count towards the checksum.
+	s, err := buildPipeline(config).Schema(s)
+	if err != nil {
+		log.Fatalf("Failed to apply middleware: %v", err)
+	}
+
+	var checksumOpts []schema.ChecksumOption
+	if *ignoreComments {
+		checksumOpts = append(checksumOpts, schema.WithoutComments())
+	}
+	sum := s.Checksum(checksumOpts...)
+	fmt.Println(sum)
+
+	if *expect != "" && *expect != sum {
+		fmt.Fprintf(os.Stderr, "Schema changed: expected checksum %s\n", *expect)
+		os.Exit(1)
+	}
+}
+
+func printChecksumUsage() {
+	fmt.Printf(`dbml checksum - Print a stable checksum of the schema
+
+Prints the SHA-256 checksum of the schema's structure, as computed by
+schema.Checksum. Tables, indexes, and references are hashed in a canonical
+order, and table statistics and migration versions are ignored, so the
+checksum only changes when the schema does.
+
+USAGE:
+    dbml checksum [OPTIONS]
+
+OPTIONS:
+    --ignore-comments              Leave comments and comment annotations out of the checksum
+    --expect <CHECKSUM>            Exit with status 1 if the checksum differs from this one
+    -url, --url <URL>              PostgreSQL connection URL
+    -s, --schemas <SCHEMAS>        Comma-separated schemas to include (default: public)
+    -x, --exclude-tables <TABLES>  Comma-separated tables to exclude
+    -a, --all-schemas              Include all non-system schemas
+    --views                        Include views and materialized views
+    -h, --help                     Show help
+
+All other options of 'dbml' that select or transform the schema, such as
+--exclude-schemas, --rename-file, and --schema-alias, apply too.
+
+ENVIRONMENT VARIABLES:
+    DATABASE_URL                   PostgreSQL connection URL
+
+EXAMPLES:
+    # Regenerate docs only when the schema changed
+    sum=$(dbml checksum --url "$DATABASE_URL")
+    if [ "$sum" != "$(cat .schema-checksum)" ]; then
+        dbml --url "$DATABASE_URL" --output schema.dbml
+        echo "$sum" > .schema-checksum
+    fi
+
+    # Fail CI when production drifts from the reviewed schema
+    dbml checksum --url "$DATABASE_URL" --expect "$(cat .schema-checksum)"
+
+`)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasefe/dbml/convert"
+)
+
+// formatExtensions maps file extensions to the formats inferred from them
+// when --from or --to is omitted.
+var formatExtensions = map[string]convert.Format{
+	".dbml": convert.DBML,
+	".json": convert.JSON,
+	".sql":  convert.SQL,
+	".mmd":  convert.Mermaid,
+}
+
+func runConvert(args []string) {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	from := fs.String("from", "", "Input format: dbml, json, or sql (default: from the input file's extension)")
+	to := fs.String("to", "", "Output format: dbml, json, sql, or mermaid (default: from the output file's extension, or dbml)")
+	output := fs.String("output", "", "Output file (default: stdout)")
+	fs.StringVar(output, "o", "", "Output file (short form)")
+	help := fs.Bool("help", false, "Show help information")
+	fs.BoolVar(help, "h", false, "Show help information (short form)")
+	fs.Parse(args)
+
+	if *help {
+		printConvertUsage()
+		os.Exit(0)
+	}
+	if fs.NArg() > 1 {
+		log.Fatal("dbml convert takes at most one input file")
+	}
+	inputFile := fs.Arg(0)
+
+	fromFormat, err := conversionFormat(*from, inputFile, "--from")
+	if err != nil {
+		log.Fatal(err)
+	}
+	toFormat := convert.DBML
+	if *to != "" || filepath.Ext(*output) != "" {
+		if toFormat, err = conversionFormat(*to, *output, "--to"); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var input []byte
+	if inputFile == "" {
+		input, err = io.ReadAll(os.Stdin)
+	} else {
+		input, err = os.ReadFile(inputFile)
+	}
+	if err != nil {
+		log.Fatalf("Failed to read input: %v", err)
+	}
+
+	content, err := convert.Convert(input, fromFormat, toFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(content)
+		return
+	}
+	if err := os.WriteFile(*output, content, 0644); err != nil {
+		log.Fatalf("Failed to write to file %s: %v", *output, err)
+	}
+	fmt.Fprintf(os.Stderr, "%s written to %s (%d bytes)\n", strings.ToUpper(string(toFormat)), *output, len(content))
+}
+
+// conversionFormat parses the format named by flag, or infers it from
+// filename's extension when the flag is empty.
+func conversionFormat(name, filename, flag string) (convert.Format, error) {
+	if name != "" {
+		return convert.ParseFormat(name)
+	}
+	if f, ok := formatExtensions[strings.ToLower(filepath.Ext(filename))]; ok {
+		return f, nil
+	}
+	return "", fmt.Errorf("%s is required when it cannot be inferred from a file extension", flag)
+}
+
+func printConvertUsage() {
+	fmt.Printf(`dbml convert - Convert a schema between formats without a database
+
+Reads a schema as DBML, JSON (as served at /schema.json), or PostgreSQL DDL
+such as pg_dump --schema-only output, and writes it as DBML, JSON, DDL, or a
+Mermaid erDiagram. Formats default to the input and output file extensions
+(.dbml, .json, .sql, .mmd); output is DBML otherwise.
+
+USAGE:
+    dbml convert [OPTIONS] [FILE]
+
+With no file, reads stdin.
+
+OPTIONS:
+    --from <FORMAT>                Input format: dbml, json, or sql
+    --to <FORMAT>                  Output format: dbml (default), json, sql, or mermaid
+    -o, --output <FILE>            Output file (default: stdout)
+    -h, --help                     Show help
+
+EXAMPLES:
+    # Document a pg_dump in DBML
+    pg_dump --schema-only mydb > schema.sql
+    dbml convert -o schema.dbml schema.sql
+
+    # Turn DBML into DDL
+    dbml convert --from dbml --to sql < schema.dbml
+
+    # Embed a diagram in a README
+    dbml convert --to mermaid schema.dbml
+
+`)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+	"github.com/lucasefe/dbml/sink"
+)
+
+// writeDatabases writes each database's schema to its own file in the
+// --output directory or bucket, named after the database (app.dbml,
+// billing.dbml, ...).
+func writeDatabases(databases []schema.Database, config Config) {
+	// handleCommonFlags checked that --output is set
+	if !strings.HasSuffix(config.OutputFile, "/") {
+		config.OutputFile += "/"
+	}
+
+	ctx := context.Background()
+	dst := openOutput(config)
+	p := buildPipeline(config)
+	ext := filepath.Ext(sink.FileName(config.Format))
+
+	for _, db := range databases {
+		s := db.Schema
+		if config.TagPII {
+			s = detectPII(s, config)
+		}
+
+		s, err := p.Schema(s)
+		if err != nil {
+			log.Fatalf("Failed to generate %s for database %s: %v", config.Format, db.Name, err)
+		}
+		content, err := renderFormat(s, config.Format, config)
+		if err == nil {
+			content, err = p.Output(content, config.Format)
+		}
+		if err != nil {
+			log.Fatalf("Failed to generate %s for database %s: %v", config.Format, db.Name, err)
+		}
+
+		name := db.Name + ext
+		if err := dst.Write(ctx, name, content); err != nil {
+			log.Fatalf("Failed to write output: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "%s for %s written to %s%s (%d bytes)\n", strings.ToUpper(config.Format), db.Name, config.OutputFile, name, len(content))
+	}
+}
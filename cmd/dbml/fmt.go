@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/parser"
+)
+
+func runFmt(args []string) {
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := fs.Bool("w", false, "Write the result to each file instead of stdout")
+	list := fs.Bool("l", false, "List files whose formatting differs")
+	check := fs.Bool("check", false, "Exit with status 1 if any file's formatting differs")
+	sortFlag := fs.String("sort", "alpha", "How to order tables and columns: alpha or natural")
+	columnOrderFlag := fs.String("column-order", "original", "How to order columns: original (as written) or alpha (by name)")
+	alphaColumns := fs.Bool("alphabetical-columns", false, "Deprecated: use --column-order alpha")
+	indentFlag := fs.String("indent", "2", "Indentation: tab or a number of spaces")
+	blankLinesFlag := fs.String("blank-lines", "sections", "Where to write blank lines: sections or blocks")
+	spacingFlag := fs.String("attribute-spacing", "normal", "How to space settings: normal, compact, or padded")
+	help := fs.Bool("help", false, "Show help information")
+	fs.BoolVar(help, "h", false, "Show help information (short form)")
+	fs.Parse(args)
+
+	if *help {
+		printFmtUsage()
+		os.Exit(0)
+	}
+	order, err := generator.ParseSortOrder(*sortFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	columnOrder, err := generator.ParseColumnOrder(resolveColumnOrder(fs, *columnOrderFlag, *alphaColumns))
+	if err != nil {
+		log.Fatal(err)
+	}
+	indent, err := generator.ParseIndent(*indentFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	blankLines, err := generator.ParseBlankLines(*blankLinesFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spacing, err := generator.ParseAttributeSpacing(*spacingFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := []generator.Option{
+		generator.WithSortOrder(order),
+		generator.WithColumnOrder(columnOrder),
+		generator.WithIndent(indent),
+		generator.WithBlankLines(blankLines),
+		generator.WithAttributeSpacing(spacing),
+	}
+
+	if fs.NArg() == 0 {
+		if *write {
+			log.Fatal("-w requires file arguments")
+		}
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("Failed to read stdin: %v", err)
+		}
+		formatted, err := formatDBML(input, opts)
+		if err != nil {
+			log.Fatalf("<stdin>: %v", err)
+		}
+		if *check || *list {
+			if !bytes.Equal(input, formatted) {
+				if *list {
+					fmt.Println("<stdin>")
+				}
+				os.Exit(1)
+			}
+			return
+		}
+		os.Stdout.Write(formatted)
+		return
+	}
+
+	changed := false
+	for _, filename := range fs.Args() {
+		input, err := os.ReadFile(filename)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", filename, err)
+		}
+		formatted, err := formatDBML(input, opts)
+		if err != nil {
+			log.Fatalf("%s: %v", filename, err)
+		}
+
+		differs := !bytes.Equal(input, formatted)
+		changed = changed || differs
+		if *list && differs {
+			fmt.Println(filename)
+		}
+		if *write && differs {
+			if err := os.WriteFile(filename, formatted, 0644); err != nil {
+				log.Fatalf("Failed to write to file %s: %v", filename, err)
+			}
+		}
+		if !*write && !*list && !*check {
+			os.Stdout.Write(formatted)
+		}
+	}
+
+	if *check && changed {
+		os.Exit(1)
+	}
+}
+
+// formatDBML rewrites a DBML document in the generator's canonical style,
+// with notes and the given options. Blocks the schema cannot represent, such
+// as Project, are kept verbatim at the top.
+func formatDBML(input []byte, opts []generator.Option) ([]byte, error) {
+	doc, err := parser.ParseDocument(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Dropped) > 0 {
+		return nil, fmt.Errorf("cannot format without losing:\n  %s", strings.Join(doc.Dropped, "\n  "))
+	}
+
+	// Enum blocks are kept verbatim among the blocks, with their comments
+	doc.Schema.Enums = nil
+	body, err := generator.Generate(doc.Schema, append([]generator.Option{generator.WithNotes()}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	for _, block := range doc.Blocks {
+		b.WriteString(strings.TrimSpace(block))
+		b.WriteString("\n\n")
+	}
+	b.Write(body)
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n'), nil
+}
+
+func printFmtUsage() {
+	fmt.Printf(`dbml fmt - Rewrite DBML files in canonical style
+
+Parses each file and writes it back the way dbml generates DBML: tables,
+indexes, and refs sorted, columns in the order they are written, with
+consistent indentation, quoting, and settings. Like gofmt, it makes
+hand-written and generated files diff cleanly.
+
+Project, Enum, and other blocks dbml does not model are kept verbatim, with
+their comments, at the top of the file. Files with anything else formatting
+would lose, such as comments elsewhere or settings dbml does not model, are
+left alone and reported with the line of each.
+
+USAGE:
+    dbml fmt [OPTIONS] [FILES...]
+
+With no files, formats stdin to stdout.
+
+OPTIONS:
+    -w                             Write the result to each file instead of stdout
+    -l                             List files whose formatting differs
+    --check                        Exit with status 1 if any file's formatting differs
+    --sort <ORDER>                 Order names alpha (default) or natural
+    --column-order <ORDER>         Order columns original (default, as written) or alpha (by name)
+    --alphabetical-columns         Deprecated: same as --column-order alpha
+    --indent <INDENT>              Indent with tab or N spaces (default: 2)
+    --blank-lines <POLICY>         Blank lines between blocks and table sections (default) or blocks only
+    --attribute-spacing <SPACING>  Space settings normal [a, b] (default), compact [a,b], or padded [ a, b ]
+    -h, --help                     Show help
+
+EXAMPLES:
+    # Format files in place
+    dbml fmt -w schema.dbml docs/*.dbml
+
+    # Fail CI when a file is not formatted
+    dbml fmt --check schema.dbml
+
+`)
+}
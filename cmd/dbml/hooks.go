@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lucasefe/dbml/introspect"
+)
+
+func runHooks(args []string) {
+	if len(args) == 0 || (args[0] != "install" && args[0] != "uninstall") {
+		printHooksUsage()
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "-help") {
+			os.Exit(0)
+		}
+		os.Exit(1)
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("hooks "+action, flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the SQL without running it")
+
+	config := parseFlags(fs, args[1:])
+
+	script := introspect.InstallEventTriggerSQL()
+	if action == "uninstall" {
+		script = introspect.UninstallEventTriggerSQL()
+	}
+	if *dryRun && !config.ShowHelp {
+		fmt.Print(script)
+		return
+	}
+
+	handleCommonFlags(&config, printHooksUsage)
+	if config.ReadOnly {
+		log.Fatal("dbml hooks cannot be used with --read-only")
+	}
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open database connection: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if action == "install" {
+		if err := introspect.InstallEventTrigger(ctx, db); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "Installed the dbml_schema_change event trigger; 'dbml watch --live' now reacts to schema changes\n")
+		return
+	}
+
+	if err := introspect.UninstallEventTrigger(ctx, db); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(os.Stderr, "Removed the dbml_schema_change event trigger\n")
+}
+
+func printHooksUsage() {
+	fmt.Printf(`dbml hooks - Manage the event trigger used by live watch
+
+'dbml hooks install' creates the public.dbml_notify_schema_change() function
+and the dbml_schema_change event trigger, which sends a NOTIFY on the
+dbml_schema_change channel after every DDL command. 'dbml watch --live'
+listens on that channel. 'dbml hooks uninstall' removes both.
+
+Creating event triggers requires superuser privileges. Use --dry-run to print
+the exact SQL for review, or to run it yourself with psql.
+
+USAGE:
+    dbml hooks install [OPTIONS]
+    dbml hooks uninstall [OPTIONS]
+
+OPTIONS:
+    --dry-run                      Print the SQL without connecting to the database
+    -url, --url <URL>              PostgreSQL connection URL
+    -h, --help                     Show help
+
+ENVIRONMENT VARIABLES:
+    DATABASE_URL                   PostgreSQL connection URL
+
+EXAMPLES:
+    # Review the SQL before granting
+    dbml hooks install --dry-run > hooks.sql
+
+    # Install, then watch live
+    dbml hooks install --url "postgres://postgres@localhost/dev"
+    dbml watch --url "postgres://postgres@localhost/dev" --live --output schema.dbml
+
+`)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lucasefe/dbml/introspect"
+	"github.com/lucasefe/dbml/lint"
+)
+
+func runLint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	configFile := fs.String("config", "", "Lint configuration file (JSON)")
+	failOn := fs.String("fail-on", "error", "Exit with status 1 when a finding has at least this severity")
+	jsonOutput := fs.Bool("json", false, "Print findings as JSON")
+	listRules := fs.Bool("list-rules", false, "List the available rules and exit")
+
+	config := parseFlags(fs, args)
+
+	if *listRules {
+		for _, rule := range lint.Rules() {
+			fmt.Printf("%-16s %-8s %s\n", rule.Name, rule.Severity, rule.Description)
+		}
+		return
+	}
+
+	handleCommonFlags(&config, printLintUsage)
+
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil {
+		log.Fatalf("Invalid --fail-on: %v", err)
+	}
+
+	lintConfig := lint.DefaultConfig()
+	if *configFile != "" {
+		lintConfig, err = lint.LoadConfig(*configFile)
+		if err != nil {
+			log.Fatalf("Failed to load lint config: %v", err)
+		}
+	}
+
+	s, err := introspect.FromConnectionString(config.DatabaseURL, introspectOptions(config)...)
+	if err != nil {
+		log.Fatalf("Failed to introspect database: %v", err)
+	}
+
+	findings := lint.Lint(s, lintConfig)
+
+	if *jsonOutput {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			log.Fatalf("Failed to encode findings: %v", err)
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+		fmt.Fprintf(os.Stderr, "%d finding(s) in %d table(s)\n", len(findings), len(s.Tables))
+	}
+
+	if threshold != lint.Off && lint.Max(findings) >= threshold {
+		os.Exit(1)
+	}
+}
+
+func printLintUsage() {
+	fmt.Printf(`dbml lint - Check the database schema against design rules
+
+USAGE:
+    dbml lint [OPTIONS]
+
+OPTIONS:
+    --config <FILE>                Lint configuration file (JSON)
+    --fail-on <SEVERITY>           Exit with status 1 at or above this severity:
+                                   info, warning, error, or off (default: error)
+    --json                         Print findings as JSON
+    --list-rules                   List the available rules and exit
+    -url, --url <URL>              PostgreSQL connection URL
+    -s, --schemas <SCHEMAS>        Comma-separated schemas to include (default: public)
+    -x, --exclude-tables <TABLES>  Comma-separated tables to exclude
+    -a, --all-schemas              Include all non-system schemas
+    -h, --help                     Show help
+
+RULES:
+    primary-key                    Every table has a primary key (error)
+    fk-index                       Foreign key columns are covered by an index (warning)
+    naming                         Table and column names match the naming pattern (warning)
+    varchar-length                 varchar columns declare a length (info)
+
+CONFIGURATION:
+    {
+      "rules": {"varchar-length": "off", "fk-index": "error"},
+      "suppress": ["primary-key:public.audit_log", "naming:*.legacy_*"],
+      "naming_pattern": "^[a-z][a-z0-9_]*$"
+    }
+
+ENVIRONMENT VARIABLES:
+    DATABASE_URL                   PostgreSQL connection URL
+
+`)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lucasefe/dbml/mcp"
+)
+
+func runMCP(args []string) {
+	fs := flag.NewFlagSet("mcp", flag.ExitOnError)
+
+	config := parseFlags(fs, args)
+	handleCommonFlags(&config, printMCPUsage)
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open database connection: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	srv := mcp.New(db,
+		mcp.WithServerInfo("dbml", version),
+		mcp.WithIntrospectOptions(introspectOptions(config)...),
+	)
+
+	if err := srv.Serve(os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("MCP server failed: %v", err)
+	}
+}
+
+func printMCPUsage() {
+	fmt.Printf(`dbml mcp - Serve the database schema to AI assistants over MCP (stdio)
+
+USAGE:
+    dbml mcp [OPTIONS]
+
+OPTIONS:
+    -url, --url <URL>              PostgreSQL connection URL
+    -s, --schemas <SCHEMAS>        Comma-separated schemas to include (default: public)
+    -x, --exclude-tables <TABLES>  Comma-separated tables to exclude
+    -a, --all-schemas              Include all non-system schemas
+    -h, --help                     Show help
+
+TOOLS:
+    list_tables                    Names of all tables
+    get_schema                     Full schema as DBML
+    get_table                      Columns, keys, and indexes of one table
+    find_relationships             Foreign keys from and to one table
+
+EXAMPLE CLIENT CONFIGURATION:
+    {
+      "mcpServers": {
+        "database": {
+          "command": "dbml",
+          "args": ["mcp", "--url", "postgres://localhost/mydb"]
+        }
+      }
+    }
+
+`)
+}
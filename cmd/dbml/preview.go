@@ -0,0 +1,70 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lucasefe/dbml/preview"
+)
+
+func runPreview(args []string) {
+	fs := flag.NewFlagSet("preview", flag.ExitOnError)
+	listen := fs.String("listen", "localhost:8081", "Address to listen on")
+	interval := fs.Duration("interval", preview.DefaultRefreshInterval, "How often to re-introspect while the page is open")
+
+	config := parseFlags(fs, args)
+	handleCommonFlags(&config, printPreviewUsage)
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open database connection: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	srv := preview.New(db,
+		preview.WithRefreshInterval(*interval),
+		preview.WithIntrospectOptions(introspectOptions(config)...),
+		preview.WithGeneratorOptions(generatorOptions(config)...),
+	)
+
+	fmt.Fprintf(os.Stderr, "Previewing schema at http://%s/ (refreshing every %s)\n", *listen, interval.Round(time.Second))
+	if err := srv.ListenAndServe(*listen); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+func printPreviewUsage() {
+	fmt.Printf(`dbml preview - Explore the schema as a live diagram in the browser
+
+Serves a page showing the schema as a diagram next to its DBML source. The
+page reloads automatically when the schema changes. Everything is rendered
+locally; nothing is uploaded.
+
+USAGE:
+    dbml preview [OPTIONS]
+
+OPTIONS:
+    --listen <ADDR>                Address to listen on (default: localhost:8081)
+    --interval <DURATION>          How often to re-introspect while the page is open (default: 5s)
+    -url, --url <URL>              PostgreSQL connection URL
+    -s, --schemas <SCHEMAS>        Comma-separated schemas to include (default: public)
+    -x, --exclude-tables <TABLES>  Comma-separated tables to exclude
+    -a, --all-schemas              Include all non-system schemas
+    -h, --help                     Show help
+
+The DBML source is generated with the same options as dbml's DBML output,
+such as --notes, --column-order, and --self-refs.
+
+ENVIRONMENT VARIABLES:
+    DATABASE_URL                   PostgreSQL connection URL
+
+`)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lucasefe/dbml/server"
+)
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	listen := fs.String("listen", ":8080", "Address to listen on")
+	token := fs.String("token", "", "Bearer token required by clients (can also use DBML_TOKEN env var)")
+	cacheTTL := fs.Duration("cache-ttl", 0, "Reuse the introspected schema for this long (default: introspect on every request)")
+
+	config := parseFlags(fs, args)
+	handleCommonFlags(&config, printServeUsage)
+
+	if *token == "" {
+		*token = os.Getenv("DBML_TOKEN")
+	}
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open database connection: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	srv := server.New(db,
+		server.WithToken(*token),
+		server.WithCacheTTL(*cacheTTL),
+		server.WithIntrospectOptions(introspectOptions(config)...),
+	)
+
+	fmt.Fprintf(os.Stderr, "Serving schema on %s\n", *listen)
+	if err := srv.ListenAndServe(*listen); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+func printServeUsage() {
+	fmt.Printf(`dbml serve - Serve the database schema over HTTP
+
+USAGE:
+    dbml serve [OPTIONS]
+
+OPTIONS:
+    --listen <ADDR>                Address to listen on (default: :8080)
+    --token <TOKEN>                Bearer token required by clients
+    --cache-ttl <DURATION>         Reuse the schema for this long, e.g. 30s (default: always introspect)
+    -url, --url <URL>              PostgreSQL connection URL
+    -s, --schemas <SCHEMAS>        Comma-separated schemas to include (default: public)
+    -x, --exclude-tables <TABLES>  Comma-separated tables to exclude
+    -a, --all-schemas              Include all non-system schemas
+    -h, --help                     Show help
+
+ENDPOINTS:
+    GET /dbml                      Schema rendered as DBML
+    GET /schema.json               Schema as JSON
+    GET /health                    Database connectivity check (no token required)
+
+    Add ?refresh=true to bypass the cache.
+
+ENVIRONMENT VARIABLES:
+    DATABASE_URL                   PostgreSQL connection URL
+    DBML_TOKEN                     Bearer token required by clients
+
+`)
+}
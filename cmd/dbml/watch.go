@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/introspect"
+	"github.com/lucasefe/dbml/notify"
+	"github.com/lucasefe/dbml/pipeline"
+	"github.com/lucasefe/dbml/schema"
+	"github.com/lucasefe/dbml/sink"
+)
+
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := fs.Duration("interval", time.Minute, "How often to introspect the database")
+	webhookURL := fs.String("webhook", "", "POST a JSON change event to this URL (can also use DBML_WEBHOOK_URL env var)")
+	slackURL := fs.String("slack-webhook", "", "Post change messages to this Slack incoming webhook (can also use DBML_SLACK_WEBHOOK_URL env var)")
+	live := fs.Bool("live", false, "Regenerate as soon as the event trigger reports a schema change")
+	installTrigger := fs.Bool("install-trigger", false, "Install the DDL event trigger used by --live (requires superuser)")
+
+	config := parseFlags(fs, args)
+	handleCommonFlags(&config, printWatchUsage)
+
+	if *webhookURL == "" {
+		*webhookURL = os.Getenv("DBML_WEBHOOK_URL")
+	}
+	if *slackURL == "" {
+		*slackURL = os.Getenv("DBML_SLACK_WEBHOOK_URL")
+	}
+	if *interval <= 0 {
+		log.Fatalf("Invalid interval %s: must be positive", *interval)
+	}
+
+	var notifiers []notify.Notifier
+	if *webhookURL != "" {
+		notifiers = append(notifiers, notify.NewWebhook(*webhookURL))
+	}
+	if *slackURL != "" {
+		notifiers = append(notifiers, notify.NewSlack(*slackURL))
+	}
+
+	if *live && config.Standby {
+		log.Fatal("--live cannot be used with --standby: notifications are not replicated to standbys")
+	}
+	if *installTrigger && config.ReadOnly {
+		log.Fatal("--install-trigger cannot be used with --read-only")
+	}
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open database connection: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *installTrigger {
+		if err := introspect.InstallEventTrigger(ctx, db); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintln(os.Stderr, "Installed the dbml_schema_change event trigger")
+	}
+
+	var changes <-chan *pq.Notification
+	if *live {
+		listener := pq.NewListener(config.DatabaseURL, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+			if err != nil {
+				log.Printf("Schema change listener: %v", err)
+			}
+		})
+		defer listener.Close()
+		if err := listener.Listen(introspect.SchemaChangeChannel); err != nil {
+			log.Fatalf("Failed to listen for schema changes: %v", err)
+		}
+		changes = listener.Notify
+	}
+
+	opts := introspectOptions(config)
+	p := buildPipeline(config)
+	current, err := introspect.Database(db, opts...)
+	if err != nil {
+		log.Fatalf("Failed to introspect database: %v", err)
+	}
+	writeWatchOutput(ctx, current, config, p)
+	if *live {
+		fmt.Fprintf(os.Stderr, "Watching schema live, rechecking every %s (fingerprint %s)\n", *interval, current.Fingerprint())
+	} else {
+		fmt.Fprintf(os.Stderr, "Watching schema every %s (fingerprint %s)\n", *interval, current.Fingerprint())
+	}
+
+	// In live mode the interval still runs, catching changes made while the
+	// listener was reconnecting or before the trigger was installed.
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-changes:
+			// A nil notification means the listener reconnected and may
+			// have missed changes, which a recheck covers as well
+			waitQuiet(ctx, changes, liveDebounce)
+		}
+
+		next, err := introspect.Database(db, opts...)
+		if err != nil {
+			// Keep watching through transient failures such as restarts
+			log.Printf("Failed to introspect database: %v", err)
+			continue
+		}
+		if next.Fingerprint() == current.Fingerprint() {
+			continue
+		}
+
+		event := notify.NewEvent(current, next)
+		event.Database = databaseLabel(config.DatabaseURL)
+		fmt.Fprintf(os.Stderr, "Schema changed (fingerprint %s):\n%s", event.Fingerprint, event.Changes)
+
+		writeWatchOutput(ctx, next, config, p)
+		for _, n := range notifiers {
+			if err := n.Notify(ctx, event); err != nil {
+				log.Printf("Failed to deliver notification: %v", err)
+			}
+		}
+		current = next
+	}
+}
+
+// liveDebounce is how long the schema must stay quiet after a change
+// notification before regenerating, so a migration running many DDL
+// statements in separate transactions regenerates once.
+const liveDebounce = time.Second
+
+// waitQuiet returns once no notification has arrived on changes for d.
+func waitQuiet(ctx context.Context, changes <-chan *pq.Notification, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			return
+		case <-changes:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(d)
+		}
+	}
+}
+
+// writeWatchOutput regenerates the output through the pipeline, if an output
+// was requested.
+func writeWatchOutput(ctx context.Context, s *schema.Schema, config Config, p *pipeline.Pipeline) {
+	if config.OutputFile == "" {
+		return
+	}
+
+	s, err := p.Schema(s)
+	if err != nil {
+		log.Printf("Failed to generate %s: %v", config.Format, err)
+		return
+	}
+
+	dst := openOutput(config)
+	if config.Format == "ent" {
+		if _, err := writeEntSchema(ctx, s, dst, p); err != nil {
+			log.Printf("Failed to generate ent schema: %v", err)
+		}
+		return
+	}
+
+	content, err := renderFormat(s, config.Format, config)
+	if err == nil {
+		content, err = p.Output(content, config.Format)
+	}
+	if err != nil {
+		log.Printf("Failed to generate %s: %v", config.Format, err)
+		return
+	}
+	if err := dst.Write(ctx, sink.FileName(config.Format), content); err != nil {
+		log.Printf("Failed to write output: %v", err)
+	}
+}
+
+// databaseLabel identifies the database in notifications without leaking
+// credentials: host and database name for URLs, nothing otherwise.
+func databaseLabel(connStr string) string {
+	u, err := url.Parse(connStr)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Host + u.Path
+}
+
+func printWatchUsage() {
+	fmt.Printf(`dbml watch - Watch the database for schema changes
+
+Introspects the database on an interval. When the schema fingerprint changes,
+prints a summary of the changes, regenerates the output file if one is given,
+and notifies the configured webhooks.
+
+With --live, it also listens for notifications from a DDL event trigger and
+rechecks as soon as a change commits. --install-trigger installs the trigger,
+which requires superuser privileges; see 'dbml hooks --help' to review or
+remove it.
+
+USAGE:
+    dbml watch [OPTIONS]
+
+OPTIONS:
+    --interval <DURATION>          How often to introspect, e.g. 30s (default: 1m)
+    --webhook <URL>                POST a JSON change event to this URL
+    --slack-webhook <URL>          Post change messages to a Slack incoming webhook
+    --live                         Recheck as soon as the event trigger reports a change
+    --install-trigger              Install the DDL event trigger used by --live
+    -o, --output <TARGET>          Regenerate this file, directory, URL, or bucket on every change
+    -f, --format <FORMAT>          Output format for --output (default: dbml)
+    -url, --url <URL>              PostgreSQL connection URL
+    -s, --schemas <SCHEMAS>        Comma-separated schemas to include (default: public)
+    -x, --exclude-tables <TABLES>  Comma-separated tables to exclude
+    -a, --all-schemas              Include all non-system schemas
+    -h, --help                     Show help
+
+ENVIRONMENT VARIABLES:
+    DATABASE_URL                   PostgreSQL connection URL
+    DBML_WEBHOOK_URL               Webhook URL for change events
+    DBML_SLACK_WEBHOOK_URL         Slack incoming webhook URL
+
+WEBHOOK PAYLOAD:
+    {
+      "database": "db.example.com:5432/app",
+      "previous_fingerprint": "...",
+      "fingerprint": "...",
+      "detected_at": "2024-01-01T12:00:00Z",
+      "changes": {
+        "added_tables": ["orders"],
+        "changed_tables": [{"table": "users", "added_columns": ["last_login"]}]
+      }
+    }
+
+`)
+}
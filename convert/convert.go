@@ -0,0 +1,105 @@
+// Package convert translates schemas between JSON, DBML, SQL, and Mermaid.
+//
+// It depends only on the standard library and the schema, parser, generator,
+// ddl, and diagram packages, so it can be compiled to WebAssembly.
+//
+// Basic usage:
+//
+//	sql, err := convert.Convert(dbmlInput, convert.DBML, convert.SQL)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lucasefe/dbml/ddl"
+	"github.com/lucasefe/dbml/diagram"
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/parser"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Format identifies a schema representation.
+type Format string
+
+// Supported formats.
+const (
+	// JSON is the encoding/json form of schema.Schema, as served by
+	// the server package's /schema.json endpoint.
+	JSON Format = "json"
+	// DBML is Database Markup Language.
+	DBML Format = "dbml"
+	// SQL is PostgreSQL DDL. Reading it understands the statements that
+	// define tables, as written by the ddl package or pg_dump.
+	SQL Format = "sql"
+	// Mermaid is a Mermaid erDiagram. It can only be produced, not read.
+	Mermaid Format = "mermaid"
+)
+
+// ParseFormat converts a case-insensitive format name into a Format.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case JSON, DBML, SQL, Mermaid:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown format: %s", name)
+	}
+}
+
+// Decode reads a schema from input in the given format.
+func Decode(input []byte, from Format) (*schema.Schema, error) {
+	switch from {
+	case JSON:
+		var s schema.Schema
+		if err := json.Unmarshal(input, &s); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		return &s, nil
+	case DBML:
+		s, err := parser.Parse(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DBML: %w", err)
+		}
+		return s, nil
+	case SQL:
+		s, err := ddl.Parse(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SQL: %w", err)
+		}
+		return s, nil
+	case Mermaid:
+		return nil, fmt.Errorf("reading %s is not supported", from)
+	default:
+		return nil, fmt.Errorf("unknown format: %s", from)
+	}
+}
+
+// Encode writes a schema in the given format. DBML output includes table
+// and column notes.
+func Encode(s *schema.Schema, to Format) ([]byte, error) {
+	switch to {
+	case JSON:
+		return json.MarshalIndent(s, "", "  ")
+	case DBML:
+		return generator.Generate(s, generator.WithNotes())
+	case SQL:
+		return ddl.Generate(s)
+	case Mermaid:
+		return diagram.Mermaid(s), nil
+	default:
+		return nil, fmt.Errorf("unknown format: %s", to)
+	}
+}
+
+// Convert decodes input in one format and encodes it in another.
+func Convert(input []byte, from, to Format) ([]byte, error) {
+	s, err := Decode(input, from)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(s, to)
+}
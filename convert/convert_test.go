@@ -0,0 +1,131 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleDBML = `Table users {
+  id int [pk]
+  email varchar(255) [not null]
+}
+
+Table posts {
+  id int [pk]
+  user_id int [not null]
+}
+
+Ref: posts.user_id > users.id [delete: cascade]
+`
+
+func TestConvertDBMLToSQL(t *testing.T) {
+	output, err := Convert([]byte(sampleDBML), DBML, SQL)
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+
+	if !strings.Contains(string(output), "ALTER TABLE posts ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;") {
+		t.Errorf("SQL output missing foreign key:\n%s", output)
+	}
+}
+
+func TestConvertEnums(t *testing.T) {
+	input := `Enum status {
+  active
+  archived
+}
+
+Table users {
+  id int [pk]
+  state status
+}
+`
+
+	sql, err := Convert([]byte(input), DBML, SQL)
+	if err != nil {
+		t.Fatalf("Convert to SQL returned error: %v", err)
+	}
+	if !strings.Contains(string(sql), "CREATE TYPE status AS ENUM ('active', 'archived');") {
+		t.Errorf("SQL output missing enum type:\n%s", sql)
+	}
+
+	dbmlOutput, err := Convert([]byte(input), DBML, DBML)
+	if err != nil {
+		t.Fatalf("Convert to DBML returned error: %v", err)
+	}
+	if !strings.Contains(string(dbmlOutput), "Enum status {\n  active\n  archived\n}") {
+		t.Errorf("DBML output missing Enum block:\n%s", dbmlOutput)
+	}
+}
+
+func TestConvertDBMLRoundTripKeepsNotes(t *testing.T) {
+	input := `Table users {
+  id int [pk]
+  email varchar [note: 'Login address']
+
+  Note: 'Registered users'
+}
+`
+
+	output, err := Convert([]byte(input), DBML, DBML)
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+	if strings.TrimSpace(string(output)) != strings.TrimSpace(input) {
+		t.Errorf("Expected the DBML unchanged, got:\n%s", output)
+	}
+}
+
+func TestConvertJSONRoundTrip(t *testing.T) {
+	jsonOutput, err := Convert([]byte(sampleDBML), DBML, JSON)
+	if err != nil {
+		t.Fatalf("Convert to JSON returned error: %v", err)
+	}
+
+	dbmlOutput, err := Convert(jsonOutput, JSON, DBML)
+	if err != nil {
+		t.Fatalf("Convert from JSON returned error: %v", err)
+	}
+
+	if !strings.Contains(string(dbmlOutput), "Ref: posts.user_id > users.id [delete: cascade]") {
+		t.Errorf("DBML output missing reference:\n%s", dbmlOutput)
+	}
+}
+
+func TestConvertSQLToDBML(t *testing.T) {
+	sql, err := Convert([]byte(sampleDBML), DBML, SQL)
+	if err != nil {
+		t.Fatalf("Convert to SQL returned error: %v", err)
+	}
+
+	dbmlOutput, err := Convert(sql, SQL, DBML)
+	if err != nil {
+		t.Fatalf("Convert from SQL returned error: %v", err)
+	}
+
+	for _, want := range []string{"email varchar(255) [not null]", "Ref: posts.user_id > users.id [delete: cascade]"} {
+		if !strings.Contains(string(dbmlOutput), want) {
+			t.Errorf("DBML output missing %q:\n%s", want, dbmlOutput)
+		}
+	}
+}
+
+func TestConvertToMermaid(t *testing.T) {
+	output, err := Convert([]byte(sampleDBML), DBML, Mermaid)
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+
+	if !strings.Contains(string(output), `posts }o--|| users : "user_id"`) {
+		t.Errorf("Mermaid output missing relationship:\n%s", output)
+	}
+}
+
+func TestConvertUnsupported(t *testing.T) {
+	if _, err := Convert([]byte("erDiagram\n"), Mermaid, DBML); err == nil {
+		t.Errorf("Expected error when reading Mermaid")
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Errorf("Expected error for unknown format")
+	}
+}
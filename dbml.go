@@ -1,11 +1,13 @@
 package dbml
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 
 	_ "github.com/lib/pq"
+	"github.com/lucasefe/dbml/sink"
 )
 
 // Config specifies options for DBML generation.
@@ -110,6 +112,18 @@ func WriteToFileFromConnectionString(connStr, filename string, config *Config) e
 	return WriteToFile(db, filename, config)
 }
 
+// WriteToSink generates DBML from an existing database connection and writes
+// it to dst as "schema.dbml", e.g. a file, directory, URL, or bucket opened
+// with sink.Open.
+func WriteToSink(ctx context.Context, db *sql.DB, dst sink.Sink, config *Config) error {
+	dbmlContent, err := GenerateFromConnection(db, config)
+	if err != nil {
+		return err
+	}
+
+	return dst.Write(ctx, sink.FileName("dbml"), []byte(dbmlContent))
+}
+
 // FilterTables removes tables from the schema that match the exclude list.
 // It returns a new Schema with the filtered tables; the original is not modified.
 func FilterTables(schema *Schema, excludeTables []string) *Schema {
@@ -0,0 +1,427 @@
+// Package ddl renders schema definitions as PostgreSQL DDL statements, and
+// reads them back with Parse.
+//
+// Columns are rendered with their DatabaseType when introspection recorded
+// one. Otherwise the DBML type is translated back to its PostgreSQL
+// equivalent (for example "double" becomes "double precision"). Columns whose default is a sequence are rendered as
+// serial types so the output runs against an empty database.
+//
+// Basic usage:
+//
+//	output, err := ddl.Generate(schema)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.Stdout.Write(output)
+package ddl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Generate converts a Schema into PostgreSQL DDL.
+// The output creates non-public schemas, then enum types, then tables, then
+// indexes, and finally adds foreign keys so that tables can be created in any
+// order. WithDependencyOrder creates referenced tables first instead.
+func Generate(s *schema.Schema, opts ...Option) ([]byte, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var builder strings.Builder
+
+	tables := sortedTables(s.Tables)
+
+	for _, name := range extraSchemas(tables, s.Enums) {
+		builder.WriteString(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;\n\n", QuoteIdent(name)))
+	}
+
+	for _, enum := range sortedEnums(s.Enums) {
+		writeCreateEnum(&builder, enum)
+	}
+	if len(s.Enums) > 0 {
+		builder.WriteString("\n")
+	}
+
+	var deferred []schema.Reference
+	if o.dependencyOrder {
+		var inline map[string][]schema.Reference
+		tables, inline, deferred = dependencyOrder(tables)
+		for _, table := range tables {
+			writeCreateTable(&builder, table, inline[tableID(table.Name, table.Schema)])
+			builder.WriteString("\n")
+		}
+	} else {
+		for _, table := range tables {
+			writeCreateTable(&builder, table, nil)
+			builder.WriteString("\n")
+			deferred = append(deferred, table.References...)
+		}
+	}
+
+	for _, table := range tables {
+		if writeIndexes(&builder, table) {
+			builder.WriteString("\n")
+		}
+	}
+
+	for _, ref := range deferred {
+		writeForeignKey(&builder, ref)
+	}
+
+	return []byte(strings.TrimRight(builder.String(), "\n") + "\n"), nil
+}
+
+// GenerateString is a convenience wrapper that returns the DDL as a string.
+func GenerateString(s *schema.Schema, opts ...Option) (string, error) {
+	result, err := Generate(s, opts...)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+func sortedTables(tables []schema.Table) []schema.Table {
+	sorted := make([]schema.Table, len(tables))
+	copy(sorted, tables)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Schema != sorted[j].Schema {
+			return sorted[i].Schema < sorted[j].Schema
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func sortedEnums(enums []schema.Enum) []schema.Enum {
+	sorted := make([]schema.Enum, len(enums))
+	copy(sorted, enums)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Schema != sorted[j].Schema {
+			return sorted[i].Schema < sorted[j].Schema
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func extraSchemas(tables []schema.Table, enums []schema.Enum) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && name != "public" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, table := range tables {
+		add(table.Schema)
+	}
+	for _, enum := range enums {
+		add(enum.Schema)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeCreateEnum(builder *strings.Builder, enum schema.Enum) {
+	values := make([]string, len(enum.Values))
+	for i, value := range enum.Values {
+		values[i] = QuoteLiteral(value)
+	}
+	builder.WriteString(fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);\n", QualifiedName(enum.Name, enum.Schema), strings.Join(values, ", ")))
+}
+
+func writeCreateTable(builder *strings.Builder, table schema.Table, references []schema.Reference) {
+	kind := "TABLE"
+	if table.Foreign != nil {
+		kind = "FOREIGN TABLE"
+	}
+	builder.WriteString(fmt.Sprintf("CREATE %s %s (\n", kind, QualifiedName(table.Name, table.Schema)))
+
+	var lines []string
+	for _, column := range table.Columns {
+		lines = append(lines, "  "+columnDefinition(column))
+	}
+	if len(table.PrimaryKeys) > 0 {
+		lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", quoteList(table.PrimaryKeys)))
+	}
+	for _, index := range sortedIndexes(table.Indexes) {
+		if !index.UniqueConstraint {
+			continue
+		}
+		clause := fmt.Sprintf("UNIQUE (%s)", quoteList(index.Columns))
+		if len(index.Include) > 0 {
+			clause += fmt.Sprintf(" INCLUDE (%s)", quoteList(index.Include))
+		}
+		if index.Tablespace != "" {
+			clause += " USING INDEX TABLESPACE " + QuoteIdent(index.Tablespace)
+		}
+		if index.Name != "" {
+			clause = fmt.Sprintf("CONSTRAINT %s %s", QuoteIdent(index.Name), clause)
+		}
+		lines = append(lines, "  "+clause)
+	}
+	for _, check := range table.CheckConstraints {
+		clause := fmt.Sprintf("CHECK (%s)", check.Expression)
+		if check.Name != "" {
+			clause = fmt.Sprintf("CONSTRAINT %s %s", QuoteIdent(check.Name), clause)
+		}
+		lines = append(lines, "  "+clause)
+	}
+	for _, ref := range references {
+		lines = append(lines, "  "+foreignKeyClause(ref))
+	}
+
+	builder.WriteString(strings.Join(lines, ",\n"))
+	builder.WriteString("\n)")
+	if len(table.Inherits) > 0 {
+		parents := make([]string, len(table.Inherits))
+		for i, parent := range table.Inherits {
+			parents[i] = QualifiedName(parent.Name, parent.Schema)
+		}
+		builder.WriteString(" INHERITS (" + strings.Join(parents, ", ") + ")")
+	}
+	if table.PartitionKey != "" {
+		builder.WriteString(" PARTITION BY " + table.PartitionKey)
+	}
+	if table.Tablespace != "" {
+		builder.WriteString(" TABLESPACE " + QuoteIdent(table.Tablespace))
+	}
+	if table.Foreign != nil {
+		builder.WriteString(" SERVER " + QuoteIdent(table.Foreign.Server))
+		if options := foreignOptions(table.Foreign.Options); options != "" {
+			builder.WriteString(" OPTIONS (" + options + ")")
+		}
+	}
+	builder.WriteString(";\n")
+}
+
+// foreignOptions converts "key=value" options to the "key 'value'" list
+// CREATE FOREIGN TABLE takes.
+func foreignOptions(options []string) string {
+	parts := make([]string, len(options))
+	for i, option := range options {
+		key, value, _ := strings.Cut(option, "=")
+		parts[i] = fmt.Sprintf("%s %s", key, QuoteLiteral(value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func columnDefinition(column schema.Column) string {
+	typ := column.DatabaseType
+	if typ == "" {
+		typ = PostgresType(column.Type)
+	}
+	isSequence := column.AutoIncrement && column.Identity == ""
+	if isSequence {
+		typ = serialType(typ)
+	}
+
+	definition := fmt.Sprintf("%s %s", QuoteIdent(column.Name), typ)
+	if column.Collation != "" {
+		definition += " COLLATE " + column.Collation
+	}
+	if !column.Nullable || column.IsPrimaryKey {
+		definition += " NOT NULL"
+	}
+	if column.DefaultValue != nil && !isSequence {
+		definition += " DEFAULT " + *column.DefaultValue
+	}
+	if column.Identity != "" {
+		definition += " GENERATED " + column.Identity + " AS IDENTITY"
+	}
+	if clause := column.GenerationClause(); clause != "" {
+		definition += " " + clause
+	}
+	return definition
+}
+
+func serialType(typ string) string {
+	switch typ {
+	case "bigint", "int8":
+		return "bigserial"
+	case "smallint", "int2":
+		return "smallserial"
+	default:
+		return "serial"
+	}
+}
+
+func sortedIndexes(indexes []schema.Index) []schema.Index {
+	sorted := make([]schema.Index, len(indexes))
+	copy(sorted, indexes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// writeIndexes writes CREATE INDEX statements and reports whether any were
+// written. Indexes backing UNIQUE constraints are declared with their table.
+func writeIndexes(builder *strings.Builder, table schema.Table) bool {
+	written := false
+	for _, index := range sortedIndexes(table.Indexes) {
+		if index.UniqueConstraint {
+			continue
+		}
+		written = true
+
+		statement := "CREATE INDEX"
+		if index.Unique {
+			statement = "CREATE UNIQUE INDEX"
+		}
+		if index.Name != "" {
+			statement += " " + QuoteIdent(index.Name)
+		}
+
+		columns := make([]string, len(index.Columns))
+		for i, column := range index.Columns {
+			if strings.HasPrefix(column, "`") && strings.HasSuffix(column, "`") {
+				columns[i] = "(" + strings.Trim(column, "`") + ")"
+			} else {
+				columns[i] = QuoteIdent(column)
+			}
+		}
+
+		using := ""
+		if index.Type != "" {
+			using = " USING " + index.Type
+		}
+
+		include := ""
+		if len(index.Include) > 0 {
+			include = fmt.Sprintf(" INCLUDE (%s)", quoteList(index.Include))
+		}
+
+		tablespace := ""
+		if index.Tablespace != "" {
+			tablespace = " TABLESPACE " + QuoteIdent(index.Tablespace)
+		}
+
+		where := ""
+		if index.Where != "" {
+			where = " WHERE " + index.Where
+		}
+
+		builder.WriteString(fmt.Sprintf("%s ON %s%s (%s)%s%s%s;\n", statement, QualifiedName(table.Name, table.Schema), using, strings.Join(columns, ", "), include, tablespace, where))
+	}
+
+	return written
+}
+
+func writeForeignKey(builder *strings.Builder, ref schema.Reference) {
+	builder.WriteString(fmt.Sprintf("ALTER TABLE %s ADD %s;\n", QualifiedName(ref.FromTable, ref.FromSchema), foreignKeyClause(ref)))
+}
+
+func foreignKeyClause(ref schema.Reference) string {
+	clause := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteList(ref.FromColumns), QualifiedName(ref.ToTable, ref.ToSchema), quoteList(ref.ToColumns))
+
+	if match := ref.MatchClause(); match != "" {
+		clause += " " + match
+	}
+	if ref.OnDelete != "" && ref.OnDelete != "NO ACTION" {
+		clause += " ON DELETE " + ref.OnDelete
+	}
+	if ref.OnUpdate != "" && ref.OnUpdate != "NO ACTION" {
+		clause += " ON UPDATE " + ref.OnUpdate
+	}
+	if deferrable := ref.DeferrableClause(); deferrable != "" {
+		clause += " " + deferrable
+	}
+	return clause
+}
+
+// PostgresType converts a DBML type produced by introspection back to its
+// PostgreSQL spelling. Types without a known translation are returned as is.
+func PostgresType(dbmlType string) string {
+	base, suffix := dbmlType, ""
+	if i := strings.IndexAny(dbmlType, "(["); i >= 0 {
+		base, suffix = dbmlType[:i], dbmlType[i:]
+	}
+
+	switch strings.ToLower(base) {
+	case "int":
+		return "integer" + suffix
+	case "float":
+		return "real" + suffix
+	case "double":
+		return "double precision" + suffix
+	case "decimal":
+		return "numeric" + suffix
+	case "binary":
+		return "bytea" + suffix
+	default:
+		return dbmlType
+	}
+}
+
+// QualifiedName returns a quoted table name with a schema prefix if not "public".
+func QualifiedName(tableName, schemaName string) string {
+	if schemaName != "" && schemaName != "public" {
+		return QuoteIdent(schemaName) + "." + QuoteIdent(tableName)
+	}
+	return QuoteIdent(tableName)
+}
+
+// QuoteIdent quotes a PostgreSQL identifier when it is not a plain lower-case
+// name or collides with a reserved word.
+func QuoteIdent(name string) string {
+	if isPlainIdent(name) && !reservedWords[name] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteLiteral quotes a string as a PostgreSQL literal.
+func QuoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = QuoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func isPlainIdent(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// reservedWords lists PostgreSQL reserved key words that cannot be used as
+// unquoted identifiers.
+var reservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true,
+	"array": true, "as": true, "asc": true, "asymmetric": true, "both": true,
+	"case": true, "cast": true, "check": true, "collate": true, "column": true,
+	"constraint": true, "create": true, "current_catalog": true, "current_date": true,
+	"current_role": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "deferrable": true, "desc": true,
+	"distinct": true, "do": true, "else": true, "end": true, "except": true,
+	"false": true, "fetch": true, "for": true, "foreign": true, "from": true,
+	"grant": true, "group": true, "having": true, "in": true, "initially": true,
+	"intersect": true, "into": true, "lateral": true, "leading": true, "limit": true,
+	"localtime": true, "localtimestamp": true, "not": true, "null": true, "offset": true,
+	"on": true, "only": true, "or": true, "order": true, "placing": true,
+	"primary": true, "references": true, "returning": true, "select": true,
+	"session_user": true, "some": true, "symmetric": true, "table": true, "then": true,
+	"to": true, "trailing": true, "true": true, "union": true, "unique": true,
+	"user": true, "using": true, "variadic": true, "when": true, "where": true,
+	"window": true, "with": true,
+}
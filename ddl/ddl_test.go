@@ -0,0 +1,219 @@
+package ddl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerate(t *testing.T) {
+	seq := "nextval('users_id_seq'::regclass)"
+	now := "now()"
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:   "users",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "id", Type: "int", IsPrimaryKey: true, DefaultValue: &seq, AutoIncrement: true},
+					{Name: "email", Type: "varchar(255)"},
+					{Name: "score", Type: "double", Nullable: true},
+					{Name: "created_at", Type: "timestamp", DefaultValue: &now},
+				},
+				PrimaryKeys: []string{"id"},
+				Indexes: []schema.Index{
+					{Name: "idx_users_email", Columns: []string{"email"}, Unique: true},
+				},
+			},
+			{
+				Name:    "sessions",
+				Schema:  "auth",
+				Columns: []schema.Column{{Name: "user", Type: "int"}},
+				References: []schema.Reference{
+					{FromTable: "sessions", FromSchema: "auth", FromColumns: []string{"user"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "NO ACTION", Match: "FULL", Deferrable: true, InitiallyDeferred: true},
+				},
+			},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	expectedContains := []string{
+		"CREATE SCHEMA IF NOT EXISTS auth;",
+		"CREATE TABLE users (",
+		"  id serial NOT NULL,",
+		"  email varchar(255) NOT NULL,",
+		"  score double precision,",
+		"  created_at timestamp NOT NULL DEFAULT now(),",
+		"  PRIMARY KEY (id)",
+		"CREATE UNIQUE INDEX idx_users_email ON users (email);",
+		`CREATE TABLE auth.sessions (`,
+		`ALTER TABLE auth.sessions ADD FOREIGN KEY ("user") REFERENCES users (id) MATCH FULL ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;`,
+	}
+
+	for _, expected := range expectedContains {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generated DDL does not contain expected string: %s\n%s", expected, output)
+		}
+	}
+
+	if strings.Index(output, "CREATE TABLE auth.sessions") > strings.Index(output, "CREATE TABLE users") {
+		t.Errorf("Expected tables sorted by schema then name")
+	}
+}
+
+func TestGenerateUniqueConstraints(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:   "accounts",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "org_id", Type: "int"},
+					{Name: "slug", Type: "text"},
+					{Name: "email", Type: "text"},
+				},
+				Indexes: []schema.Index{
+					{Name: "accounts_org_id_slug_key", Columns: []string{"org_id", "slug"}, Unique: true, UniqueConstraint: true},
+					{Name: "idx_accounts_email", Columns: []string{"email"}, Include: []string{"slug"}, Unique: true},
+				},
+			},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	if !strings.Contains(output, "  CONSTRAINT accounts_org_id_slug_key UNIQUE (org_id, slug)\n);") {
+		t.Errorf("Expected the UNIQUE constraint in CREATE TABLE:\n%s", output)
+	}
+	if strings.Contains(output, "INDEX accounts_org_id_slug_key") {
+		t.Errorf("Expected no separate index for the UNIQUE constraint:\n%s", output)
+	}
+	if !strings.Contains(output, "CREATE UNIQUE INDEX idx_accounts_email ON accounts (email) INCLUDE (slug);") {
+		t.Errorf("Expected the standalone unique index:\n%s", output)
+	}
+}
+
+func TestGenerateCheckConstraints(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "products",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "price", Type: "decimal"}, {Name: "active", Type: "boolean"}},
+			CheckConstraints: []schema.CheckConstraint{
+				{Name: "products_price_check", Columns: []string{"price"}, Expression: "(price > (0)::numeric)"},
+				{Columns: []string{"active"}, Expression: "active"},
+			},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{
+		"  CONSTRAINT products_price_check CHECK ((price > (0)::numeric)),\n",
+		"  CHECK (active)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestGenerateEnumsFirst(t *testing.T) {
+	s := &schema.Schema{
+		Enums: []schema.Enum{
+			{Name: "mood", Schema: "public", Values: []string{"happy", "it's fine"}},
+		},
+		Tables: []schema.Table{
+			{
+				Name:    "people",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "current_mood", Type: "text", DatabaseType: "mood", Nullable: true}},
+			},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	enum := strings.Index(output, "CREATE TYPE mood AS ENUM ('happy', 'it''s fine');")
+	table := strings.Index(output, "CREATE TABLE people")
+	if enum < 0 || table < 0 || enum > table {
+		t.Errorf("Expected enum type before tables:\n%s", output)
+	}
+	if !strings.Contains(output, "  current_mood mood\n") {
+		t.Errorf("Expected column to use its database type:\n%s", output)
+	}
+}
+
+func TestGenerateForeignTable(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "remote_users",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "id", Type: "int", DatabaseType: "integer"}},
+			Foreign: &schema.ForeignTable{Server: "crm", Options: []string{"schema_name=public", "table_name=o'brien"}},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "CREATE FOREIGN TABLE remote_users (\n  id integer NOT NULL\n) SERVER crm OPTIONS (schema_name 'public', table_name 'o''brien');\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+	}
+}
+
+func TestPostgresType(t *testing.T) {
+	tests := []struct {
+		dbmlType string
+		expected string
+	}{
+		{"int", "integer"},
+		{"double", "double precision"},
+		{"float", "real"},
+		{"decimal(10,2)", "numeric(10,2)"},
+		{"binary", "bytea"},
+		{"int[]", "integer[]"},
+		{"varchar(255)", "varchar(255)"},
+		{"timestamptz", "timestamptz"},
+	}
+
+	for _, tt := range tests {
+		if result := PostgresType(tt.dbmlType); result != tt.expected {
+			t.Errorf("PostgresType(%s) = %s, want %s", tt.dbmlType, result, tt.expected)
+		}
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"users", "users"},
+		{"user", `"user"`},
+		{"CamelCase", `"CamelCase"`},
+		{"has space", `"has space"`},
+		{`a"b`, `"a""b"`},
+	}
+
+	for _, tt := range tests {
+		if result := QuoteIdent(tt.name); result != tt.expected {
+			t.Errorf("QuoteIdent(%s) = %s, want %s", tt.name, result, tt.expected)
+		}
+	}
+}
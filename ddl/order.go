@@ -0,0 +1,95 @@
+package ddl
+
+import (
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Option configures DDL generation.
+type Option func(*options)
+
+type options struct {
+	dependencyOrder bool
+}
+
+// WithDependencyOrder creates tables after the tables they reference and
+// declares foreign keys inside CREATE TABLE. Only foreign keys that are part
+// of a cycle are added afterwards with ALTER TABLE. Use it for scripts that
+// are read by people or by tools that expect a baseline in creation order.
+func WithDependencyOrder() Option {
+	return func(o *options) {
+		o.dependencyOrder = true
+	}
+}
+
+func tableID(tableName, schemaName string) string {
+	return schemaName + "." + tableName
+}
+
+// dependencyOrder sorts tables so referenced tables come first, breaking ties
+// by schema and name. It returns the foreign keys that can be declared inline
+// for each table and those that must be deferred because of cycles.
+func dependencyOrder(tables []schema.Table) ([]schema.Table, map[string][]schema.Reference, []schema.Reference) {
+	known := make(map[string]bool)
+	for _, table := range tables {
+		known[tableID(table.Name, table.Schema)] = true
+	}
+
+	// pending counts the distinct tables each table still waits on.
+	pending := make(map[string]map[string]bool)
+	for _, table := range tables {
+		id := tableID(table.Name, table.Schema)
+		pending[id] = make(map[string]bool)
+		for _, ref := range table.References {
+			target := tableID(ref.ToTable, ref.ToSchema)
+			if target != id && known[target] {
+				pending[id][target] = true
+			}
+		}
+	}
+
+	created := make(map[string]bool)
+	inline := make(map[string][]schema.Reference)
+	var deferred []schema.Reference
+	var ordered []schema.Table
+
+	for len(ordered) < len(tables) {
+		// tables is sorted, so the first ready table keeps output stable.
+		next := -1
+		for i, table := range tables {
+			id := tableID(table.Name, table.Schema)
+			if !created[id] && len(pending[id]) == 0 {
+				next = i
+				break
+			}
+		}
+		// Every remaining table is part of a cycle: create the first one and
+		// defer its unresolved foreign keys.
+		if next < 0 {
+			for i, table := range tables {
+				if !created[tableID(table.Name, table.Schema)] {
+					next = i
+					break
+				}
+			}
+		}
+
+		table := tables[next]
+		id := tableID(table.Name, table.Schema)
+		for _, ref := range table.References {
+			target := tableID(ref.ToTable, ref.ToSchema)
+			if target == id || created[target] || !known[target] {
+				inline[id] = append(inline[id], ref)
+			} else {
+				deferred = append(deferred, ref)
+			}
+		}
+
+		created[id] = true
+		ordered = append(ordered, table)
+		for _, waiting := range pending {
+			delete(waiting, id)
+		}
+	}
+
+	return ordered, inline, deferred
+}
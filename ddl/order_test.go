@@ -0,0 +1,93 @@
+package ddl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerateWithDependencyOrder(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:    "comments",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "post_id", Type: "int"}, {Name: "parent_id", Type: "int", Nullable: true}},
+				References: []schema.Reference{
+					{FromTable: "comments", FromSchema: "public", FromColumns: []string{"post_id"}, ToTable: "posts", ToSchema: "public", ToColumns: []string{"id"}, OnDelete: "CASCADE"},
+					{FromTable: "comments", FromSchema: "public", FromColumns: []string{"parent_id"}, ToTable: "comments", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+			{
+				Name:    "posts",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "id", Type: "int"}, {Name: "author_id", Type: "int"}},
+				References: []schema.Reference{
+					{FromTable: "posts", FromSchema: "public", FromColumns: []string{"author_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+			{
+				Name:    "users",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "id", Type: "int"}},
+			},
+		},
+	}
+
+	output, err := GenerateString(s, WithDependencyOrder())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	users := strings.Index(output, "CREATE TABLE users")
+	posts := strings.Index(output, "CREATE TABLE posts")
+	comments := strings.Index(output, "CREATE TABLE comments")
+	if !(users < posts && posts < comments) {
+		t.Errorf("Expected users, posts, comments in order:\n%s", output)
+	}
+
+	expected := []string{
+		"  FOREIGN KEY (author_id) REFERENCES users (id)\n);",
+		"  FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,\n  FOREIGN KEY (parent_id) REFERENCES comments (id)\n);",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain:\n%s\n\nGot:\n%s", e, output)
+		}
+	}
+	if strings.Contains(output, "ALTER TABLE") {
+		t.Errorf("Expected no deferred foreign keys:\n%s", output)
+	}
+}
+
+func TestDependencyOrderDefersCycles(t *testing.T) {
+	tables := []schema.Table{
+		{
+			Name:   "a",
+			Schema: "public",
+			References: []schema.Reference{
+				{FromTable: "a", FromSchema: "public", FromColumns: []string{"b_id"}, ToTable: "b", ToSchema: "public", ToColumns: []string{"id"}},
+			},
+		},
+		{
+			Name:   "b",
+			Schema: "public",
+			References: []schema.Reference{
+				{FromTable: "b", FromSchema: "public", FromColumns: []string{"a_id"}, ToTable: "a", ToSchema: "public", ToColumns: []string{"id"}},
+			},
+		},
+	}
+
+	ordered, inline, deferred := dependencyOrder(tables)
+
+	if len(ordered) != 2 || ordered[0].Name != "a" || ordered[1].Name != "b" {
+		t.Fatalf("Expected a then b, got %v", ordered)
+	}
+	if len(deferred) != 1 || deferred[0].FromTable != "a" {
+		t.Errorf("Expected a's foreign key to be deferred, got %v", deferred)
+	}
+	if len(inline["public.b"]) != 1 {
+		t.Errorf("Expected b's foreign key inline, got %v", inline["public.b"])
+	}
+}
@@ -0,0 +1,1061 @@
+package ddl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Parse reads PostgreSQL DDL into a Schema. It understands the statements
+// Generate writes and the ones pg_dump --schema-only writes for tables:
+// CREATE TYPE ... AS ENUM, CREATE TABLE, CREATE [UNIQUE] INDEX, ALTER TABLE
+// ... ADD constraints and SET DEFAULT, and COMMENT ON TABLE and COLUMN.
+// Sequences are only read for the column that owns them (OWNED BY). Other
+// statements, such as functions and grants, are skipped.
+//
+// Column types keep their SQL spelling in DatabaseType and are translated to
+// DBML types, so "character varying(255)" becomes "varchar(255)". Serial
+// columns become auto-incrementing integers with a nextval default.
+func Parse(data []byte) (*schema.Schema, error) {
+	tokens, err := lexSQL(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &sqlParser{src: string(data), byName: make(map[string]*schema.Table)}
+	for _, statement := range splitStatements(tokens) {
+		if err := p.statement(statement); err != nil {
+			return nil, err
+		}
+	}
+	return p.build()
+}
+
+// ParseString is a convenience wrapper that parses DDL from a string.
+func ParseString(s string) (*schema.Schema, error) {
+	return Parse([]byte(s))
+}
+
+type sqlTokenKind int
+
+const (
+	sqlIdent  sqlTokenKind = iota // bare word or number
+	sqlQuoted                     // "identifier"
+	sqlString                     // 'literal' or $$literal$$
+	sqlPunct                      // punctuation, including ::
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	// text is the token's value with quotes removed.
+	text string
+	line int
+	// start and end are byte offsets of the raw token in the source.
+	start, end int
+}
+
+func (t sqlToken) isKeyword(keyword string) bool {
+	return t.kind == sqlIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (t sqlToken) isPunct(text string) bool {
+	return t.kind == sqlPunct && t.text == text
+}
+
+var dollarQuote = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+func lexSQL(src string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	line := 1
+	i := 0
+
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			comment := src[i : i+2+end+2]
+			line += strings.Count(comment, "\n")
+			i += len(comment)
+		case c == '\'' || c == '"':
+			kind := sqlString
+			if c == '"' {
+				kind = sqlQuoted
+			}
+			var b strings.Builder
+			start, startLine := i, line
+			j := i + 1
+			for ; j < len(src); j++ {
+				if src[j] == c {
+					// A doubled quote is an escaped quote
+					if j+1 < len(src) && src[j+1] == c {
+						b.WriteByte(c)
+						j++
+						continue
+					}
+					break
+				}
+				if src[j] == '\n' {
+					line++
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated %q", startLine, string(c))
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: b.String(), line: startLine, start: start, end: j + 1})
+			i = j + 1
+		case c == '$' && dollarQuote.MatchString(src[i:]):
+			tag := dollarQuote.FindString(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated %s string", line, tag)
+			}
+			raw := src[i : i+len(tag)+end+len(tag)]
+			tokens = append(tokens, sqlToken{kind: sqlString, text: raw[len(tag) : len(raw)-len(tag)], line: line, start: i, end: i + len(raw)})
+			line += strings.Count(raw, "\n")
+			i += len(raw)
+		case isSQLIdentChar(c):
+			j := i
+			for j < len(src) && isSQLIdentChar(src[j]) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlIdent, text: src[i:j], line: line, start: i, end: j})
+			i = j
+		case strings.HasPrefix(src[i:], "::"):
+			tokens = append(tokens, sqlToken{kind: sqlPunct, text: "::", line: line, start: i, end: i + 2})
+			i += 2
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlPunct, text: string(c), line: line, start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isSQLIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// splitStatements splits tokens at semicolons, dropping empty statements.
+func splitStatements(tokens []sqlToken) [][]sqlToken {
+	var statements [][]sqlToken
+	start := 0
+	for i, t := range tokens {
+		if t.isPunct(";") {
+			if i > start {
+				statements = append(statements, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		statements = append(statements, tokens[start:])
+	}
+	return statements
+}
+
+// splitTopLevel splits tokens at commas outside parentheses.
+func splitTopLevel(tokens []sqlToken) [][]sqlToken {
+	var parts [][]sqlToken
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case t.isPunct(",") && depth == 0:
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+// pendingRef is a foreign key whose tables are resolved once every
+// statement has been read.
+type pendingRef struct {
+	ref  schema.Reference
+	line int
+}
+
+type sqlParser struct {
+	src    string
+	tables []*schema.Table
+	byName map[string]*schema.Table
+	enums  []schema.Enum
+	refs   []pendingRef
+}
+
+// cursor walks the tokens of one statement.
+type cursor struct {
+	tokens []sqlToken
+	pos    int
+}
+
+func (c *cursor) peek() sqlToken {
+	if c.pos < len(c.tokens) {
+		return c.tokens[c.pos]
+	}
+	return sqlToken{kind: sqlPunct}
+}
+
+func (c *cursor) done() bool {
+	return c.pos >= len(c.tokens)
+}
+
+func (c *cursor) next() sqlToken {
+	t := c.peek()
+	if c.pos < len(c.tokens) {
+		c.pos++
+	}
+	return t
+}
+
+// accept consumes the keywords if they come next, in order.
+func (c *cursor) accept(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if c.pos+i >= len(c.tokens) || !c.tokens[c.pos+i].isKeyword(keyword) {
+			return false
+		}
+	}
+	c.pos += len(keywords)
+	return true
+}
+
+func (c *cursor) line() int {
+	if c.pos < len(c.tokens) {
+		return c.tokens[c.pos].line
+	}
+	if len(c.tokens) > 0 {
+		return c.tokens[len(c.tokens)-1].line
+	}
+	return 0
+}
+
+// group consumes a parenthesized list and returns the tokens inside it.
+func (c *cursor) group() ([]sqlToken, error) {
+	if !c.peek().isPunct("(") {
+		return nil, fmt.Errorf("line %d: expected (", c.line())
+	}
+	start := c.pos + 1
+	depth := 0
+	for !c.done() {
+		t := c.next()
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+			if depth == 0 {
+				return c.tokens[start : c.pos-1], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("line %d: unbalanced parentheses", c.line())
+}
+
+// name reads a possibly schema-qualified name.
+func (c *cursor) name() (tableName, error) {
+	t := c.next()
+	if t.kind != sqlIdent && t.kind != sqlQuoted {
+		return tableName{}, fmt.Errorf("line %d: expected a name", t.line)
+	}
+	name := tableName{schema: "public", name: identText(t)}
+	if c.peek().isPunct(".") {
+		c.next()
+		t = c.next()
+		if t.kind != sqlIdent && t.kind != sqlQuoted {
+			return tableName{}, fmt.Errorf("line %d: expected a name", t.line)
+		}
+		name = tableName{schema: name.name, name: identText(t)}
+	}
+	return name, nil
+}
+
+type tableName struct {
+	schema, name string
+}
+
+func (n tableName) key() string {
+	return n.schema + "." + n.name
+}
+
+// identText returns an identifier's name. Unquoted identifiers fold to lower
+// case, as in PostgreSQL.
+func identText(t sqlToken) string {
+	if t.kind == sqlQuoted {
+		return t.text
+	}
+	return strings.ToLower(t.text)
+}
+
+// identList reads a comma-separated list of column names.
+func identList(tokens []sqlToken) ([]string, error) {
+	var names []string
+	for _, part := range splitTopLevel(tokens) {
+		if len(part) != 1 || (part[0].kind != sqlIdent && part[0].kind != sqlQuoted) {
+			if len(part) > 0 {
+				return nil, fmt.Errorf("line %d: expected a column name", part[0].line)
+			}
+			return nil, fmt.Errorf("expected a column name")
+		}
+		names = append(names, identText(part[0]))
+	}
+	return names, nil
+}
+
+// text returns the source text spanned by tokens with whitespace collapsed.
+func (p *sqlParser) text(tokens []sqlToken) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return strings.Join(strings.Fields(p.src[tokens[0].start:tokens[len(tokens)-1].end]), " ")
+}
+
+func (p *sqlParser) statement(tokens []sqlToken) error {
+	c := &cursor{tokens: tokens}
+	switch {
+	case c.accept("CREATE", "TYPE"):
+		return p.createType(c)
+	case c.accept("CREATE", "TABLE"), c.accept("CREATE", "UNLOGGED", "TABLE"):
+		return p.createTable(c)
+	case c.accept("CREATE", "INDEX"):
+		return p.createIndex(c, false)
+	case c.accept("CREATE", "UNIQUE", "INDEX"):
+		return p.createIndex(c, true)
+	case c.accept("ALTER", "TABLE"):
+		return p.alterTable(c)
+	case c.accept("CREATE", "SEQUENCE"), c.accept("ALTER", "SEQUENCE"):
+		return p.sequenceOwner(c)
+	case c.accept("COMMENT", "ON"):
+		return p.comment(c)
+	default:
+		return nil
+	}
+}
+
+func (p *sqlParser) createType(c *cursor) error {
+	name, err := c.name()
+	if err != nil {
+		return err
+	}
+	if !c.accept("AS", "ENUM") {
+		// Composite and range types have no representation
+		return nil
+	}
+	inner, err := c.group()
+	if err != nil {
+		return err
+	}
+
+	enum := schema.Enum{Name: name.name, Schema: name.schema}
+	for _, part := range splitTopLevel(inner) {
+		if len(part) != 1 || part[0].kind != sqlString {
+			return fmt.Errorf("line %d: expected an enum label", c.line())
+		}
+		enum.Values = append(enum.Values, part[0].text)
+	}
+	p.enums = append(p.enums, enum)
+	return nil
+}
+
+func (p *sqlParser) createTable(c *cursor) error {
+	c.accept("IF", "NOT", "EXISTS")
+	name, err := c.name()
+	if err != nil {
+		return err
+	}
+	if p.byName[name.key()] != nil {
+		return fmt.Errorf("line %d: duplicate table %s", c.line(), name.key())
+	}
+	if !c.peek().isPunct("(") {
+		// CREATE TABLE ... AS and PARTITION OF have no column list
+		return nil
+	}
+	inner, err := c.group()
+	if err != nil {
+		return err
+	}
+
+	table := &schema.Table{Name: name.name, Schema: name.schema}
+	if c.accept("INHERITS") {
+		parents, err := c.group()
+		if err != nil {
+			return err
+		}
+		for _, element := range splitTopLevel(parents) {
+			parent, err := (&cursor{tokens: element}).name()
+			if err != nil {
+				return err
+			}
+			table.Inherits = append(table.Inherits, schema.TableName{Schema: parent.schema, Name: parent.name})
+		}
+	}
+	if c.accept("PARTITION", "BY") {
+		start := c.pos
+		for !c.done() && !c.peek().isKeyword("TABLESPACE") {
+			c.next()
+		}
+		table.PartitionKey = p.text(c.tokens[start:c.pos])
+	}
+	if c.accept("TABLESPACE") {
+		table.Tablespace = identText(c.next())
+	}
+	p.tables = append(p.tables, table)
+	p.byName[name.key()] = table
+
+	for _, element := range splitTopLevel(inner) {
+		if len(element) == 0 {
+			continue
+		}
+		ec := &cursor{tokens: element}
+		if isTableConstraint(element[0]) {
+			if err := p.tableConstraint(ec, table); err != nil {
+				return err
+			}
+			continue
+		}
+		if element[0].isKeyword("LIKE") {
+			continue
+		}
+		if err := p.column(ec, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isTableConstraint(t sqlToken) bool {
+	for _, keyword := range []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE"} {
+		if t.isKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnKeywords end a column's type.
+var columnKeywords = []string{"CONSTRAINT", "NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK", "GENERATED", "COLLATE"}
+
+func isColumnKeyword(t sqlToken) bool {
+	for _, keyword := range columnKeywords {
+		if t.isKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// until returns the tokens from the cursor up to the next column keyword
+// outside parentheses, consuming them.
+func (c *cursor) until() []sqlToken {
+	start := c.pos
+	depth := 0
+	for !c.done() {
+		t := c.peek()
+		if depth == 0 && isColumnKeyword(t) {
+			break
+		}
+		if t.isPunct("(") {
+			depth++
+		}
+		if t.isPunct(")") {
+			depth--
+		}
+		c.next()
+	}
+	return c.tokens[start:c.pos]
+}
+
+func (p *sqlParser) column(c *cursor, table *schema.Table) error {
+	nameToken := c.next()
+	if nameToken.kind != sqlIdent && nameToken.kind != sqlQuoted {
+		return fmt.Errorf("line %d: expected a column name", nameToken.line)
+	}
+	typeTokens := c.until()
+	if len(typeTokens) == 0 {
+		return fmt.Errorf("line %d: missing type for column %s", nameToken.line, nameToken.text)
+	}
+
+	column := schema.Column{Name: identText(nameToken), Nullable: true, OrdinalPosition: len(table.Columns) + 1}
+	databaseType := strings.ReplaceAll(p.text(typeTokens), " (", "(")
+	if !strings.Contains(databaseType, `"`) {
+		databaseType = strings.ToLower(databaseType)
+	}
+	if base, ok := serialTypes[databaseType]; ok {
+		databaseType = base
+		defaultValue := fmt.Sprintf("nextval('%s_%s_seq'::regclass)", table.Name, column.Name)
+		column.DefaultValue = &defaultValue
+		column.Nullable = false
+		column.AutoIncrement = true
+	}
+	column.DatabaseType = databaseType
+	column.Type = DBMLType(databaseType)
+
+	constraintName := ""
+	for !c.done() {
+		switch {
+		case c.accept("CONSTRAINT"):
+			constraintName = identText(c.next())
+			continue
+		case c.accept("CHECK"):
+			if err := p.check(c, table, constraintName, column.Name); err != nil {
+				return err
+			}
+		case c.accept("NOT", "NULL"):
+			column.Nullable = false
+		case c.accept("NULL"):
+			column.Nullable = true
+		case c.accept("DEFAULT"):
+			defaultValue := p.text(c.until())
+			column.DefaultValue = &defaultValue
+		case c.accept("PRIMARY", "KEY"):
+			column.IsPrimaryKey = true
+			column.Nullable = false
+			table.PrimaryKeys = append(table.PrimaryKeys, column.Name)
+		case c.accept("UNIQUE"):
+			table.Indexes = append(table.Indexes, schema.Index{Columns: []string{column.Name}, Unique: true, UniqueConstraint: true})
+		case c.accept("REFERENCES"):
+			ref, err := p.references(c, []string{column.Name})
+			if err != nil {
+				return err
+			}
+			ref.FromTable, ref.FromSchema = table.Name, table.Schema
+			p.refs = append(p.refs, pendingRef{ref: ref, line: nameToken.line})
+		case c.accept("GENERATED"):
+			// BY DEFAULT is not a default value
+			generation := "ALWAYS"
+			if c.accept("BY", "DEFAULT") {
+				generation = "BY DEFAULT"
+			} else {
+				c.accept("ALWAYS")
+			}
+			c.accept("AS")
+			if c.accept("IDENTITY") {
+				column.Identity = generation
+				column.Nullable = false
+				column.AutoIncrement = true
+			} else if c.peek().isPunct("(") {
+				expression, err := c.group()
+				if err != nil {
+					return err
+				}
+				column.Generated = p.text(expression)
+			}
+			// Sequence options and STORED
+			c.until()
+		case c.accept("COLLATE"):
+			column.Collation = p.text(c.until())
+		default:
+			// Anything else we don't model
+			c.next()
+			c.until()
+		}
+		constraintName = ""
+	}
+
+	table.Columns = append(table.Columns, column)
+	return nil
+}
+
+var serialTypes = map[string]string{
+	"serial":      "integer",
+	"serial4":     "integer",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+}
+
+// references reads "REFERENCES table [(columns)] [MATCH type]
+// [ON DELETE action] [ON UPDATE action]" after the REFERENCES keyword. Without a column list the
+// referenced table's primary key is used when the tables are resolved.
+func (p *sqlParser) references(c *cursor, fromColumns []string) (schema.Reference, error) {
+	to, err := c.name()
+	if err != nil {
+		return schema.Reference{}, err
+	}
+	ref := schema.Reference{
+		FromColumns: fromColumns,
+		ToTable:     to.name,
+		ToSchema:    to.schema,
+		OnDelete:    "NO ACTION",
+		OnUpdate:    "NO ACTION",
+	}
+	if c.peek().isPunct("(") {
+		inner, err := c.group()
+		if err != nil {
+			return schema.Reference{}, err
+		}
+		if ref.ToColumns, err = identList(inner); err != nil {
+			return schema.Reference{}, err
+		}
+	}
+
+	for !c.done() {
+		switch {
+		case c.accept("ON", "DELETE"):
+			ref.OnDelete = referentialAction(c)
+		case c.accept("ON", "UPDATE"):
+			ref.OnUpdate = referentialAction(c)
+		case c.accept("MATCH"):
+			if match := strings.ToUpper(c.next().text); match != "SIMPLE" {
+				ref.Match = match
+			}
+		case c.accept("DEFERRABLE"):
+			ref.Deferrable = true
+		case c.accept("INITIALLY", "DEFERRED"):
+			ref.InitiallyDeferred = true
+		case c.accept("NOT", "DEFERRABLE"), c.accept("INITIALLY", "IMMEDIATE"), c.accept("NOT", "VALID"):
+		default:
+			return ref, nil
+		}
+	}
+	return ref, nil
+}
+
+func referentialAction(c *cursor) string {
+	for _, action := range [][]string{{"CASCADE"}, {"RESTRICT"}, {"NO", "ACTION"}, {"SET", "NULL"}, {"SET", "DEFAULT"}} {
+		if c.accept(action...) {
+			return strings.Join(action, " ")
+		}
+	}
+	return "NO ACTION"
+}
+
+// tableConstraint reads a table constraint from CREATE TABLE or
+// ALTER TABLE ... ADD.
+func (p *sqlParser) tableConstraint(c *cursor, table *schema.Table) error {
+	line := c.line()
+	name := ""
+	if c.accept("CONSTRAINT") {
+		name = identText(c.next())
+	}
+
+	switch {
+	case c.accept("PRIMARY", "KEY"):
+		inner, err := c.group()
+		if err != nil {
+			return err
+		}
+		columns, err := identList(inner)
+		if err != nil {
+			return err
+		}
+		table.PrimaryKeys = columns
+		for i := range table.Columns {
+			for _, pk := range columns {
+				if table.Columns[i].Name == pk {
+					table.Columns[i].IsPrimaryKey = true
+					table.Columns[i].Nullable = false
+				}
+			}
+		}
+	case c.accept("UNIQUE"):
+		c.accept("NULLS", "NOT", "DISTINCT")
+		inner, err := c.group()
+		if err != nil {
+			return err
+		}
+		index := schema.Index{Name: name, Unique: true, UniqueConstraint: true}
+		if index.Columns, err = identList(inner); err != nil {
+			return err
+		}
+		if c.accept("INCLUDE") {
+			inner, err := c.group()
+			if err != nil {
+				return err
+			}
+			if index.Include, err = identList(inner); err != nil {
+				return err
+			}
+		}
+		if c.accept("USING", "INDEX", "TABLESPACE") {
+			index.Tablespace = identText(c.next())
+		}
+		table.Indexes = append(table.Indexes, index)
+	case c.accept("FOREIGN", "KEY"):
+		inner, err := c.group()
+		if err != nil {
+			return err
+		}
+		columns, err := identList(inner)
+		if err != nil {
+			return err
+		}
+		if !c.accept("REFERENCES") {
+			return fmt.Errorf("line %d: expected REFERENCES", c.line())
+		}
+		ref, err := p.references(c, columns)
+		if err != nil {
+			return err
+		}
+		ref.FromTable, ref.FromSchema = table.Name, table.Schema
+		p.refs = append(p.refs, pendingRef{ref: ref, line: line})
+	case c.accept("CHECK"):
+		return p.check(c, table, name, "")
+	}
+	// EXCLUDE constraints are not read
+	return nil
+}
+
+// parenthesized reports whether tokens are one parenthesized group.
+func parenthesized(tokens []sqlToken) bool {
+	if len(tokens) < 2 || !tokens[0].isPunct("(") {
+		return false
+	}
+	depth := 0
+	for i, t := range tokens {
+		if t.isPunct("(") {
+			depth++
+		}
+		if t.isPunct(")") {
+			depth--
+			if depth == 0 {
+				return i == len(tokens)-1
+			}
+		}
+	}
+	return false
+}
+
+// check reads the parenthesized expression after CHECK into a constraint on
+// table. column is the column being defined for a column constraint, which
+// is not yet among the table's columns.
+func (p *sqlParser) check(c *cursor, table *schema.Table, name, column string) error {
+	inner, err := c.group()
+	if err != nil {
+		return err
+	}
+
+	// The expression refers to the columns named by its identifiers
+	referenced := make(map[string]bool)
+	for _, t := range inner {
+		if t.kind == sqlIdent || t.kind == sqlQuoted {
+			referenced[identText(t)] = true
+		}
+	}
+	// PostgreSQL prints the expression in parentheses, as pg_dump's
+	// CHECK ((age > 0)) shows
+	expression := p.text(inner)
+	if !parenthesized(inner) {
+		expression = "(" + expression + ")"
+	}
+	check := schema.CheckConstraint{Name: name, Expression: expression}
+	for _, existing := range table.Columns {
+		if referenced[existing.Name] {
+			check.Columns = append(check.Columns, existing.Name)
+		}
+	}
+	if column != "" && referenced[column] {
+		check.Columns = append(check.Columns, column)
+	}
+
+	table.CheckConstraints = append(table.CheckConstraints, check)
+	return nil
+}
+
+func (p *sqlParser) createIndex(c *cursor, unique bool) error {
+	c.accept("CONCURRENTLY")
+	c.accept("IF", "NOT", "EXISTS")
+
+	index := schema.Index{Unique: unique}
+	if !c.peek().isKeyword("ON") {
+		t := c.next()
+		index.Name = identText(t)
+	}
+	if !c.accept("ON") {
+		return fmt.Errorf("line %d: expected ON", c.line())
+	}
+	c.accept("ONLY")
+	name, err := c.name()
+	if err != nil {
+		return err
+	}
+	table := p.byName[name.key()]
+	if table == nil {
+		// Indexes on materialized views have no representation
+		return nil
+	}
+	if c.accept("USING") {
+		if method := strings.ToLower(c.next().text); method != "btree" {
+			index.Type = method
+		}
+	}
+
+	inner, err := c.group()
+	if err != nil {
+		return err
+	}
+	for _, part := range splitTopLevel(inner) {
+		index.Columns = append(index.Columns, p.indexColumn(part))
+	}
+	if c.accept("INCLUDE") {
+		inner, err := c.group()
+		if err != nil {
+			return err
+		}
+		if index.Include, err = identList(inner); err != nil {
+			return err
+		}
+	}
+	// Storage parameters and the tablespace come before the predicate
+	for !c.done() && !c.peek().isKeyword("WHERE") {
+		if c.accept("TABLESPACE") {
+			index.Tablespace = identText(c.next())
+			continue
+		}
+		c.next()
+	}
+	if c.accept("WHERE") {
+		index.Where = p.text(c.tokens[c.pos:])
+	}
+
+	table.Indexes = append(table.Indexes, index)
+	return nil
+}
+
+// indexColumn returns an index element's column name, or the expression in
+// backticks as the generator writes it.
+func (p *sqlParser) indexColumn(part []sqlToken) string {
+	if len(part) > 0 && (part[0].kind == sqlIdent || part[0].kind == sqlQuoted) {
+		rest := part[1:]
+		// Drop the ordering; the schema has no place for it
+		for len(rest) > 0 && (rest[0].isKeyword("ASC") || rest[0].isKeyword("DESC") || rest[0].isKeyword("NULLS") || rest[0].isKeyword("FIRST") || rest[0].isKeyword("LAST")) {
+			rest = rest[1:]
+		}
+		if len(rest) == 0 {
+			return identText(part[0])
+		}
+	}
+
+	expression := p.text(part)
+	if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		expression = expression[1 : len(expression)-1]
+	}
+	return "`" + expression + "`"
+}
+
+func (p *sqlParser) alterTable(c *cursor) error {
+	c.accept("IF", "EXISTS")
+	c.accept("ONLY")
+	name, err := c.name()
+	if err != nil {
+		return err
+	}
+	table := p.byName[name.key()]
+	if table == nil {
+		// pg_dump also alters sequences and views with ALTER TABLE
+		return nil
+	}
+
+	for _, action := range splitTopLevel(c.tokens[c.pos:]) {
+		ac := &cursor{tokens: action}
+		switch {
+		case ac.accept("ADD"):
+			if isTableConstraint(ac.peek()) {
+				if err := p.tableConstraint(ac, table); err != nil {
+					return err
+				}
+				continue
+			}
+			ac.accept("COLUMN")
+			ac.accept("IF", "NOT", "EXISTS")
+			if err := p.column(ac, table); err != nil {
+				return err
+			}
+		case ac.accept("ALTER"):
+			ac.accept("COLUMN")
+			columnName := identText(ac.next())
+			if ac.accept("SET", "DEFAULT") {
+				defaultValue := p.text(ac.tokens[ac.pos:])
+				for i := range table.Columns {
+					if table.Columns[i].Name == columnName {
+						table.Columns[i].DefaultValue = &defaultValue
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *sqlParser) comment(c *cursor) error {
+	switch {
+	case c.accept("TABLE"):
+		name, err := c.name()
+		if err != nil {
+			return err
+		}
+		comment, ok := commentText(c)
+		if table := p.byName[name.key()]; table != nil && ok {
+			table.Comment = comment
+		}
+	case c.accept("COLUMN"):
+		name, columnName, err := columnPath(c)
+		if err != nil {
+			return err
+		}
+		comment, ok := commentText(c)
+		if table := p.byName[name.key()]; table != nil && ok {
+			for i := range table.Columns {
+				if table.Columns[i].Name == columnName {
+					table.Columns[i].Comment = comment
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sequenceOwner reads the OWNED BY clause of CREATE and ALTER SEQUENCE, which
+// pg_dump writes for serial columns, and marks the owning column as
+// auto-incrementing.
+func (p *sqlParser) sequenceOwner(c *cursor) error {
+	for !c.done() && !c.accept("OWNED", "BY") {
+		c.next()
+	}
+	if c.done() || c.peek().isKeyword("NONE") {
+		return nil
+	}
+	name, columnName, err := columnPath(c)
+	if err != nil {
+		return err
+	}
+	if table := p.byName[name.key()]; table != nil {
+		for i := range table.Columns {
+			if table.Columns[i].Name == columnName {
+				table.Columns[i].AutoIncrement = true
+			}
+		}
+	}
+	return nil
+}
+
+// columnPath reads schema.table.column or table.column.
+func columnPath(c *cursor) (tableName, string, error) {
+	var parts []string
+	for {
+		parts = append(parts, identText(c.next()))
+		if !c.peek().isPunct(".") {
+			break
+		}
+		c.next()
+	}
+	if len(parts) < 2 {
+		return tableName{}, "", fmt.Errorf("line %d: expected table.column", c.line())
+	}
+	name := tableName{schema: "public", name: parts[len(parts)-2]}
+	if len(parts) > 2 {
+		name.schema = parts[len(parts)-3]
+	}
+	return name, parts[len(parts)-1], nil
+}
+
+func commentText(c *cursor) (string, bool) {
+	if !c.accept("IS") {
+		return "", false
+	}
+	t := c.next()
+	if t.kind != sqlString {
+		return "", t.isKeyword("NULL")
+	}
+	return t.text, true
+}
+
+// build resolves foreign keys and returns the schema.
+func (p *sqlParser) build() (*schema.Schema, error) {
+	for _, pending := range p.refs {
+		ref := pending.ref
+		from := p.byName[tableName{ref.FromSchema, ref.FromTable}.key()]
+		to := p.byName[tableName{ref.ToSchema, ref.ToTable}.key()]
+		if to == nil {
+			return nil, fmt.Errorf("line %d: unknown table %s.%s", pending.line, ref.ToSchema, ref.ToTable)
+		}
+		if ref.ToColumns == nil {
+			ref.ToColumns = to.PrimaryKeys
+		}
+		if len(ref.FromColumns) != len(ref.ToColumns) {
+			return nil, fmt.Errorf("line %d: foreign key column counts do not match", pending.line)
+		}
+		from.References = append(from.References, ref)
+	}
+
+	result := &schema.Schema{Enums: p.enums}
+	for _, t := range p.tables {
+		result.Tables = append(result.Tables, *t)
+	}
+	return result, nil
+}
+
+// DBMLType converts a PostgreSQL type to the DBML type introspection would
+// report for it, such as "varchar(255)" for "character varying(255)". It is
+// the inverse of PostgresType. Types without a known translation are
+// returned as is.
+func DBMLType(postgresType string) string {
+	base, suffix := postgresType, ""
+	if i := strings.IndexAny(postgresType, "(["); i >= 0 {
+		base, suffix = strings.TrimSpace(postgresType[:i]), postgresType[i:]
+	}
+	// Modifiers follow the length in "timestamp(3) with time zone"
+	modifier := ""
+	if i := strings.Index(suffix, ")"); i >= 0 && strings.HasPrefix(suffix, "(") {
+		suffix, modifier = suffix[:i+1], strings.TrimSpace(suffix[i+1:])
+	}
+	if modifier != "" && !strings.HasPrefix(modifier, "[") {
+		base += " " + modifier
+		modifier = ""
+	}
+	suffix = strings.ReplaceAll(suffix, " ", "") + modifier
+
+	switch strings.ToLower(base) {
+	case "integer", "int", "int4":
+		return "int" + suffix
+	case "bigint", "int8":
+		return "bigint" + suffix
+	case "smallint", "int2":
+		return "smallint" + suffix
+	case "boolean", "bool":
+		return "boolean" + suffix
+	case "character varying", "varchar":
+		return "varchar" + suffix
+	case "character", "char", "bpchar":
+		return "char" + suffix
+	case "numeric", "decimal":
+		return "decimal" + suffix
+	case "real", "float4":
+		return "float" + suffix
+	case "double precision", "float8":
+		return "double" + suffix
+	case "timestamp", "timestamp without time zone":
+		return "timestamp" + suffix
+	case "timestamptz", "timestamp with time zone":
+		return "timestamptz" + suffix
+	case "time", "time without time zone":
+		return "time" + suffix
+	case "timetz", "time with time zone":
+		return "timetz" + suffix
+	case "bytea":
+		return "binary" + suffix
+	case "bit varying", "varbit":
+		return "varbit" + suffix
+	default:
+		return postgresType
+	}
+}
@@ -0,0 +1,292 @@
+package ddl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	defaultVal := "now()"
+	sequence := "nextval('users_id_seq'::regclass)"
+	original := &schema.Schema{
+		Enums: []schema.Enum{{Name: "mood", Schema: "public", Values: []string{"happy", "it's fine"}}},
+		Tables: []schema.Table{
+			{
+				Name:   "users",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "id", Type: "bigint", IsPrimaryKey: true, DefaultValue: &sequence, AutoIncrement: true},
+					{Name: "email", Type: "varchar(255)", Collation: "case_insensitive"},
+					{Name: "created_at", Type: "timestamp", DefaultValue: &defaultVal},
+					{Name: "mood", Type: "mood", Nullable: true},
+				},
+				PrimaryKeys: []string{"id"},
+				Inherits:    []schema.TableName{{Schema: "audit", Name: "tracked"}},
+				Indexes: []schema.Index{
+					{Name: "users_email_key", Columns: []string{"email"}, Unique: true, UniqueConstraint: true, Tablespace: "fast"},
+					{Name: "users_lower_email", Columns: []string{"`lower(email)`"}, Include: []string{"id"}},
+					{Name: "users_email_hash", Columns: []string{"email"}, Type: "hash"},
+					{Name: "users_active_email", Columns: []string{"email"}, Where: "(mood <> 'sad'::mood)", Tablespace: "fast"},
+				},
+				Tablespace: "archive",
+			},
+			{
+				Name:         "posts",
+				Schema:       "blog",
+				PartitionKey: "RANGE (id)",
+				Tablespace:   "archive",
+				Columns:      []schema.Column{{Name: "user_id", Type: "bigint"}, {Name: "score", Type: "double"}, {Name: "rank", Type: "double", Generated: "(score * 2)"}, {Name: "id", Type: "bigint", Identity: "BY DEFAULT", AutoIncrement: true}},
+				References: []schema.Reference{
+					{FromTable: "posts", FromSchema: "blog", FromColumns: []string{"user_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "NO ACTION", Match: "FULL", Deferrable: true},
+				},
+			},
+		},
+	}
+
+	first, err := Generate(original)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	parsed, err := Parse(first)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v\n%s", err, first)
+	}
+
+	second, err := Generate(parsed)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("Round trip changed output:\n%s\n---\n%s", first, second)
+	}
+
+	var users, posts schema.Table
+	for _, table := range parsed.Tables {
+		switch table.Name {
+		case "users":
+			users = table
+		case "posts":
+			posts = table
+		}
+	}
+	if users.Columns[1].Type != "varchar(255)" || users.Columns[1].DatabaseType != "varchar(255)" {
+		t.Errorf("Expected email varchar(255), got %+v", users.Columns[1])
+	}
+	if users.Columns[1].Collation != "case_insensitive" || users.Columns[1].Nullable {
+		t.Errorf("Expected email to keep its collation, got %+v", users.Columns[1])
+	}
+	if posts.Schema != "blog" || posts.Columns[1].Type != "double" || posts.Columns[1].DatabaseType != "double precision" {
+		t.Errorf("Expected blog.posts with a double score, got %+v", posts)
+	}
+	if posts.Columns[2].Generated != "score * 2" {
+		t.Errorf("Expected rank to be generated from score, got %+v", posts.Columns[2])
+	}
+	if len(users.Inherits) != 1 || users.Inherits[0] != (schema.TableName{Schema: "audit", Name: "tracked"}) {
+		t.Errorf("Expected users to inherit from audit.tracked, got %+v", users.Inherits)
+	}
+	if posts.PartitionKey != "RANGE (id)" {
+		t.Errorf("Expected posts to be partitioned by range on id, got %q", posts.PartitionKey)
+	}
+	if len(posts.References) != 1 || !posts.References[0].Deferrable || posts.References[0].InitiallyDeferred || posts.References[0].Match != "FULL" {
+		t.Errorf("Expected a deferrable MATCH FULL reference from posts, got %+v", posts.References)
+	}
+	if users.Tablespace != "archive" || posts.Tablespace != "archive" {
+		t.Errorf("Expected users and posts in the archive tablespace, got %q and %q", users.Tablespace, posts.Tablespace)
+	}
+	if posts.Columns[3].Identity != "BY DEFAULT" || posts.Columns[3].Nullable {
+		t.Errorf("Expected id to be a BY DEFAULT identity column, got %+v", posts.Columns[3])
+	}
+	for _, index := range users.Indexes {
+		if index.Name == "users_email_hash" && index.Type != "hash" {
+			t.Errorf("Expected a hash index, got %+v", index)
+		}
+		if index.Name == "users_active_email" && (index.Where != "(mood <> 'sad'::mood)" || index.Tablespace != "fast") {
+			t.Errorf("Expected a partial index in the fast tablespace, got %+v", index)
+		}
+		if index.Name == "users_email_key" && index.Tablespace != "fast" {
+			t.Errorf("Expected the UNIQUE constraint's index in the fast tablespace, got %+v", index)
+		}
+	}
+	if len(parsed.Enums) != 1 || parsed.Enums[0].Values[1] != "it's fine" {
+		t.Errorf("Expected the mood enum, got %+v", parsed.Enums)
+	}
+}
+
+func TestParsePgDump(t *testing.T) {
+	input := `--
+-- PostgreSQL database dump
+--
+SET statement_timeout = 0;
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE FUNCTION public.touch() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  NEW.updated_at = now();
+  RETURN NEW;
+END;
+$$;
+
+CREATE TABLE public.accounts (
+    id integer NOT NULL,
+    "Name" character varying(100) NOT NULL,
+    balance numeric(12,2) DEFAULT 0.00,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+    row_id bigint GENERATED BY DEFAULT AS IDENTITY,
+    CONSTRAINT positive CHECK ((balance >= (0)::numeric))
+);
+
+CREATE TABLE public.transfers (
+    id serial PRIMARY KEY,
+    account_id integer REFERENCES public.accounts ON DELETE SET NULL,
+    amount numeric(12,2) NOT NULL
+);
+
+CREATE SEQUENCE public.accounts_id_seq AS integer START WITH 1;
+ALTER TABLE public.accounts_id_seq OWNER TO postgres;
+ALTER SEQUENCE public.accounts_id_seq OWNED BY public.accounts.id;
+ALTER TABLE ONLY public.accounts ALTER COLUMN id SET DEFAULT nextval('public.accounts_id_seq'::regclass);
+ALTER TABLE ONLY public.accounts ADD CONSTRAINT accounts_pkey PRIMARY KEY (id);
+CREATE UNIQUE INDEX accounts_name_idx ON public.accounts USING btree ("Name" DESC);
+COMMENT ON TABLE public.accounts IS 'Customer accounts';
+COMMENT ON COLUMN public.accounts.balance IS 'In dollars';
+`
+
+	s, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(s.Tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(s.Tables))
+	}
+
+	accounts := s.Tables[0]
+	if accounts.Comment != "Customer accounts" || accounts.Columns[2].Comment != "In dollars" {
+		t.Errorf("Expected comments, got %q and %q", accounts.Comment, accounts.Columns[2].Comment)
+	}
+	if len(accounts.PrimaryKeys) != 1 || !accounts.Columns[0].IsPrimaryKey {
+		t.Errorf("Expected primary key id, got %v", accounts.PrimaryKeys)
+	}
+	if d := accounts.Columns[0].DefaultValue; d == nil || *d != "nextval('public.accounts_id_seq'::regclass)" {
+		t.Errorf("Expected the sequence default, got %v", d)
+	}
+	if !accounts.Columns[0].AutoIncrement {
+		t.Errorf("Expected id to be auto-incrementing, got %+v", accounts.Columns[0])
+	}
+	if c := accounts.Columns[1]; c.Name != "Name" || c.Type != "varchar(100)" || c.Nullable {
+		t.Errorf("Expected Name varchar(100) not null, got %+v", c)
+	}
+	if c := accounts.Columns[2]; c.Type != "decimal(12,2)" || *c.DefaultValue != "0.00" {
+		t.Errorf("Expected balance decimal(12,2) default 0.00, got %+v", c)
+	}
+	if c := accounts.Columns[3]; c.Type != "timestamptz" {
+		t.Errorf("Expected created_at timestamptz, got %q", c.Type)
+	}
+	if c := accounts.Columns[4]; c.Type != "bigint" || c.DefaultValue != nil {
+		t.Errorf("Expected identity column without a default, got %+v", c)
+	}
+	if len(accounts.Indexes) != 1 || accounts.Indexes[0].Columns[0] != "Name" || !accounts.Indexes[0].Unique {
+		t.Errorf("Expected a unique index on Name, got %+v", accounts.Indexes)
+	}
+
+	transfers := s.Tables[1]
+	if c := transfers.Columns[0]; c.Type != "int" || !c.IsPrimaryKey || c.DefaultValue == nil {
+		t.Errorf("Expected serial id to become an int primary key with a default, got %+v", c)
+	}
+	if len(transfers.References) != 1 {
+		t.Fatalf("Expected 1 reference, got %d", len(transfers.References))
+	}
+	ref := transfers.References[0]
+	if ref.ToTable != "accounts" || ref.ToColumns[0] != "id" || ref.OnDelete != "SET NULL" {
+		t.Errorf("Expected a reference to accounts.id on delete set null, got %+v", ref)
+	}
+}
+
+func TestParseCheckConstraints(t *testing.T) {
+	input := `CREATE TABLE bookings (
+  id integer NOT NULL CONSTRAINT bookings_id_check CHECK (id > 0),
+  starts_at timestamp,
+  ends_at timestamp,
+  seats int CHECK ((seats > 0)) NOT NULL,
+  CONSTRAINT bookings_check CHECK (ends_at > starts_at)
+);
+ALTER TABLE ONLY bookings ADD CONSTRAINT bookings_seats_max CHECK ((seats < 100)) NOT VALID;
+`
+
+	s, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	bookings := s.Tables[0]
+
+	expected := []schema.CheckConstraint{
+		{Name: "bookings_id_check", Columns: []string{"id"}, Expression: "(id > 0)"},
+		{Name: "", Columns: []string{"seats"}, Expression: "(seats > 0)"},
+		{Name: "bookings_check", Columns: []string{"starts_at", "ends_at"}, Expression: "(ends_at > starts_at)"},
+		{Name: "bookings_seats_max", Columns: []string{"seats"}, Expression: "(seats < 100)"},
+	}
+	if !reflect.DeepEqual(bookings.CheckConstraints, expected) {
+		t.Errorf("Expected checks %+v, got %+v", expected, bookings.CheckConstraints)
+	}
+	if seats := bookings.Columns[3]; seats.Nullable {
+		t.Errorf("Expected NOT NULL after the check to be read, got %+v", seats)
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	reparsed, err := ParseString(output)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v\n%s", err, output)
+	}
+	if !reflect.DeepEqual(reparsed.Tables[0].CheckConstraints, expected) {
+		t.Errorf("Expected the checks to round-trip, got %+v from:\n%s", reparsed.Tables[0].CheckConstraints, output)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unterminated string", "CREATE TABLE t (a text DEFAULT 'x);"},
+		{"unbalanced parentheses", "CREATE TABLE t (a int"},
+		{"unknown referenced table", "CREATE TABLE t (a int REFERENCES missing (id));"},
+		{"column count mismatch", "CREATE TABLE u (id int PRIMARY KEY);\nCREATE TABLE t (a int, FOREIGN KEY (a) REFERENCES u (id, id));"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseString(tt.input); err == nil {
+				t.Errorf("Expected error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestDBMLType(t *testing.T) {
+	tests := map[string]string{
+		"integer":                     "int",
+		"character varying(255)":      "varchar(255)",
+		"numeric(10, 2)":              "decimal(10,2)",
+		"timestamp(3) with time zone": "timestamptz(3)",
+		"timestamp without time zone": "timestamp",
+		"text[]":                      "text[]",
+		"integer[]":                   "int[]",
+		"bytea":                       "binary",
+		"mood":                        "mood",
+		"double precision":            "double",
+		"bit varying(64)":             "varbit(64)",
+		"inet":                        "inet",
+	}
+	for input, want := range tests {
+		if got := DBMLType(input); got != want {
+			t.Errorf("DBMLType(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
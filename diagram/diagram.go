@@ -0,0 +1,218 @@
+// Package diagram draws a schema as a self-contained SVG entity-relationship
+// diagram.
+//
+// Tables are laid out on a grid, each drawn as a box listing its columns,
+// with a curve from every foreign key column to the column it references.
+// The output has no external dependencies, so it can be embedded in HTML or
+// opened directly in a browser. Mermaid writes the schema as Mermaid
+// erDiagram source instead, for Markdown tools that draw it.
+//
+// Basic usage:
+//
+//	svg := diagram.SVG(s)
+//	os.WriteFile("schema.svg", svg, 0644)
+package diagram
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Layout dimensions, in pixels. Text is set in a 12px monospace font, which
+// is about 7.2px per character.
+const (
+	charWidth    = 7.2
+	rowHeight    = 20
+	headerHeight = 26
+	padding      = 10
+	gap          = 70
+	margin       = 20
+	minBoxWidth  = 140
+)
+
+type box struct {
+	table  schema.Table
+	name   string
+	x, y   float64
+	width  float64
+	height float64
+	rows   map[string]int
+}
+
+// rowY returns the vertical center of column's row, or of the header if the
+// column is not listed.
+func (b *box) rowY(column string) float64 {
+	if i, ok := b.rows[column]; ok {
+		return b.y + headerHeight + float64(i)*rowHeight + rowHeight/2
+	}
+	return b.y + headerHeight/2
+}
+
+// SVG renders the schema as an SVG document.
+func SVG(s *schema.Schema) []byte {
+	boxes, width, height := layout(s)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="ui-monospace, SFMono-Regular, Menlo, monospace" font-size="12">`+"\n",
+		width, height, width, height)
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="#64748b"/></marker></defs>` + "\n")
+	b.WriteString(`<rect width="100%" height="100%" fill="#f8fafc"/>` + "\n")
+
+	index := make(map[string]*box)
+	for _, bx := range boxes {
+		index[bx.table.Schema+"."+bx.table.Name] = bx
+	}
+
+	// Draw relationships first so tables sit on top of them
+	for _, from := range boxes {
+		for _, ref := range from.table.References {
+			to, ok := index[ref.ToSchema+"."+ref.ToTable]
+			if !ok || len(ref.FromColumns) == 0 || len(ref.ToColumns) == 0 {
+				continue
+			}
+			writeEdge(&b, from, ref.FromColumns[0], to, ref.ToColumns[0], ref)
+		}
+	}
+
+	for _, bx := range boxes {
+		writeBox(&b, bx)
+	}
+
+	b.WriteString("</svg>\n")
+	return []byte(b.String())
+}
+
+// layout places tables on a roughly square grid, sized to fit the widest
+// table in each grid column and the tallest table in each grid row.
+func layout(s *schema.Schema) ([]*box, float64, float64) {
+	tables := append([]schema.Table(nil), s.Tables...)
+	sort.SliceStable(tables, func(i, j int) bool {
+		if tables[i].Schema != tables[j].Schema {
+			return tables[i].Schema < tables[j].Schema
+		}
+		return tables[i].Name < tables[j].Name
+	})
+
+	boxes := make([]*box, len(tables))
+	for i, table := range tables {
+		name := generator.GetQualifiedTableName(table.Name, table.Schema)
+		chars := len(name)
+		rows := make(map[string]int)
+		for j, col := range table.Columns {
+			rows[col.Name] = j
+			if n := len(col.Name) + len(col.Type) + 3; n > chars {
+				chars = n
+			}
+		}
+		boxes[i] = &box{
+			table:  table,
+			name:   name,
+			width:  math.Max(minBoxWidth, float64(chars)*charWidth+2*padding),
+			height: headerHeight + float64(len(table.Columns))*rowHeight,
+			rows:   rows,
+		}
+	}
+
+	if len(boxes) == 0 {
+		return boxes, 2 * margin, 2 * margin
+	}
+
+	columns := int(math.Ceil(math.Sqrt(float64(len(boxes)))))
+	gridRows := (len(boxes) + columns - 1) / columns
+	colWidths := make([]float64, columns)
+	rowHeights := make([]float64, gridRows)
+	for i, bx := range boxes {
+		colWidths[i%columns] = math.Max(colWidths[i%columns], bx.width)
+		rowHeights[i/columns] = math.Max(rowHeights[i/columns], bx.height)
+	}
+
+	for i, bx := range boxes {
+		bx.x = margin
+		for c := 0; c < i%columns; c++ {
+			bx.x += colWidths[c] + gap
+		}
+		bx.y = margin
+		for r := 0; r < i/columns; r++ {
+			bx.y += rowHeights[r] + gap
+		}
+	}
+
+	width, height := 2.0*margin-gap, 2.0*margin-gap
+	for _, w := range colWidths {
+		width += w + gap
+	}
+	for _, h := range rowHeights {
+		height += h + gap
+	}
+	return boxes, width, height
+}
+
+func writeBox(b *strings.Builder, bx *box) {
+	fmt.Fprintf(b, `<g class="table" id="%s">`, escape("table-"+bx.name))
+	if bx.table.Comment != "" {
+		fmt.Fprintf(b, "<title>%s</title>", escape(bx.table.Comment))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="4" fill="#ffffff" stroke="#94a3b8"/>`+"\n",
+		bx.x, bx.y, bx.width, bx.height)
+	fmt.Fprintf(b, `<path d="M%.1f,%.1f h%.1f v%d h%.1f z" fill="#1e3a8a"/>`+"\n",
+		bx.x, bx.y, bx.width, headerHeight, -bx.width)
+	fmt.Fprintf(b, `<text x="%.1f" y="%.1f" fill="#ffffff" font-weight="bold">%s</text>`+"\n",
+		bx.x+padding, bx.y+headerHeight/2+4, escape(bx.name))
+
+	for i, col := range bx.table.Columns {
+		y := bx.y + headerHeight + float64(i)*rowHeight
+		if i > 0 {
+			fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e2e8f0"/>`+"\n", bx.x, y, bx.x+bx.width, y)
+		}
+		weight := ""
+		if col.IsPrimaryKey {
+			weight = ` font-weight="bold"`
+		}
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" fill="#0f172a"%s>`, bx.x+padding, y+rowHeight/2+4, weight)
+		if col.Comment != "" {
+			fmt.Fprintf(b, "<title>%s</title>", escape(col.Comment))
+		}
+		fmt.Fprintf(b, "%s</text>\n", escape(col.Name))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" fill="#64748b" text-anchor="end">%s</text>`+"\n",
+			bx.x+bx.width-padding, y+rowHeight/2+4, escape(col.Type))
+	}
+	b.WriteString("</g>\n")
+}
+
+// writeEdge draws a curve from the referencing column to the referenced
+// column, leaving and entering each box on the side facing the other.
+func writeEdge(b *strings.Builder, from *box, fromColumn string, to *box, toColumn string, ref schema.Reference) {
+	y1, y2 := from.rowY(fromColumn), to.rowY(toColumn)
+
+	var x1, x2, c1, c2 float64
+	switch {
+	case from == to:
+		// Self-reference: loop out of the right edge and back
+		x1, x2 = from.x+from.width, from.x+from.width
+		c1, c2 = x1+gap/2, x2+gap/2
+	case from.x+from.width < to.x:
+		x1, x2 = from.x+from.width, to.x
+		c1, c2 = x1+gap/2, x2-gap/2
+	case to.x+to.width < from.x:
+		x1, x2 = from.x, to.x+to.width
+		c1, c2 = x1-gap/2, x2+gap/2
+	default:
+		// Same grid column: route around the left edge
+		x1, x2 = from.x, to.x
+		c1, c2 = x1-gap/2, x2-gap/2
+	}
+
+	title := fmt.Sprintf("%s.%s → %s.%s", from.name, strings.Join(ref.FromColumns, ", "), to.name, strings.Join(ref.ToColumns, ", "))
+	fmt.Fprintf(b, `<path class="ref" d="M%.1f,%.1f C%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="none" stroke="#64748b" stroke-width="1.5" marker-end="url(#arrow)"><title>%s</title></path>`+"\n",
+		x1, y1, c1, y1, c2, y2, x2, y2, escape(title))
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
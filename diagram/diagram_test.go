@@ -0,0 +1,113 @@
+package diagram
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func testSchema() *schema.Schema {
+	return &schema.Schema{Tables: []schema.Table{
+		{
+			Name:    "users",
+			Schema:  "public",
+			Comment: "People & <bots>",
+			Columns: []schema.Column{
+				{Name: "id", Type: "int", IsPrimaryKey: true},
+				{Name: "manager_id", Type: "int"},
+			},
+			References: []schema.Reference{{
+				FromColumns: []string{"manager_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"},
+			}},
+		},
+		{
+			Name:   "posts",
+			Schema: "blog",
+			Columns: []schema.Column{
+				{Name: "id", Type: "int", IsPrimaryKey: true},
+				{Name: "author_id", Type: "int"},
+			},
+			References: []schema.Reference{{
+				FromColumns: []string{"author_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"},
+			}},
+		},
+		{Name: "tags", Schema: "public", Columns: []schema.Column{{Name: "name", Type: "varchar(50)"}}},
+	}}
+}
+
+func TestSVGIsWellFormed(t *testing.T) {
+	output := SVG(testSchema())
+
+	decoder := xml.NewDecoder(strings.NewReader(string(output)))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG is not well-formed XML: %v\n%s", err, output)
+		}
+	}
+}
+
+func TestSVGContent(t *testing.T) {
+	output := string(SVG(testSchema()))
+
+	expected := []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`id="table-users"`,
+		`id="table-blog.posts"`,
+		`<title>People &amp; &lt;bots&gt;</title>`,
+		`>manager_id</text>`,
+		`>varchar(50)</text>`,
+		`<title>blog.posts.author_id → users.id</title>`,
+		`<title>users.manager_id → users.id</title>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected SVG to contain %q", e)
+		}
+	}
+
+	if n := strings.Count(output, `class="ref"`); n != 2 {
+		t.Errorf("Expected 2 relationships, got %d", n)
+	}
+}
+
+func TestSVGSkipsUnknownReferences(t *testing.T) {
+	s := &schema.Schema{Tables: []schema.Table{{
+		Name:       "orders",
+		Schema:     "public",
+		Columns:    []schema.Column{{Name: "customer_id", Type: "int"}},
+		References: []schema.Reference{{FromColumns: []string{"customer_id"}, ToTable: "customers", ToSchema: "crm", ToColumns: []string{"id"}}},
+	}}}
+
+	if strings.Contains(string(SVG(s)), `class="ref"`) {
+		t.Error("Expected reference to a missing table to be skipped")
+	}
+}
+
+func TestLayoutDoesNotOverlap(t *testing.T) {
+	boxes, width, height := layout(testSchema())
+
+	for i, a := range boxes {
+		if a.x+a.width > width || a.y+a.height > height {
+			t.Errorf("Box %s extends past the canvas", a.name)
+		}
+		for _, b := range boxes[i+1:] {
+			if a.x < b.x+b.width && b.x < a.x+a.width && a.y < b.y+b.height && b.y < a.y+a.height {
+				t.Errorf("Boxes %s and %s overlap", a.name, b.name)
+			}
+		}
+	}
+}
+
+func TestSVGEmptySchema(t *testing.T) {
+	output := string(SVG(&schema.Schema{}))
+	if !strings.HasPrefix(output, "<svg") || !strings.HasSuffix(output, "</svg>\n") {
+		t.Errorf("Expected an empty SVG document, got %s", output)
+	}
+}
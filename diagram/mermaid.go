@@ -0,0 +1,159 @@
+package diagram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Mermaid renders the schema as a Mermaid erDiagram, which GitHub, GitLab,
+// and most Markdown tools draw inline.
+//
+// Each column is listed with its type and PK, FK, and UK markers, and the
+// column comment if any. Each foreign key becomes a relationship labeled with
+// its columns: zero-or-more to exactly one, or zero-or-one when the foreign
+// key columns are unique, and zero-or-one on the referenced side when they
+// are nullable.
+func Mermaid(s *schema.Schema) []byte {
+	tables := append([]schema.Table(nil), s.Tables...)
+	sort.SliceStable(tables, func(i, j int) bool {
+		if tables[i].Schema != tables[j].Schema {
+			return tables[i].Schema < tables[j].Schema
+		}
+		return tables[i].Name < tables[j].Name
+	})
+
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+
+	for _, table := range tables {
+		foreignKeys := make(map[string]bool)
+		for _, ref := range table.References {
+			for _, column := range ref.FromColumns {
+				foreignKeys[column] = true
+			}
+		}
+		uniqueKeys := make(map[string]bool)
+		for _, index := range table.Indexes {
+			if index.Unique && len(index.Columns) == 1 {
+				uniqueKeys[index.Columns[0]] = true
+			}
+		}
+
+		fmt.Fprintf(&b, "    %s {\n", mermaidEntity(table.Name, table.Schema))
+		for _, column := range table.Columns {
+			var keys []string
+			if column.IsPrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if foreignKeys[column.Name] {
+				keys = append(keys, "FK")
+			}
+			if uniqueKeys[column.Name] {
+				keys = append(keys, "UK")
+			}
+
+			line := fmt.Sprintf("        %s %s", mermaidWord(column.Type), mermaidWord(column.Name))
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ",")
+			}
+			if column.Comment != "" {
+				line += " " + mermaidString(column.Comment)
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, table := range tables {
+		for _, ref := range table.References {
+			fmt.Fprintf(&b, "    %s %s %s : %s\n",
+				mermaidEntity(table.Name, table.Schema),
+				mermaidCardinality(table, ref.FromColumns),
+				mermaidEntity(ref.ToTable, ref.ToSchema),
+				mermaidString(strings.Join(ref.FromColumns, ", ")))
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// mermaidCardinality returns the relationship between a table and the table
+// its foreign key columns reference.
+func mermaidCardinality(table schema.Table, columns []string) string {
+	from := "}o"
+	if isUniqueKey(table, columns) {
+		from = "|o"
+	}
+
+	to := "||"
+	for _, column := range table.Columns {
+		for _, name := range columns {
+			if column.Name == name && column.Nullable {
+				to = "o|"
+			}
+		}
+	}
+	return from + "--" + to
+}
+
+// isUniqueKey reports whether columns are the table's primary key or the
+// columns of a unique index.
+func isUniqueKey(table schema.Table, columns []string) bool {
+	same := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	if same(table.PrimaryKeys, columns) {
+		return true
+	}
+	for _, index := range table.Indexes {
+		if index.Unique && same(index.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// mermaidEntity names a table, quoting schema-qualified names.
+func mermaidEntity(tableName, schemaName string) string {
+	if schemaName != "" && schemaName != "public" {
+		return mermaidString(schemaName + "." + tableName)
+	}
+	if mermaidWord(tableName) != tableName {
+		return mermaidString(tableName)
+	}
+	return tableName
+}
+
+// mermaidWord replaces characters Mermaid does not allow in attribute types
+// and names, so "decimal(10,2)" becomes "decimal(10_2)".
+func mermaidWord(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '_' || r == '-' || r == '(' || r == ')' || r == '[' || r == ']':
+			return r
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// mermaidString quotes s. Mermaid strings cannot contain double quotes or
+// line breaks.
+func mermaidString(s string) string {
+	s = strings.NewReplacer(`"`, "'", "\n", " ", "\r", "").Replace(s)
+	return `"` + s + `"`
+}
@@ -0,0 +1,65 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestMermaid(t *testing.T) {
+	output := string(Mermaid(testSchema()))
+
+	expected := []string{
+		"erDiagram\n",
+		"    \"blog.posts\" {\n        int id PK\n        int author_id FK\n    }\n",
+		"    users {\n        int id PK\n        int manager_id FK\n    }\n",
+		"        varchar(50) name\n",
+		"    \"blog.posts\" }o--|| users : \"author_id\"\n",
+		"    users }o--|| users : \"manager_id\"\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, output)
+		}
+	}
+
+	// Tables are sorted by schema, then name
+	if strings.Index(output, "blog.posts") > strings.Index(output, "    tags {") {
+		t.Errorf("Expected blog.posts before tags:\n%s", output)
+	}
+}
+
+func TestMermaidCardinality(t *testing.T) {
+	s := &schema.Schema{Tables: []schema.Table{
+		{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}, PrimaryKeys: []string{"id"}},
+		{
+			Name:   "profiles",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "user_id", Type: "int"},
+				{Name: "referrer_id", Type: "int", Nullable: true, Comment: `The "inviter"`},
+				{Name: "balance", Type: "decimal(10,2)"},
+			},
+			Indexes: []schema.Index{{Name: "profiles_user_id_key", Columns: []string{"user_id"}, Unique: true}},
+			References: []schema.Reference{
+				{FromColumns: []string{"user_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+				{FromColumns: []string{"referrer_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+			},
+		},
+	}}
+	output := string(Mermaid(s))
+
+	expected := []string{
+		"        int user_id FK,UK\n",
+		"        int referrer_id FK \"The 'inviter'\"\n",
+		"        decimal(10_2) balance\n",
+		"    profiles |o--|| users : \"user_id\"\n",
+		"    profiles }o--o| users : \"referrer_id\"\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package diff compares two schemas and summarizes what changed.
+//
+// The summary is structural: tables, columns, indexes, foreign keys, and
+// enums that were added, removed, or changed. It is meant for notifications
+// and reviews rather than for generating migrations.
+//
+// Basic usage:
+//
+//	changes := diff.Compare(before, after)
+//	if !changes.Empty() {
+//	    fmt.Print(changes)
+//	}
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Summary lists the differences between two schemas. Table and enum names
+// are qualified, omitting "public".
+type Summary struct {
+	AddedTables   []string      `json:"added_tables,omitempty"`
+	RemovedTables []string      `json:"removed_tables,omitempty"`
+	ChangedTables []TableChange `json:"changed_tables,omitempty"`
+	AddedEnums    []string      `json:"added_enums,omitempty"`
+	RemovedEnums  []string      `json:"removed_enums,omitempty"`
+	ChangedEnums  []string      `json:"changed_enums,omitempty"`
+}
+
+// TableChange lists the differences within a table present in both schemas.
+type TableChange struct {
+	Table          string   `json:"table"`
+	AddedColumns   []string `json:"added_columns,omitempty"`
+	RemovedColumns []string `json:"removed_columns,omitempty"`
+	// ChangedColumns describes columns whose type, nullability, or default
+	// changed, e.g. "status: varchar(20) -> text".
+	ChangedColumns []string `json:"changed_columns,omitempty"`
+	AddedIndexes   []string `json:"added_indexes,omitempty"`
+	RemovedIndexes []string `json:"removed_indexes,omitempty"`
+	// AddedReferences and RemovedReferences describe foreign keys as
+	// "columns -> table(columns)".
+	AddedReferences   []string `json:"added_references,omitempty"`
+	RemovedReferences []string `json:"removed_references,omitempty"`
+	// PrimaryKeyChanged is set when the primary key columns differ.
+	PrimaryKeyChanged bool `json:"primary_key_changed,omitempty"`
+}
+
+// Empty reports whether the summary has no differences.
+func (s *Summary) Empty() bool {
+	return len(s.AddedTables) == 0 && len(s.RemovedTables) == 0 && len(s.ChangedTables) == 0 &&
+		len(s.AddedEnums) == 0 && len(s.RemovedEnums) == 0 && len(s.ChangedEnums) == 0
+}
+
+// Compare summarizes the differences from old to new.
+func Compare(old, new *schema.Schema) *Summary {
+	summary := &Summary{}
+
+	oldTables := tablesByName(old)
+	newTables := tablesByName(new)
+	for _, name := range sortedKeys(newTables) {
+		before, ok := oldTables[name]
+		if !ok {
+			summary.AddedTables = append(summary.AddedTables, name)
+			continue
+		}
+		if change := compareTable(name, before, newTables[name]); change != nil {
+			summary.ChangedTables = append(summary.ChangedTables, *change)
+		}
+	}
+	for _, name := range sortedKeys(oldTables) {
+		if _, ok := newTables[name]; !ok {
+			summary.RemovedTables = append(summary.RemovedTables, name)
+		}
+	}
+
+	oldEnums := enumsByName(old)
+	newEnums := enumsByName(new)
+	for _, name := range sortedKeys(newEnums) {
+		before, ok := oldEnums[name]
+		if !ok {
+			summary.AddedEnums = append(summary.AddedEnums, name)
+		} else if strings.Join(before.Values, "\x00") != strings.Join(newEnums[name].Values, "\x00") {
+			summary.ChangedEnums = append(summary.ChangedEnums, name)
+		}
+	}
+	for _, name := range sortedKeys(oldEnums) {
+		if _, ok := newEnums[name]; !ok {
+			summary.RemovedEnums = append(summary.RemovedEnums, name)
+		}
+	}
+
+	return summary
+}
+
+func compareTable(name string, old, new schema.Table) *TableChange {
+	change := &TableChange{Table: name}
+
+	oldColumns := make(map[string]schema.Column)
+	for _, c := range old.Columns {
+		oldColumns[c.Name] = c
+	}
+	newColumns := make(map[string]bool)
+	for _, c := range new.Columns {
+		newColumns[c.Name] = true
+		before, ok := oldColumns[c.Name]
+		if !ok {
+			change.AddedColumns = append(change.AddedColumns, c.Name)
+			continue
+		}
+		if detail := compareColumn(before, c); detail != "" {
+			change.ChangedColumns = append(change.ChangedColumns, c.Name+": "+detail)
+		}
+	}
+	for _, c := range old.Columns {
+		if !newColumns[c.Name] {
+			change.RemovedColumns = append(change.RemovedColumns, c.Name)
+		}
+	}
+
+	change.AddedIndexes, change.RemovedIndexes = compareSets(indexNames(old), indexNames(new))
+	change.AddedReferences, change.RemovedReferences = compareSets(references(old), references(new))
+	change.PrimaryKeyChanged = strings.Join(old.PrimaryKeys, ",") != strings.Join(new.PrimaryKeys, ",")
+
+	if len(change.AddedColumns) == 0 && len(change.RemovedColumns) == 0 && len(change.ChangedColumns) == 0 &&
+		len(change.AddedIndexes) == 0 && len(change.RemovedIndexes) == 0 &&
+		len(change.AddedReferences) == 0 && len(change.RemovedReferences) == 0 && !change.PrimaryKeyChanged {
+		return nil
+	}
+	return change
+}
+
+// compareColumn describes how a column changed, or returns "" if it did not.
+func compareColumn(old, new schema.Column) string {
+	var details []string
+	if old.Type != new.Type {
+		details = append(details, old.Type+" -> "+new.Type)
+	}
+	if old.Nullable != new.Nullable {
+		if new.Nullable {
+			details = append(details, "now nullable")
+		} else {
+			details = append(details, "now not null")
+		}
+	}
+	if defaultValue(old) != defaultValue(new) {
+		details = append(details, fmt.Sprintf("default %s -> %s", defaultValue(old), defaultValue(new)))
+	}
+	return strings.Join(details, ", ")
+}
+
+func defaultValue(c schema.Column) string {
+	if c.DefaultValue == nil {
+		return "none"
+	}
+	return *c.DefaultValue
+}
+
+func indexNames(t schema.Table) []string {
+	names := make([]string, len(t.Indexes))
+	for i, idx := range t.Indexes {
+		names[i] = idx.Name
+	}
+	return names
+}
+
+func references(t schema.Table) []string {
+	refs := make([]string, len(t.References))
+	for i, ref := range t.References {
+		refs[i] = fmt.Sprintf("%s -> %s(%s)",
+			strings.Join(ref.FromColumns, ", "),
+			generator.GetQualifiedTableName(ref.ToTable, ref.ToSchema),
+			strings.Join(ref.ToColumns, ", "))
+	}
+	return refs
+}
+
+// compareSets returns the sorted elements only in new and only in old.
+func compareSets(old, new []string) (added, removed []string) {
+	oldSet := make(map[string]bool)
+	for _, v := range old {
+		oldSet[v] = true
+	}
+	newSet := make(map[string]bool)
+	for _, v := range new {
+		newSet[v] = true
+		if !oldSet[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range old {
+		if !newSet[v] {
+			removed = append(removed, v)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func tablesByName(s *schema.Schema) map[string]schema.Table {
+	tables := make(map[string]schema.Table)
+	for _, t := range s.Tables {
+		tables[generator.GetQualifiedTableName(t.Name, t.Schema)] = t
+	}
+	return tables
+}
+
+func enumsByName(s *schema.Schema) map[string]schema.Enum {
+	enums := make(map[string]schema.Enum)
+	for _, e := range s.Enums {
+		enums[generator.GetQualifiedTableName(e.Name, e.Schema)] = e
+	}
+	return enums
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
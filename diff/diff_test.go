@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func before() *schema.Schema {
+	return &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:        "users",
+				Schema:      "public",
+				PrimaryKeys: []string{"id"},
+				Columns: []schema.Column{
+					{Name: "id", Type: "int"},
+					{Name: "status", Type: "varchar(20)", Nullable: true},
+					{Name: "legacy", Type: "text"},
+				},
+				Indexes: []schema.Index{{Name: "users_status_idx"}},
+			},
+			{Name: "audit", Schema: "ops", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		},
+		Enums: []schema.Enum{
+			{Name: "mood", Schema: "public", Values: []string{"happy", "sad"}},
+			{Name: "color", Schema: "public", Values: []string{"red"}},
+		},
+	}
+}
+
+func after() *schema.Schema {
+	return &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:        "users",
+				Schema:      "public",
+				PrimaryKeys: []string{"id"},
+				Columns: []schema.Column{
+					{Name: "id", Type: "int"},
+					{Name: "status", Type: "text", DefaultValue: strPtr("'active'")},
+					{Name: "team_id", Type: "int"},
+				},
+				References: []schema.Reference{{
+					FromColumns: []string{"team_id"}, ToTable: "teams", ToSchema: "public", ToColumns: []string{"id"},
+				}},
+			},
+			{Name: "teams", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		},
+		Enums: []schema.Enum{
+			{Name: "mood", Schema: "public", Values: []string{"happy", "sad", "meh"}},
+			{Name: "size", Schema: "public", Values: []string{"s"}},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	summary := Compare(before(), after())
+
+	expected := &Summary{
+		AddedTables:   []string{"teams"},
+		RemovedTables: []string{"ops.audit"},
+		ChangedTables: []TableChange{{
+			Table:           "users",
+			AddedColumns:    []string{"team_id"},
+			RemovedColumns:  []string{"legacy"},
+			ChangedColumns:  []string{"status: varchar(20) -> text, now not null, default none -> 'active'"},
+			RemovedIndexes:  []string{"users_status_idx"},
+			AddedReferences: []string{"team_id -> teams(id)"},
+		}},
+		AddedEnums:   []string{"size"},
+		RemovedEnums: []string{"color"},
+		ChangedEnums: []string{"mood"},
+	}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("Compare() =\n%+v\nwant\n%+v", summary, expected)
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	summary := Compare(before(), before())
+	if !summary.Empty() {
+		t.Errorf("Expected no changes, got %+v", summary)
+	}
+	if summary.String() != "" {
+		t.Errorf("Expected empty string, got %q", summary.String())
+	}
+}
+
+func TestCompareIgnoresOrder(t *testing.T) {
+	reordered := before()
+	reordered.Tables[0], reordered.Tables[1] = reordered.Tables[1], reordered.Tables[0]
+	reordered.Tables[1].Indexes = append(reordered.Tables[1].Indexes, schema.Index{Name: "a"}, schema.Index{Name: "b"})
+	original := before()
+	original.Tables[0].Indexes = append(original.Tables[0].Indexes, schema.Index{Name: "b"}, schema.Index{Name: "a"})
+
+	if summary := Compare(original, reordered); !summary.Empty() {
+		t.Errorf("Expected no changes for reordered schema, got %+v", summary)
+	}
+}
+
+func TestSummaryString(t *testing.T) {
+	result := Compare(before(), after()).String()
+
+	expected := []string{
+		"+ table teams\n",
+		"- table ops.audit\n",
+		"~ users: + column team_id\n",
+		"~ users: - column legacy\n",
+		"~ users: ~ column status: varchar(20) -> text",
+		"~ users: - index users_status_idx\n",
+		"~ users: + ref team_id -> teams(id)\n",
+		"+ enum size\n",
+		"~ enum mood: values changed\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(result, e) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", e, result)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String renders the summary as one line per change, e.g.
+// "+ table orders" or "~ users: + column last_login".
+func (s *Summary) String() string {
+	var b strings.Builder
+	for _, name := range s.AddedTables {
+		fmt.Fprintf(&b, "+ table %s\n", name)
+	}
+	for _, name := range s.RemovedTables {
+		fmt.Fprintf(&b, "- table %s\n", name)
+	}
+	for _, change := range s.ChangedTables {
+		for _, line := range change.lines() {
+			fmt.Fprintf(&b, "~ %s: %s\n", change.Table, line)
+		}
+	}
+	for _, name := range s.AddedEnums {
+		fmt.Fprintf(&b, "+ enum %s\n", name)
+	}
+	for _, name := range s.RemovedEnums {
+		fmt.Fprintf(&b, "- enum %s\n", name)
+	}
+	for _, name := range s.ChangedEnums {
+		fmt.Fprintf(&b, "~ enum %s: values changed\n", name)
+	}
+	return b.String()
+}
+
+func (c TableChange) lines() []string {
+	var lines []string
+	for _, name := range c.AddedColumns {
+		lines = append(lines, "+ column "+name)
+	}
+	for _, name := range c.RemovedColumns {
+		lines = append(lines, "- column "+name)
+	}
+	for _, detail := range c.ChangedColumns {
+		lines = append(lines, "~ column "+detail)
+	}
+	if c.PrimaryKeyChanged {
+		lines = append(lines, "~ primary key")
+	}
+	for _, name := range c.AddedIndexes {
+		lines = append(lines, "+ index "+name)
+	}
+	for _, name := range c.RemovedIndexes {
+		lines = append(lines, "- index "+name)
+	}
+	for _, ref := range c.AddedReferences {
+		lines = append(lines, "+ ref "+ref)
+	}
+	for _, ref := range c.RemovedReferences {
+		lines = append(lines, "- ref "+ref)
+	}
+	return lines
+}
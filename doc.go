@@ -56,6 +56,29 @@
 //   - github.com/lucasefe/dbml/schema - Data structures for representing database schemas
 //   - github.com/lucasefe/dbml/introspect - Database introspection with functional options
 //   - github.com/lucasefe/dbml/generator - DBML generation with []byte output
+//   - github.com/lucasefe/dbml/parser - DBML parsing into schema structures
+//   - github.com/lucasefe/dbml/render - Registry of named output formats
+//   - github.com/lucasefe/dbml/pipeline - Schema and output middleware, including external plugins
+//   - github.com/lucasefe/dbml/ddl - PostgreSQL DDL generation
+//   - github.com/lucasefe/dbml/atlas - Atlas HCL generation
+//   - github.com/lucasefe/dbml/ent - ent schema generation
+//   - github.com/lucasefe/dbml/liquibase - Liquibase changelog generation
+//   - github.com/lucasefe/dbml/lint - Schema linting with configurable rules
+//   - github.com/lucasefe/dbml/graph - Relationship graph analysis
+//   - github.com/lucasefe/dbml/report - Schema summary reports
+//   - github.com/lucasefe/dbml/diff - Structural comparison of two schemas
+//   - github.com/lucasefe/dbml/notify - Webhook and Slack notifications of schema changes
+//   - github.com/lucasefe/dbml/pii - Detection of likely PII columns
+//   - github.com/lucasefe/dbml/sample - Sample data capture
+//   - github.com/lucasefe/dbml/openapi - OpenAPI component schema generation
+//   - github.com/lucasefe/dbml/convert - Conversion between JSON, DBML, and SQL
+//   - github.com/lucasefe/dbml/diagram - SVG entity-relationship diagrams
+//   - github.com/lucasefe/dbml/preview - Local live-preview server with diagrams
+//   - github.com/lucasefe/dbml/supabase - Connection strings for Supabase projects
+//   - github.com/lucasefe/dbml/objectstore - Uploads to S3, GCS, and Azure Blob Storage
+//   - github.com/lucasefe/dbml/sink - Output destinations: stdout, files, directories, HTTP, and object storage
+//   - github.com/lucasefe/dbml/server - HTTP server exposing generated schemas
+//   - github.com/lucasefe/dbml/mcp - Model Context Protocol server for AI assistants
 //
 // # Custom Type Mapping
 //
@@ -0,0 +1,433 @@
+// Package ent renders schema definitions as ent (entgo.io) schema files.
+//
+// The output is a starting point for teams adopting ent on an existing
+// database: one file per table declaring fields, edges derived from foreign
+// keys, and indexes. Review the generated code before committing it, since
+// some PostgreSQL features (such as composite primary keys) have no ent
+// equivalent.
+//
+// Basic usage:
+//
+//	files, err := ent.Generate(s)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for name, content := range files {
+//	    os.WriteFile(filepath.Join("ent/schema", name), content, 0644)
+//	}
+package ent
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Import paths used by generated files.
+const (
+	importEnt     = "entgo.io/ent"
+	importDialect = "entgo.io/ent/dialect"
+	importEntSQL  = "entgo.io/ent/dialect/entsql"
+	importSchema  = "entgo.io/ent/schema"
+	importEdge    = "entgo.io/ent/schema/edge"
+	importField   = "entgo.io/ent/schema/field"
+	importIndex   = "entgo.io/ent/schema/index"
+	importUUID    = "github.com/google/uuid"
+	importTime    = "time"
+)
+
+// Generate converts a Schema into ent schema files keyed by file name.
+// Every file belongs to package "schema".
+func Generate(s *schema.Schema) (map[string][]byte, error) {
+	g := newGenerator(s)
+
+	files := make(map[string][]byte)
+	for _, table := range g.tables {
+		source, err := g.file(table)
+		if err != nil {
+			return nil, err
+		}
+		files[fileName(g.entity(table.Name, table.Schema))] = source
+	}
+	return files, nil
+}
+
+type tableKey struct {
+	schema string
+	name   string
+}
+
+// edgeDef describes one edge of an entity.
+type edgeDef struct {
+	name string
+	code string
+}
+
+type generator struct {
+	tables   []schema.Table
+	entities map[tableKey]string
+	enums    map[string]schema.Enum
+	edges    map[tableKey][]edgeDef
+}
+
+func newGenerator(s *schema.Schema) *generator {
+	g := &generator{
+		entities: make(map[tableKey]string),
+		enums:    make(map[string]schema.Enum),
+		edges:    make(map[tableKey][]edgeDef),
+	}
+
+	g.tables = make([]schema.Table, len(s.Tables))
+	copy(g.tables, s.Tables)
+	sort.Slice(g.tables, func(i, j int) bool {
+		if g.tables[i].Schema != g.tables[j].Schema {
+			return g.tables[i].Schema < g.tables[j].Schema
+		}
+		return g.tables[i].Name < g.tables[j].Name
+	})
+
+	for _, table := range g.tables {
+		g.entities[tableKey{table.Schema, table.Name}] = entityName(table.Name, table.Schema)
+	}
+
+	for _, enum := range s.Enums {
+		g.enums[enum.Schema+"."+enum.Name] = enum
+		if enum.Schema == "public" || enum.Schema == "" {
+			g.enums[enum.Name] = enum
+		}
+	}
+
+	g.collectEdges()
+	return g
+}
+
+func (g *generator) entity(tableName, schemaName string) string {
+	return g.entities[tableKey{schemaName, tableName}]
+}
+
+// collectEdges turns every single-column foreign key into an edge.From on the
+// referencing entity and a matching edge.To on the referenced entity.
+func (g *generator) collectEdges() {
+	incoming := make(map[string]int)
+	for _, table := range g.tables {
+		for _, ref := range table.References {
+			incoming[ref.ToSchema+"."+ref.ToTable+"<"+table.Schema+"."+table.Name]++
+		}
+	}
+
+	for _, table := range g.tables {
+		from := tableKey{table.Schema, table.Name}
+		for _, ref := range table.References {
+			to := tableKey{ref.ToSchema, ref.ToTable}
+			target, ok := g.entities[to]
+			if !ok || len(ref.FromColumns) != 1 {
+				continue
+			}
+
+			column := ref.FromColumns[0]
+			name := edgeName(column)
+			inverse := table.Name
+			if incoming[ref.ToSchema+"."+ref.ToTable+"<"+table.Schema+"."+table.Name] > 1 {
+				inverse = name + "_" + table.Name
+			}
+
+			modifiers := fmt.Sprintf(".Field(%q).Unique()", column)
+			if c, ok := findColumn(table, column); ok && !c.Nullable {
+				modifiers += ".Required()"
+			}
+
+			if from == to {
+				// ent declares both sides of a self-reference in one edge.
+				g.edges[from] = append(g.edges[from], edgeDef{
+					name: inverse,
+					code: fmt.Sprintf("edge.To(%q, %s.Type).From(%q)%s", inverse, target, name, modifiers),
+				})
+				continue
+			}
+
+			g.edges[from] = append(g.edges[from], edgeDef{
+				name: name,
+				code: fmt.Sprintf("edge.From(%q, %s.Type).Ref(%q)%s", name, target, inverse, modifiers),
+			})
+			g.edges[to] = append(g.edges[to], edgeDef{
+				name: inverse,
+				code: fmt.Sprintf("edge.To(%q, %s.Type)", inverse, g.entities[from]),
+			})
+		}
+	}
+
+	for key := range g.edges {
+		edges := g.edges[key]
+		sort.Slice(edges, func(i, j int) bool { return edges[i].name < edges[j].name })
+	}
+}
+
+func findColumn(table schema.Table, name string) (schema.Column, bool) {
+	for _, column := range table.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return schema.Column{}, false
+}
+
+func (g *generator) file(table schema.Table) ([]byte, error) {
+	entity := g.entity(table.Name, table.Schema)
+	imports := map[string]bool{importEnt: true}
+
+	var body strings.Builder
+
+	fmt.Fprintf(&body, "// %s holds the schema definition for the %s entity.\n", entity, entity)
+	fmt.Fprintf(&body, "type %s struct {\n\tent.Schema\n}\n\n", entity)
+
+	// Annotations
+	imports[importSchema] = true
+	imports[importEntSQL] = true
+	fmt.Fprintf(&body, "// Annotations of the %s.\n", entity)
+	fmt.Fprintf(&body, "func (%s) Annotations() []schema.Annotation {\n\treturn []schema.Annotation{\n", entity)
+	if table.Schema != "" && table.Schema != "public" {
+		fmt.Fprintf(&body, "\t\tentsql.Annotation{Table: %q, Schema: %q},\n", table.Name, table.Schema)
+	} else {
+		fmt.Fprintf(&body, "\t\tentsql.Annotation{Table: %q},\n", table.Name)
+	}
+	body.WriteString("\t}\n}\n\n")
+
+	// Fields
+	uniqueColumns := make(map[string]bool)
+	for _, index := range table.Indexes {
+		if index.Unique && len(index.Columns) == 1 {
+			uniqueColumns[index.Columns[0]] = true
+		}
+	}
+
+	fmt.Fprintf(&body, "// Fields of the %s.\n", entity)
+	fmt.Fprintf(&body, "func (%s) Fields() []ent.Field {\n", entity)
+	if len(table.PrimaryKeys) > 1 {
+		fmt.Fprintf(&body, "\t// NOTE: ent requires a single-column primary key, but %s has a composite key (%s).\n",
+			table.Name, strings.Join(table.PrimaryKeys, ", "))
+	}
+	body.WriteString("\treturn []ent.Field{\n")
+	for _, column := range table.Columns {
+		isID := len(table.PrimaryKeys) == 1 && table.PrimaryKeys[0] == column.Name
+		fmt.Fprintf(&body, "\t\t%s,\n", g.field(column, isID, uniqueColumns[column.Name], imports))
+	}
+	body.WriteString("\t}\n}\n")
+
+	// Edges
+	if edges := g.edges[tableKey{table.Schema, table.Name}]; len(edges) > 0 {
+		imports[importEdge] = true
+		fmt.Fprintf(&body, "\n// Edges of the %s.\n", entity)
+		fmt.Fprintf(&body, "func (%s) Edges() []ent.Edge {\n\treturn []ent.Edge{\n", entity)
+		for _, edge := range edges {
+			fmt.Fprintf(&body, "\t\t%s,\n", edge.code)
+		}
+		body.WriteString("\t}\n}\n")
+	}
+
+	// Indexes. ent indexes list fields, so indexes on expressions, such as
+	// lower(email), are left out
+	var indexes []string
+	for _, index := range sortedIndexes(table.Indexes) {
+		if len(index.Columns) < 2 || !allColumns(table, index.Columns) {
+			continue
+		}
+		code := "index.Fields(" + quoteAll(index.Columns) + ")"
+		if index.Unique {
+			code += ".Unique()"
+		}
+		if index.Name != "" {
+			code += fmt.Sprintf(".StorageKey(%q)", index.Name)
+		}
+		indexes = append(indexes, code)
+	}
+	if len(indexes) > 0 {
+		imports[importIndex] = true
+		fmt.Fprintf(&body, "\n// Indexes of the %s.\n", entity)
+		fmt.Fprintf(&body, "func (%s) Indexes() []ent.Index {\n\treturn []ent.Index{\n", entity)
+		for _, code := range indexes {
+			fmt.Fprintf(&body, "\t\t%s,\n", code)
+		}
+		body.WriteString("\t}\n}\n")
+	}
+
+	var source strings.Builder
+	source.WriteString("// Generated by dbml from a live database. Review before use.\n\n")
+	source.WriteString("package schema\n\n")
+	source.WriteString(importBlock(imports))
+	source.WriteString("\n")
+	source.WriteString(body.String())
+
+	formatted, err := format.Source([]byte(source.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format ent schema for table %s: %w", table.Name, err)
+	}
+	return formatted, nil
+}
+
+// allColumns reports whether every name is one of the table's columns.
+func allColumns(table schema.Table, names []string) bool {
+	for _, name := range names {
+		found := false
+		for _, column := range table.Columns {
+			if column.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedIndexes(indexes []schema.Index) []schema.Index {
+	sorted := make([]schema.Index, len(indexes))
+	copy(sorted, indexes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+func importBlock(imports map[string]bool) string {
+	var std, external []string
+	for path := range imports {
+		if strings.Contains(path, ".") {
+			external = append(external, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(external)
+
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&b, "\t%q\n", path)
+	}
+	if len(std) > 0 && len(external) > 0 {
+		b.WriteString("\n")
+	}
+	for _, path := range external {
+		fmt.Fprintf(&b, "\t%q\n", path)
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+var lengthPattern = regexp.MustCompile(`^\w+\((\d+)\)$`)
+
+// field renders the ent field declaration for a column.
+func (g *generator) field(column schema.Column, isID, unique bool, imports map[string]bool) string {
+	imports[importField] = true
+
+	name := column.Name
+	var storageKey string
+	if isID && name != "id" {
+		storageKey = name
+		name = "id"
+	}
+
+	builder, isTime := g.fieldBuilder(column, name, imports)
+	code := builder
+
+	if storageKey != "" {
+		code += fmt.Sprintf(".StorageKey(%q)", storageKey)
+	}
+	if unique && !isID {
+		code += ".Unique()"
+	}
+	if column.Nullable && !isID {
+		code += ".Optional().Nillable()"
+	}
+	if def := defaultValue(column, isTime, imports); def != "" && !isID {
+		code += def
+	}
+	return code
+}
+
+func (g *generator) fieldBuilder(column schema.Column, name string, imports map[string]bool) (string, bool) {
+	if enum, ok := g.enums[column.DatabaseType]; ok {
+		return fmt.Sprintf("field.Enum(%q).Values(%s)", name, quoteAll(enum.Values)), false
+	}
+
+	typ := strings.ToLower(column.Type)
+	switch {
+	case typ == "int":
+		return fmt.Sprintf("field.Int(%q)", name), false
+	case typ == "bigint":
+		return fmt.Sprintf("field.Int64(%q)", name), false
+	case typ == "smallint":
+		return fmt.Sprintf("field.Int16(%q)", name), false
+	case typ == "boolean":
+		return fmt.Sprintf("field.Bool(%q)", name), false
+	case typ == "float":
+		return fmt.Sprintf("field.Float32(%q)", name), false
+	case typ == "double":
+		return fmt.Sprintf("field.Float(%q)", name), false
+	case strings.HasPrefix(typ, "varchar"), strings.HasPrefix(typ, "char"):
+		code := fmt.Sprintf("field.String(%q)", name)
+		if m := lengthPattern.FindStringSubmatch(typ); m != nil {
+			code += fmt.Sprintf(".MaxLen(%s)", m[1])
+		}
+		return code, false
+	case typ == "text":
+		return fmt.Sprintf("field.Text(%q)", name), false
+	case typ == "timestamp", typ == "timestamptz", typ == "date":
+		return fmt.Sprintf("field.Time(%q)", name), true
+	case typ == "uuid":
+		imports[importUUID] = true
+		return fmt.Sprintf("field.UUID(%q, uuid.UUID{})", name), false
+	case typ == "json", typ == "jsonb":
+		return fmt.Sprintf("field.JSON(%q, map[string]interface{}{})", name), false
+	case typ == "binary":
+		return fmt.Sprintf("field.Bytes(%q)", name), false
+	}
+
+	// Keep the database type for anything without a natural Go type.
+	imports[importDialect] = true
+	schemaType := column.DatabaseType
+	if schemaType == "" {
+		schemaType = column.Type
+	}
+	return fmt.Sprintf("field.String(%q).SchemaType(map[string]string{dialect.Postgres: %q})", name, schemaType), false
+}
+
+var numberPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// defaultValue translates simple column defaults into ent Default calls.
+// Sequences and complex expressions are left to the database.
+func defaultValue(column schema.Column, isTime bool, imports map[string]bool) string {
+	if column.DefaultValue == nil {
+		return ""
+	}
+
+	value := strings.TrimSpace(*column.DefaultValue)
+	lower := strings.ToLower(value)
+	switch {
+	case isTime && (lower == "now()" || lower == "current_timestamp" || strings.HasPrefix(lower, "now()::")):
+		imports[importTime] = true
+		return ".Default(time.Now)"
+	case lower == "true" || lower == "false":
+		return fmt.Sprintf(".Default(%s)", lower)
+	case numberPattern.MatchString(value) && strings.HasPrefix(strings.ToLower(column.Type), "int"):
+		return fmt.Sprintf(".Default(%s)", value)
+	case lower == "gen_random_uuid()" || lower == "uuid_generate_v4()":
+		imports[importUUID] = true
+		return ".Default(uuid.New)"
+	}
+	return ""
+}
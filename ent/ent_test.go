@@ -0,0 +1,122 @@
+package ent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerate(t *testing.T) {
+	seq := "nextval('users_id_seq'::regclass)"
+	now := "now()"
+	s := &schema.Schema{
+		Enums: []schema.Enum{{Name: "post_status", Schema: "public", Values: []string{"draft", "published"}}},
+		Tables: []schema.Table{
+			{
+				Name:   "users",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "id", Type: "int", IsPrimaryKey: true, DefaultValue: &seq},
+					{Name: "email", Type: "varchar(255)"},
+					{Name: "created_at", Type: "timestamp", DefaultValue: &now},
+				},
+				PrimaryKeys: []string{"id"},
+				Indexes:     []schema.Index{{Name: "idx_users_email", Columns: []string{"email"}, Unique: true}},
+			},
+			{
+				Name:   "posts",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "id", Type: "bigint", IsPrimaryKey: true},
+					{Name: "author_id", Type: "int"},
+					{Name: "status", Type: "text", DatabaseType: "post_status"},
+					{Name: "body", Type: "text", Nullable: true},
+				},
+				PrimaryKeys: []string{"id"},
+				Indexes: []schema.Index{
+					{Name: "idx_posts_author_status", Columns: []string{"author_id", "status"}},
+					{Name: "idx_posts_author_lower_body", Columns: []string{"author_id", "`lower(body)`"}},
+				},
+				References: []schema.Reference{
+					{FromTable: "posts", FromSchema: "public", FromColumns: []string{"author_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+		},
+	}
+
+	files, err := Generate(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	user, ok := files["user.go"]
+	if !ok {
+		t.Fatalf("Expected user.go, got %v", keys(files))
+	}
+	post, ok := files["post.go"]
+	if !ok {
+		t.Fatalf("Expected post.go, got %v", keys(files))
+	}
+
+	userExpected := []string{
+		"type User struct {",
+		`entsql.Annotation{Table: "users"}`,
+		`field.Int("id"),`,
+		`field.String("email").MaxLen(255).Unique(),`,
+		`field.Time("created_at").Default(time.Now),`,
+		`edge.To("posts", Post.Type),`,
+	}
+	for _, expected := range userExpected {
+		if !strings.Contains(string(user), expected) {
+			t.Errorf("user.go missing %s:\n%s", expected, user)
+		}
+	}
+
+	postExpected := []string{
+		`field.Int64("id"),`,
+		`field.Enum("status").Values("draft", "published"),`,
+		`field.Text("body").Optional().Nillable(),`,
+		`edge.From("author", User.Type).Ref("posts").Field("author_id").Unique().Required(),`,
+		`index.Fields("author_id", "status").StorageKey("idx_posts_author_status"),`,
+	}
+	for _, expected := range postExpected {
+		if !strings.Contains(string(post), expected) {
+			t.Errorf("post.go missing %s:\n%s", expected, post)
+		}
+	}
+	if strings.Contains(string(post), "lower(body)") {
+		t.Errorf("Expected the expression index to be left out:\n%s", post)
+	}
+}
+
+func TestNaming(t *testing.T) {
+	tests := []struct {
+		table, schema, entity, file string
+	}{
+		{"users", "public", "User", "user.go"},
+		{"categories", "public", "Category", "category.go"},
+		{"addresses", "public", "Address", "address.go"},
+		{"order_items", "public", "OrderItem", "order_item.go"},
+		{"status", "public", "Status", "status.go"},
+		{"sessions", "auth", "AuthSession", "auth_session.go"},
+	}
+
+	for _, tt := range tests {
+		entity := entityName(tt.table, tt.schema)
+		if entity != tt.entity {
+			t.Errorf("entityName(%s, %s) = %s, want %s", tt.table, tt.schema, entity, tt.entity)
+		}
+		if file := fileName(entity); file != tt.file {
+			t.Errorf("fileName(%s) = %s, want %s", entity, file, tt.file)
+		}
+	}
+}
+
+func keys(files map[string][]byte) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,77 @@
+package ent
+
+import (
+	"strings"
+	"unicode"
+)
+
+// entityName converts a table name into a singular, CamelCase entity name,
+// prefixing tables outside the public schema with their schema name.
+func entityName(tableName, schemaName string) string {
+	name := camelCase(singular(tableName))
+	if schemaName != "" && schemaName != "public" {
+		name = camelCase(schemaName) + name
+	}
+	return name
+}
+
+// fileName returns the snake_case file name for an entity.
+func fileName(entity string) string {
+	var b strings.Builder
+	for i, r := range entity {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + ".go"
+}
+
+func camelCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == ' ' || r == '.' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// singular strips common English plural endings. It does not attempt to
+// handle irregular nouns.
+func singular(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "uses"),
+		strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"), strings.HasSuffix(lower, "is"):
+		return name
+	case strings.HasSuffix(lower, "s") && len(name) > 1:
+		return name[:len(name)-1]
+	default:
+		return name
+	}
+}
+
+// edgeName derives the name of a foreign key edge from its column, so
+// "author_id" becomes "author".
+func edgeName(column string) string {
+	name := strings.TrimSuffix(column, "_id")
+	if name == "" || name == column {
+		name = column + "_ref"
+	}
+	return name
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+var colorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// headerColor is a table pattern and the header color of the tables it
+// matches.
+type headerColor struct {
+	pattern string
+	color   string
+}
+
+// headerColors validates colors and returns them in the order they are
+// tried: longest pattern first, since longer patterns are usually more
+// specific, then by pattern.
+func headerColors(colors map[string]string) ([]headerColor, error) {
+	var result []headerColor
+	for pattern, color := range colors {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid header color pattern %q: %w", pattern, err)
+		}
+		if !colorPattern.MatchString(color) {
+			return nil, fmt.Errorf("invalid header color %q for %q (use #rgb or #rrggbb)", color, pattern)
+		}
+		result = append(result, headerColor{pattern: pattern, color: color})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].pattern) != len(result[j].pattern) {
+			return len(result[i].pattern) > len(result[j].pattern)
+		}
+		return result[i].pattern < result[j].pattern
+	})
+	return result, nil
+}
+
+// tableColor returns the header color of the first of colors whose pattern
+// matches the table's name, its schema-qualified name, or its schema, or
+// an empty string if none does.
+func tableColor(colors []headerColor, table schema.Table) string {
+	schemaName := table.Schema
+	if schemaName == "" {
+		schemaName = "public"
+	}
+	for _, c := range colors {
+		for _, name := range []string{table.Name, schemaName + "." + table.Name, schemaName} {
+			if ok, _ := path.Match(c.pattern, name); ok {
+				return c.color
+			}
+		}
+	}
+	return ""
+}
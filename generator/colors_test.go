@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerateHeaderColors(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "audit_log", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "invoices", Schema: "billing", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "payments", Schema: "billing", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "refunds", Schema: "billing", Color: "#000", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		},
+	}
+
+	output, err := GenerateString(s, WithHeaderColors(map[string]string{
+		"billing":          "#3498db",
+		"billing.payments": "#e67e22",
+		"audit_*":          "#999999",
+	}))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	expected := []string{
+		"Table users {\n",
+		"Table audit_log [headercolor: #999999] {\n",
+		"Table billing.invoices [headercolor: #3498db] {\n",
+		"Table billing.payments [headercolor: #e67e22] {\n",
+		"Table billing.refunds [headercolor: #000] {\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, output)
+		}
+	}
+}
+
+func TestGenerateHeaderColorsInvalid(t *testing.T) {
+	s := &schema.Schema{Tables: []schema.Table{{Name: "users", Schema: "public"}}}
+
+	for _, colors := range []map[string]string{
+		{"users": "blue"},
+		{"[users": "#fff"},
+	} {
+		if _, err := Generate(s, WithHeaderColors(colors)); err == nil {
+			t.Errorf("Expected an error for %v", colors)
+		}
+	}
+}
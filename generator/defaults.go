@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// castLiteral matches a literal with the casts PostgreSQL prints after it,
+// such as 'active'::character varying or '-1'::integer.
+var castLiteral = regexp.MustCompile(`^('(?:[^']|'')*'|[^':()\s]+)((?:::[^:']+)*)$`)
+
+// numberLiteral matches the numbers DBML writes bare.
+var numberLiteral = regexp.MustCompile(`^-?[0-9]+(?:\.[0-9]+)?(?:[eE][-+]?[0-9]+)?$`)
+
+// numericTypes are the types whose string literals, such as '-1'::integer,
+// are written as numbers.
+var numericTypes = map[string]bool{
+	"smallint": true, "integer": true, "bigint": true, "numeric": true,
+	"real": true, "double precision": true,
+}
+
+// defaultValue returns a column default, as PostgreSQL prints it, as a DBML
+// value. Casts are stripped from literals, which are written as a string
+// ('active'), number (0), boolean (true), or null; anything else, such as
+// now() or nextval('users_id_seq'::regclass), is a backticked expression.
+func defaultValue(expression string) string {
+	expression = strings.TrimSpace(expression)
+	match := castLiteral.FindStringSubmatch(expression)
+	if match == nil {
+		return "`" + expression + "`"
+	}
+
+	literal, casts := match[1], match[2]
+	if strings.HasPrefix(literal, "'") {
+		text := strings.ReplaceAll(literal[1:len(literal)-1], "''", "'")
+		if numberLiteral.MatchString(text) && numericTypes[castType(casts)] {
+			return text
+		}
+		return quote(text)
+	}
+	switch lower := strings.ToLower(literal); {
+	case numberLiteral.MatchString(literal):
+		return literal
+	case lower == "true" || lower == "false" || lower == "null":
+		return lower
+	}
+	return "`" + expression + "`"
+}
+
+// castType returns the base type of the last of casts, such as "numeric"
+// for "::numeric(10,2)", or an empty string when there are none.
+func castType(casts string) string {
+	if casts == "" {
+		return ""
+	}
+	last := casts[strings.LastIndex(casts, "::")+2:]
+	if i := strings.Index(last, "("); i >= 0 {
+		last = last[:i]
+	}
+	return strings.TrimSpace(last)
+}
@@ -0,0 +1,33 @@
+package generator
+
+import "testing"
+
+func TestDefaultValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"'active'::character varying", "'active'"},
+		{"'it''s'::text", `'it\'s'`},
+		{"'{}'::jsonb", "'{}'"},
+		{"'2024-01-01'::date", "'2024-01-01'"},
+		{"'-1'::integer", "-1"},
+		{"'42'::character varying", "'42'"},
+		{"0", "0"},
+		{"0.00", "0.00"},
+		{"'1.5'::numeric(10,2)", "1.5"},
+		{"true", "true"},
+		{"FALSE", "false"},
+		{"NULL::character varying", "null"},
+		{"now()", "`now()`"},
+		{"CURRENT_TIMESTAMP", "`CURRENT_TIMESTAMP`"},
+		{"nextval('users_id_seq'::regclass)", "`nextval('users_id_seq'::regclass)`"},
+		{"'a'::text || 'b'::text", "`'a'::text || 'b'::text`"},
+		{"(now() + '1 day'::interval)", "`(now() + '1 day'::interval)`"},
+	}
+	for _, tt := range tests {
+		if result := defaultValue(tt.input); result != tt.expected {
+			t.Errorf("defaultValue(%q) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Diagnose reports the parts of s that Generate, given the same options,
+// leaves out of the DBML. DBML only has btree and hash indexes, so other
+// index access methods are kept in the index note instead unless
+// WithIndexTypes is given.
+func Diagnose(s *schema.Schema, opts ...Option) []schema.Diagnostic {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var diagnostics []schema.Diagnostic
+	for _, table := range s.Tables {
+		for _, index := range table.Indexes {
+			if index.Type == "" || index.Type == "hash" || o.indexTypes {
+				continue
+			}
+			diagnostics = append(diagnostics, schema.Diagnostic{
+				Code:    schema.DiagnosticUnsupported,
+				Schema:  table.Schema,
+				Table:   table.Name,
+				Object:  index.Name,
+				Message: fmt.Sprintf("index type %s has no DBML equivalent and was written as a note", index.Type),
+			})
+		}
+	}
+	return diagnostics
+}
@@ -0,0 +1,39 @@
+package generator
+
+import "github.com/lucasefe/dbml/schema"
+
+// withDomainTypes returns a copy of columns whose domain-typed columns have
+// the domain name as their type instead of the domain's base type.
+func withDomainTypes(columns []schema.Column) []schema.Column {
+	result := make([]schema.Column, len(columns))
+	for i, column := range columns {
+		if column.Domain != "" {
+			column.Type = column.Domain
+		}
+		result[i] = column
+	}
+	return result
+}
+
+// withDomainNotes returns a copy of notes with the domain name and the
+// domain's CHECK constraints prepended to the notes of each column that uses
+// a domain, since the column is written with the base type.
+func withDomainNotes(s *schema.Schema, columns []schema.Column, notes map[string][]string) map[string][]string {
+	result := make(map[string][]string, len(notes))
+	for column, columnNotes := range notes {
+		result[column] = columnNotes
+	}
+	for _, column := range columns {
+		if column.Domain == "" {
+			continue
+		}
+		domainNotes := []string{"DOMAIN " + column.Domain}
+		if domain, ok := s.ColumnDomain(column.Domain); ok {
+			for _, check := range domain.CheckConstraints {
+				domainNotes = append(domainNotes, "CHECK "+check.Expression)
+			}
+		}
+		result[column.Name] = append(domainNotes, result[column.Name]...)
+	}
+	return result
+}
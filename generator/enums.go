@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// sortedEnums returns the enums sorted by schema and name.
+func sortedEnums(enums []schema.Enum, less func(a, b string) bool) []schema.Enum {
+	sorted := make([]schema.Enum, len(enums))
+	copy(sorted, enums)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Schema != sorted[j].Schema {
+			return less(sorted[i].Schema, sorted[j].Schema)
+		}
+		return less(sorted[i].Name, sorted[j].Name)
+	})
+	return sorted
+}
+
+// generateEnum writes an Enum block with the values in declaration order.
+// Values that are not plain identifiers are double-quoted.
+func generateEnum(builder *strings.Builder, enum schema.Enum, qualify func(name, schemaName string) string) {
+	builder.WriteString(fmt.Sprintf("Enum %s {\n", qualify(enum.Name, enum.Schema)))
+	for _, value := range enum.Values {
+		builder.WriteString(fmt.Sprintf("  %s\n", quoteName(value)))
+	}
+	builder.WriteString("}\n")
+}
+
+// withEnumTypes returns a copy of columns whose enum-typed columns, which
+// introspection writes as text, have the enum's DBML name as their type.
+func withEnumTypes(s *schema.Schema, columns []schema.Column, qualify func(name, schemaName string) string) []schema.Column {
+	result := make([]schema.Column, len(columns))
+	for i, column := range columns {
+		if enum, ok := s.ColumnEnum(column.DatabaseType); ok {
+			column.Type = qualify(enum.Name, enum.Schema)
+			if strings.HasSuffix(column.DatabaseType, "[]") {
+				column.Type += "[]"
+			}
+		}
+		result[i] = column
+	}
+	return result
+}
@@ -0,0 +1,172 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultIndent is the indentation the generator writes, one level per
+// nested block.
+const defaultIndent = "  "
+
+// ParseIndent parses "tab" or a number of spaces from 1 to 8 into the
+// indentation for WithIndent.
+func ParseIndent(s string) (string, error) {
+	if s == "tab" {
+		return "\t", nil
+	}
+	width, err := strconv.Atoi(s)
+	if err != nil || width < 1 || width > 8 {
+		return defaultIndent, fmt.Errorf("invalid indent %q (use tab or a number of spaces from 1 to 8)", s)
+	}
+	return strings.Repeat(" ", width), nil
+}
+
+// BlankLines controls where blank lines are written.
+type BlankLines int
+
+const (
+	// BlankLinesSections writes a blank line after each block and before a
+	// table's indexes and note.
+	BlankLinesSections BlankLines = iota
+	// BlankLinesBlocks writes a blank line after each block only, keeping
+	// tables in one piece.
+	BlankLinesBlocks
+)
+
+// ParseBlankLines parses "sections" or "blocks".
+func ParseBlankLines(s string) (BlankLines, error) {
+	switch s {
+	case "sections":
+		return BlankLinesSections, nil
+	case "blocks":
+		return BlankLinesBlocks, nil
+	default:
+		return BlankLinesSections, fmt.Errorf("invalid blank line policy %q (use sections or blocks)", s)
+	}
+}
+
+// AttributeSpacing controls the spacing of settings lists, such as a
+// column's [pk, not null].
+type AttributeSpacing int
+
+const (
+	// SpacingNormal separates settings with ", ": [pk, not null].
+	SpacingNormal AttributeSpacing = iota
+	// SpacingCompact separates settings with ",": [pk,not null].
+	SpacingCompact
+	// SpacingPadded separates settings with ", " and pads the brackets:
+	// [ pk, not null ].
+	SpacingPadded
+)
+
+// ParseAttributeSpacing parses "normal", "compact", or "padded".
+func ParseAttributeSpacing(s string) (AttributeSpacing, error) {
+	switch s {
+	case "normal":
+		return SpacingNormal, nil
+	case "compact":
+		return SpacingCompact, nil
+	case "padded":
+		return SpacingPadded, nil
+	default:
+		return SpacingNormal, fmt.Errorf("invalid attribute spacing %q (use normal, compact, or padded)", s)
+	}
+}
+
+// reformat rewrites DBML as the generator writes it, indented by two spaces
+// with blank lines between sections and normal attribute spacing, in the
+// given style. The text of triple-quoted notes is kept as is, apart from the
+// indentation of the note itself.
+func reformat(dbml, indent string, blankLines BlankLines, spacing AttributeSpacing) string {
+	if indent == defaultIndent && blankLines == BlankLinesSections && spacing == SpacingNormal {
+		return dbml
+	}
+
+	var builder strings.Builder
+	depth := 0
+	inNote := false
+	for _, line := range strings.SplitAfter(dbml, "\n") {
+		content := strings.TrimLeft(line, " ")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inNote && trimmed == "'''":
+			inNote = false
+		case inNote:
+			// Note text is written two levels deep, past which any
+			// leading spaces are its own
+			if strings.HasPrefix(line, defaultIndent+defaultIndent) {
+				line = strings.Repeat(indent, 2) + line[2*len(defaultIndent):]
+			}
+			builder.WriteString(line)
+			continue
+		case trimmed == "'''":
+			inNote = true
+		case trimmed == "":
+			if depth == 0 || blankLines == BlankLinesSections {
+				builder.WriteString(line)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "}") {
+			depth--
+		}
+		if strings.HasSuffix(trimmed, "{") {
+			depth++
+		}
+		level := (len(line) - len(content)) / len(defaultIndent)
+		builder.WriteString(strings.Repeat(indent, level) + spaced(content, spacing))
+	}
+	return builder.String()
+}
+
+// spaced returns line with its settings lists, the bracketed lists that
+// follow a space, spaced as given. Brackets and commas in quoted strings and
+// backticked expressions are left alone.
+func spaced(line string, spacing AttributeSpacing) string {
+	if spacing == SpacingNormal {
+		return line
+	}
+
+	var builder strings.Builder
+	var quote byte
+	inSettings := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if quote != 0 {
+			builder.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(line) {
+				i++
+				builder.WriteByte(line[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[' && i > 0 && line[i-1] == ' ' && !inSettings:
+			inSettings = true
+			if spacing == SpacingPadded {
+				builder.WriteString("[ ")
+				continue
+			}
+		case c == ']' && inSettings:
+			inSettings = false
+			if spacing == SpacingPadded {
+				builder.WriteString(" ]")
+				continue
+			}
+		case c == ',' && inSettings && spacing == SpacingCompact && strings.HasPrefix(line[i+1:], " "):
+			builder.WriteByte(c)
+			i++
+			continue
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String()
+}
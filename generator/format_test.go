@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestParseIndent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"tab", "\t"},
+		{"2", "  "},
+		{"4", "    "},
+	}
+	for _, tt := range tests {
+		if indent, err := ParseIndent(tt.input); err != nil || indent != tt.expected {
+			t.Errorf("ParseIndent(%q) = %q, %v, want %q", tt.input, indent, err, tt.expected)
+		}
+	}
+	for _, input := range []string{"0", "9", "spaces"} {
+		if _, err := ParseIndent(input); err == nil {
+			t.Errorf("Expected error for indent %q", input)
+		}
+	}
+}
+
+func TestGenerateFormatting(t *testing.T) {
+	defaultValue := "'a, b'::text"
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "users",
+			Schema:  "public",
+			Comment: "People\n  indented",
+			Columns: []schema.Column{
+				{Name: "id", Type: "int", IsPrimaryKey: true},
+				{Name: "tags", Type: "text[]", DefaultValue: &defaultValue},
+			},
+			Indexes: []schema.Index{{Name: "idx_users_tags", Columns: []string{"tags"}, Unique: true}},
+		}},
+	}
+
+	output, err := GenerateString(s, WithNotes(), WithIndent("\t"), WithBlankLines(BlankLinesBlocks), WithAttributeSpacing(SpacingCompact))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "Table users {\n" +
+		"\tid int [pk]\n" +
+		"\ttags text[] [not null,default: 'a, b']\n" +
+		"\tindexes {\n" +
+		"\t\t(tags) [unique,name: 'idx_users_tags']\n" +
+		"\t}\n" +
+		"\tNote {\n" +
+		"\t\t'''\n" +
+		"\t\tPeople\n" +
+		"\t\t  indented\n" +
+		"\t\t'''\n" +
+		"\t}\n" +
+		"}\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain:\n%s\ngot:\n%s", expected, output)
+	}
+
+	output, err = GenerateString(s, WithAttributeSpacing(SpacingPadded))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  tags text[] [ not null, default: 'a, b' ]\n") {
+		t.Errorf("Expected padded settings, got:\n%s", output)
+	}
+}
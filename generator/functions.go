@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// generateFunctions writes the functions and procedures as a sticky note
+// named functions, one signature per line, since DBML has no syntax for
+// them. Comments follow each signature when comments is set.
+func generateFunctions(builder *strings.Builder, functions []schema.Function, comments bool, less func(a, b string) bool) {
+	sorted := make([]schema.Function, len(functions))
+	copy(sorted, functions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Schema != sorted[j].Schema {
+			return less(sorted[i].Schema, sorted[j].Schema)
+		}
+		return less(sorted[i].Name, sorted[j].Name)
+	})
+
+	lines := make([]string, len(sorted))
+	for i, function := range sorted {
+		lines[i] = functionSignature(function)
+		if comments && function.Comment != "" {
+			lines[i] += " -- " + function.Comment
+		}
+	}
+	builder.WriteString("Note functions {\n")
+	builder.WriteString(fmt.Sprintf("  %s\n", quote(strings.Join(lines, "\n"))))
+	builder.WriteString("}\n")
+}
+
+// functionSignature describes a function in CREATE FUNCTION terms, such as
+// "FUNCTION billing.charge(amount numeric) RETURNS integer LANGUAGE plpgsql".
+func functionSignature(function schema.Function) string {
+	kind := "FUNCTION"
+	if function.Kind == "procedure" {
+		kind = "PROCEDURE"
+	}
+	signature := fmt.Sprintf("%s %s(%s)", kind, GetQualifiedTableName(function.Name, function.Schema), function.Arguments)
+	if function.Returns != "" {
+		signature += " RETURNS " + function.Returns
+	}
+	if function.Language != "" {
+		signature += " LANGUAGE " + function.Language
+	}
+	return signature
+}
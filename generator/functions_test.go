@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerateFunctions(t *testing.T) {
+	s := &schema.Schema{
+		Functions: []schema.Function{
+			{Name: "touch_updated_at", Schema: "public", Kind: "function", Returns: "trigger", Language: "plpgsql"},
+			{Name: "charge", Schema: "billing", Kind: "function", Arguments: "amount numeric", Returns: "integer", Language: "sql", Comment: "Charges the default card"},
+			{Name: "archive_orders", Schema: "billing", Kind: "procedure", Arguments: "before date", Language: "plpgsql"},
+		},
+	}
+
+	output, err := GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "Note functions {\n" +
+		"  'PROCEDURE billing.archive_orders(before date) LANGUAGE plpgsql\\n" +
+		"FUNCTION billing.charge(amount numeric) RETURNS integer LANGUAGE sql -- Charges the default card\\n" +
+		"FUNCTION touch_updated_at() RETURNS trigger LANGUAGE plpgsql'\n" +
+		"}\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected the functions note, got:\n%s", output)
+	}
+}
@@ -14,39 +14,193 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/lucasefe/dbml/graph"
 	"github.com/lucasefe/dbml/schema"
 )
 
-// Generate converts a Schema into DBML-formatted bytes.
-// The output includes table definitions with columns, indexes, and foreign key
-// references in standard DBML syntax. Tables and references are sorted
-// alphabetically for deterministic output.
-func Generate(s *schema.Schema) ([]byte, error) {
+// Generate converts a Schema into DBML-formatted bytes. The output includes
+// table definitions with columns, indexes, and foreign key references in
+// standard DBML syntax. Enum blocks come first, and columns of an enum type
+// use the enum's name. Views follow the tables as Table blocks whose note
+// holds the view definition, then table groups in their given order, followed
+// by a group per schema with WithSchemaGroups, and sticky notes listing the
+// standalone sequences and functions. Tables and references are sorted
+// alphabetically for deterministic output, or naturally with WithSortOrder.
+// Columns keep their declared order unless WithColumnOrder says otherwise. The
+// layout, two-space indents and blank lines between blocks and sections, can
+// be changed with WithIndent, WithBlankLines, and WithAttributeSpacing.
+func Generate(s *schema.Schema, opts ...Option) ([]byte, error) {
+	o := &options{viewReferences: RefOmit, indent: defaultIndent}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	colors, err := headerColors(o.headerColors)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReferences(s); err != nil {
+		return nil, err
+	}
+
+	qualify := tableNamer(o.qualifyPublic)
+
+	if o.strict {
+		if problems := strictProblems(s, qualify); len(problems) > 0 {
+			return nil, &StrictError{Problems: problems}
+		}
+	}
+
 	var builder strings.Builder
 
+	if s.Migration != nil {
+		generateProject(&builder, s.Migration)
+		builder.WriteString("\n")
+	}
+
+	less := o.sortOrder.less()
+
+	for _, enum := range sortedEnums(s.Enums, less) {
+		generateEnum(&builder, enum, qualify)
+		builder.WriteString("\n")
+	}
+
 	// Sort tables by schema.name for consistent output
 	sortedTables := make([]schema.Table, len(s.Tables))
 	copy(sortedTables, s.Tables)
 	sort.Slice(sortedTables, func(i, j int) bool {
 		if sortedTables[i].Schema != sortedTables[j].Schema {
-			return sortedTables[i].Schema < sortedTables[j].Schema
+			return less(sortedTables[i].Schema, sortedTables[j].Schema)
 		}
-		return sortedTables[i].Name < sortedTables[j].Name
+		return less(sortedTables[i].Name, sortedTables[j].Name)
 	})
 
-	for _, table := range sortedTables {
-		generateTable(&builder, table)
-		builder.WriteString("\n")
+	// Junction tables' foreign keys are replaced by a many-to-many Ref, and
+	// the tables themselves are left out when hidden
+	var junctionTables map[schema.TableName]schema.Table
+	if o.manyToMany {
+		junctionTables = junctions(sortedTables)
+	}
+	groups := s.TableGroups
+	if o.hideJunctions {
+		var shown []schema.Table
+		for _, table := range sortedTables {
+			if _, ok := junctionTables[qualified(schema.TableName{Schema: table.Schema, Name: table.Name})]; !ok {
+				shown = append(shown, table)
+			}
+		}
+		sortedTables = shown
+		groups = withoutTables(groups, junctionTables)
 	}
 
-	// Collect and sort all references
+	// Collect and sort all references, turning those rendered as notes
+	// into column notes
+	styles := referenceStyles(s, o)
 	var allReferences []schema.Reference
+	notes := make(map[string]map[string][]string)
 	for _, table := range sortedTables {
+		if _, ok := junctionTables[qualified(schema.TableName{Schema: table.Schema, Name: table.Name})]; ok {
+			continue
+		}
 		for _, ref := range table.References {
-			allReferences = append(allReferences, ref)
+			// DBML has no syntax for match types or deferrable
+			// constraints, so they are noted on the referencing column
+			tableName := GetQualifiedTableName(table.Name, table.Schema)
+			var note string
+			switch styles[refKey(ref)] {
+			case RefStandard:
+				allReferences = append(allReferences, ref)
+				note = referenceClauses(ref)
+			case RefNote:
+				note = "References " + refTarget(ref.ToTable, ref.ToSchema, ref.ToColumns, qualify)
+				if clauses := referenceClauses(ref); clauses != "" {
+					note += " " + clauses
+				}
+			}
+			if note == "" {
+				continue
+			}
+			if notes[tableName] == nil {
+				notes[tableName] = make(map[string][]string)
+			}
+			column := ref.FromColumns[0]
+			notes[tableName][column] = append(notes[tableName][column], note)
 		}
 	}
 
+	for _, table := range sortedTables {
+		if !o.notes {
+			table.Comment = ""
+			table.Columns = withoutComments(table.Columns)
+		}
+		if !o.owners {
+			table.Owner = ""
+		}
+		if table.Color == "" {
+			table.Color = tableColor(colors, table)
+		}
+		table.Columns = sortedColumns(withEnumTypes(s, table.Columns, qualify), o.columnOrder, less)
+		if o.typeDetail != TypesDetailed {
+			table.Columns = withTypeDetail(table.Columns, o.typeDetail)
+		}
+		tableNotes := withTypeNotes(s, table.Columns, notes[GetQualifiedTableName(table.Name, table.Schema)])
+		if o.domainTypes {
+			table.Columns = withDomainTypes(table.Columns)
+		} else {
+			tableNotes = withDomainNotes(s, table.Columns, tableNotes)
+		}
+		generateTable(&builder, table, tableNotes, o.indexTypes, !o.withoutColumnNotes, qualify, less)
+		builder.WriteString("\n")
+	}
+
+	sortedViews := make([]schema.View, len(s.Views))
+	copy(sortedViews, s.Views)
+	sort.Slice(sortedViews, func(i, j int) bool {
+		if sortedViews[i].Schema != sortedViews[j].Schema {
+			return less(sortedViews[i].Schema, sortedViews[j].Schema)
+		}
+		return less(sortedViews[i].Name, sortedViews[j].Name)
+	})
+
+	var viewReferences []string
+	for _, view := range sortedViews {
+		if !o.notes {
+			view.Comment = ""
+			view.Columns = withoutComments(view.Columns)
+		}
+		view.Columns = sortedColumns(withEnumTypes(s, view.Columns, qualify), o.columnOrder, less)
+		if o.typeDetail != TypesDetailed {
+			view.Columns = withTypeDetail(view.Columns, o.typeDetail)
+		}
+		if o.domainTypes {
+			view.Columns = withDomainTypes(view.Columns)
+		}
+		generateView(&builder, view, o.viewReferences == RefNote, !o.withoutColumnNotes, qualify, less)
+		builder.WriteString("\n")
+		if o.viewReferences == RefStandard {
+			viewReferences = append(viewReferences, lineage(view, qualify, less)...)
+		}
+	}
+
+	if o.schemaGroups {
+		groups = append(append([]schema.TableGroup(nil), groups...), schemaGroups(sortedTables, sortedViews, groups, less)...)
+	}
+	for _, group := range groups {
+		generateTableGroup(&builder, group, qualify)
+		builder.WriteString("\n")
+	}
+
+	if len(s.Sequences) > 0 {
+		generateSequences(&builder, s.Sequences, o.notes, less)
+		builder.WriteString("\n")
+	}
+
+	if len(s.Functions) > 0 {
+		generateFunctions(&builder, s.Functions, o.notes, less)
+		builder.WriteString("\n")
+	}
+
 	// Sort references for consistent output
 	sort.Slice(allReferences, func(i, j int) bool {
 		refI := allReferences[i]
@@ -55,79 +209,430 @@ func Generate(s *schema.Schema) ([]byte, error) {
 		fromTableI := GetQualifiedTableName(refI.FromTable, refI.FromSchema)
 		fromTableJ := GetQualifiedTableName(refJ.FromTable, refJ.FromSchema)
 		if fromTableI != fromTableJ {
-			return fromTableI < fromTableJ
+			return less(fromTableI, fromTableJ)
 		}
 
-		if len(refI.FromColumns) > 0 && len(refJ.FromColumns) > 0 {
-			if refI.FromColumns[0] != refJ.FromColumns[0] {
-				return refI.FromColumns[0] < refJ.FromColumns[0]
-			}
+		// checkReferences makes sure every reference has columns
+		if refI.FromColumns[0] != refJ.FromColumns[0] {
+			return less(refI.FromColumns[0], refJ.FromColumns[0])
 		}
 
 		toTableI := GetQualifiedTableName(refI.ToTable, refI.ToSchema)
 		toTableJ := GetQualifiedTableName(refJ.ToTable, refJ.ToSchema)
 		if toTableI != toTableJ {
-			return toTableI < toTableJ
+			return less(toTableI, toTableJ)
 		}
 
-		if len(refI.ToColumns) > 0 && len(refJ.ToColumns) > 0 {
-			return refI.ToColumns[0] < refJ.ToColumns[0]
-		}
-
-		return false
+		return less(refI.ToColumns[0], refJ.ToColumns[0])
 	})
 
-	// Generate sorted references
+	// Generate sorted references. DBML needs Ref names to be unique, while
+	// PostgreSQL only needs them to be unique per table, so a name already
+	// written is left off
+	named := make(map[string]bool)
 	for _, ref := range allReferences {
-		generateReference(&builder, ref)
+		if named[ref.Name] {
+			ref.Name = ""
+		}
+		named[ref.Name] = true
+		generateReference(&builder, ref, qualify)
+	}
+	for _, ref := range manyToManyRefs(junctionTables, named, qualify, less) {
+		builder.WriteString(ref)
+	}
+	for _, ref := range viewReferences {
+		builder.WriteString(ref)
 	}
 
-	return []byte(builder.String()), nil
+	return []byte(reformat(builder.String(), o.indent, o.blankLines, o.attributeSpacing)), nil
 }
 
 // GenerateString is a convenience wrapper that returns the DBML as a string.
-func GenerateString(s *schema.Schema) (string, error) {
-	result, err := Generate(s)
+func GenerateString(s *schema.Schema, opts ...Option) (string, error) {
+	result, err := Generate(s, opts...)
 	if err != nil {
 		return "", err
 	}
 	return string(result), nil
 }
 
-func generateTable(builder *strings.Builder, table schema.Table) {
-	tableName := table.Name
-	if table.Schema != "" && table.Schema != "public" {
-		tableName = fmt.Sprintf("%s.%s", table.Schema, table.Name)
+// generateProject records the migration state in the Project note.
+func generateProject(builder *strings.Builder, migration *schema.Migration) {
+	builder.WriteString("Project {\n")
+	builder.WriteString("  database_type: 'PostgreSQL'\n")
+	builder.WriteString(fmt.Sprintf("  Note: %s\n", quote(fmt.Sprintf("Migration version %s (%s, %s)", migration.Version, migration.Tool, migration.Table))))
+	builder.WriteString("}\n")
+}
+
+// referenceStyles returns the style for each self-referencing or cyclic
+// foreign key, keyed by refKey. Other references are RefStandard.
+func referenceStyles(s *schema.Schema, o *options) map[string]RefStyle {
+	styles := make(map[string]RefStyle)
+	if o.selfReferences == RefStandard && o.cyclicReferences == RefStandard {
+		return styles
+	}
+
+	component := make(map[graph.Node]int)
+	for i, cycle := range graph.New(s).Cycles() {
+		for _, n := range cycle {
+			component[n] = i + 1
+		}
+	}
+
+	for _, table := range s.Tables {
+		for _, ref := range table.References {
+			from := graph.Node{Schema: ref.FromSchema, Name: ref.FromTable}
+			to := graph.Node{Schema: ref.ToSchema, Name: ref.ToTable}
+			switch {
+			case from == to:
+				styles[refKey(ref)] = o.selfReferences
+			case component[from] != 0 && component[from] == component[to]:
+				styles[refKey(ref)] = o.cyclicReferences
+			}
+		}
+	}
+	return styles
+}
+
+// refKey identifies the pair of tables a reference connects.
+func refKey(ref schema.Reference) string {
+	return strings.Join([]string{ref.FromSchema, ref.FromTable, ref.ToSchema, ref.ToTable}, "\x00")
+}
+
+func generateTable(builder *strings.Builder, table schema.Table, notes map[string][]string, indexTypes, columnNotes bool, qualify func(name, schemaName string) string, less func(a, b string) bool) {
+	tableName := qualify(table.Name, table.Schema)
+	if table.Color != "" {
+		tableName += fmt.Sprintf(" [headercolor: %s]", table.Color)
 	}
 	builder.WriteString(fmt.Sprintf("Table %s {\n", tableName))
 
-	// Sort columns by name for consistent output
-	sortedColumns := make([]schema.Column, len(table.Columns))
-	copy(sortedColumns, table.Columns)
-	sort.Slice(sortedColumns, func(i, j int) bool {
-		return sortedColumns[i].Name < sortedColumns[j].Name
-	})
+	// DBML reads pk on several columns as several primary keys, so a
+	// composite primary key goes in the indexes block instead
+	compositeKey := compositePrimaryKey(table)
+	if compositeKey != nil {
+		table.Columns = withoutPrimaryKey(table.Columns)
+	}
 
-	for _, column := range sortedColumns {
-		generateColumn(builder, column)
+	columnChecks, tableChecks := checkNotes(table.CheckConstraints)
+	for _, column := range table.Columns {
+		if !columnNotes {
+			generateColumn(builder, column, "")
+			continue
+		}
+		note := append(columnChecks[column.Name], notes[column.Name]...)
+		if sequence := sequenceNote(column.Sequence); sequence != "" {
+			note = append([]string{sequence}, note...)
+		}
+		if column.Collation != "" {
+			note = append([]string{"COLLATE " + column.Collation}, note...)
+		}
+		if clause := column.GenerationClause(); clause != "" {
+			note = append([]string{clause}, note...)
+		}
+		if comment := deprecationNote(column.Comment, column.Deprecated); comment != "" {
+			note = append([]string{comment}, note...)
+		}
+		generateColumn(builder, column, strings.Join(note, "; "))
 	}
 
-	if len(table.Indexes) > 0 {
+	if indexes := withUniqueConstraints(table.Indexes, table.UniqueConstraints); len(indexes) > 0 || compositeKey != nil {
 		builder.WriteString("\n")
 		// Sort indexes by name for consistent output
-		sortedIndexes := make([]schema.Index, len(table.Indexes))
-		copy(sortedIndexes, table.Indexes)
+		sortedIndexes := make([]schema.Index, len(indexes))
+		copy(sortedIndexes, indexes)
 		sort.Slice(sortedIndexes, func(i, j int) bool {
-			return sortedIndexes[i].Name < sortedIndexes[j].Name
+			return less(sortedIndexes[i].Name, sortedIndexes[j].Name)
 		})
-		generateIndexes(builder, sortedIndexes)
+		generateIndexes(builder, compositeKey, sortedIndexes, indexTypes)
+	}
+
+	tableNotes := append(foreignNotes(table), inheritanceNotes(table, qualify)...)
+	tableNotes = append(tableNotes, partitionNotes(table, qualify)...)
+	if table.Owner != "" {
+		tableNotes = append(tableNotes, "OWNER "+table.Owner)
+	}
+	if table.Tablespace != "" {
+		tableNotes = append(tableNotes, "TABLESPACE "+table.Tablespace)
+	}
+	tableNotes = append(tableNotes, tableChecks...)
+	tableNotes = append(tableNotes, exclusionNotes(table.ExclusionConstraints)...)
+	tableNotes = append(tableNotes, rowSecurityNotes(table)...)
+	tableNotes = append(tableNotes, triggerNotes(table.Triggers)...)
+	tableNotes = append(tableNotes, grantNotes(table.Grants)...)
+	tableNotes = append(tableNotes, statisticsNotes(table.Statistics)...)
+	if comment := deprecationNote(table.Comment, table.Deprecated); comment != "" {
+		tableNotes = append([]string{comment}, tableNotes...)
+	}
+	if len(tableNotes) > 0 {
+		builder.WriteString("\n")
+		writeNote(builder, strings.Join(tableNotes, "\n"))
+	}
+
+	builder.WriteString("}\n")
+}
+
+// compositePrimaryKey returns the columns of a table's primary key when it
+// has more than one, from PrimaryKeys or else the columns marked
+// IsPrimaryKey, or nil otherwise.
+func compositePrimaryKey(table schema.Table) []string {
+	key := table.PrimaryKeys
+	if len(key) == 0 {
+		for _, column := range table.Columns {
+			if column.IsPrimaryKey {
+				key = append(key, column.Name)
+			}
+		}
+	}
+	if len(key) < 2 {
+		return nil
+	}
+	return key
+}
+
+// withoutPrimaryKey returns a copy of columns with none marked as part of
+// the primary key. They are not null, which is written instead.
+func withoutPrimaryKey(columns []schema.Column) []schema.Column {
+	result := make([]schema.Column, len(columns))
+	for i, column := range columns {
+		if column.IsPrimaryKey {
+			column.IsPrimaryKey = false
+			column.Nullable = false
+		}
+		result[i] = column
+	}
+	return result
+}
+
+// foreignNotes returns the table note line for a foreign table, naming its
+// server and the options that locate the remote data.
+func foreignNotes(table schema.Table) []string {
+	if table.Foreign == nil {
+		return nil
+	}
+	note := "FOREIGN TABLE on server " + table.Foreign.Server
+	if len(table.Foreign.Options) > 0 {
+		note += " (" + strings.Join(table.Foreign.Options, ", ") + ")"
+	}
+	return []string{note}
+}
+
+// inheritanceNotes returns the table note line naming the tables a table
+// inherits from. DBML has no syntax for inheritance, and inherited columns
+// are repeated in the child, so a note is the closest match.
+func inheritanceNotes(table schema.Table, qualify func(name, schemaName string) string) []string {
+	if len(table.Inherits) == 0 {
+		return nil
+	}
+	parents := make([]string, len(table.Inherits))
+	for i, parent := range table.Inherits {
+		parents[i] = qualify(parent.Name, parent.Schema)
+	}
+	return []string{"INHERITS (" + strings.Join(parents, ", ") + ")"}
+}
+
+// partitionNotes returns the table note lines for a partitioned table: its
+// partition key, then its partitions, if they were introspected.
+func partitionNotes(table schema.Table, qualify func(name, schemaName string) string) []string {
+	if table.PartitionKey == "" {
+		return nil
+	}
+	notes := []string{"PARTITION BY " + table.PartitionKey}
+	for _, partition := range table.Partitions {
+		notes = append(notes, fmt.Sprintf("Partition %s %s", qualify(partition.Name, partition.Schema), partition.Bound))
+	}
+	return notes
+}
+
+// checkNotes returns the notes for a table's CHECK constraints. DBML has no
+// syntax for them, so a constraint on one column is noted on that column and
+// the others on the table. Constraints are written whether or not comments
+// are, since they are part of the schema.
+func checkNotes(checks []schema.CheckConstraint) (map[string][]string, []string) {
+	columns := make(map[string][]string)
+	var table []string
+	for _, check := range checks {
+		note := "CHECK " + check.Expression
+		if len(check.Columns) == 1 {
+			columns[check.Columns[0]] = append(columns[check.Columns[0]], note)
+		} else {
+			table = append(table, note)
+		}
+	}
+	return columns, table
+}
+
+// exclusionNotes returns the table note lines for a table's EXCLUDE
+// constraints, which DBML has no syntax for, named so they can be told apart.
+func exclusionNotes(exclusions []schema.ExclusionConstraint) []string {
+	var notes []string
+	for _, exclusion := range exclusions {
+		notes = append(notes, fmt.Sprintf("CONSTRAINT %s %s", exclusion.Name, exclusion.Definition))
+	}
+	return notes
+}
+
+// rowSecurityNotes returns the table note lines for row level security:
+// whether it is enabled, then each policy with the command and roles it
+// applies to. Policies on a table without row level security are listed
+// too, since they take effect once it is enabled.
+func rowSecurityNotes(table schema.Table) []string {
+	if !table.RowSecurity && len(table.Policies) == 0 {
+		return nil
+	}
+	var notes []string
+	switch {
+	case table.ForceRowSecurity:
+		notes = append(notes, "ROW LEVEL SECURITY (forced)")
+	case table.RowSecurity:
+		notes = append(notes, "ROW LEVEL SECURITY")
+	default:
+		notes = append(notes, "ROW LEVEL SECURITY disabled")
+	}
+	for _, policy := range table.Policies {
+		note := fmt.Sprintf("POLICY %s FOR %s", policy.Name, policy.Command)
+		if len(policy.Roles) > 0 {
+			note += " TO " + strings.Join(policy.Roles, ", ")
+		}
+		if !policy.Permissive {
+			note += " AS RESTRICTIVE"
+		}
+		notes = append(notes, note)
 	}
+	return notes
+}
 
+// triggerNotes returns a table note line for each trigger, spelled like the
+// CREATE TRIGGER statement that defines it, so readers can see the side
+// effects of writing to the table.
+func triggerNotes(triggers []schema.Trigger) []string {
+	var notes []string
+	for _, trigger := range triggers {
+		note := fmt.Sprintf("TRIGGER %s %s %s FOR EACH %s EXECUTE FUNCTION %s()",
+			trigger.Name, trigger.Timing, strings.Join(trigger.Events, " OR "), trigger.ForEach, trigger.Function)
+		if !trigger.Enabled {
+			note += " (disabled)"
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+// grantNotes returns a table note line for each grantee, spelled like the
+// GRANT statement that gives its privileges, so readers can see who can read
+// and write the table.
+func grantNotes(grants []schema.Grant) []string {
+	var notes []string
+	for _, grant := range grants {
+		notes = append(notes, fmt.Sprintf("GRANT %s TO %s", strings.Join(grant.Privileges, ", "), grant.Grantee))
+	}
+	return notes
+}
+
+// statisticsNotes returns a table note line with the table's estimated row
+// count and total size, when they were introspected.
+func statisticsNotes(stats *schema.TableStatistics) []string {
+	if stats == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("STATISTICS ~%d rows, %s", stats.RowEstimate, schema.FormatBytes(stats.TotalBytes))}
+}
+
+// deprecationNote prefixes the comment of a deprecated table or column with
+// "Deprecated". Deprecation is shown even when comments are not.
+func deprecationNote(comment string, deprecated bool) string {
+	switch {
+	case !deprecated:
+		return comment
+	case comment == "":
+		return "Deprecated"
+	default:
+		return "Deprecated: " + comment
+	}
+}
+
+// withoutComments returns a copy of columns with their comments cleared.
+func withoutComments(columns []schema.Column) []schema.Column {
+	result := make([]schema.Column, len(columns))
+	for i, column := range columns {
+		column.Comment = ""
+		result[i] = column
+	}
+	return result
+}
+
+func generateTableGroup(builder *strings.Builder, group schema.TableGroup, qualify func(name, schemaName string) string) {
+	builder.WriteString(fmt.Sprintf("TableGroup %s {\n", quoteName(group.Name)))
+	for _, table := range group.Tables {
+		builder.WriteString(fmt.Sprintf("  %s\n", qualify(table.Name, table.Schema)))
+	}
 	builder.WriteString("}\n")
 }
 
-func generateColumn(builder *strings.Builder, column schema.Column) {
-	builder.WriteString(fmt.Sprintf("  %s %s", column.Name, column.Type))
+// generateView writes a view as a Table block. DBML has no view syntax, so the
+// definition goes in the table note, after the view's comment, along with the
+// tables it reads from when sources is set. Column comments are written as
+// notes when columnNotes is set.
+func generateView(builder *strings.Builder, view schema.View, sources, columnNotes bool, qualify func(name, schemaName string) string, less func(a, b string) bool) {
+	builder.WriteString(fmt.Sprintf("Table %s {\n", qualify(view.Name, view.Schema)))
+
+	for _, column := range view.Columns {
+		note := column.Comment
+		if !columnNotes {
+			note = ""
+		}
+		generateColumn(builder, column, note)
+	}
+
+	kind := "VIEW"
+	if view.Materialized {
+		kind = "MATERIALIZED VIEW"
+	}
+	note := kind + ": " + strings.TrimSpace(view.Definition)
+	if view.Comment != "" {
+		note = view.Comment + "\n" + note
+	}
+	if sources && len(view.Sources) > 0 {
+		names := make([]string, len(view.Sources))
+		for i, source := range view.Sources {
+			names[i] = qualify(source.Table, source.Schema)
+		}
+		note += "\nReads from: " + strings.Join(names, ", ")
+	}
+	builder.WriteString("\n")
+	writeNote(builder, note)
+	builder.WriteString("}\n")
+}
+
+// lineage returns a Ref line from each view column to the source column it
+// most likely comes from: the only source column with the same name. Columns
+// that match several sources, such as id, are ambiguous and skipped.
+func lineage(view schema.View, qualify func(name, schemaName string) string, less func(a, b string) bool) []string {
+	var refs []string
+	viewName := qualify(view.Name, view.Schema)
+	for _, column := range view.Columns {
+		var match *schema.ViewSource
+		matches := 0
+		for i, source := range view.Sources {
+			for _, name := range source.Columns {
+				if name == column.Name {
+					match = &view.Sources[i]
+					matches++
+				}
+			}
+		}
+		if matches != 1 {
+			continue
+		}
+		refs = append(refs, fmt.Sprintf("Ref: %s.%s > %s\n", viewName, quoteName(column.Name), refTarget(match.Table, match.Schema, []string{column.Name}, qualify)))
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return less(refs[i], refs[j])
+	})
+	return refs
+}
+
+func generateColumn(builder *strings.Builder, column schema.Column, note string) {
+	builder.WriteString(fmt.Sprintf("  %s %s", quoteName(column.Name), column.Type))
 
 	var attributes []string
 
@@ -139,13 +644,14 @@ func generateColumn(builder *strings.Builder, column schema.Column) {
 		attributes = append(attributes, "not null")
 	}
 
-	if column.DefaultValue != nil {
-		defaultVal := *column.DefaultValue
-		if strings.HasPrefix(defaultVal, "nextval(") {
-			attributes = append(attributes, "increment")
-		} else {
-			attributes = append(attributes, fmt.Sprintf("default: `%s`", defaultVal))
-		}
+	if column.AutoIncrement {
+		attributes = append(attributes, "increment")
+	} else if column.DefaultValue != nil {
+		attributes = append(attributes, "default: "+defaultValue(*column.DefaultValue))
+	}
+
+	if note != "" {
+		attributes = append(attributes, "note: "+quote(note))
 	}
 
 	if len(attributes) > 0 {
@@ -155,45 +661,127 @@ func generateColumn(builder *strings.Builder, column schema.Column) {
 	builder.WriteString("\n")
 }
 
-func generateIndexes(builder *strings.Builder, indexes []schema.Index) {
+// withUniqueConstraints returns indexes with the UNIQUE constraints among
+// them: an index named after a constraint is marked as backing it, and a
+// constraint without one is added as a unique index.
+func withUniqueConstraints(indexes []schema.Index, constraints []schema.UniqueConstraint) []schema.Index {
+	if len(constraints) == 0 {
+		return indexes
+	}
+	merged := make([]schema.Index, len(indexes))
+	copy(merged, indexes)
+	for _, constraint := range constraints {
+		found := false
+		for i := range merged {
+			if merged[i].Name == constraint.Name {
+				merged[i].Unique = true
+				merged[i].UniqueConstraint = true
+				found = true
+			}
+		}
+		if !found {
+			merged = append(merged, schema.Index{
+				Name:             constraint.Name,
+				Columns:          constraint.Columns,
+				Unique:           true,
+				UniqueConstraint: true,
+			})
+		}
+	}
+	return merged
+}
+
+// generateIndexes writes the indexes block: the composite primary key, if
+// primaryKey is not nil, then indexes.
+func generateIndexes(builder *strings.Builder, primaryKey []string, indexes []schema.Index, indexTypes bool) {
 	builder.WriteString("  indexes {\n")
+	if primaryKey != nil {
+		builder.WriteString(fmt.Sprintf("    (%s) [pk]\n", strings.Join(quoteColumns(primaryKey), ", ")))
+	}
 	for _, index := range indexes {
+		var settings []string
 		if index.Unique {
-			if len(index.Columns) == 1 {
-				builder.WriteString(fmt.Sprintf("    (%s) [unique]\n", index.Columns[0]))
-			} else {
-				builder.WriteString(fmt.Sprintf("    (%s) [unique]\n", strings.Join(index.Columns, ", ")))
+			settings = append(settings, "unique")
+		}
+		// Indexes are named, so the DDL can be rebuilt from the DBML
+		if index.Name != "" {
+			settings = append(settings, "name: "+quote(index.Name))
+		}
+		// DBML only has index types for btree and hash, and no syntax for
+		// constraints, covering indexes, partial indexes, or tablespaces, so
+		// UNIQUE constraints, other access methods (unless indexTypes is
+		// set), INCLUDE columns, WHERE predicates, and tablespaces go in a
+		// note.
+		var notes []string
+		if index.UniqueConstraint {
+			notes = append(notes, "UNIQUE constraint")
+		}
+		switch index.Type {
+		case "":
+		case "hash":
+			settings = append(settings, "type: hash")
+		default:
+			if indexTypes {
+				settings = append(settings, "type: "+index.Type)
+				break
 			}
+			notes = append(notes, "USING "+index.Type)
+		}
+		if len(index.Include) > 0 {
+			notes = append(notes, "INCLUDE ("+strings.Join(index.Include, ", ")+")")
+		}
+		if index.Where != "" {
+			notes = append(notes, "WHERE "+index.Where)
+		}
+		if index.Tablespace != "" {
+			notes = append(notes, "TABLESPACE "+index.Tablespace)
+		}
+		if len(notes) > 0 {
+			settings = append(settings, "note: "+quote(strings.Join(notes, "; ")))
+		}
+
+		indexColumns := quoteColumns(index.Columns)
+		columns := fmt.Sprintf("(%s)", strings.Join(indexColumns, ", "))
+		if len(indexColumns) == 1 && len(settings) == 0 {
+			columns = indexColumns[0]
+		}
+
+		if len(settings) > 0 {
+			builder.WriteString(fmt.Sprintf("    %s [%s]\n", columns, strings.Join(settings, ", ")))
 		} else {
-			if len(index.Columns) == 1 {
-				builder.WriteString(fmt.Sprintf("    %s\n", index.Columns[0]))
-			} else {
-				builder.WriteString(fmt.Sprintf("    (%s)\n", strings.Join(index.Columns, ", ")))
-			}
+			builder.WriteString(fmt.Sprintf("    %s\n", columns))
 		}
 	}
 	builder.WriteString("  }\n")
 }
 
-func generateReference(builder *strings.Builder, ref schema.Reference) {
-	fromTable := GetQualifiedTableName(ref.FromTable, ref.FromSchema)
-	toTable := GetQualifiedTableName(ref.ToTable, ref.ToSchema)
-
-	fromRef := fromTable
-	if len(ref.FromColumns) == 1 {
-		fromRef = fmt.Sprintf("%s.%s", fromTable, ref.FromColumns[0])
-	} else {
-		fromRef = fmt.Sprintf("%s.(%s)", fromTable, strings.Join(ref.FromColumns, ", "))
+// checkReferences returns an error for a reference without columns on
+// either side, which DBML cannot write.
+func checkReferences(s *schema.Schema) error {
+	for _, table := range s.Tables {
+		for _, ref := range table.References {
+			if len(ref.FromColumns) == 0 || len(ref.ToColumns) == 0 {
+				return fmt.Errorf("reference %q from %s to %s has no columns",
+					ref.Name, GetQualifiedTableName(table.Name, table.Schema), GetQualifiedTableName(ref.ToTable, ref.ToSchema))
+			}
+		}
 	}
+	return nil
+}
 
-	toRef := toTable
-	if len(ref.ToColumns) == 1 {
-		toRef = fmt.Sprintf("%s.%s", toTable, ref.ToColumns[0])
-	} else {
-		toRef = fmt.Sprintf("%s.(%s)", toTable, strings.Join(ref.ToColumns, ", "))
-	}
+func generateReference(builder *strings.Builder, ref schema.Reference, qualify func(name, schemaName string) string) {
+	fromRef := refTarget(ref.FromTable, ref.FromSchema, ref.FromColumns, qualify)
+	toRef := refTarget(ref.ToTable, ref.ToSchema, ref.ToColumns, qualify)
 
-	builder.WriteString(fmt.Sprintf("Ref: %s > %s", fromRef, toRef))
+	name := ""
+	if ref.Name != "" {
+		name = " " + quoteName(ref.Name)
+	}
+	relationship := ">"
+	if ref.Relationship != "" {
+		relationship = ref.Relationship
+	}
+	builder.WriteString(fmt.Sprintf("Ref%s: %s %s %s", name, fromRef, relationship, toRef))
 
 	var refAttributes []string
 	if ref.OnDelete != "NO ACTION" && ref.OnDelete != "" {
@@ -210,6 +798,69 @@ func generateReference(builder *strings.Builder, ref schema.Reference) {
 	builder.WriteString("\n")
 }
 
+// referenceClauses returns the match type and deferrability of a foreign
+// key, such as "MATCH FULL DEFERRABLE", or an empty string when both are at
+// their defaults.
+func referenceClauses(ref schema.Reference) string {
+	var clauses []string
+	if match := ref.MatchClause(); match != "" {
+		clauses = append(clauses, match)
+	}
+	if deferrable := ref.DeferrableClause(); deferrable != "" {
+		clauses = append(clauses, deferrable)
+	}
+	return strings.Join(clauses, " ")
+}
+
+// refTarget returns one end of a reference: table.column, or
+// table.(a, b) for composite keys.
+func refTarget(tableName, schemaName string, columns []string, qualify func(name, schemaName string) string) string {
+	table := qualify(tableName, schemaName)
+	if len(columns) == 1 {
+		return fmt.Sprintf("%s.%s", table, quoteName(columns[0]))
+	}
+	return fmt.Sprintf("%s.(%s)", table, strings.Join(quoteColumns(columns), ", "))
+}
+
+// writeNote writes the Note of a Table block. Multi-line text is written as a
+// Note block holding a triple-quoted string, one line of text per line, so it
+// reads as written. Triple-quoted strings cannot hold three quotes in a row,
+// and DBML tools disagree on backslashes in them, so text with either is
+// written single-quoted instead, with escaped line breaks.
+func writeNote(builder *strings.Builder, text string) {
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(text)
+	if !strings.Contains(text, "\n") || strings.Contains(text, "'''") || strings.Contains(text, `\`) {
+		builder.WriteString(fmt.Sprintf("  Note: %s\n", quote(text)))
+		return
+	}
+	builder.WriteString("  Note {\n    '''\n")
+	for _, line := range strings.Split(text, "\n") {
+		if line != "" {
+			builder.WriteString("    " + line)
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("    '''\n  }\n")
+}
+
+// quote returns s as a single-quoted DBML string. Line breaks, \r\n and \r
+// included, are written as \n so the string stays on one line.
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s) + "'"
+}
+
+// tableNamer returns how table and enum names are written: qualified with
+// their schema unless it is public, or, with qualifyPublic, with any schema,
+// and each part quoted with quoteName.
+func tableNamer(qualifyPublic bool) func(name, schemaName string) string {
+	return func(name, schemaName string) string {
+		if schemaName == "" || (schemaName == "public" && !qualifyPublic) {
+			return quoteName(name)
+		}
+		return quoteName(schemaName) + "." + quoteName(name)
+	}
+}
+
 // GetQualifiedTableName returns a table name with schema prefix if not "public".
 // For the public schema, returns just the table name.
 func GetQualifiedTableName(tableName, schemaName string) string {
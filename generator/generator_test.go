@@ -39,7 +39,7 @@ func TestGenerate(t *testing.T) {
 		"email varchar(255) [not null]",
 		"name varchar(100)",
 		"indexes {",
-		"(email) [unique]",
+		"(email) [unique, name: 'idx_users_email']",
 	}
 
 	for _, expected := range expectedContains {
@@ -49,6 +49,550 @@ func TestGenerate(t *testing.T) {
 	}
 }
 
+func TestGenerateIndexInclude(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:   "orders",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "customer_id", Type: "int"},
+					{Name: "created_at", Type: "timestamp"},
+					{Name: "total", Type: "decimal"},
+				},
+				Indexes: []schema.Index{
+					{Name: "idx_orders_customer", Columns: []string{"customer_id", "created_at"}, Include: []string{"total"}},
+					{Name: "idx_orders_created", Columns: []string{"created_at"}, Include: []string{"total"}, Unique: true},
+					{Name: "idx_orders_total", Columns: []string{"total"}, Type: "hash"},
+					{Name: "idx_orders_embedding", Columns: []string{"customer_id"}, Include: []string{"total"}, Type: "hnsw"},
+					{Name: "orders_customer_id_total_key", Columns: []string{"customer_id", "total"}, Unique: true, UniqueConstraint: true},
+					{Name: "idx_orders_open", Columns: []string{"created_at", "customer_id"}, Where: "(total > (0)::numeric)", Tablespace: "fast"},
+				},
+				Tablespace: "archive",
+			},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	expected := []string{
+		"    (customer_id, created_at) [name: 'idx_orders_customer', note: 'INCLUDE (total)']\n",
+		"    (created_at) [unique, name: 'idx_orders_created', note: 'INCLUDE (total)']\n",
+		"    (total) [name: 'idx_orders_total', type: hash]\n",
+		"    (customer_id) [name: 'idx_orders_embedding', note: 'USING hnsw; INCLUDE (total)']\n",
+		"    (customer_id, total) [unique, name: 'orders_customer_id_total_key', note: 'UNIQUE constraint']\n",
+		"    (created_at, customer_id) [name: 'idx_orders_open', note: 'WHERE (total > (0)::numeric); TABLESPACE fast']\n",
+		"  Note: 'TABLESPACE archive'\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, output)
+		}
+	}
+}
+
+func TestGenerateReferenceStyles(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:    "employees",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "id", Type: "int"}, {Name: "manager_id", Type: "int", Nullable: true}},
+				References: []schema.Reference{
+					{FromTable: "employees", FromSchema: "public", FromColumns: []string{"manager_id"}, ToTable: "employees", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+			{
+				Name:    "teams",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "id", Type: "int"}, {Name: "lead_id", Type: "int"}},
+				References: []schema.Reference{
+					{FromTable: "teams", FromSchema: "public", FromColumns: []string{"lead_id"}, ToTable: "members", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+			{
+				Name:    "members",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "id", Type: "int"}, {Name: "team_id", Type: "int"}, {Name: "employee_id", Type: "int"}},
+				References: []schema.Reference{
+					{FromTable: "members", FromSchema: "public", FromColumns: []string{"team_id"}, ToTable: "teams", ToSchema: "public", ToColumns: []string{"id"}},
+					{FromTable: "members", FromSchema: "public", FromColumns: []string{"employee_id"}, ToTable: "employees", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+		},
+	}
+
+	standard, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, ref := range []string{"Ref: employees.manager_id > employees.id", "Ref: teams.lead_id > members.id", "Ref: members.team_id > teams.id"} {
+		if !strings.Contains(standard, ref) {
+			t.Errorf("Expected default output to contain %q, got:\n%s", ref, standard)
+		}
+	}
+
+	output, err := GenerateString(s, WithSelfReferences(RefNote), WithCyclicReferences(RefOmit))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	if strings.Contains(output, "Ref: employees.manager_id") {
+		t.Errorf("Expected the self-reference to become a note, got:\n%s", output)
+	}
+	if !strings.Contains(output, "  manager_id int [note: 'References employees.id']\n") {
+		t.Errorf("Expected a note on manager_id, got:\n%s", output)
+	}
+	if strings.Contains(output, "Ref: teams.lead_id") || strings.Contains(output, "Ref: members.team_id") {
+		t.Errorf("Expected the cycle between teams and members to be omitted, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Ref: members.employee_id > employees.id") {
+		t.Errorf("Expected references outside the cycle to be kept, got:\n%s", output)
+	}
+}
+
+func TestGenerateViews(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}, {Name: "email", Type: "varchar"}}},
+			{Name: "orders", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}, {Name: "total", Type: "decimal"}}},
+		},
+		Views: []schema.View{
+			{
+				Name:       "user_totals",
+				Schema:     "public",
+				Columns:    []schema.Column{{Name: "email", Type: "varchar", Nullable: true}, {Name: "id", Type: "int", Nullable: true}, {Name: "total", Type: "decimal", Nullable: true}},
+				Definition: " SELECT u.id, u.email, o.total\n   FROM users u JOIN orders o ON o.id = u.id;",
+				Sources: []schema.ViewSource{
+					{Schema: "public", Table: "orders", Columns: []string{"id", "total"}},
+					{Schema: "public", Table: "users", Columns: []string{"email", "id"}},
+				},
+			},
+			{Name: "daily", Schema: "reporting", Materialized: true, Definition: "SELECT 1;"},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "Table user_totals {\n  email varchar\n  id int\n  total decimal\n\n  Note {\n    '''\n    VIEW: SELECT u.id, u.email, o.total\n       FROM users u JOIN orders o ON o.id = u.id;\n    '''\n  }\n}\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected view block %q, got:\n%s", expected, output)
+	}
+	if !strings.Contains(output, "Note: 'MATERIALIZED VIEW: SELECT 1;'") {
+		t.Errorf("Expected materialized view note, got:\n%s", output)
+	}
+	if strings.Index(output, "Table users") > strings.Index(output, "Table user_totals") {
+		t.Errorf("Expected views after tables, got:\n%s", output)
+	}
+	if strings.Contains(output, "Ref:") || strings.Contains(output, "Reads from") {
+		t.Errorf("Expected no lineage by default, got:\n%s", output)
+	}
+
+	output, err = GenerateString(s, WithViewReferences(RefStandard))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, ref := range []string{"Ref: user_totals.email > users.email\n", "Ref: user_totals.total > orders.total\n"} {
+		if !strings.Contains(output, ref) {
+			t.Errorf("Expected %q, got:\n%s", ref, output)
+		}
+	}
+	if strings.Contains(output, "Ref: user_totals.id") {
+		t.Errorf("Expected ambiguous column id to be skipped, got:\n%s", output)
+	}
+
+	output, err = GenerateString(s, WithViewReferences(RefNote))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "\n    Reads from: orders, users\n    '''\n") {
+		t.Errorf("Expected sources in the view note, got:\n%s", output)
+	}
+}
+
+func TestGenerateTableGroups(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "app", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "orders", Schema: "app", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		},
+		TableGroups: []schema.TableGroup{
+			{Name: "app", Tables: []schema.TableName{{Schema: "app", Name: "users"}, {Schema: "app", Name: "orders"}}},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "TableGroup app {\n  app.users\n  app.orders\n}\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected table group %q, got:\n%s", expected, output)
+	}
+}
+
+func TestGenerateSchemaGroups(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "accounts", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "events", Schema: "audit", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "logins", Schema: "audit", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		},
+		Views: []schema.View{{Name: "recent_events", Schema: "audit", Definition: "SELECT 1;"}},
+		TableGroups: []schema.TableGroup{
+			{Name: "audit", Tables: []schema.TableName{{Schema: "audit", Name: "logins"}}},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Contains(output, "TableGroup public") {
+		t.Errorf("Expected no schema groups without WithSchemaGroups, got:\n%s", output)
+	}
+
+	output, err = GenerateString(s, WithSchemaGroups())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "TableGroup audit {\n  audit.logins\n}\n\n" +
+		"TableGroup audit_2 {\n  audit.events\n  audit.recent_events\n}\n\n" +
+		"TableGroup public {\n  accounts\n  users\n}\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected table groups %q, got:\n%s", expected, output)
+	}
+
+	single := &schema.Schema{Tables: s.Tables[:2]}
+	output, err = GenerateString(single, WithSchemaGroups())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Contains(output, "TableGroup") {
+		t.Errorf("Expected no schema groups for a single schema, got:\n%s", output)
+	}
+}
+
+func TestGenerateAnnotations(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "invoices", Schema: "public", Color: "#e67e22", Comment: "Customer invoices", Columns: []schema.Column{{Name: "id", Type: "int", Nullable: true}}},
+			{Name: "legacy", Schema: "public", Deprecated: true, Comment: "Use payments", Columns: []schema.Column{
+				{Name: "fax", Type: "text", Nullable: true, Deprecated: true},
+			}},
+		},
+		TableGroups: []schema.TableGroup{
+			{Name: "Order Management", Tables: []schema.TableName{{Schema: "public", Name: "invoices"}}},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := []string{
+		"Table invoices [headercolor: #e67e22] {\n  id int\n}\n",
+		"  fax text [note: 'Deprecated']\n",
+		"  Note: 'Deprecated'\n",
+		"TableGroup \"Order Management\" {\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	output, err = GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  Note: 'Deprecated: Use payments'\n") {
+		t.Errorf("Expected the deprecation note to include the comment, got:\n%s", output)
+	}
+}
+
+func TestGenerateNotes(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "users",
+			Schema:  "public",
+			Comment: "Registered accounts",
+			Columns: []schema.Column{{Name: "email", Type: "varchar(255)", Comment: "Login address, it's unique"}},
+		}},
+		Views: []schema.View{{
+			Name:       "active_users",
+			Schema:     "public",
+			Comment:    "Users seen this month",
+			Definition: "SELECT email FROM users;",
+			Columns:    []schema.Column{{Name: "email", Type: "varchar(255)", Nullable: true, Comment: "Login address"}},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Contains(output, "Registered accounts") || strings.Contains(output, "Login address") || strings.Contains(output, "this month") {
+		t.Errorf("Expected no comments without WithNotes, got:\n%s", output)
+	}
+
+	output, err = GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := []string{
+		"  email varchar(255) [not null, note: 'Login address, it\\'s unique']\n",
+		"  Note: 'Registered accounts'\n",
+		"  email varchar(255) [note: 'Login address']\n",
+		"  Note {\n    '''\n    Users seen this month\n    VIEW: SELECT email FROM users;\n    '''\n  }\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGenerateCheckConstraints(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:   "bookings",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "starts_at", Type: "timestamp"},
+				{Name: "ends_at", Type: "timestamp"},
+				{Name: "seats", Type: "int"},
+			},
+			CheckConstraints: []schema.CheckConstraint{
+				{Name: "bookings_check", Columns: []string{"starts_at", "ends_at"}, Expression: "(ends_at > starts_at)"},
+				{Name: "bookings_seats_check", Columns: []string{"seats"}, Expression: "(seats > 0)"},
+			},
+			Comment: "Room reservations",
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := []string{
+		"  seats int [not null, note: 'CHECK (seats > 0)']\n",
+		"  Note: 'CHECK (ends_at > starts_at)'\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	output, err = GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  Note {\n    '''\n    Room reservations\n    CHECK (ends_at > starts_at)\n    '''\n  }\n") {
+		t.Errorf("Expected the table comment before the check, got:\n%s", output)
+	}
+}
+
+func TestGenerateUniqueConstraints(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:   "orders",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "customer_id", Type: "int"},
+				{Name: "number", Type: "int"},
+				{Name: "code", Type: "varchar"},
+			},
+			Indexes: []schema.Index{
+				{Name: "orders_customer_id_number_key", Columns: []string{"customer_id", "number"}, Unique: true},
+			},
+			UniqueConstraints: []schema.UniqueConstraint{
+				{Name: "orders_code_key", Columns: []string{"code"}},
+				{Name: "orders_customer_id_number_key", Columns: []string{"customer_id", "number"}},
+			},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := []string{
+		"    (code) [unique, name: 'orders_code_key', note: 'UNIQUE constraint']\n",
+		"    (customer_id, number) [unique, name: 'orders_customer_id_number_key', note: 'UNIQUE constraint']\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Count(output, "orders_customer_id_number_key") != 1 {
+		t.Errorf("Expected the constraint's index to be written once, got:\n%s", output)
+	}
+}
+
+func TestGenerateGeneratedColumns(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:   "line_items",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "price", Type: "decimal"},
+				{Name: "quantity", Type: "int"},
+				{Name: "total", Type: "decimal", Generated: "(price * (quantity)::numeric)", Comment: "Line total"},
+			},
+		}},
+	}
+
+	output, err := GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "  total decimal [not null, note: 'Line total; GENERATED ALWAYS AS (price * (quantity)::numeric) STORED']\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+	}
+}
+
+func TestGenerateCollations(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:   "users",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "email", Type: "text", Collation: "case_insensitive", Comment: "Login"},
+				{Name: "code", Type: "varchar(10)", Collation: `"C"`},
+				{Name: "name", Type: "text"},
+			},
+		}},
+	}
+
+	output, err := GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, expected := range []string{
+		"  email text [not null, note: 'Login; COLLATE case_insensitive']\n",
+		"  code varchar(10) [not null, note: 'COLLATE \"C\"']\n",
+		"  name text [not null]\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestGenerateIdentityColumns(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:   "events",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "id", Type: "bigint", IsPrimaryKey: true, Identity: "ALWAYS", AutoIncrement: true},
+				{Name: "seq", Type: "int", Identity: "BY DEFAULT", AutoIncrement: true},
+			},
+			PrimaryKeys: []string{"id"},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{"  id bigint [pk, increment]\n", "  seq int [not null, increment]\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGenerateForeignTables(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "remote_users",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "id", Type: "int"}},
+			Foreign: &schema.ForeignTable{Server: "crm", Options: []string{"schema_name=public", "table_name=users"}},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  Note: 'FOREIGN TABLE on server crm (schema_name=public, table_name=users)'\n") {
+		t.Errorf("Expected the foreign server in the table note, got:\n%s", output)
+	}
+}
+
+func TestGenerateInheritance(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:     "cars",
+			Schema:   "public",
+			Columns:  []schema.Column{{Name: "doors", Type: "int"}},
+			Inherits: []schema.TableName{{Schema: "public", Name: "vehicles"}, {Schema: "audit", Name: "tracked"}},
+			Comment:  "Road vehicles",
+		}},
+	}
+
+	output, err := GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  Note {\n    '''\n    Road vehicles\n    INHERITS (vehicles, audit.tracked)\n    '''\n  }\n") {
+		t.Errorf("Expected the parents in the table note, got:\n%s", output)
+	}
+}
+
+func TestGeneratePartitions(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:         "events",
+			Schema:       "public",
+			Columns:      []schema.Column{{Name: "created_at", Type: "timestamptz"}},
+			PartitionKey: "RANGE (created_at)",
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  Note: 'PARTITION BY RANGE (created_at)'\n") {
+		t.Errorf("Expected the partition key in the table note, got:\n%s", output)
+	}
+
+	s.Tables[0].Partitions = []schema.Partition{
+		{Name: "events_2024_01", Schema: "public", Bound: "FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')"},
+		{Name: "events_default", Schema: "archive", Bound: "DEFAULT"},
+	}
+	output, err = GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "  Note {\n    '''\n    PARTITION BY RANGE (created_at)\n    Partition events_2024_01 FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')\n    Partition archive.events_default DEFAULT\n    '''\n  }\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+	}
+}
+
+func TestParseRefStyle(t *testing.T) {
+	if style, err := ParseRefStyle("note"); err != nil || style != RefNote {
+		t.Errorf("ParseRefStyle(note) = %v, %v", style, err)
+	}
+	if _, err := ParseRefStyle("hide"); err == nil {
+		t.Error("Expected error for unknown style")
+	}
+}
+
 func TestGenerateWithReferences(t *testing.T) {
 	s := &schema.Schema{
 		Tables: []schema.Table{
@@ -82,15 +626,165 @@ func TestGenerateWithReferences(t *testing.T) {
 		},
 	}
 
-	result, err := Generate(s)
+	result, err := Generate(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	dbml := string(result)
+
+	if !strings.Contains(dbml, "Ref: posts.user_id > users.id [delete: cascade]") {
+		t.Errorf("Generated DBML missing expected reference: %s", dbml)
+	}
+}
+
+func TestGenerateQualifyPublicSchema(t *testing.T) {
+	s := &schema.Schema{
+		Enums: []schema.Enum{{Name: "mood", Schema: "public", Values: []string{"happy"}}},
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}, {Name: "mood", Type: "text", DatabaseType: "mood"}}},
+			{
+				Name:       "invoices",
+				Schema:     "billing",
+				Columns:    []schema.Column{{Name: "user_id", Type: "int"}},
+				References: []schema.Reference{{FromTable: "invoices", FromSchema: "billing", FromColumns: []string{"user_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}}},
+			},
+		},
+		TableGroups: []schema.TableGroup{{Name: "core", Tables: []schema.TableName{{Schema: "public", Name: "users"}}}},
+	}
+
+	output, err := GenerateString(s, WithQualifyPublicSchema())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{
+		"Enum public.mood {\n",
+		"Table public.users {\n",
+		"  mood public.mood [not null]\n",
+		"TableGroup core {\n  public.users\n}\n",
+		"Ref: billing.invoices.user_id > public.users.id\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGenerateCompositePrimaryKey(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:   "order_items",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "order_id", Type: "int", IsPrimaryKey: true},
+				{Name: "product_id", Type: "int", IsPrimaryKey: true},
+				{Name: "note", Type: "text", Nullable: true},
+			},
+			PrimaryKeys: []string{"order_id", "product_id"},
+			Indexes:     []schema.Index{{Name: "idx_order_items_product", Columns: []string{"product_id"}}},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	want := "Table order_items {\n" +
+		"  order_id int [not null]\n" +
+		"  product_id int [not null]\n" +
+		"  note text\n" +
+		"\n" +
+		"  indexes {\n" +
+		"    (order_id, product_id) [pk]\n" +
+		"    (product_id) [name: 'idx_order_items_product']\n" +
+		"  }\n" +
+		"}\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected the composite primary key in the indexes block %q, got:\n%s", want, output)
+	}
+}
+
+func TestGenerateCompositeReference(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "accounts", Schema: "public", Columns: []schema.Column{{Name: "tenant_id", Type: "int"}, {Name: "id", Type: "int"}}},
+			{
+				Name:    "invoices",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "tenant_id", Type: "int"}, {Name: "account_id", Type: "int"}},
+				References: []schema.Reference{{
+					Name:        "invoices_account_fkey",
+					FromTable:   "invoices",
+					FromSchema:  "public",
+					FromColumns: []string{"tenant_id", "account_id"},
+					ToTable:     "accounts",
+					ToSchema:    "public",
+					ToColumns:   []string{"tenant_id", "id"},
+					OnDelete:    "CASCADE",
+				}},
+			},
+		},
+	}
+
+	output, err := GenerateString(s)
 	if err != nil {
 		t.Fatalf("Generate returned error: %v", err)
 	}
+	want := "Ref invoices_account_fkey: invoices.(tenant_id, account_id) > accounts.(tenant_id, id) [delete: cascade]\n"
+	if !strings.Contains(output, want) || strings.Count(output, "Ref ") != 1 {
+		t.Errorf("Expected one composite Ref %q, got:\n%s", want, output)
+	}
+}
 
-	dbml := string(result)
+func TestGenerateReferenceWithoutColumns(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:   "posts",
+			Schema: "public",
+			References: []schema.Reference{{
+				Name:      "posts_user_id_fkey",
+				FromTable: "posts",
+				ToTable:   "users",
+				ToSchema:  "public",
+				ToColumns: []string{"id"},
+			}},
+		}},
+	}
 
-	if !strings.Contains(dbml, "Ref: posts.user_id > users.id [delete: cascade]") {
-		t.Errorf("Generated DBML missing expected reference: %s", dbml)
+	if _, err := GenerateString(s); err == nil || !strings.Contains(err.Error(), "posts_user_id_fkey") {
+		t.Errorf("Expected an error naming the reference, got %v", err)
+	}
+}
+
+func TestGenerateReferenceNames(t *testing.T) {
+	ref := func(name, from string) schema.Reference {
+		return schema.Reference{Name: name, FromTable: from, FromSchema: "public", FromColumns: []string{"user_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}}
+	}
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}},
+			{Name: "posts", Schema: "public", References: []schema.Reference{ref("fk_posts_user", "posts")}},
+			{Name: "comments", Schema: "public", References: []schema.Reference{ref("fk_user", "comments")}},
+			{Name: "likes", Schema: "public", References: []schema.Reference{ref("fk_user", "likes")}},
+			{Name: "shares", Schema: "public", References: []schema.Reference{ref("Shares User", "shares")}},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	expected := []string{
+		"Ref fk_user: comments.user_id > users.id\n",
+		"Ref: likes.user_id > users.id\n",
+		"Ref fk_posts_user: posts.user_id > users.id\n",
+		"Ref \"Shares User\": shares.user_id > users.id\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, output)
+		}
 	}
 }
 
@@ -191,13 +885,15 @@ func TestGenerateWithDefaultValue(t *testing.T) {
 
 func TestGenerateWithAutoIncrement(t *testing.T) {
 	defaultVal := "nextval('users_id_seq')"
+	shared := "nextval('ticket_numbers')"
 	s := &schema.Schema{
 		Tables: []schema.Table{
 			{
 				Name:   "users",
 				Schema: "public",
 				Columns: []schema.Column{
-					{Name: "id", Type: "int", IsPrimaryKey: true, DefaultValue: &defaultVal},
+					{Name: "id", Type: "int", IsPrimaryKey: true, DefaultValue: &defaultVal, AutoIncrement: true},
+					{Name: "ticket", Type: "bigint", DefaultValue: &shared},
 				},
 				PrimaryKeys: []string{"id"},
 			},
@@ -211,7 +907,521 @@ func TestGenerateWithAutoIncrement(t *testing.T) {
 
 	dbml := string(result)
 
-	if !strings.Contains(dbml, "increment") {
+	if !strings.Contains(dbml, "id int [pk, increment]") {
 		t.Errorf("Generated DBML missing increment attribute: %s", dbml)
 	}
+	if !strings.Contains(dbml, "ticket bigint [not null, default: `nextval('ticket_numbers')`]") {
+		t.Errorf("Expected a shared sequence to stay a default: %s", dbml)
+	}
+}
+
+func TestGenerateWithMigration(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		},
+		Migration: &schema.Migration{Tool: "goose", Table: "goose_db_version", Version: "20240101120000"},
+	}
+
+	result, err := Generate(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	expected := "Project {\n" +
+		"  database_type: 'PostgreSQL'\n" +
+		"  Note: 'Migration version 20240101120000 (goose, goose_db_version)'\n" +
+		"}\n\n" +
+		"Table users {"
+	if !strings.HasPrefix(string(result), expected) {
+		t.Errorf("Expected Project note before tables, got:\n%s", result)
+	}
+}
+
+func TestGenerateWithoutMigration(t *testing.T) {
+	result, err := GenerateString(&schema.Schema{})
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Contains(result, "Project") {
+		t.Errorf("Expected no Project block without a migration, got:\n%s", result)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", `'plain'`},
+		{"it's", `'it\'s'`},
+		{`back\slash`, `'back\\slash'`},
+		{"two\nlines", `'two\nlines'`},
+		{"windows\r\nlines", `'windows\nlines'`},
+		{"old mac\rlines", `'old mac\nlines'`},
+	}
+
+	for _, tt := range tests {
+		if result := quote(tt.input); result != tt.expected {
+			t.Errorf("quote(%q) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestWriteNote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"one line", "  Note: 'one line'\n"},
+		{"two\r\nlines", "  Note {\n    '''\n    two\n    lines\n    '''\n  }\n"},
+		{"gap\n\nbetween", "  Note {\n    '''\n    gap\n\n    between\n    '''\n  }\n"},
+		{"match\n~ '^\\d+$'", "  Note: 'match\\n~ \\'^\\\\d+$\\''\n"},
+		{"quotes\n'''", "  Note: 'quotes\\n\\'\\'\\''\n"},
+	}
+
+	for _, tt := range tests {
+		var builder strings.Builder
+		writeNote(&builder, tt.input)
+		if result := builder.String(); result != tt.expected {
+			t.Errorf("writeNote(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	s := &schema.Schema{
+		Enums: []schema.Enum{{Name: "mood", Schema: "public", Values: []string{"happy", "sad"}}},
+		Tables: []schema.Table{{
+			Name:   "documents",
+			Schema: "public",
+			Indexes: []schema.Index{
+				{Name: "idx_documents_owner", Columns: []string{"owner_id"}},
+				{Name: "idx_documents_key", Columns: []string{"key"}, Type: "hash"},
+				{Name: "idx_documents_tags", Columns: []string{"tags"}, Type: "gin"},
+			},
+		}},
+	}
+
+	diagnostics := Diagnose(s)
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %+v", diagnostics)
+	}
+	if got := diagnostics[0].String(); got != "public.documents.idx_documents_tags: index type gin has no DBML equivalent and was written as a note (unsupported)" {
+		t.Errorf("Unexpected diagnostic: %s", got)
+	}
+
+	if diagnostics := Diagnose(s, WithIndexTypes()); len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics with WithIndexTypes, got %+v", diagnostics)
+	}
+}
+
+func TestGenerateIndexTypes(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "documents",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "tags", Type: "text[]"}},
+			Indexes: []schema.Index{
+				{Name: "idx_documents_tags", Columns: []string{"tags"}, Type: "gin"},
+				{Name: "idx_documents_key", Columns: []string{"key"}, Type: "hash"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "default",
+			want: []string{"(key) [name: 'idx_documents_key', type: hash]", "(tags) [name: 'idx_documents_tags', note: 'USING gin']"},
+		},
+		{
+			name: "index types",
+			opts: []Option{WithIndexTypes()},
+			want: []string{"(key) [name: 'idx_documents_key', type: hash]", "(tags) [name: 'idx_documents_tags', type: gin]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := Generate(s, tt.opts...)
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateEnums(t *testing.T) {
+	s := &schema.Schema{
+		Enums: []schema.Enum{
+			{Name: "status", Schema: "billing", Values: []string{"open", "paid"}},
+			{Name: "mood", Schema: "public", Values: []string{"happy", "so so", `say "hi"`}},
+		},
+		Tables: []schema.Table{{
+			Name:   "users",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "id", Type: "int", IsPrimaryKey: true},
+				{Name: "mood", Type: "text", DatabaseType: "mood", Nullable: true},
+				{Name: "moods", Type: "text", DatabaseType: "mood[]", Nullable: true},
+				{Name: "status", Type: "text", DatabaseType: "billing.status"},
+				{Name: "bio", Type: "text", DatabaseType: "text", Nullable: true},
+			},
+		}},
+		Views: []schema.View{{
+			Name:    "user_moods",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "mood", Type: "text", DatabaseType: "mood", Nullable: true}},
+		}},
+	}
+
+	result, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	expectedContains := []string{
+		"Enum billing.status {\n  open\n  paid\n}\n",
+		"Enum mood {\n  happy\n  \"so so\"\n  \"say \\\"hi\\\"\"\n}\n",
+		"  mood mood\n",
+		"  moods mood[]\n",
+		"  status billing.status [not null]\n",
+		"  bio text\n",
+		"Table user_moods {\n  mood mood\n",
+	}
+	for _, expected := range expectedContains {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+	if strings.Index(result, "Enum billing.status") > strings.Index(result, "Enum mood") {
+		t.Errorf("Expected enums sorted by schema, got:\n%s", result)
+	}
+	if strings.Index(result, "Enum mood") > strings.Index(result, "Table users") {
+		t.Errorf("Expected enums before tables, got:\n%s", result)
+	}
+}
+
+func TestGenerateDomains(t *testing.T) {
+	s := &schema.Schema{
+		Domains: []schema.Domain{{
+			Name:             "quantity",
+			Schema:           "public",
+			BaseType:         "integer",
+			CheckConstraints: []schema.CheckConstraint{{Name: "quantity_check", Expression: "(VALUE > 0)"}},
+		}},
+		Tables: []schema.Table{{
+			Name:    "line_items",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "quantity", Type: "int", DatabaseType: "integer", Domain: "quantity"}},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  quantity int [not null, note: 'DOMAIN quantity; CHECK (VALUE > 0)']\n") {
+		t.Errorf("Expected the base type with the domain in the note, got:\n%s", output)
+	}
+
+	output, err = GenerateString(s, WithDomainTypes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  quantity quantity [not null]\n") {
+		t.Errorf("Expected the domain name as the type, got:\n%s", output)
+	}
+}
+
+func TestGenerateExclusionConstraints(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "bookings",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "during", Type: "tstzrange"}, {Name: "room_id", Type: "int"}},
+			ExclusionConstraints: []schema.ExclusionConstraint{{
+				Name:       "bookings_no_overlap",
+				Definition: "EXCLUDE USING gist (room_id WITH =, during WITH &&)",
+			}},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  Note: 'CONSTRAINT bookings_no_overlap EXCLUDE USING gist (room_id WITH =, during WITH &&)'\n") {
+		t.Errorf("Expected the exclusion constraint in the table note, got:\n%s", output)
+	}
+}
+
+func TestGenerateRowSecurity(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:        "documents",
+			Schema:      "public",
+			Columns:     []schema.Column{{Name: "tenant_id", Type: "int"}},
+			RowSecurity: true,
+			Policies: []schema.Policy{
+				{Name: "tenant_isolation", Command: "ALL", Permissive: true, Roles: []string{"app_user"}, Using: "(tenant_id = current_tenant())"},
+				{Name: "no_archived", Command: "SELECT", Roles: []string{"public"}},
+			},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "  Note {\n    '''\n    ROW LEVEL SECURITY\n    POLICY tenant_isolation FOR ALL TO app_user\n    POLICY no_archived FOR SELECT TO public AS RESTRICTIVE\n    '''\n  }\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected row level security in the table note, got:\n%s", output)
+	}
+
+	s.Tables[0].RowSecurity = false
+	s.Tables[0].Policies = nil
+	output, err = GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Contains(output, "ROW LEVEL SECURITY") {
+		t.Errorf("Expected no note for a table without row level security, got:\n%s", output)
+	}
+}
+
+func TestGenerateTriggers(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "users",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "id", Type: "int"}},
+			Triggers: []schema.Trigger{
+				{Name: "users_audit", Timing: "AFTER", Events: []string{"INSERT", "UPDATE"}, ForEach: "ROW", Function: "audit.log_change", Enabled: true},
+				{Name: "users_touch", Timing: "BEFORE", Events: []string{"UPDATE"}, ForEach: "ROW", Function: "touch_updated_at"},
+			},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "  Note {\n    '''\n" +
+		"    TRIGGER users_audit AFTER INSERT OR UPDATE FOR EACH ROW EXECUTE FUNCTION audit.log_change()\n" +
+		"    TRIGGER users_touch BEFORE UPDATE FOR EACH ROW EXECUTE FUNCTION touch_updated_at() (disabled)\n" +
+		"    '''\n  }\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected the triggers in the table note, got:\n%s", output)
+	}
+}
+
+func TestGenerateGrants(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "payments",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "id", Type: "int"}},
+			Grants: []schema.Grant{
+				{Grantee: "PUBLIC", Privileges: []string{"SELECT"}},
+				{Grantee: "billing", Privileges: []string{"SELECT", "INSERT", "UPDATE"}},
+			},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "  Note {\n    '''\n    GRANT SELECT TO PUBLIC\n    GRANT SELECT, INSERT, UPDATE TO billing\n    '''\n  }\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected the grants in the table note, got:\n%s", output)
+	}
+}
+
+func TestGenerateOwners(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:       "payments",
+			Schema:     "public",
+			Columns:    []schema.Column{{Name: "id", Type: "int"}},
+			Owner:      "billing",
+			Tablespace: "archive",
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Contains(output, "OWNER") {
+		t.Errorf("Expected no owner without WithOwners, got:\n%s", output)
+	}
+
+	output, err = GenerateString(s, WithOwners())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "  Note {\n    '''\n    OWNER billing\n    TABLESPACE archive\n    '''\n  }\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected the owner in the table note, got:\n%s", output)
+	}
+}
+
+func TestGenerateWithoutColumnNotes(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "users",
+			Schema:  "public",
+			Comment: "People",
+			Columns: []schema.Column{
+				{Name: "id", Type: "int", IsPrimaryKey: true},
+				{Name: "email", Type: "varchar", Comment: "Primary 'work' address\r\nLowercased", Collation: "und-x-icu"},
+			},
+		}},
+		Views: []schema.View{{
+			Name:    "active_users",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "email", Type: "varchar", Nullable: true, Comment: "From users"}},
+		}},
+	}
+
+	output, err := GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "  email varchar [not null, note: 'Primary \\'work\\' address\\nLowercased; COLLATE und-x-icu']\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected %q, got:\n%s", expected, output)
+	}
+
+	output, err = GenerateString(s, WithNotes(), WithoutColumnNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, line := range []string{"  email varchar [not null]\n", "  email varchar\n", "  Note: 'People'\n"} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected %q, got:\n%s", line, output)
+		}
+	}
+	if strings.Contains(output, "note:") {
+		t.Errorf("Expected no column notes, got:\n%s", output)
+	}
+}
+
+func TestGenerateDeferrableReferences(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:    "employees",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "id", Type: "int"}, {Name: "manager_id", Type: "int"}},
+				References: []schema.Reference{
+					{FromTable: "employees", FromSchema: "public", FromColumns: []string{"manager_id"}, ToTable: "employees", ToSchema: "public", ToColumns: []string{"id"}, Deferrable: true},
+				},
+			},
+			{
+				Name:    "orders",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "employee_id", Type: "int"}, {Name: "reviewer_id", Type: "int"}},
+				References: []schema.Reference{
+					{FromTable: "orders", FromSchema: "public", FromColumns: []string{"employee_id"}, ToTable: "employees", ToSchema: "public", ToColumns: []string{"id"}, Deferrable: true, InitiallyDeferred: true},
+					{FromTable: "orders", FromSchema: "public", FromColumns: []string{"reviewer_id"}, ToTable: "employees", ToSchema: "public", ToColumns: []string{"id"}, Match: "FULL"},
+				},
+			},
+		},
+	}
+
+	output, err := GenerateString(s, WithSelfReferences(RefNote))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{
+		"  manager_id int [not null, note: 'References employees.id DEFERRABLE']\n",
+		"  employee_id int [not null, note: 'DEFERRABLE INITIALLY DEFERRED']\n",
+		"  reviewer_id int [not null, note: 'MATCH FULL']\n",
+		"Ref: orders.employee_id > employees.id\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGenerateStatistics(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:       "events",
+				Schema:     "public",
+				Columns:    []schema.Column{{Name: "id", Type: "int"}},
+				Statistics: &schema.TableStatistics{RowEstimate: 120000, TotalBytes: 3 * 1024 * 1024},
+			},
+			{
+				Name:    "users",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "id", Type: "int"}},
+			},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  Note: 'STATISTICS ~120000 rows, 3.0 MiB'\n") {
+		t.Errorf("Expected the statistics in the table note, got:\n%s", output)
+	}
+	if strings.Count(output, "STATISTICS") != 1 {
+		t.Errorf("Expected no statistics note without statistics, got:\n%s", output)
+	}
+}
+
+func TestGenerateQuotedIdentifiers(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:   "order",
+				Schema: "Sales",
+				Columns: []schema.Column{
+					{Name: "id", Type: "int", IsPrimaryKey: true},
+					{Name: "First Name", Type: "text", Nullable: true},
+					{Name: "userId", Type: "int", Nullable: true},
+				},
+				Indexes: []schema.Index{
+					{Name: "idx_order_name", Columns: []string{"First Name", "`lower(\"First Name\")`"}},
+				},
+				References: []schema.Reference{
+					{FromTable: "order", FromSchema: "Sales", FromColumns: []string{"userId"}, ToTable: "user", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+			{Name: "user", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := []string{
+		"Table \"Sales\".\"order\" {\n",
+		"  \"First Name\" text\n",
+		"  \"userId\" int\n",
+		"    (\"First Name\", `lower(\"First Name\")`) [name: 'idx_order_name']\n",
+		"Table \"user\" {\n",
+		"Ref: \"Sales\".\"order\".\"userId\" > \"user\".id\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, output)
+		}
+	}
 }
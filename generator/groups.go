@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// schemaGroups returns a TableGroup for each database schema, listing its
+// tables and views in the order given, when they span more than one schema.
+// Groups are ordered by schema with less and named after it, suffixed if one
+// of groups already has that name. DBML allows a table in only one group, so
+// tables already in one of groups are left out.
+func schemaGroups(tables []schema.Table, views []schema.View, groups []schema.TableGroup, less func(a, b string) bool) []schema.TableGroup {
+	grouped := make(map[schema.TableName]bool)
+	names := make(map[string]bool)
+	for _, group := range groups {
+		names[group.Name] = true
+		for _, t := range group.Tables {
+			grouped[qualified(t)] = true
+		}
+	}
+
+	var order []string
+	bySchema := make(map[string][]schema.TableName)
+	add := func(name schema.TableName) {
+		name = qualified(name)
+		if _, ok := bySchema[name.Schema]; !ok {
+			order = append(order, name.Schema)
+			bySchema[name.Schema] = nil
+		}
+		if !grouped[name] {
+			bySchema[name.Schema] = append(bySchema[name.Schema], name)
+		}
+	}
+	for _, table := range tables {
+		add(schema.TableName{Schema: table.Schema, Name: table.Name})
+	}
+	for _, view := range views {
+		add(schema.TableName{Schema: view.Schema, Name: view.Name})
+	}
+	if len(order) < 2 {
+		return nil
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return less(order[i], order[j])
+	})
+
+	var result []schema.TableGroup
+	for _, schemaName := range order {
+		if len(bySchema[schemaName]) == 0 {
+			continue
+		}
+		name := schemaName
+		for i := 2; names[name]; i++ {
+			name = fmt.Sprintf("%s_%d", schemaName, i)
+		}
+		names[name] = true
+		result = append(result, schema.TableGroup{Name: name, Tables: bySchema[schemaName]})
+	}
+	return result
+}
+
+// qualified returns name with an empty schema spelled public.
+func qualified(name schema.TableName) schema.TableName {
+	if name.Schema == "" {
+		name.Schema = "public"
+	}
+	return name
+}
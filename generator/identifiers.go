@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// plainIdentifier matches the names written without quotes: lowercase, so
+// that tools creating tables from the DBML keep the name's case, and
+// otherwise what DBML accepts bare.
+var plainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// reservedWords are PostgreSQL's reserved keywords, which are quoted even
+// though they are plain identifiers, since dbdiagram and the SQL it exports
+// reject them bare.
+var reservedWords = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true,
+	"as": true, "asc": true, "asymmetric": true, "both": true, "case": true, "cast": true,
+	"check": true, "collate": true, "column": true, "constraint": true, "create": true,
+	"current_catalog": true, "current_date": true, "current_role": true, "current_time": true,
+	"current_timestamp": true, "current_user": true, "default": true, "deferrable": true,
+	"desc": true, "distinct": true, "do": true, "else": true, "end": true, "except": true,
+	"false": true, "fetch": true, "for": true, "foreign": true, "from": true, "grant": true,
+	"group": true, "having": true, "in": true, "initially": true, "intersect": true,
+	"into": true, "lateral": true, "leading": true, "limit": true, "localtime": true,
+	"localtimestamp": true, "not": true, "null": true, "offset": true, "on": true,
+	"only": true, "or": true, "order": true, "placing": true, "primary": true,
+	"references": true, "returning": true, "select": true, "session_user": true,
+	"some": true, "symmetric": true, "table": true, "then": true, "to": true,
+	"trailing": true, "true": true, "union": true, "unique": true, "user": true,
+	"using": true, "variadic": true, "when": true, "where": true, "window": true, "with": true,
+}
+
+// quoteName returns name as written in DBML: as is if it is a plain
+// identifier that is not a reserved word, or double-quoted otherwise.
+func quoteName(name string) string {
+	if plainIdentifier.MatchString(name) && !reservedWords[name] {
+		return name
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`
+}
+
+// quoteColumns returns columns as written in a column list, each quoted
+// with quoteName unless it is a backticked expression.
+func quoteColumns(columns []string) []string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		if strings.HasPrefix(column, "`") {
+			result[i] = column
+			continue
+		}
+		result[i] = quoteName(column)
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// junctions returns the pure junction tables in tables, keyed by their
+// qualified name: tables with two foreign keys whose columns form the
+// primary key, no other columns but ones with a default (such as
+// created_at), and no foreign keys referencing them. Each stands for a
+// many-to-many relationship between the tables its foreign keys reference.
+func junctions(tables []schema.Table) map[schema.TableName]schema.Table {
+	referenced := make(map[schema.TableName]bool)
+	for _, table := range tables {
+		for _, ref := range table.References {
+			if ref.ToTable != table.Name || ref.ToSchema != table.Schema {
+				referenced[qualified(schema.TableName{Schema: ref.ToSchema, Name: ref.ToTable})] = true
+			}
+		}
+	}
+
+	result := make(map[schema.TableName]schema.Table)
+	for _, table := range tables {
+		name := qualified(schema.TableName{Schema: table.Schema, Name: table.Name})
+		if !referenced[name] && isJunction(table) {
+			result[name] = table
+		}
+	}
+	return result
+}
+
+// isJunction reports whether table has exactly two foreign keys, with
+// distinct columns that together are its primary key, and every other
+// column has a default.
+func isJunction(table schema.Table) bool {
+	if len(table.References) != 2 || len(table.PrimaryKeys) == 0 {
+		return false
+	}
+	keyColumns := make(map[string]bool)
+	for _, ref := range table.References {
+		if len(ref.FromColumns) == 0 {
+			return false
+		}
+		for _, column := range ref.FromColumns {
+			if keyColumns[column] {
+				return false
+			}
+			keyColumns[column] = true
+		}
+	}
+	if len(keyColumns) != len(table.PrimaryKeys) {
+		return false
+	}
+	for _, column := range table.PrimaryKeys {
+		if !keyColumns[column] {
+			return false
+		}
+	}
+	for _, column := range table.Columns {
+		if !keyColumns[column.Name] && column.DefaultValue == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// manyToManyRefs returns a "<>" Ref line for each junction table, between
+// the columns its foreign keys reference. Each is named after its junction
+// table unless that name is in named, which it is added to.
+func manyToManyRefs(junctions map[schema.TableName]schema.Table, named map[string]bool, qualify func(name, schemaName string) string, less func(a, b string) bool) []string {
+	names := make([]schema.TableName, 0, len(junctions))
+	for name := range junctions {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Schema != names[j].Schema {
+			return less(names[i].Schema, names[j].Schema)
+		}
+		return less(names[i].Name, names[j].Name)
+	})
+
+	var refs []string
+	for _, name := range names {
+		table := junctions[name]
+		left, right := table.References[0], table.References[1]
+		label := ""
+		if !named[table.Name] {
+			named[table.Name] = true
+			label = " " + quoteName(table.Name)
+		}
+		refs = append(refs, fmt.Sprintf("Ref%s: %s <> %s\n", label,
+			refTarget(left.ToTable, left.ToSchema, left.ToColumns, qualify),
+			refTarget(right.ToTable, right.ToSchema, right.ToColumns, qualify)))
+	}
+	return refs
+}
+
+// withoutTables returns a copy of groups without the tables in hidden,
+// dropping groups left empty.
+func withoutTables(groups []schema.TableGroup, hidden map[schema.TableName]schema.Table) []schema.TableGroup {
+	if len(hidden) == 0 {
+		return groups
+	}
+	var result []schema.TableGroup
+	for _, group := range groups {
+		var tables []schema.TableName
+		for _, t := range group.Tables {
+			if _, ok := hidden[qualified(t)]; !ok {
+				tables = append(tables, t)
+			}
+		}
+		if len(tables) > 0 {
+			group.Tables = tables
+			result = append(result, group)
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerateManyToMany(t *testing.T) {
+	now := "now()"
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "posts", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}, PrimaryKeys: []string{"id"}},
+			{Name: "tags", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}, PrimaryKeys: []string{"id"}},
+			{
+				Name:   "post_tags",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "post_id", Type: "int", IsPrimaryKey: true},
+					{Name: "tag_id", Type: "int", IsPrimaryKey: true},
+					{Name: "created_at", Type: "timestamp", DefaultValue: &now},
+				},
+				PrimaryKeys: []string{"post_id", "tag_id"},
+				References: []schema.Reference{
+					{Name: "post_tags_post_id_fkey", FromTable: "post_tags", FromSchema: "public", FromColumns: []string{"post_id"}, ToTable: "posts", ToSchema: "public", ToColumns: []string{"id"}},
+					{Name: "post_tags_tag_id_fkey", FromTable: "post_tags", FromSchema: "public", FromColumns: []string{"tag_id"}, ToTable: "tags", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+			{
+				// The weight column makes this an entity of its own
+				Name:   "post_ratings",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "post_id", Type: "int", IsPrimaryKey: true},
+					{Name: "tag_id", Type: "int", IsPrimaryKey: true},
+					{Name: "weight", Type: "int"},
+				},
+				PrimaryKeys: []string{"post_id", "tag_id"},
+				References: []schema.Reference{
+					{FromTable: "post_ratings", FromSchema: "public", FromColumns: []string{"post_id"}, ToTable: "posts", ToSchema: "public", ToColumns: []string{"id"}},
+					{FromTable: "post_ratings", FromSchema: "public", FromColumns: []string{"tag_id"}, ToTable: "tags", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+		},
+		TableGroups: []schema.TableGroup{{Name: "blog", Tables: []schema.TableName{{Schema: "public", Name: "post_tags"}, {Schema: "public", Name: "posts"}}}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Contains(output, "<>") {
+		t.Errorf("Expected no many-to-many Ref by default, got:\n%s", output)
+	}
+
+	output, err = GenerateString(s, WithManyToMany())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{
+		"Table post_tags {\n",
+		"Ref post_tags: posts.id <> tags.id\n",
+		"Ref: post_ratings.post_id > posts.id\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "post_tags_post_id_fkey") {
+		t.Errorf("Expected the junction table's foreign keys to be replaced, got:\n%s", output)
+	}
+
+	output, err = GenerateString(s, WithHiddenJunctionTables())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Contains(output, "Table post_tags") || !strings.Contains(output, "TableGroup blog {\n  posts\n}\n") {
+		t.Errorf("Expected the junction table to be hidden, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Ref post_tags: posts.id <> tags.id\n") {
+		t.Errorf("Expected a many-to-many Ref, got:\n%s", output)
+	}
+}
@@ -0,0 +1,250 @@
+package generator
+
+import "fmt"
+
+// RefStyle controls how a kind of foreign key is rendered.
+type RefStyle int
+
+const (
+	// RefStandard renders the foreign key as a Ref line.
+	RefStandard RefStyle = iota
+	// RefNote replaces the Ref line with a note on the referencing column,
+	// keeping the relationship documented without drawing it.
+	RefNote
+	// RefOmit leaves the foreign key out.
+	RefOmit
+)
+
+// ParseRefStyle parses "ref", "note", or "omit".
+func ParseRefStyle(s string) (RefStyle, error) {
+	switch s {
+	case "ref":
+		return RefStandard, nil
+	case "note":
+		return RefNote, nil
+	case "omit":
+		return RefOmit, nil
+	default:
+		return RefStandard, fmt.Errorf("invalid reference style %q (use ref, note, or omit)", s)
+	}
+}
+
+// Option configures DBML generation.
+type Option func(*options)
+
+type options struct {
+	selfReferences     RefStyle
+	cyclicReferences   RefStyle
+	viewReferences     RefStyle
+	sortOrder          SortOrder
+	columnOrder        ColumnOrder
+	typeDetail         TypeDetail
+	notes              bool
+	strict             bool
+	domainTypes        bool
+	indexTypes         bool
+	owners             bool
+	withoutColumnNotes bool
+	schemaGroups       bool
+	headerColors       map[string]string
+	manyToMany         bool
+	hideJunctions      bool
+	qualifyPublic      bool
+	indent             string
+	blankLines         BlankLines
+	attributeSpacing   AttributeSpacing
+}
+
+// WithSelfReferences sets how foreign keys from a table to itself, such as
+// employees.manager_id -> employees.id, are rendered. Some diagram tools
+// cannot lay them out.
+func WithSelfReferences(style RefStyle) Option {
+	return func(o *options) {
+		o.selfReferences = style
+	}
+}
+
+// WithCyclicReferences sets how foreign keys that are part of a cycle between
+// two or more tables are rendered.
+func WithCyclicReferences(style RefStyle) Option {
+	return func(o *options) {
+		o.cyclicReferences = style
+	}
+}
+
+// WithViewReferences sets how the lineage from views to the tables they read
+// is rendered. RefStandard emits a Ref from each view column to the source
+// column of the same name, RefNote lists the sources in the view's note, and
+// RefOmit, the default, leaves lineage out.
+func WithViewReferences(style RefStyle) Option {
+	return func(o *options) {
+		o.viewReferences = style
+	}
+}
+
+// WithSortOrder sets how tables, indexes, and references are ordered, and
+// columns with ColumnsAlphabetical. The default is SortAlphabetical.
+func WithSortOrder(order SortOrder) Option {
+	return func(o *options) {
+		o.sortOrder = order
+	}
+}
+
+// WithColumnOrder sets how each table's and view's columns are ordered. The
+// default, ColumnsOriginal, keeps the order they were declared in, so the
+// DBML matches the DDL; ColumnsAlphabetical orders them by name, following
+// the sort order.
+func WithColumnOrder(order ColumnOrder) Option {
+	return func(o *options) {
+		o.columnOrder = order
+	}
+}
+
+// WithAlphabeticalColumns is WithColumnOrder(ColumnsAlphabetical).
+//
+// Deprecated: Use WithColumnOrder(ColumnsAlphabetical).
+func WithAlphabeticalColumns() Option {
+	return WithColumnOrder(ColumnsAlphabetical)
+}
+
+// WithTypeDetail sets how much of each column type is written. The default,
+// TypesDetailed, keeps lengths and precision; TypesSimple and TypesFamily
+// make cleaner conceptual diagrams.
+func WithTypeDetail(detail TypeDetail) Option {
+	return func(o *options) {
+		o.typeDetail = detail
+	}
+}
+
+// WithStrict makes Generate fail with a *StrictError instead of writing
+// ambiguous or invalid DBML: tables and views whose names collide once the
+// public schema is left out (for example after renaming a schema to public),
+// and duplicate column names.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithDomainTypes writes the domain name as the type of columns that use a
+// domain. By default they get the domain's base type, with the domain name
+// and its CHECK constraints in the column note.
+func WithDomainTypes() Option {
+	return func(o *options) {
+		o.domainTypes = true
+	}
+}
+
+// WithIndexTypes writes every index access method, such as gin, gist, or
+// brin, as the index type. DBML only defines btree and hash, so the output
+// may be rejected by strict DBML tools; by default other methods are kept in
+// the index note as "USING gin".
+func WithIndexTypes() Option {
+	return func(o *options) {
+		o.indexTypes = true
+	}
+}
+
+// WithOwners writes the role that owns each table in its note, as
+// "OWNER role".
+func WithOwners() Option {
+	return func(o *options) {
+		o.owners = true
+	}
+}
+
+// WithSchemaGroups writes a TableGroup per database schema, listing its
+// tables and views, when they span more than one schema, so diagram tools
+// cluster each schema's tables. Tables already in a Schema.TableGroups group
+// stay in that group only.
+func WithSchemaGroups() Option {
+	return func(o *options) {
+		o.schemaGroups = true
+	}
+}
+
+// WithHeaderColors sets the header color of tables, such as
+// [headercolor: #3498db], so large diagrams can be read by domain. colors
+// maps patterns (path.Match syntax) to colors written as #rgb or #rrggbb.
+// A pattern matches a table's name, its schema-qualified name, or its
+// schema, so "billing", "billing.*", and "audit_*" all work. When several
+// patterns match, the longest wins. Colors from @color annotations
+// (Table.Color) take precedence. Generate fails on invalid patterns or
+// colors.
+func WithHeaderColors(colors map[string]string) Option {
+	return func(o *options) {
+		o.headerColors = colors
+	}
+}
+
+// WithManyToMany replaces the two foreign keys of each pure junction table
+// with a single many-to-many Ref between the tables it links, such as
+// "Ref post_tags: posts.id <> tags.id", for cleaner conceptual diagrams. A
+// pure junction table has two foreign keys whose columns form its primary
+// key, no other columns but ones with a default, such as created_at, and no
+// foreign keys referencing it.
+func WithManyToMany() Option {
+	return func(o *options) {
+		o.manyToMany = true
+	}
+}
+
+// WithHiddenJunctionTables is WithManyToMany, also leaving the junction
+// tables themselves out of the output and its table groups.
+func WithHiddenJunctionTables() Option {
+	return func(o *options) {
+		o.manyToMany = true
+		o.hideJunctions = true
+	}
+}
+
+// WithQualifyPublicSchema writes the names of tables, views, and enums in
+// the public schema qualified with it, such as public.users, like those in
+// other schemas, for tools that want fully-qualified names everywhere. By
+// default the public prefix is left out.
+func WithQualifyPublicSchema() Option {
+	return func(o *options) {
+		o.qualifyPublic = true
+	}
+}
+
+// WithIndent sets the indentation of each nested level, such as "\t" or
+// four spaces, as returned by ParseIndent. The default is two spaces.
+func WithIndent(indent string) Option {
+	return func(o *options) {
+		o.indent = indent
+	}
+}
+
+// WithBlankLines sets where blank lines are written. The default,
+// BlankLinesSections, also separates a table's columns from its indexes and
+// note; BlankLinesBlocks only separates blocks.
+func WithBlankLines(policy BlankLines) Option {
+	return func(o *options) {
+		o.blankLines = policy
+	}
+}
+
+// WithAttributeSpacing sets how settings lists such as [pk, not null] are
+// spaced. The default is SpacingNormal.
+func WithAttributeSpacing(spacing AttributeSpacing) Option {
+	return func(o *options) {
+		o.attributeSpacing = spacing
+	}
+}
+
+// WithNotes writes table, view, and column comments as DBML notes.
+func WithNotes() Option {
+	return func(o *options) {
+		o.notes = true
+	}
+}
+
+// WithoutColumnNotes leaves the [note: '...'] attribute off every column,
+// dropping the comments, generation clauses, collations, checks, and other
+// details it carries, for tools that render column notes poorly.
+func WithoutColumnNotes() Option {
+	return func(o *options) {
+		o.withoutColumnNotes = true
+	}
+}
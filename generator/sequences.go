@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// sequenceLimits maps sequence types to their lowest and highest values,
+// which are the default bounds of ascending and descending sequences.
+var sequenceLimits = map[string][2]int64{
+	"smallint": {math.MinInt16, math.MaxInt16},
+	"integer":  {math.MinInt32, math.MaxInt32},
+	"bigint":   {math.MinInt64, math.MaxInt64},
+}
+
+// generateSequences writes the sequences as a sticky note named sequences,
+// one per line, since DBML has no sequence syntax. Comments follow each
+// definition when comments is set.
+func generateSequences(builder *strings.Builder, sequences []schema.Sequence, comments bool, less func(a, b string) bool) {
+	sorted := make([]schema.Sequence, len(sequences))
+	copy(sorted, sequences)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Schema != sorted[j].Schema {
+			return less(sorted[i].Schema, sorted[j].Schema)
+		}
+		return less(sorted[i].Name, sorted[j].Name)
+	})
+
+	lines := make([]string, len(sorted))
+	for i, seq := range sorted {
+		lines[i] = sequenceDefinition(seq)
+		if comments && seq.Comment != "" {
+			lines[i] += " -- " + seq.Comment
+		}
+	}
+	builder.WriteString("Note sequences {\n")
+	builder.WriteString(fmt.Sprintf("  %s\n", quote(strings.Join(lines, "\n"))))
+	builder.WriteString("}\n")
+}
+
+// sequenceDefinition describes a sequence in CREATE SEQUENCE terms, such as
+// "invoice_numbers AS bigint START 1000 INCREMENT 10". Settings at their
+// defaults are left out.
+func sequenceDefinition(seq schema.Sequence) string {
+	parts := []string{GetQualifiedTableName(seq.Name, seq.Schema)}
+	if seq.Type != "" {
+		parts = append(parts, "AS "+seq.Type)
+	}
+	parts = append(parts, fmt.Sprintf("START %d", seq.Start))
+	parts = append(parts, sequenceSettings(seq)...)
+	return strings.Join(parts, " ")
+}
+
+// sequenceNote describes the sequence behind a serial or identity column,
+// such as "SEQUENCE START 1000 INCREMENT 10", or returns an empty string
+// when every setting is at its default.
+func sequenceNote(seq *schema.Sequence) string {
+	if seq == nil {
+		return ""
+	}
+	var parts []string
+	minValue, maxValue := sequenceBounds(*seq)
+	if (seq.Increment > 0 && seq.Start != minValue) || (seq.Increment < 0 && seq.Start != maxValue) {
+		parts = append(parts, fmt.Sprintf("START %d", seq.Start))
+	}
+	parts = append(parts, sequenceSettings(*seq)...)
+	if len(parts) == 0 {
+		return ""
+	}
+	return "SEQUENCE " + strings.Join(parts, " ")
+}
+
+// sequenceSettings returns the INCREMENT, MINVALUE, MAXVALUE and CYCLE
+// settings of a sequence that differ from their defaults.
+func sequenceSettings(seq schema.Sequence) []string {
+	var parts []string
+	if seq.Increment != 1 {
+		parts = append(parts, fmt.Sprintf("INCREMENT %d", seq.Increment))
+	}
+	minValue, maxValue := sequenceBounds(seq)
+	if seq.MinValue != minValue {
+		parts = append(parts, fmt.Sprintf("MINVALUE %d", seq.MinValue))
+	}
+	if seq.MaxValue != maxValue {
+		parts = append(parts, fmt.Sprintf("MAXVALUE %d", seq.MaxValue))
+	}
+	if seq.Cycle {
+		parts = append(parts, "CYCLE")
+	}
+	return parts
+}
+
+// sequenceBounds returns the default MINVALUE and MAXVALUE of a sequence,
+// which depend on its type and direction.
+func sequenceBounds(seq schema.Sequence) (minValue, maxValue int64) {
+	minValue, maxValue = int64(1), int64(math.MaxInt64)
+	limits, known := sequenceLimits[seq.Type]
+	if known {
+		maxValue = limits[1]
+	}
+	if seq.Increment < 0 {
+		minValue, maxValue = math.MinInt64, -1
+		if known {
+			minValue = limits[0]
+		}
+	}
+	return minValue, maxValue
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestSequenceDefinition(t *testing.T) {
+	tests := []struct {
+		seq      schema.Sequence
+		expected string
+	}{
+		{
+			schema.Sequence{Name: "ticket_numbers", Schema: "public", Type: "bigint", Start: 1, Increment: 1, MinValue: 1, MaxValue: math.MaxInt64},
+			"ticket_numbers AS bigint START 1",
+		},
+		{
+			schema.Sequence{Name: "invoice_numbers", Schema: "billing", Type: "integer", Start: 1000, Increment: 10, MinValue: 1000, MaxValue: 999999, Cycle: true},
+			"billing.invoice_numbers AS integer START 1000 INCREMENT 10 MINVALUE 1000 MAXVALUE 999999 CYCLE",
+		},
+		{
+			schema.Sequence{Name: "countdown", Schema: "public", Type: "smallint", Start: -1, Increment: -1, MinValue: math.MinInt16, MaxValue: -1},
+			"countdown AS smallint START -1 INCREMENT -1",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := sequenceDefinition(tt.seq); got != tt.expected {
+			t.Errorf("sequenceDefinition(%s) = %q, want %q", tt.seq.Name, got, tt.expected)
+		}
+	}
+}
+
+func TestGenerateSequences(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{Name: "tickets", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}}},
+		Sequences: []schema.Sequence{
+			{Name: "ticket_numbers", Schema: "public", Type: "bigint", Start: 1, Increment: 1, MinValue: 1, MaxValue: math.MaxInt64, Comment: "Printed on tickets"},
+			{Name: "batch_ids", Schema: "public", Type: "bigint", Start: 1, Increment: 1, MinValue: 1, MaxValue: math.MaxInt64},
+		},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	expected := "Note sequences {\n  'batch_ids AS bigint START 1\\nticket_numbers AS bigint START 1'\n}\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected sequences note %q, got:\n%s", expected, output)
+	}
+
+	output, err = GenerateString(s, WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "ticket_numbers AS bigint START 1 -- Printed on tickets'") {
+		t.Errorf("Expected the sequence comment with WithNotes, got:\n%s", output)
+	}
+}
+
+func TestGenerateColumnSequences(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{Name: "invoices", Schema: "public", Columns: []schema.Column{
+			{Name: "id", Type: "int", Sequence: &schema.Sequence{Name: "invoices_id_seq", Schema: "public", Type: "integer", Start: 1, Increment: 1, MinValue: 1, MaxValue: math.MaxInt32}},
+			{Name: "number", Type: "bigint", Sequence: &schema.Sequence{Name: "invoices_number_seq", Schema: "public", Type: "bigint", Start: 1000, Increment: 10, MinValue: 1, MaxValue: 999999, Cycle: true}},
+		}}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(output, "  id int [not null]\n") {
+		t.Errorf("Expected no note for a sequence with default settings, got:\n%s", output)
+	}
+	if !strings.Contains(output, "number bigint [not null, note: 'SEQUENCE START 1000 INCREMENT 10 MAXVALUE 999999 CYCLE']") {
+		t.Errorf("Expected the sequence settings note, got:\n%s", output)
+	}
+}
@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// SortOrder controls how tables, columns, indexes, and references are ordered
+// in the output.
+type SortOrder int
+
+const (
+	// SortAlphabetical orders names byte by byte, so table_10 sorts before
+	// table_2.
+	SortAlphabetical SortOrder = iota
+	// SortNatural compares runs of digits by their numeric value, so table_2
+	// sorts before table_10 and partitions such as events_2024_9 and
+	// events_2024_10 stay in order.
+	SortNatural
+)
+
+// ParseSortOrder parses "alpha" or "natural".
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch s {
+	case "alpha":
+		return SortAlphabetical, nil
+	case "natural":
+		return SortNatural, nil
+	default:
+		return SortAlphabetical, fmt.Errorf("invalid sort order %q (use alpha or natural)", s)
+	}
+}
+
+// ColumnOrder controls how each table's and view's columns are ordered.
+type ColumnOrder int
+
+const (
+	// ColumnsOriginal keeps columns in the order they were declared in, by
+	// Column.OrdinalPosition, so the DBML reads like the DDL.
+	ColumnsOriginal ColumnOrder = iota
+	// ColumnsAlphabetical orders columns by name, following the sort order.
+	ColumnsAlphabetical
+)
+
+// ParseColumnOrder parses "original" or "alpha".
+func ParseColumnOrder(s string) (ColumnOrder, error) {
+	switch s {
+	case "original":
+		return ColumnsOriginal, nil
+	case "alpha":
+		return ColumnsAlphabetical, nil
+	default:
+		return ColumnsOriginal, fmt.Errorf("invalid column order %q (use original or alpha)", s)
+	}
+}
+
+// less returns the comparison for the sort order.
+func (order SortOrder) less() func(a, b string) bool {
+	if order == SortNatural {
+		return naturalLess
+	}
+	return func(a, b string) bool { return a < b }
+}
+
+// sortedColumns returns a copy of columns in the order they were declared, by
+// OrdinalPosition, or ordered by name with less for ColumnsAlphabetical.
+// Columns without a position keep their relative order after those with one.
+func sortedColumns(columns []schema.Column, order ColumnOrder, less func(a, b string) bool) []schema.Column {
+	sorted := make([]schema.Column, len(columns))
+	copy(sorted, columns)
+	if order == ColumnsAlphabetical {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return less(sorted[i].Name, sorted[j].Name)
+		})
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].OrdinalPosition, sorted[j].OrdinalPosition
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+	return sorted
+}
+
+// naturalLess reports whether a sorts before b, comparing runs of digits
+// numerically and everything else byte by byte. Names that compare equal
+// this way, such as t01 and t1, fall back to byte order.
+func naturalLess(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			startA, startB := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			numA, numB := trimZeros(a[startA:i]), trimZeros(b[startB:j])
+			if len(numA) != len(numB) {
+				return len(numA) < len(numB)
+			}
+			if numA != numB {
+				return numA < numB
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			return a[i] < b[j]
+		}
+		i++
+		j++
+	}
+	if len(a)-i != len(b)-j {
+		return len(a)-i < len(b)-j
+	}
+	return a < b
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func trimZeros(s string) string {
+	for len(s) > 1 && s[0] == '0' {
+		s = s[1:]
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestNaturalLess(t *testing.T) {
+	names := []string{"table_10", "table_2", "table_1", "events_2024_10", "events_2024_9", "t01", "t1", "table", "a10b2", "a10b10"}
+	sort.Slice(names, func(i, j int) bool { return naturalLess(names[i], names[j]) })
+
+	expected := []string{"a10b2", "a10b10", "events_2024_9", "events_2024_10", "t01", "t1", "table", "table_1", "table_2", "table_10"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	if order, err := ParseSortOrder("natural"); err != nil || order != SortNatural {
+		t.Errorf("ParseSortOrder(natural) = %v, %v", order, err)
+	}
+	if _, err := ParseSortOrder("random"); err == nil {
+		t.Error("Expected error for unknown sort order")
+	}
+}
+
+func TestGenerateSortOrder(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "table_10", Schema: "public", Columns: []schema.Column{{Name: "col_10", Type: "int"}, {Name: "col_2", Type: "int"}}},
+			{Name: "table_2", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		},
+	}
+
+	alpha, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Index(alpha, "Table table_10") > strings.Index(alpha, "Table table_2") {
+		t.Errorf("Expected alphabetical order by default, got:\n%s", alpha)
+	}
+
+	natural, err := GenerateString(s, WithSortOrder(SortNatural), WithColumnOrder(ColumnsAlphabetical))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if strings.Index(natural, "Table table_2") > strings.Index(natural, "Table table_10") {
+		t.Errorf("Expected table_2 before table_10, got:\n%s", natural)
+	}
+	if strings.Index(natural, "col_2") > strings.Index(natural, "col_10") {
+		t.Errorf("Expected col_2 before col_10, got:\n%s", natural)
+	}
+}
+
+func TestGenerateColumnOrder(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{Name: "users", Schema: "public", Columns: []schema.Column{
+			{Name: "email", Type: "varchar", OrdinalPosition: 3},
+			{Name: "legacy", Type: "text"},
+			{Name: "id", Type: "int", OrdinalPosition: 1},
+			{Name: "name", Type: "text", OrdinalPosition: 2},
+		}}},
+		Views: []schema.View{{Name: "active_users", Schema: "public", Columns: []schema.Column{
+			{Name: "name", Type: "text", OrdinalPosition: 2},
+			{Name: "id", Type: "int", OrdinalPosition: 1},
+		}}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{
+		"Table users {\n  id int [not null]\n  name text [not null]\n  email varchar [not null]\n  legacy text [not null]\n",
+		"Table active_users {\n  id int [not null]\n  name text [not null]\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected columns in declared order %q, got:\n%s", want, output)
+		}
+	}
+
+	output, err = GenerateString(s, WithAlphabeticalColumns())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	want := "Table users {\n  email varchar [not null]\n  id int [not null]\n  legacy text [not null]\n  name text [not null]\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected columns in alphabetical order %q, got:\n%s", want, output)
+	}
+
+	alphabetical, err := GenerateString(s, WithColumnOrder(ColumnsAlphabetical))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if alphabetical != output {
+		t.Errorf("Expected WithColumnOrder(ColumnsAlphabetical) to match WithAlphabeticalColumns, got:\n%s", alphabetical)
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// StrictError lists the problems WithStrict found, each prefixed with the
+// object it concerns.
+type StrictError struct {
+	Problems []string
+}
+
+func (e *StrictError) Error() string {
+	return fmt.Sprintf("strict mode found %d problem(s):\n  %s", len(e.Problems), strings.Join(e.Problems, "\n  "))
+}
+
+// strictProblems lists what would make the DBML for s ambiguous or invalid:
+// tables and views whose names collide once the public schema is left out,
+// and duplicate column names.
+func strictProblems(s *schema.Schema, qualify func(name, schemaName string) string) []string {
+	var problems []string
+	written := make(map[string]string)
+
+	check := func(kind, schemaName, name string, columns []schema.Column) {
+		location := kind + " " + name
+		if schemaName != "" {
+			location = fmt.Sprintf("%s %s.%s", kind, schemaName, name)
+		}
+
+		qualified := qualify(name, schemaName)
+		if other, ok := written[qualified]; ok {
+			problems = append(problems, fmt.Sprintf("%s: collides with %s, both are written as %s", location, other, qualified))
+		} else {
+			written[qualified] = location
+		}
+
+		seen := make(map[string]bool)
+		for _, column := range columns {
+			if seen[column.Name] {
+				problems = append(problems, fmt.Sprintf("%s: duplicate column %s", location, column.Name))
+			}
+			seen[column.Name] = true
+		}
+	}
+
+	for _, table := range s.Tables {
+		check("table", table.Schema, table.Name, table.Columns)
+	}
+	for _, view := range s.Views {
+		check("view", view.Schema, view.Name, view.Columns)
+	}
+	return problems
+}
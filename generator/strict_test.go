@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerateStrict(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "users", Schema: "", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "order items", Schema: "sales", Columns: []schema.Column{{Name: "first name", Type: "text"}, {Name: "sku", Type: "text"}, {Name: "sku", Type: "text"}}},
+		},
+		Views: []schema.View{{Name: "report", Schema: "my-schema"}},
+	}
+
+	_, err := Generate(s, WithStrict())
+	var strictErr *StrictError
+	if !errors.As(err, &strictErr) {
+		t.Fatalf("Expected a StrictError, got %v", err)
+	}
+
+	expected := []string{
+		"table users: collides with table public.users, both are written as users",
+		"table sales.order items: duplicate column sku",
+	}
+	if len(strictErr.Problems) != len(expected) {
+		t.Errorf("Expected %d problems, got %d:\n%s", len(expected), len(strictErr.Problems), err)
+	}
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got:\n%s", want, err)
+		}
+	}
+}
+
+func TestGenerateStrictValid(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+			{Name: "users", Schema: "auth", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		},
+	}
+
+	if _, err := Generate(s, WithStrict()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	// Without strict mode, problems are written as they are
+	s.Tables[1].Schema = ""
+	if _, err := Generate(s); err != nil {
+		t.Errorf("Expected no error without strict mode, got %v", err)
+	}
+}
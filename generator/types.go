@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// TypeDetail controls how much of each column type is written.
+type TypeDetail int
+
+const (
+	// TypesDetailed writes types as introspected, such as varchar(255) and
+	// decimal(10,2).
+	TypesDetailed TypeDetail = iota
+	// TypesSimple drops lengths and precision, so varchar(255) becomes
+	// varchar and decimal(10,2) becomes decimal.
+	TypesSimple
+	// TypesFamily also coalesces related types into one family, such as
+	// int, bigint, and smallint into integer, for high-level diagrams.
+	TypesFamily
+)
+
+// ParseTypeDetail parses "detailed", "simple", or "family".
+func ParseTypeDetail(s string) (TypeDetail, error) {
+	switch s {
+	case "detailed":
+		return TypesDetailed, nil
+	case "simple":
+		return TypesSimple, nil
+	case "family":
+		return TypesFamily, nil
+	default:
+		return TypesDetailed, fmt.Errorf("invalid type detail %q (use detailed, simple, or family)", s)
+	}
+}
+
+// typeFamilies maps types without modifiers to the family TypesFamily
+// writes them as. Types not listed are their own family.
+var typeFamilies = map[string]string{
+	"int":         "integer",
+	"integer":     "integer",
+	"bigint":      "integer",
+	"smallint":    "integer",
+	"serial":      "integer",
+	"bigserial":   "integer",
+	"smallserial": "integer",
+	"decimal":     "number",
+	"numeric":     "number",
+	"float":       "number",
+	"double":      "number",
+	"real":        "number",
+	"money":       "number",
+	"varchar":     "text",
+	"char":        "text",
+	"citext":      "text",
+	"tsvector":    "text",
+	"tsquery":     "text",
+	"timestamp":   "timestamp",
+	"timestamptz": "timestamp",
+	"time":        "time",
+	"timetz":      "time",
+	"json":        "json",
+	"jsonb":       "json",
+	"bit":         "bit",
+	"varbit":      "bit",
+	"vector":      "vector",
+	"halfvec":     "vector",
+	"sparsevec":   "vector",
+}
+
+// columnType returns the column type written at this level of detail.
+// Array brackets are kept.
+func (detail TypeDetail) columnType(columnType string) string {
+	if detail == TypesDetailed {
+		return columnType
+	}
+
+	base, array := columnType, ""
+	if i := strings.Index(base, "["); i >= 0 {
+		base, array = base[:i], base[i:]
+	}
+	if i := strings.Index(base, "("); i >= 0 {
+		base = base[:i]
+	}
+	if detail == TypesFamily {
+		if family, ok := typeFamilies[strings.ToLower(base)]; ok {
+			base = family
+		}
+	}
+	return base + array
+}
+
+// withTypeDetail returns a copy of columns with their types written at this
+// level of detail.
+func withTypeDetail(columns []schema.Column, detail TypeDetail) []schema.Column {
+	result := make([]schema.Column, len(columns))
+	for i, column := range columns {
+		column.Type = detail.columnType(column.Type)
+		result[i] = column
+	}
+	return result
+}
+
+// withTypeNotes returns a copy of notes with the database type prepended to
+// the notes of each column written as text for want of a DBML equivalent,
+// such as PostGIS's geometry, so the type is not lost.
+func withTypeNotes(s *schema.Schema, columns []schema.Column, notes map[string][]string) map[string][]string {
+	result := make(map[string][]string, len(notes))
+	for column, columnNotes := range notes {
+		result[column] = columnNotes
+	}
+	for _, column := range columns {
+		if s.TypeFallback(column) {
+			result[column.Name] = append([]string{"TYPE " + column.DatabaseType}, result[column.Name]...)
+		}
+	}
+	return result
+}
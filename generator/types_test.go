@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestTypeDetailColumnType(t *testing.T) {
+	tests := []struct {
+		detail   TypeDetail
+		input    string
+		expected string
+	}{
+		{TypesDetailed, "varchar(255)", "varchar(255)"},
+		{TypesSimple, "varchar(255)", "varchar"},
+		{TypesSimple, "decimal(10,2)", "decimal"},
+		{TypesSimple, "varchar(50)[]", "varchar[]"},
+		{TypesSimple, "bigint", "bigint"},
+		{TypesFamily, "bigint", "integer"},
+		{TypesFamily, "decimal(10,2)", "number"},
+		{TypesFamily, "char(2)", "text"},
+		{TypesFamily, "timestamptz", "timestamp"},
+		{TypesFamily, "int[]", "integer[]"},
+		{TypesFamily, "uuid", "uuid"},
+		{TypesFamily, "vector(1536)", "vector"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.detail.columnType(tt.input); got != tt.expected {
+			t.Errorf("TypeDetail(%d).columnType(%q) = %q, want %q", tt.detail, tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestGenerateWithTypeDetail(t *testing.T) {
+	s := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:   "orders",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "id", Type: "bigint", IsPrimaryKey: true},
+				{Name: "total", Type: "decimal(10,2)"},
+			},
+		}},
+		Views: []schema.View{{
+			Name:    "order_totals",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "total", Type: "decimal(10,2)", Nullable: true}},
+		}},
+	}
+
+	output, err := GenerateString(s, WithTypeDetail(TypesFamily))
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{"  id integer [pk", "  total number [not null]", "  total number\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if s.Tables[0].Columns[1].Type != "decimal(10,2)" {
+		t.Errorf("Generate modified the schema")
+	}
+}
+
+func TestGenerateTypeNotes(t *testing.T) {
+	s := &schema.Schema{
+		Enums: []schema.Enum{{Name: "mood", Schema: "public", Values: []string{"happy", "sad"}}},
+		Tables: []schema.Table{{
+			Name:   "places",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "location", Type: "text", DatabaseType: "geography(Point,4326)", Nullable: true},
+				{Name: "mood", Type: "text", DatabaseType: "mood"},
+				{Name: "name", Type: "text", DatabaseType: "text"},
+				{Name: "search", Type: "text[]", DatabaseType: "tsvector[]", Nullable: true},
+			},
+		}},
+	}
+
+	output, err := GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{
+		"  location text [note: 'TYPE geography(Point,4326)']\n",
+		"  mood mood [not null]\n",
+		"  name text [not null]\n",
+		"  search text[] [note: 'TYPE tsvector[]']\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestParseTypeDetail(t *testing.T) {
+	for input, want := range map[string]TypeDetail{"detailed": TypesDetailed, "simple": TypesSimple, "family": TypesFamily} {
+		got, err := ParseTypeDetail(input)
+		if err != nil || got != want {
+			t.Errorf("ParseTypeDetail(%q) = %v, %v", input, got, err)
+		}
+	}
+	if _, err := ParseTypeDetail("coarse"); err == nil {
+		t.Error("Expected an error for an unknown type detail")
+	}
+}
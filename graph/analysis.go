@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Analysis summarizes the relationship graph of a schema.
+type Analysis struct {
+	Tables     int      `json:"tables"`
+	Edges      int      `json:"edges"`
+	Orphans    []Node   `json:"orphans"`
+	Cycles     [][]Node `json:"cycles"`
+	Hubs       []Degree `json:"hubs"`
+	Components [][]Node `json:"components"`
+}
+
+// Analyze computes the full analysis for a schema, listing at most topHubs
+// hubs (every connected table if topHubs is not positive).
+func Analyze(s *schema.Schema, topHubs int) *Analysis {
+	g := New(s)
+
+	edges := 0
+	for _, nodes := range g.out {
+		edges += len(nodes)
+	}
+
+	a := &Analysis{
+		Tables:     len(g.nodes),
+		Edges:      edges,
+		Orphans:    g.Orphans(),
+		Cycles:     g.Cycles(),
+		Hubs:       g.Hubs(topHubs),
+		Components: g.Components(),
+	}
+
+	// Keep JSON output free of nulls.
+	if a.Orphans == nil {
+		a.Orphans = []Node{}
+	}
+	if a.Cycles == nil {
+		a.Cycles = [][]Node{}
+	}
+	if a.Hubs == nil {
+		a.Hubs = []Degree{}
+	}
+	if a.Components == nil {
+		a.Components = [][]Node{}
+	}
+	return a
+}
+
+// WriteText writes the analysis as a human-readable report.
+func (a *Analysis) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Tables: %d\nRelationships: %d\nComponents: %d\n", a.Tables, a.Edges, len(a.Components))
+
+	fmt.Fprintf(&b, "\nHubs:\n")
+	if len(a.Hubs) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, d := range a.Hubs {
+		fmt.Fprintf(&b, "  %-40s %3d in %3d out\n", d.Node, d.In, d.Out)
+	}
+
+	fmt.Fprintf(&b, "\nCycles:\n")
+	if len(a.Cycles) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, cycle := range a.Cycles {
+		fmt.Fprintf(&b, "  %s\n", joinNodes(cycle))
+	}
+
+	fmt.Fprintf(&b, "\nOrphans:\n")
+	if len(a.Orphans) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, n := range a.Orphans {
+		fmt.Fprintf(&b, "  %s\n", n)
+	}
+
+	fmt.Fprintf(&b, "\nComponents:\n")
+	for i, component := range a.Components {
+		fmt.Fprintf(&b, "  %d. (%d tables) %s\n", i+1, len(component), joinNodes(component))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func joinNodes(nodes []Node) string {
+	names := make([]string, len(nodes))
+	for i, n := range nodes {
+		names[i] = n.String()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestAnalyze(t *testing.T) {
+	a := Analyze(testSchema(), 3)
+
+	if a.Tables != 7 {
+		t.Errorf("Expected 7 tables, got %d", a.Tables)
+	}
+	// posts->users, comments->posts, comments->users, comments->comments,
+	// teams->members, members->teams
+	if a.Edges != 6 {
+		t.Errorf("Expected 6 edges, got %d", a.Edges)
+	}
+	if len(a.Hubs) != 3 {
+		t.Errorf("Expected 3 hubs, got %d", len(a.Hubs))
+	}
+
+	var b strings.Builder
+	if err := a.WriteText(&b); err != nil {
+		t.Fatalf("WriteText returned error: %v", err)
+	}
+	for _, expected := range []string{"Tables: 7", "Relationships: 6", "  members, teams\n", "  settings\n", "1. (3 tables) comments, posts, users"} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected text report to contain %q, got:\n%s", expected, b.String())
+		}
+	}
+}
+
+func TestAnalyzeEmptyJSON(t *testing.T) {
+	data, err := json.Marshal(Analyze(&schema.Schema{}, 0))
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if strings.Contains(string(data), "null") {
+		t.Errorf("Expected no nulls in JSON output, got %s", data)
+	}
+}
@@ -0,0 +1,284 @@
+// Package graph analyzes the relationships between tables.
+//
+// A Graph has one node per table and a directed edge from each referencing
+// table to the table it references. It answers structural questions about a
+// schema: which tables are isolated, which foreign keys form cycles, which
+// tables are hubs, and how the schema splits into connected components.
+//
+// Basic usage:
+//
+//	g := graph.New(s)
+//	for _, cycle := range g.Cycles() {
+//	    fmt.Println(cycle)
+//	}
+package graph
+
+import (
+	"sort"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Node identifies a table.
+type Node struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+}
+
+// String returns the qualified table name, omitting "public" like the DBML
+// generator does. (The generator uses this package, so it cannot be called.)
+func (n Node) String() string {
+	if n.Schema != "" && n.Schema != "public" {
+		return n.Schema + "." + n.Name
+	}
+	return n.Name
+}
+
+func less(a, b Node) bool {
+	if a.Schema != b.Schema {
+		return a.Schema < b.Schema
+	}
+	return a.Name < b.Name
+}
+
+func sortNodes(nodes []Node) {
+	sort.Slice(nodes, func(i, j int) bool { return less(nodes[i], nodes[j]) })
+}
+
+// Graph is the foreign key graph of a schema. Multiple foreign keys between
+// the same pair of tables count as one edge, and references to tables outside
+// the schema are ignored.
+type Graph struct {
+	nodes []Node
+	out   map[Node][]Node
+	in    map[Node][]Node
+}
+
+// New builds the graph for a schema.
+func New(s *schema.Schema) *Graph {
+	g := &Graph{
+		out: make(map[Node][]Node),
+		in:  make(map[Node][]Node),
+	}
+
+	known := make(map[Node]bool)
+	for _, table := range s.Tables {
+		node := Node{Schema: table.Schema, Name: table.Name}
+		if !known[node] {
+			known[node] = true
+			g.nodes = append(g.nodes, node)
+		}
+	}
+	sortNodes(g.nodes)
+
+	seen := make(map[[2]Node]bool)
+	for _, table := range s.Tables {
+		from := Node{Schema: table.Schema, Name: table.Name}
+		for _, ref := range table.References {
+			to := Node{Schema: ref.ToSchema, Name: ref.ToTable}
+			edge := [2]Node{from, to}
+			if !known[to] || seen[edge] {
+				continue
+			}
+			seen[edge] = true
+			g.out[from] = append(g.out[from], to)
+			g.in[to] = append(g.in[to], from)
+		}
+	}
+
+	for _, nodes := range g.out {
+		sortNodes(nodes)
+	}
+	for _, nodes := range g.in {
+		sortNodes(nodes)
+	}
+	return g
+}
+
+// Nodes returns every table, sorted by schema and name.
+func (g *Graph) Nodes() []Node {
+	return append([]Node(nil), g.nodes...)
+}
+
+// References returns the tables n references.
+func (g *Graph) References(n Node) []Node {
+	return append([]Node(nil), g.out[n]...)
+}
+
+// ReferencedBy returns the tables referencing n.
+func (g *Graph) ReferencedBy(n Node) []Node {
+	return append([]Node(nil), g.in[n]...)
+}
+
+// Orphans returns tables with no foreign keys in or out.
+func (g *Graph) Orphans() []Node {
+	var orphans []Node
+	for _, n := range g.nodes {
+		if len(g.out[n]) == 0 && len(g.in[n]) == 0 {
+			orphans = append(orphans, n)
+		}
+	}
+	return orphans
+}
+
+// Degree counts a table's relationships.
+type Degree struct {
+	Node Node `json:"table"`
+	// In is the number of tables referencing this table.
+	In int `json:"in"`
+	// Out is the number of tables this table references.
+	Out int `json:"out"`
+}
+
+// Total returns In + Out.
+func (d Degree) Total() int {
+	return d.In + d.Out
+}
+
+// Hubs returns the n tables with the most relationships, most connected
+// first. Tables without relationships are never hubs. A non-positive n
+// returns every connected table.
+func (g *Graph) Hubs(n int) []Degree {
+	var degrees []Degree
+	for _, node := range g.nodes {
+		d := Degree{Node: node, In: len(g.in[node]), Out: len(g.out[node])}
+		if d.Total() > 0 {
+			degrees = append(degrees, d)
+		}
+	}
+
+	sort.SliceStable(degrees, func(i, j int) bool {
+		if degrees[i].Total() != degrees[j].Total() {
+			return degrees[i].Total() > degrees[j].Total()
+		}
+		return degrees[i].In > degrees[j].In
+	})
+
+	if n > 0 && len(degrees) > n {
+		degrees = degrees[:n]
+	}
+	return degrees
+}
+
+// Components returns the connected components, ignoring edge direction.
+// Components are sorted largest first; tables within a component are sorted
+// by schema and name.
+func (g *Graph) Components() [][]Node {
+	visited := make(map[Node]bool)
+	var components [][]Node
+
+	for _, start := range g.nodes {
+		if visited[start] {
+			continue
+		}
+
+		var component []Node
+		stack := []Node{start}
+		visited[start] = true
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			component = append(component, n)
+
+			for _, neighbors := range [][]Node{g.out[n], g.in[n]} {
+				for _, m := range neighbors {
+					if !visited[m] {
+						visited[m] = true
+						stack = append(stack, m)
+					}
+				}
+			}
+		}
+
+		sortNodes(component)
+		components = append(components, component)
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return len(components[i]) > len(components[j])
+	})
+	return components
+}
+
+// Cycles returns groups of tables whose foreign keys form cycles: every
+// strongly connected component with more than one table, plus each table
+// that references itself. Tables in a group are sorted by schema and name.
+func (g *Graph) Cycles() [][]Node {
+	t := &tarjan{
+		graph:   g,
+		index:   make(map[Node]int),
+		lowlink: make(map[Node]int),
+		onStack: make(map[Node]bool),
+	}
+	for _, n := range g.nodes {
+		if _, ok := t.index[n]; !ok {
+			t.connect(n)
+		}
+	}
+
+	var cycles [][]Node
+	for _, component := range t.components {
+		if len(component) > 1 || g.referencesItself(component[0]) {
+			sortNodes(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return less(cycles[i][0], cycles[j][0])
+	})
+	return cycles
+}
+
+func (g *Graph) referencesItself(n Node) bool {
+	for _, m := range g.out[n] {
+		if m == n {
+			return true
+		}
+	}
+	return false
+}
+
+// tarjan finds strongly connected components with Tarjan's algorithm.
+type tarjan struct {
+	graph      *Graph
+	counter    int
+	index      map[Node]int
+	lowlink    map[Node]int
+	stack      []Node
+	onStack    map[Node]bool
+	components [][]Node
+}
+
+func (t *tarjan) connect(n Node) {
+	t.index[n] = t.counter
+	t.lowlink[n] = t.counter
+	t.counter++
+	t.stack = append(t.stack, n)
+	t.onStack[n] = true
+
+	for _, m := range t.graph.out[n] {
+		if _, ok := t.index[m]; !ok {
+			t.connect(m)
+			if t.lowlink[m] < t.lowlink[n] {
+				t.lowlink[n] = t.lowlink[m]
+			}
+		} else if t.onStack[m] && t.index[m] < t.lowlink[n] {
+			t.lowlink[n] = t.index[m]
+		}
+	}
+
+	if t.lowlink[n] == t.index[n] {
+		var component []Node
+		for {
+			m := t.stack[len(t.stack)-1]
+			t.stack = t.stack[:len(t.stack)-1]
+			t.onStack[m] = false
+			component = append(component, m)
+			if m == n {
+				break
+			}
+		}
+		t.components = append(t.components, component)
+	}
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func ref(from, to string) schema.Reference {
+	return schema.Reference{FromTable: from, FromSchema: "public", FromColumns: []string{to + "_id"}, ToTable: to, ToSchema: "public", ToColumns: []string{"id"}}
+}
+
+func table(name string, refs ...schema.Reference) schema.Table {
+	return schema.Table{Name: name, Schema: "public", References: refs}
+}
+
+func testSchema() *schema.Schema {
+	return &schema.Schema{
+		Tables: []schema.Table{
+			table("users"),
+			table("posts", ref("posts", "users")),
+			table("comments", ref("comments", "posts"), ref("comments", "users"), ref("comments", "comments")),
+			table("teams", ref("teams", "members")),
+			table("members", ref("members", "teams")),
+			table("settings"),
+			table("audit", ref("audit", "external")),
+		},
+	}
+}
+
+func names(nodes []Node) []string {
+	var result []string
+	for _, n := range nodes {
+		result = append(result, n.String())
+	}
+	return result
+}
+
+func TestOrphans(t *testing.T) {
+	g := New(testSchema())
+
+	expected := []string{"audit", "settings"}
+	if result := names(g.Orphans()); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Orphans() = %v, want %v", result, expected)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	g := New(testSchema())
+
+	cycles := g.Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("Expected 2 cycles, got %v", cycles)
+	}
+	if result := names(cycles[0]); !reflect.DeepEqual(result, []string{"comments"}) {
+		t.Errorf("Expected self-reference cycle on comments, got %v", result)
+	}
+	if result := names(cycles[1]); !reflect.DeepEqual(result, []string{"members", "teams"}) {
+		t.Errorf("Expected members/teams cycle, got %v", result)
+	}
+}
+
+func TestHubs(t *testing.T) {
+	g := New(testSchema())
+
+	hubs := g.Hubs(2)
+	if len(hubs) != 2 {
+		t.Fatalf("Expected 2 hubs, got %v", hubs)
+	}
+	// comments: out to posts, users, and itself; in from itself
+	if hubs[0].Node.Name != "comments" || hubs[0].In != 1 || hubs[0].Out != 3 {
+		t.Errorf("Unexpected top hub: %+v", hubs[0])
+	}
+	if hubs[1].Node.Name != "posts" && hubs[1].Node.Name != "users" {
+		t.Errorf("Unexpected second hub: %+v", hubs[1])
+	}
+
+	if all := g.Hubs(0); len(all) != 5 {
+		t.Errorf("Expected 5 connected tables, got %d", len(all))
+	}
+}
+
+func TestComponents(t *testing.T) {
+	g := New(testSchema())
+
+	components := g.Components()
+	if len(components) != 4 {
+		t.Fatalf("Expected 4 components, got %v", components)
+	}
+	if result := names(components[0]); !reflect.DeepEqual(result, []string{"comments", "posts", "users"}) {
+		t.Errorf("Unexpected largest component: %v", result)
+	}
+	if result := names(components[1]); !reflect.DeepEqual(result, []string{"members", "teams"}) {
+		t.Errorf("Unexpected second component: %v", result)
+	}
+}
+
+func TestNodeString(t *testing.T) {
+	if s := (Node{Schema: "auth", Name: "users"}).String(); s != "auth.users" {
+		t.Errorf("Expected auth.users, got %s", s)
+	}
+	if s := (Node{Schema: "public", Name: "users"}).String(); s != "users" {
+		t.Errorf("Expected users, got %s", s)
+	}
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// ClusterMethod selects how AutoGroups clusters tables.
+type ClusterMethod int
+
+const (
+	// ClusterComponents groups the tables of each connected component.
+	ClusterComponents ClusterMethod = iota
+	// ClusterCommunities splits the graph into densely connected
+	// communities, which keeps one large component from becoming one group.
+	ClusterCommunities
+)
+
+// ParseClusterMethod parses "components" or "communities".
+func ParseClusterMethod(s string) (ClusterMethod, error) {
+	switch s {
+	case "components":
+		return ClusterComponents, nil
+	case "communities":
+		return ClusterCommunities, nil
+	default:
+		return ClusterComponents, fmt.Errorf("invalid cluster method %q (use components or communities)", s)
+	}
+}
+
+// maxRounds bounds the local moving of Communities, which almost always
+// settles within a few rounds.
+const maxRounds = 100
+
+// Communities splits the graph into communities of densely connected tables,
+// ignoring edge direction, with the local moving phase of the Louvain method:
+// every table starts in its own community and moves to the neighboring
+// community that most increases modularity until no move does. Tables are
+// visited in order and ties go to the current community, then the earliest
+// one, so the result is deterministic. Communities are sorted largest first;
+// tables within a community are sorted by schema and name.
+func (g *Graph) Communities() [][]Node {
+	neighbors := make(map[Node][]Node, len(g.nodes))
+	edges := 0
+	for _, n := range g.nodes {
+		neighbors[n] = g.neighbors(n)
+		edges += len(neighbors[n])
+	}
+	// Each edge was counted from both ends, which is the 2m of the
+	// modularity formula
+	if edges == 0 {
+		return g.Components()
+	}
+
+	community := make(map[Node]int, len(g.nodes))
+	total := make(map[int]int, len(g.nodes))
+	for i, n := range g.nodes {
+		community[n] = i
+		total[i] = len(neighbors[n])
+	}
+
+	for round := 0; round < maxRounds; round++ {
+		moved := false
+		for _, n := range g.nodes {
+			degree := len(neighbors[n])
+			if degree == 0 {
+				continue
+			}
+
+			current := community[n]
+			total[current] -= degree
+
+			links := make(map[int]int)
+			for _, m := range neighbors[n] {
+				links[community[m]]++
+			}
+			candidates := make([]int, 0, len(links))
+			for c := range links {
+				candidates = append(candidates, c)
+			}
+			sort.Ints(candidates)
+
+			// The modularity gain of joining c, scaled by 2m so it stays
+			// an integer
+			gain := func(c int) int {
+				return edges*links[c] - total[c]*degree
+			}
+			best := current
+			for _, c := range candidates {
+				if gain(c) > gain(best) {
+					best = c
+				}
+			}
+
+			community[n] = best
+			total[best] += degree
+			if best != current {
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	byCommunity := make(map[int][]Node)
+	var order []int
+	for _, n := range g.nodes {
+		c := community[n]
+		if _, ok := byCommunity[c]; !ok {
+			order = append(order, c)
+		}
+		byCommunity[c] = append(byCommunity[c], n)
+	}
+
+	communities := make([][]Node, len(order))
+	for i, c := range order {
+		communities[i] = byCommunity[c]
+	}
+	sort.SliceStable(communities, func(i, j int) bool {
+		return len(communities[i]) > len(communities[j])
+	})
+	return communities
+}
+
+// neighbors returns the tables n references or is referenced by, excluding
+// n itself, each once.
+func (g *Graph) neighbors(n Node) []Node {
+	seen := map[Node]bool{n: true}
+	var result []Node
+	for _, nodes := range [][]Node{g.out[n], g.in[n]} {
+		for _, m := range nodes {
+			if !seen[m] {
+				seen[m] = true
+				result = append(result, m)
+			}
+		}
+	}
+	return result
+}
+
+// AutoGroups returns a copy of s with a TableGroup for each cluster of two or
+// more tables, for diagrams of large databases that lack schema-based
+// organization. Tables already in a group are left where they are. Each group
+// is named after its most connected table.
+func AutoGroups(s *schema.Schema, method ClusterMethod) *schema.Schema {
+	grouped := make(map[Node]bool)
+	names := make(map[string]bool)
+	for _, group := range s.TableGroups {
+		names[group.Name] = true
+		for _, t := range group.Tables {
+			grouped[Node{Schema: t.Schema, Name: t.Name}] = true
+		}
+	}
+
+	// Cluster only the ungrouped tables, so existing groups do not pull
+	// clusters together
+	ungrouped := *s
+	ungrouped.Tables = nil
+	for _, table := range s.Tables {
+		if !grouped[Node{Schema: table.Schema, Name: table.Name}] {
+			ungrouped.Tables = append(ungrouped.Tables, table)
+		}
+	}
+	g := New(&ungrouped)
+
+	clusters := g.Components()
+	if method == ClusterCommunities {
+		clusters = g.Communities()
+	}
+
+	result := *s
+	result.TableGroups = append([]schema.TableGroup(nil), s.TableGroups...)
+	for _, cluster := range clusters {
+		if len(cluster) < 2 {
+			continue
+		}
+
+		name := g.hub(cluster).String()
+		for i := 2; names[name]; i++ {
+			name = fmt.Sprintf("%s_%d", g.hub(cluster), i)
+		}
+		names[name] = true
+
+		group := schema.TableGroup{Name: name}
+		for _, n := range cluster {
+			group.Tables = append(group.Tables, schema.TableName{Schema: n.Schema, Name: n.Name})
+		}
+		result.TableGroups = append(result.TableGroups, group)
+	}
+	return &result
+}
+
+// hub returns the table in cluster with the most relationships, the first
+// one on ties.
+func (g *Graph) hub(cluster []Node) Node {
+	hub := cluster[0]
+	for _, n := range cluster[1:] {
+		if len(g.neighbors(n)) > len(g.neighbors(hub)) {
+			hub = n
+		}
+	}
+	return hub
+}
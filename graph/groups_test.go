@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// twoClusters is two triangles of tables joined by a single foreign key from
+// invoices to customers.
+func twoClusters() *schema.Schema {
+	return &schema.Schema{
+		Tables: []schema.Table{
+			table("customers"),
+			table("addresses", ref("addresses", "customers")),
+			table("contacts", ref("contacts", "customers"), ref("contacts", "addresses")),
+			table("invoices", ref("invoices", "customers")),
+			table("invoice_lines", ref("invoice_lines", "invoices")),
+			table("payments", ref("payments", "invoices"), ref("payments", "invoice_lines")),
+			table("settings"),
+		},
+	}
+}
+
+func TestCommunities(t *testing.T) {
+	communities := New(twoClusters()).Communities()
+
+	var got [][]string
+	for _, c := range communities {
+		got = append(got, names(c))
+	}
+	expected := [][]string{
+		{"addresses", "contacts", "customers"},
+		{"invoice_lines", "invoices", "payments"},
+		{"settings"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Communities() = %v, want %v", got, expected)
+	}
+}
+
+func TestAutoGroups(t *testing.T) {
+	s := twoClusters()
+
+	components := AutoGroups(s, ClusterComponents)
+	if len(components.TableGroups) != 1 || len(components.TableGroups[0].Tables) != 6 {
+		t.Errorf("Expected one group of 6 connected tables, got %+v", components.TableGroups)
+	}
+	if components.TableGroups[0].Name != "customers" {
+		t.Errorf("Expected the group to be named after customers, got %q", components.TableGroups[0].Name)
+	}
+
+	communities := AutoGroups(s, ClusterCommunities)
+	var groupNames []string
+	for _, group := range communities.TableGroups {
+		groupNames = append(groupNames, group.Name)
+	}
+	if !reflect.DeepEqual(groupNames, []string{"customers", "invoices"}) {
+		t.Errorf("Expected customers and invoices groups, got %v", groupNames)
+	}
+	if len(s.TableGroups) != 0 {
+		t.Errorf("AutoGroups modified the schema")
+	}
+}
+
+func TestAutoGroupsKeepsExistingGroups(t *testing.T) {
+	s := twoClusters()
+	s.TableGroups = []schema.TableGroup{{Name: "customers", Tables: []schema.TableName{{Schema: "public", Name: "settings"}, {Schema: "public", Name: "payments"}}}}
+
+	result := AutoGroups(s, ClusterCommunities)
+
+	if len(result.TableGroups) != 3 || result.TableGroups[0].Name != "customers" {
+		t.Fatalf("Expected the existing group first, got %+v", result.TableGroups)
+	}
+	if result.TableGroups[1].Name != "customers_2" {
+		t.Errorf("Expected the clashing name to get a suffix, got %q", result.TableGroups[1].Name)
+	}
+	for _, group := range result.TableGroups[1:] {
+		for _, t2 := range group.Tables {
+			if t2.Name == "payments" {
+				t.Errorf("Expected payments to stay in its existing group, got %+v", group)
+			}
+		}
+	}
+}
+
+func TestParseClusterMethod(t *testing.T) {
+	if m, err := ParseClusterMethod("communities"); err != nil || m != ClusterCommunities {
+		t.Errorf("ParseClusterMethod(communities) = %v, %v", m, err)
+	}
+	if _, err := ParseClusterMethod("louvain"); err == nil {
+		t.Error("Expected an error for an unknown method")
+	}
+}
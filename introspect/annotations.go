@@ -0,0 +1,95 @@
+package introspect
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Annotations are the settings a comment can carry, such as
+// "Customer invoices @color(#ff0000) @group(Billing) @deprecated".
+type Annotations struct {
+	// Color is the table header color from @color(#rgb) or
+	// @color(#rrggbb).
+	Color string
+	// Group is the table group from @group(name).
+	Group string
+	// Deprecated is set by @deprecated.
+	Deprecated bool
+}
+
+// annotationPattern matches @name or @name(argument) at the start of the
+// comment or after whitespace, so e-mail addresses are left alone.
+var annotationPattern = regexp.MustCompile(`(^|\s)@(color|group|deprecated)(?:\(([^)]*)\))?`)
+
+var colorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ParseAnnotations extracts @color, @group, and @deprecated from a comment
+// and returns them with the rest of the comment. @deprecated(reason) keeps
+// the reason in the comment. Invalid colors, empty groups, and unknown
+// annotations are left in the comment untouched.
+func ParseAnnotations(comment string) (Annotations, string) {
+	var a Annotations
+	rest := annotationPattern.ReplaceAllStringFunc(comment, func(match string) string {
+		m := annotationPattern.FindStringSubmatch(match)
+		leading, name, argument := m[1], m[2], strings.TrimSpace(m[3])
+		switch name {
+		case "color":
+			if !colorPattern.MatchString(argument) {
+				return match
+			}
+			a.Color = argument
+		case "group":
+			if argument == "" {
+				return match
+			}
+			a.Group = argument
+		case "deprecated":
+			a.Deprecated = true
+			if argument != "" {
+				return leading + argument
+			}
+		}
+		return leading
+	})
+	return a, strings.Join(strings.Fields(rest), " ")
+}
+
+// applyAnnotations moves the annotations in table and column comments into
+// the schema: colors and deprecation onto tables and columns, and groups into
+// TableGroups in the order they first appear.
+func applyAnnotations(s *schema.Schema) {
+	groups := make(map[string]int)
+	for i := range s.Tables {
+		table := &s.Tables[i]
+
+		a, comment := ParseAnnotations(table.Comment)
+		table.Comment = comment
+		if a.Color != "" {
+			table.Color = a.Color
+		}
+		if a.Deprecated {
+			table.Deprecated = true
+		}
+		if a.Group != "" {
+			index, ok := groups[a.Group]
+			if !ok {
+				index = len(s.TableGroups)
+				groups[a.Group] = index
+				s.TableGroups = append(s.TableGroups, schema.TableGroup{Name: a.Group})
+			}
+			s.TableGroups[index].Tables = append(s.TableGroups[index].Tables, schema.TableName{Schema: table.Schema, Name: table.Name})
+		}
+
+		for j := range table.Columns {
+			column := &table.Columns[j]
+			// Colors and groups only apply to tables and are dropped here
+			a, comment := ParseAnnotations(column.Comment)
+			column.Comment = comment
+			if a.Deprecated {
+				column.Deprecated = true
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package introspect
+
+import (
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	tests := []struct {
+		comment     string
+		annotations Annotations
+		rest        string
+	}{
+		{"Customer invoices @color(#ff0000) @group(Billing)", Annotations{Color: "#ff0000", Group: "Billing"}, "Customer invoices"},
+		{"@deprecated", Annotations{Deprecated: true}, ""},
+		{"@deprecated(Use payments) Old payments", Annotations{Deprecated: true}, "Use payments Old payments"},
+		{"@group(Order Management)", Annotations{Group: "Order Management"}, ""},
+		{"Contact admin@example.com", Annotations{}, "Contact admin@example.com"},
+		{"@color(red) @owner(team)", Annotations{}, "@color(red) @owner(team)"},
+		{"@color(#abc)", Annotations{Color: "#abc"}, ""},
+	}
+
+	for _, tt := range tests {
+		annotations, rest := ParseAnnotations(tt.comment)
+		if annotations != tt.annotations || rest != tt.rest {
+			t.Errorf("ParseAnnotations(%q) = %+v, %q, want %+v, %q", tt.comment, annotations, rest, tt.annotations, tt.rest)
+		}
+	}
+}
+
+func TestApplyAnnotations(t *testing.T) {
+	s := &schema.Schema{Tables: []schema.Table{
+		{Name: "invoices", Schema: "public", Comment: "Invoices @color(#e67e22) @group(Billing)"},
+		{Name: "users", Schema: "public", Columns: []schema.Column{
+			{Name: "fax", Comment: "@deprecated Fax number"},
+			{Name: "email", Comment: "Login"},
+		}},
+		{Name: "legacy_payments", Schema: "billing", Comment: "@deprecated @group(Billing)"},
+	}}
+
+	applyAnnotations(s)
+
+	invoices, users, legacy := s.Tables[0], s.Tables[1], s.Tables[2]
+	if invoices.Color != "#e67e22" || invoices.Comment != "Invoices" || invoices.Deprecated {
+		t.Errorf("Unexpected invoices table: %+v", invoices)
+	}
+	if !legacy.Deprecated || legacy.Comment != "" {
+		t.Errorf("Expected legacy_payments to be deprecated, got %+v", legacy)
+	}
+	if !users.Columns[0].Deprecated || users.Columns[0].Comment != "Fax number" || users.Columns[1].Deprecated {
+		t.Errorf("Expected only fax to be deprecated, got %+v", users.Columns)
+	}
+
+	if len(s.TableGroups) != 1 {
+		t.Fatalf("Expected 1 table group, got %+v", s.TableGroups)
+	}
+	group := s.TableGroups[0]
+	want := []schema.TableName{{Schema: "public", Name: "invoices"}, {Schema: "billing", Name: "legacy_payments"}}
+	if group.Name != "Billing" || len(group.Tables) != 2 || group.Tables[0] != want[0] || group.Tables[1] != want[1] {
+		t.Errorf("Expected Billing group with invoices and legacy_payments, got %+v", group)
+	}
+}
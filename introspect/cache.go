@@ -0,0 +1,130 @@
+package introspect
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// catalogVersionQuery summarizes the system catalogs that describe the
+// introspected objects. Any DDL touching a table, column, default,
+// constraint, comment, view definition, installed extension, function,
+// trigger, policy, sequence, type, enum value, or table inheritance writes a
+// new row version to one of these catalogs, which changes the aggregate. The
+// database name keeps databases on one server from sharing cache entries.
+// Catalogs the server predates, pg_policy before 9.5 and pg_sequence before
+// 10, are left out.
+func catalogVersionQuery(o *options) string {
+	catalogs := []string{"pg_attrdef", "pg_constraint", "pg_description", "pg_rewrite", "pg_extension",
+		"pg_proc", "pg_trigger", "pg_type", "pg_enum", "pg_inherits"}
+	if o.supports(version95) {
+		catalogs = append(catalogs, "pg_policy")
+	}
+	if o.supports(version10) {
+		catalogs = append(catalogs, "pg_sequence")
+	}
+
+	query := `
+	SELECT
+		current_database()
+		|| '/' ||
+		(SELECT count(*) || ':' || COALESCE(sum(c.oid::text::bigint), 0) || ':' || COALESCE(sum(c.xmin::text::bigint), 0)
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+				AND n.nspname NOT LIKE 'pg_toast%')
+		|| '/' ||
+		(SELECT count(*) || ':' || COALESCE(sum(a.xmin::text::bigint), 0)
+			FROM pg_attribute a
+			JOIN pg_class c ON c.oid = a.attrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+				AND n.nspname NOT LIKE 'pg_toast%')`
+	for _, catalog := range catalogs {
+		query += fmt.Sprintf(`
+		|| '/' ||
+		(SELECT count(*) || ':' || COALESCE(sum(xmin::text::bigint), 0) FROM %s)`, catalog)
+	}
+	return query + "\n"
+}
+
+// cacheFormat is part of every cache key. Bump it whenever introspection
+// starts collecting new information so older cache entries are not reused.
+const cacheFormat = "35"
+
+func catalogVersion(db *sql.DB, o *options) (string, error) {
+	var version string
+	if err := db.QueryRow(catalogVersionQuery(o)).Scan(&version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// cacheKey derives the cache file name from the catalog version and every
+// option that influences the introspected result.
+func cacheKey(version string, schemaNames []string, o *options) string {
+	excluded := make([]string, len(o.excludeTables))
+	copy(excluded, o.excludeTables)
+	sort.Strings(excluded)
+
+	parts := []string{
+		cacheFormat,
+		version,
+		fmt.Sprintf("server=%d", o.serverVersion),
+		strings.Join(schemaNames, ","),
+		strings.Join(excluded, ","),
+		fmt.Sprintf("%T%v", o.typeMapper, o.typeMapper),
+		"views=" + strings.Join(o.viewKinds(), ","),
+		fmt.Sprintf("sequences=%t", o.sequences),
+		fmt.Sprintf("partitions=%t", o.partitions),
+		fmt.Sprintf("foreign=%t", o.foreignTables),
+		fmt.Sprintf("triggers=%t", o.triggers),
+		fmt.Sprintf("privileges=%t", o.privileges),
+		fmt.Sprintf("functions=%t", o.functions),
+		fmt.Sprintf("system=%t", o.systemSchemas),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+func cachePath(dir, key string) string {
+	return filepath.Join(dir, key+".json")
+}
+
+// loadCachedSchema returns the cached schema for key, or nil if there is no
+// usable cache entry.
+func loadCachedSchema(dir, key string) *schema.Schema {
+	data, err := os.ReadFile(cachePath(dir, key))
+	if err != nil {
+		return nil
+	}
+
+	var cached schema.Schema
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil
+	}
+
+	return &cached
+}
+
+func storeCachedSchema(dir, key string, s *schema.Schema) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(cachePath(dir, key), data, 0644)
+}
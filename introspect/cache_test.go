@@ -0,0 +1,92 @@
+package introspect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	s := &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}},
+		},
+	}
+
+	if cached := loadCachedSchema(dir, "missing"); cached != nil {
+		t.Fatalf("Expected cache miss for unknown key")
+	}
+
+	if err := storeCachedSchema(dir, "key", s); err != nil {
+		t.Fatalf("storeCachedSchema returned error: %v", err)
+	}
+
+	cached := loadCachedSchema(dir, "key")
+	if cached == nil {
+		t.Fatalf("Expected cache hit after storing schema")
+	}
+	if cached.Fingerprint() != s.Fingerprint() {
+		t.Errorf("Cached schema does not match the stored schema")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	o := defaultOptions()
+
+	base := cacheKey("v1", []string{"public"}, o)
+	if base != cacheKey("v1", []string{"public"}, o) {
+		t.Errorf("Expected identical inputs to produce identical keys")
+	}
+	if base == cacheKey("v2", []string{"public"}, o) {
+		t.Errorf("Expected a catalog change to produce a different key")
+	}
+	if base == cacheKey("v1", []string{"public", "auth"}, o) {
+		t.Errorf("Expected different schemas to produce a different key")
+	}
+
+	WithExcludeTables("migrations")(o)
+	if base == cacheKey("v1", []string{"public"}, o) {
+		t.Errorf("Expected excluded tables to change the key")
+	}
+
+	excluded := cacheKey("v1", []string{"public"}, o)
+	WithSystemSchemas()(o)
+	if !o.includeAllSchemas {
+		t.Errorf("Expected WithSystemSchemas to include all schemas")
+	}
+	if excluded == cacheKey("v1", []string{"public"}, o) {
+		t.Errorf("Expected system schemas to change the key")
+	}
+}
+
+func TestCatalogVersionQueryCatalogs(t *testing.T) {
+	tests := []struct {
+		catalog string
+		change  string
+		since   int
+	}{
+		{"pg_proc", "CREATE, ALTER, or DROP FUNCTION", 0},
+		{"pg_trigger", "CREATE TRIGGER on a table that already has triggers", 0},
+		{"pg_policy", "CREATE POLICY", version95},
+		{"pg_sequence", "ALTER SEQUENCE ... INCREMENT", version10},
+		{"pg_type", "CREATE TYPE ... AS ENUM", 0},
+		{"pg_enum", "ALTER TYPE ... ADD VALUE", 0},
+		{"pg_inherits", "ALTER TABLE ... INHERIT", 0},
+	}
+
+	current := catalogVersionQuery(&options{serverVersion: 160000})
+	old := catalogVersionQuery(&options{serverVersion: 90424})
+	for _, tt := range tests {
+		t.Run(tt.catalog, func(t *testing.T) {
+			term := "sum(xmin::text::bigint), 0) FROM " + tt.catalog + ")"
+			if !strings.Contains(current, term) {
+				t.Errorf("Expected the catalog version to read %s, so %s invalidates the cache", tt.catalog, tt.change)
+			}
+			if want := tt.since <= 90424; strings.Contains(old, term) != want {
+				t.Errorf("Expected reading %s on 9.4 to be %t", tt.catalog, want)
+			}
+		})
+	}
+}
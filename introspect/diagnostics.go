@@ -0,0 +1,37 @@
+package introspect
+
+import (
+	"fmt"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// diagnoseTypes reports the table and view columns whose database type was
+// written as text, or as text[] for arrays, for want of a DBML equivalent,
+// such as extension types. Enum columns are written with their Enum block, so
+// they are not reported.
+func diagnoseTypes(s *schema.Schema) {
+	for _, table := range s.Tables {
+		s.Diagnostics = append(s.Diagnostics, typeFallbacks(s, table.Schema, table.Name, table.Columns)...)
+	}
+	for _, view := range s.Views {
+		s.Diagnostics = append(s.Diagnostics, typeFallbacks(s, view.Schema, view.Name, view.Columns)...)
+	}
+}
+
+func typeFallbacks(s *schema.Schema, schemaName, tableName string, columns []schema.Column) []schema.Diagnostic {
+	var diagnostics []schema.Diagnostic
+	for _, column := range columns {
+		if !s.TypeFallback(column) {
+			continue
+		}
+		diagnostics = append(diagnostics, schema.Diagnostic{
+			Code:    schema.DiagnosticTypeFallback,
+			Schema:  schemaName,
+			Table:   tableName,
+			Object:  column.Name,
+			Message: fmt.Sprintf("type %s has no DBML equivalent and was written as %s", column.DatabaseType, column.Type),
+		})
+	}
+	return diagnostics
+}
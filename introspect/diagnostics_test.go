@@ -0,0 +1,46 @@
+package introspect
+
+import (
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestDiagnoseTypes(t *testing.T) {
+	s := &schema.Schema{
+		Enums: []schema.Enum{{Name: "mood", Schema: "public", Values: []string{"happy", "sad"}}},
+		Tables: []schema.Table{{
+			Name:   "users",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "bio", Type: "text", DatabaseType: "text"},
+				{Name: "mood", Type: "text", DatabaseType: "mood"},
+				{Name: "location", Type: "text", DatabaseType: "geography(Point,4326)"},
+				{Name: "tags", Type: "text[]", DatabaseType: "text[]"},
+				{Name: "moods", Type: "text[]", DatabaseType: "mood[]"},
+				{Name: "keywords", Type: "text[]", DatabaseType: "tsvector[]"},
+				{Name: "email", Type: "varchar(255)", DatabaseType: "character varying(255)"},
+			},
+		}},
+		Views: []schema.View{{
+			Name:    "user_moods",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "shape", Type: "text", DatabaseType: "geometry"}},
+		}},
+	}
+
+	diagnoseTypes(s)
+
+	if len(s.Diagnostics) != 3 {
+		t.Fatalf("Expected 3 diagnostics, got %+v", s.Diagnostics)
+	}
+	expected := []string{"users.location", "users.keywords", "user_moods.shape"}
+	for i, d := range s.Diagnostics {
+		if d.Code != schema.DiagnosticTypeFallback || d.Table+"."+d.Object != expected[i] {
+			t.Errorf("Expected a type fallback for %s, got %+v", expected[i], d)
+		}
+	}
+	if got := s.Diagnostics[1].Message; got != "type tsvector[] has no DBML equivalent and was written as text[]" {
+		t.Errorf("Unexpected message: %s", got)
+	}
+}
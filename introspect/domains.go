@@ -0,0 +1,111 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// domainsQuery lists the domain types in the requested schemas with their
+// base type, NOT NULL, default, and CHECK constraints.
+const domainsQuery = `
+	SELECT
+		n.nspname,
+		t.typname,
+		format_type(t.typbasetype, t.typtypmod),
+		t.typnotnull,
+		t.typdefault,
+		COALESCE(obj_description(t.oid, 'pg_type'), ''),
+		ARRAY(SELECT con.conname FROM pg_constraint con
+			WHERE con.contypid = t.oid AND con.contype = 'c'
+			ORDER BY con.conname),
+		ARRAY(SELECT pg_get_expr(con.conbin, 0) FROM pg_constraint con
+			WHERE con.contypid = t.oid AND con.contype = 'c'
+			ORDER BY con.conname)
+	FROM pg_type t
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	WHERE n.nspname = ANY($1) AND t.typtype = 'd'
+	ORDER BY n.nspname, t.typname
+`
+
+// baseTypeSQL returns an expression spelling the type of the pg_attribute
+// row attribute, whose pg_type row is typ, with domains resolved to their
+// base type.
+func baseTypeSQL(attribute, typ string) string {
+	return fmt.Sprintf("format_type(information_schema._pg_truetypid(%[1]s.*, %[2]s.*), information_schema._pg_truetypmod(%[1]s.*, %[2]s.*))",
+		attribute, typ)
+}
+
+// domainSQL returns an expression naming a column's domain the way
+// Column.Domain does, or an empty string when name is NULL.
+func domainSQL(schemaName, name string) string {
+	return fmt.Sprintf("CASE WHEN (%[2]s) IS NULL THEN '' WHEN %[1]s = 'public' THEN (%[2]s)::text ELSE %[1]s || '.' || (%[2]s) END",
+		schemaName, name)
+}
+
+// addDomains fills in s.Domains with the domains in schemaNames, and applies
+// their NOT NULL constraints and defaults to the table columns using them.
+func addDomains(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	start := time.Now()
+	domains, err := getDomains(db, schemaNames)
+	o.recordPhase(PhaseDomains, start, len(domains))
+	if err != nil {
+		return err
+	}
+	s.Domains = domains
+	resolveDomains(s)
+	return nil
+}
+
+func getDomains(db *sql.DB, schemaNames []string) ([]schema.Domain, error) {
+	rows, err := db.Query(domainsQuery, pq.Array(schemaNames))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var domains []schema.Domain
+	for rows.Next() {
+		var domain schema.Domain
+		var defaultValue sql.NullString
+		var names, expressions []string
+		err := rows.Scan(&domain.Schema, &domain.Name, &domain.BaseType, &domain.NotNull,
+			&defaultValue, &domain.Comment, pq.Array(&names), pq.Array(&expressions))
+		if err != nil {
+			return nil, err
+		}
+		if defaultValue.Valid {
+			domain.DefaultValue = &defaultValue.String
+		}
+		for i, name := range names {
+			domain.CheckConstraints = append(domain.CheckConstraints, schema.CheckConstraint{Name: name, Expression: expressions[i]})
+		}
+		domains = append(domains, domain)
+	}
+	return domains, rows.Err()
+}
+
+// resolveDomains applies the NOT NULL constraint and default of each domain
+// to the table columns using it, since information_schema reports those
+// columns as nullable and without a default.
+func resolveDomains(s *schema.Schema) {
+	for i := range s.Tables {
+		columns := s.Tables[i].Columns
+		for j := range columns {
+			domain, ok := s.ColumnDomain(columns[j].Domain)
+			if !ok {
+				continue
+			}
+			if domain.NotNull {
+				columns[j].Nullable = false
+			}
+			if columns[j].DefaultValue == nil && domain.DefaultValue != nil {
+				value := *domain.DefaultValue
+				columns[j].DefaultValue = &value
+			}
+		}
+	}
+}
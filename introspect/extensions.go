@@ -0,0 +1,94 @@
+package introspect
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// extensionsQuery lists the installed extensions.
+const extensionsQuery = `SELECT extname FROM pg_extension ORDER BY extname`
+
+// ExtensionTypeMappings contains the default DBML types of the types that
+// common extensions add, keyed by extension name and then by type name. They
+// apply when the extension is installed, unless WithTypeMappings maps the
+// same type. Types mapped to text, such as PostGIS's geometry, have their
+// database type written as a column note.
+var ExtensionTypeMappings = map[string]map[string]string{
+	"citext": {
+		"citext": "varchar",
+	},
+	"hstore": {
+		"hstore": "json",
+	},
+	"isn": {
+		"ean13":  "varchar",
+		"isbn":   "varchar",
+		"isbn13": "varchar",
+		"ismn":   "varchar",
+		"ismn13": "varchar",
+		"issn":   "varchar",
+		"issn13": "varchar",
+		"upc":    "varchar",
+	},
+	"ltree": {
+		"ltree": "varchar",
+	},
+	"postgis": {
+		"box2d":     "text",
+		"box3d":     "text",
+		"geography": "text",
+		"geometry":  "text",
+	},
+}
+
+// getExtensions returns the names of the installed extensions.
+func getExtensions(db *sql.DB, o *options) ([]string, error) {
+	start := time.Now()
+	rows, err := db.Query(extensionsQuery)
+	if err != nil {
+		o.recordPhase(PhaseExtensions, start, 0)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var extensions []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, name)
+	}
+	o.recordPhase(PhaseExtensions, start, len(extensions))
+	return extensions, rows.Err()
+}
+
+// withExtensionTypes returns mapper with the ExtensionTypeMappings of the
+// installed extensions added. Custom mappings given with WithTypeMappings
+// take precedence, and a TypeMapper set with WithTypeMapper is returned
+// unchanged, since it decides every type itself.
+func withExtensionTypes(mapper TypeMapper, extensions []string) TypeMapper {
+	var custom map[string]string
+	switch m := mapper.(type) {
+	case nil:
+	case *PostgreSQLTypeMapper:
+		custom = m.CustomMappings
+	default:
+		return mapper
+	}
+
+	mappings := make(map[string]string)
+	for _, extension := range extensions {
+		for typeName, dbmlType := range ExtensionTypeMappings[extension] {
+			mappings[typeName] = dbmlType
+		}
+	}
+	if len(mappings) == 0 {
+		return mapper
+	}
+	for typeName, dbmlType := range custom {
+		mappings[strings.ToLower(typeName)] = dbmlType
+	}
+	return NewPostgreSQLTypeMapper(mappings)
+}
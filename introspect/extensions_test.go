@@ -0,0 +1,50 @@
+package introspect
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestWithExtensionTypes(t *testing.T) {
+	mapType := func(mapper TypeMapper, udtName string) string {
+		return mapColumnType(mapper, "USER-DEFINED", udtName, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+	}
+
+	mapper := withExtensionTypes(nil, []string{"citext", "hstore", "plpgsql"})
+	if got := mapType(mapper, "citext"); got != "varchar" {
+		t.Errorf("Expected citext to map to varchar, got %q", got)
+	}
+	if got := mapType(mapper, "hstore"); got != "json" {
+		t.Errorf("Expected hstore to map to json, got %q", got)
+	}
+	if got := mapColumnType(mapper, "ARRAY", "_citext", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}); got != "varchar[]" {
+		t.Errorf("Expected citext[] to map to varchar[], got %q", got)
+	}
+
+	if mapper := withExtensionTypes(nil, []string{"plpgsql"}); mapper != nil {
+		t.Errorf("Expected no mapper without known extensions, got %+v", mapper)
+	}
+
+	custom := NewPostgreSQLTypeMapper(map[string]string{"citext": "text", "ltree": "text"})
+	mapper = withExtensionTypes(custom, []string{"citext", "hstore"})
+	if got := mapType(mapper, "citext"); got != "text" {
+		t.Errorf("Expected the custom citext mapping to win, got %q", got)
+	}
+	if got := mapType(mapper, "hstore"); got != "json" {
+		t.Errorf("Expected hstore to map to json alongside custom mappings, got %q", got)
+	}
+	if got := mapType(mapper, "ltree"); got != "text" {
+		t.Errorf("Expected the custom ltree mapping to be kept, got %q", got)
+	}
+
+	other := mapperFunc(func(string) string { return "custom" })
+	if got := mapType(withExtensionTypes(other, []string{"citext"}), "citext"); got != "custom" {
+		t.Errorf("Expected a custom TypeMapper to be left alone, got %q", got)
+	}
+}
+
+type mapperFunc func(udtName string) string
+
+func (f mapperFunc) MapType(dataType, udtName string, charMaxLength, numericPrecision, numericScale sql.NullInt64) string {
+	return f(udtName)
+}
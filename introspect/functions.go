@@ -0,0 +1,68 @@
+package introspect
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// functionsQuery lists the plain functions and procedures in the requested
+// schemas. Functions an extension installed depend on it ('e') and belong to
+// the extension rather than the schema, so they are left out unless system
+// schemas are included.
+func functionsQuery(o *options) string {
+	return `
+	SELECT
+		n.nspname,
+		p.proname,
+		` + functionKindSQL(o, "p") + `,
+		pg_get_function_arguments(p.oid),
+		COALESCE(pg_get_function_result(p.oid), ''),
+		l.lanname,
+		COALESCE(obj_description(p.oid, 'pg_proc'), '')
+	FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	JOIN pg_language l ON l.oid = p.prolang
+	WHERE n.nspname = ANY($1) AND ` + functionFilterSQL(o, "p") + `
+		AND ($2 OR NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+		))
+	ORDER BY n.nspname, p.proname, pg_get_function_arguments(p.oid)
+`
+}
+
+// addFunctions fills in s.Functions with the functions and procedures in
+// schemaNames.
+func addFunctions(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	start := time.Now()
+	functions, err := getFunctions(db, schemaNames, o)
+	o.recordPhase(PhaseFunctions, start, len(functions))
+	if err != nil {
+		return err
+	}
+	s.Functions = functions
+	return nil
+}
+
+func getFunctions(db *sql.DB, schemaNames []string, o *options) ([]schema.Function, error) {
+	rows, err := db.Query(functionsQuery(o), pq.Array(schemaNames), o.systemSchemas)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var functions []schema.Function
+	for rows.Next() {
+		var function schema.Function
+		err := rows.Scan(&function.Schema, &function.Name, &function.Kind,
+			&function.Arguments, &function.Returns, &function.Language, &function.Comment)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, function)
+	}
+	return functions, rows.Err()
+}
@@ -0,0 +1,60 @@
+package introspect
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// inheritanceQuery lists the parents of the tables in the requested schemas
+// that use table inheritance. Partitions are left out: they are attached to
+// their parent with pg_inherits too, but are not tables of their own.
+func inheritanceQuery(o *options) string {
+	return `
+	SELECT
+		cn.nspname,
+		c.relname,
+		pn.nspname,
+		p.relname
+	FROM pg_inherits i
+	JOIN pg_class c ON c.oid = i.inhrelid
+	JOIN pg_namespace cn ON cn.oid = c.relnamespace
+	JOIN pg_class p ON p.oid = i.inhparent
+	JOIN pg_namespace pn ON pn.oid = p.relnamespace
+	WHERE cn.nspname = ANY($1) AND ` + notPartitionSQL(o, "c") + ` AND c.relkind = 'r'
+	ORDER BY cn.nspname, c.relname, i.inhseqno
+`
+}
+
+// addInheritance fills in Table.Inherits for the tables in s.
+func addInheritance(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	start := time.Now()
+	rows, err := db.Query(inheritanceQuery(o), pq.Array(schemaNames))
+	if err != nil {
+		o.recordPhase(PhaseInheritance, start, 0)
+		return err
+	}
+	defer rows.Close()
+
+	byName := make(map[string]int)
+	for i, table := range s.Tables {
+		byName[table.Schema+"."+table.Name] = i
+	}
+
+	count := 0
+	for rows.Next() {
+		var childSchema, childName string
+		var parent schema.TableName
+		if err := rows.Scan(&childSchema, &childName, &parent.Schema, &parent.Name); err != nil {
+			return err
+		}
+		count++
+		if i, ok := byName[childSchema+"."+childName]; ok {
+			s.Tables[i].Inherits = append(s.Tables[i].Inherits, parent)
+		}
+	}
+	o.recordPhase(PhaseInheritance, start, count)
+	return rows.Err()
+}
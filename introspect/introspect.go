@@ -20,12 +20,11 @@ package introspect
 import (
 	"database/sql"
 	"fmt"
-	"sort"
-	"strings"
+	"slices"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/lucasefe/dbml/schema"
-
-	_ "github.com/lib/pq"
 )
 
 // Database introspects a PostgreSQL database and returns its schema.
@@ -36,9 +35,23 @@ func Database(db *sql.DB, opts ...Option) (*schema.Schema, error) {
 		opt(o)
 	}
 
+	if err := checkReadOnly(db, o); err != nil {
+		return nil, err
+	}
+
+	serverVersion, err := getServerVersion(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	if serverVersion < minServerVersion {
+		return nil, fmt.Errorf("PostgreSQL %s is not supported; introspection needs %s or later",
+			formatServerVersion(serverVersion), formatServerVersion(minServerVersion))
+	}
+	o.serverVersion = serverVersion
+
 	var schemaNames []string
 	if o.includeAllSchemas {
-		schemas, err := getAllSchemas(db)
+		schemas, err := getAllSchemas(db, o.systemSchemas)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get schemas: %w", err)
 		}
@@ -46,14 +59,122 @@ func Database(db *sql.DB, opts ...Option) (*schema.Schema, error) {
 	} else {
 		schemaNames = o.schemas
 	}
+	if len(o.excludeSchemas) > 0 {
+		schemaNames = excludeSchemas(schemaNames, o.excludeSchemas)
+	}
 
-	result, err := introspectSchemas(db, schemaNames, o.typeMapper)
-	if err != nil {
-		return nil, err
+	var key string
+	var result *schema.Schema
+	if o.cacheDir != "" {
+		version, err := catalogVersion(db, o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog version: %w", err)
+		}
+		key = cacheKey(version, schemaNames, o)
+		result = loadCachedSchema(o.cacheDir, key)
+	}
+
+	if result == nil {
+		extensions, err := getExtensions(db, o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get extensions: %w", err)
+		}
+		o.typeMapper = withExtensionTypes(o.typeMapper, extensions)
+		ranges, err := getRanges(db, schemaNames, o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get range types: %w", err)
+		}
+		o.typeMapper = withRangeTypes(o.typeMapper, ranges)
+
+		if o.singleQuery {
+			result, err = introspectSingleQuery(db, schemaNames, o)
+		} else {
+			result, err = introspectSchemas(db, schemaNames, o)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := addInheritance(db, result, schemaNames, o); err != nil {
+			return nil, fmt.Errorf("failed to get table inheritance: %w", err)
+		}
+		if err := addRowSecurity(db, result, schemaNames, o); err != nil {
+			return nil, fmt.Errorf("failed to get row level security: %w", err)
+		}
+		if kinds := o.viewKinds(); len(kinds) > 0 {
+			if err := addViews(db, result, schemaNames, kinds, o); err != nil {
+				return nil, fmt.Errorf("failed to get views: %w", err)
+			}
+		}
+		if o.partitions {
+			if err := addPartitions(db, result, schemaNames, o); err != nil {
+				return nil, fmt.Errorf("failed to get partitions: %w", err)
+			}
+		}
+		if o.sequences {
+			if err := addSequences(db, result, schemaNames, o); err != nil {
+				return nil, fmt.Errorf("failed to get sequences: %w", err)
+			}
+		}
+		if o.triggers {
+			if err := addTriggers(db, result, schemaNames, o); err != nil {
+				return nil, fmt.Errorf("failed to get triggers: %w", err)
+			}
+		}
+		if o.privileges {
+			if err := addPrivileges(db, result, schemaNames, o); err != nil {
+				return nil, fmt.Errorf("failed to get privileges: %w", err)
+			}
+		}
+		if o.functions {
+			if err := addFunctions(db, result, schemaNames, o); err != nil {
+				return nil, fmt.Errorf("failed to get functions: %w", err)
+			}
+		}
+		if err := addDomains(db, result, schemaNames, o); err != nil {
+			return nil, fmt.Errorf("failed to get domains: %w", err)
+		}
+		result.Ranges = ranges
+		result.ServerVersion = o.serverVersion
+		if slices.Contains(extensions, "postgis") {
+			if err := addPostGISTypes(db, result, schemaNames, o); err != nil {
+				return nil, fmt.Errorf("failed to get PostGIS column types: %w", err)
+			}
+		}
+
+		diagnoseTypes(result)
+
+		if len(o.excludeTables) > 0 {
+			result = schema.FilterTables(result, o.excludeTables)
+		}
+
+		if o.cacheDir != "" {
+			if err := storeCachedSchema(o.cacheDir, key, result); err != nil {
+				return nil, fmt.Errorf("failed to write schema cache: %w", err)
+			}
+		}
+	}
+
+	// INCLUDE columns are always cached, so the option does not split the cache
+	if !o.indexInclude {
+		dropIndexInclude(result)
+	}
+	// Annotations are applied after caching, so the cache keeps the comments whole
+	if o.annotations {
+		applyAnnotations(result)
 	}
 
-	if len(o.excludeTables) > 0 {
-		result = schema.FilterTables(result, o.excludeTables)
+	// Statistics and migration versions change with the data rather than
+	// the catalog, so they are never cached.
+	if o.statistics {
+		if err := addStatistics(db, result, o); err != nil {
+			return nil, fmt.Errorf("failed to get table statistics: %w", err)
+		}
+	}
+	if o.migration {
+		if err := addMigration(db, result, o); err != nil {
+			return nil, fmt.Errorf("failed to get migration version: %w", err)
+		}
 	}
 
 	return result, nil
@@ -75,7 +196,7 @@ func FromConnectionString(connStr string, opts ...Option) (*schema.Schema, error
 	return Database(db, opts...)
 }
 
-func introspectSchemas(db *sql.DB, schemaNames []string, mapper TypeMapper) (*schema.Schema, error) {
+func introspectSchemas(db *sql.DB, schemaNames []string, o *options) (*schema.Schema, error) {
 	if len(schemaNames) == 0 {
 		schemaNames = []string{"public"}
 	}
@@ -83,19 +204,33 @@ func introspectSchemas(db *sql.DB, schemaNames []string, mapper TypeMapper) (*sc
 	result := &schema.Schema{}
 
 	for _, schemaName := range schemaNames {
-		tables, err := getTables(db, schemaName)
+		start := time.Now()
+		tables, err := getTables(db, schemaName, o)
+		o.recordPhase(PhaseTables, start, len(tables))
 		if err != nil {
 			return nil, fmt.Errorf("failed to get tables for schema %s: %w", schemaName, err)
 		}
 
+		start = time.Now()
+		enums, err := getEnums(db, schemaName)
+		o.recordPhase(PhaseEnums, start, len(enums))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get enums for schema %s: %w", schemaName, err)
+		}
+		result.Enums = append(result.Enums, enums...)
+
 		for _, table := range tables {
-			columns, err := getColumns(db, schemaName, table.Name, mapper)
+			start = time.Now()
+			columns, err := getColumns(db, schemaName, table.Name, o.typeMapper)
+			o.recordPhase(PhaseColumns, start, len(columns))
 			if err != nil {
 				return nil, fmt.Errorf("failed to get columns for table %s.%s: %w", schemaName, table.Name, err)
 			}
 			table.Columns = columns
 
+			start = time.Now()
 			primaryKeys, err := getPrimaryKeys(db, schemaName, table.Name)
+			o.recordPhase(PhasePrimaryKeys, start, len(primaryKeys))
 			if err != nil {
 				return nil, fmt.Errorf("failed to get primary keys for table %s.%s: %w", schemaName, table.Name, err)
 			}
@@ -110,18 +245,46 @@ func introspectSchemas(db *sql.DB, schemaNames []string, mapper TypeMapper) (*sc
 				}
 			}
 
-			indexes, err := getIndexes(db, schemaName, table.Name)
+			start = time.Now()
+			indexes, err := getIndexes(db, schemaName, table.Name, o)
+			o.recordPhase(PhaseIndexes, start, len(indexes))
 			if err != nil {
 				return nil, fmt.Errorf("failed to get indexes for table %s.%s: %w", schemaName, table.Name, err)
 			}
 			table.Indexes = indexes
 
+			start = time.Now()
 			references, err := getForeignKeys(db, schemaName, table.Name)
+			o.recordPhase(PhaseForeignKeys, start, len(references))
 			if err != nil {
 				return nil, fmt.Errorf("failed to get foreign keys for table %s.%s: %w", schemaName, table.Name, err)
 			}
 			table.References = references
 
+			start = time.Now()
+			uniques, err := getUniqueConstraints(db, schemaName, table.Name)
+			o.recordPhase(PhaseUniques, start, len(uniques))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get unique constraints for table %s.%s: %w", schemaName, table.Name, err)
+			}
+			table.UniqueConstraints = uniques
+
+			start = time.Now()
+			checks, err := getCheckConstraints(db, schemaName, table.Name)
+			o.recordPhase(PhaseChecks, start, len(checks))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get check constraints for table %s.%s: %w", schemaName, table.Name, err)
+			}
+			table.CheckConstraints = checks
+
+			start = time.Now()
+			exclusions, err := getExclusionConstraints(db, schemaName, table.Name)
+			o.recordPhase(PhaseExclusions, start, len(exclusions))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get exclusion constraints for table %s.%s: %w", schemaName, table.Name, err)
+			}
+			table.ExclusionConstraints = exclusions
+
 			result.Tables = append(result.Tables, table)
 		}
 	}
@@ -129,15 +292,32 @@ func introspectSchemas(db *sql.DB, schemaNames []string, mapper TypeMapper) (*sc
 	return result, nil
 }
 
-func getAllSchemas(db *sql.DB) ([]string, error) {
+// excludeSchemas returns names without the excluded schemas.
+func excludeSchemas(names, excluded []string) []string {
+	skip := make(map[string]bool)
+	for _, name := range excluded {
+		skip[name] = true
+	}
+	var result []string
+	for _, name := range names {
+		if !skip[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// getAllSchemas lists the schemas in the database, leaving out the system
+// schemas unless system is set.
+func getAllSchemas(db *sql.DB, system bool) ([]string, error) {
 	query := `
 		SELECT schema_name
 		FROM information_schema.schemata
-		WHERE schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast', 'pg_temp_1', 'pg_toast_temp_1')
+		WHERE $1 OR schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast', 'pg_temp_1', 'pg_toast_temp_1')
 		ORDER BY schema_name
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, system)
 	if err != nil {
 		return nil, err
 	}
@@ -155,15 +335,35 @@ func getAllSchemas(db *sql.DB) ([]string, error) {
 	return schemas, rows.Err()
 }
 
-func getTables(db *sql.DB, schemaName string) ([]schema.Table, error) {
+// tablespaceSQL returns SQL naming the tablespace of the pg_class row
+// relation, or an empty string for the database's default tablespace, which
+// reltablespace records as 0.
+func tablespaceSQL(relation string) string {
+	return fmt.Sprintf("COALESCE((SELECT spcname FROM pg_tablespace WHERE oid = %s.reltablespace), '')", relation)
+}
+
+func getTables(db *sql.DB, schemaName string, o *options) ([]schema.Table, error) {
 	query := `
-		SELECT table_name
-		FROM information_schema.tables
-		WHERE table_schema = $1 AND table_type = 'BASE TABLE'
-		ORDER BY table_name
+		SELECT
+			t.table_name,
+			COALESCE(obj_description(c.oid, 'pg_class'), '') as comment,
+			` + partitionKeySQL(o, "c") + ` as partition_key,
+			fs.srvname as foreign_server,
+			COALESCE(ft.ftoptions, '{}') as foreign_options,
+			` + tablespaceSQL("c") + ` as tablespace,
+			pg_get_userbyid(c.relowner) as owner
+		FROM information_schema.tables t
+		JOIN pg_namespace n ON n.nspname = t.table_schema
+		JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = t.table_name
+		LEFT JOIN pg_foreign_table ft ON ft.ftrelid = c.oid
+		LEFT JOIN pg_foreign_server fs ON fs.oid = ft.ftserver
+		WHERE t.table_schema = $1
+			AND (t.table_type = 'BASE TABLE' OR ($2 AND t.table_type = 'FOREIGN'))
+			AND ` + notPartitionSQL(o, "c") + `
+		ORDER BY t.table_name
 	`
 
-	rows, err := db.Query(query, schemaName)
+	rows, err := db.Query(query, schemaName, o.foreignTables)
 	if err != nil {
 		return nil, err
 	}
@@ -171,31 +371,84 @@ func getTables(db *sql.DB, schemaName string) ([]schema.Table, error) {
 
 	var tables []schema.Table
 	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
+		var tableName, comment, partitionKey, tablespace, owner string
+		var foreignServer sql.NullString
+		var foreignOptions []string
+		if err := rows.Scan(&tableName, &comment, &partitionKey, &foreignServer, pq.Array(&foreignOptions), &tablespace, &owner); err != nil {
 			return nil, err
 		}
-		tables = append(tables, schema.Table{
-			Name:   tableName,
-			Schema: schemaName,
-		})
+		table := schema.Table{
+			Name:         tableName,
+			Schema:       schemaName,
+			Comment:      comment,
+			PartitionKey: partitionKey,
+			Tablespace:   tablespace,
+			Owner:        owner,
+		}
+		if foreignServer.Valid {
+			table.Foreign = &schema.ForeignTable{Server: foreignServer.String, Options: foreignOptions}
+		}
+		tables = append(tables, table)
 	}
 
 	return tables, rows.Err()
 }
 
+func getEnums(db *sql.DB, schemaName string) ([]schema.Enum, error) {
+	query := `
+		SELECT
+			t.typname,
+			array_agg(e.enumlabel ORDER BY e.enumsortorder) as labels
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		WHERE n.nspname = $1
+		GROUP BY t.typname
+		ORDER BY t.typname
+	`
+
+	rows, err := db.Query(query, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var enums []schema.Enum
+	for rows.Next() {
+		enum := schema.Enum{Schema: schemaName}
+		if err := rows.Scan(&enum.Name, pq.Array(&enum.Values)); err != nil {
+			return nil, err
+		}
+		enums = append(enums, enum)
+	}
+
+	return enums, rows.Err()
+}
+
 func getColumns(db *sql.DB, schemaName, tableName string, mapper TypeMapper) ([]schema.Column, error) {
 	query := `
 		SELECT
 			c.column_name,
+			c.ordinal_position,
 			c.data_type,
-			c.character_maximum_length,
+			` + characterMaximumLengthSQL("c.character_maximum_length", "c.udt_name", "a.atttypmod") + `,
 			c.numeric_precision,
 			c.numeric_scale,
 			c.is_nullable,
 			c.column_default,
-			COALESCE(c.udt_name, c.data_type) as udt_name
+			COALESCE(c.udt_name, c.data_type) as udt_name,
+			COALESCE(` + baseTypeSQL("a", "t") + `, '') as database_type,
+			COALESCE(col_description(a.attrelid, a.attnum), '') as comment,
+			CASE WHEN c.is_generated = 'ALWAYS' THEN COALESCE(c.generation_expression, '') ELSE '' END as generated,
+			CASE WHEN c.is_identity = 'YES' THEN COALESCE(c.identity_generation, '') ELSE '' END as identity,
+			` + domainSQL("c.domain_schema", "c.domain_name") + ` as domain,
+			` + collationSQL("c.collation_schema", "c.collation_name") + ` as collation,
+			` + ownedSequenceSQL("a.attrelid", "a.attnum") + ` as auto_increment
 		FROM information_schema.columns c
+		LEFT JOIN pg_attribute a
+			ON a.attrelid = (quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass
+			AND a.attnum = c.ordinal_position
+		LEFT JOIN pg_type t ON t.oid = a.atttypid
 		WHERE c.table_schema = $1 AND c.table_name = $2
 		ORDER BY c.ordinal_position
 	`
@@ -217,6 +470,7 @@ func getColumns(db *sql.DB, schemaName, tableName string, mapper TypeMapper) ([]
 
 		err := rows.Scan(
 			&col.Name,
+			&col.OrdinalPosition,
 			&dataType,
 			&charMaxLength,
 			&numericPrecision,
@@ -224,16 +478,19 @@ func getColumns(db *sql.DB, schemaName, tableName string, mapper TypeMapper) ([]
 			&isNullable,
 			&columnDefault,
 			&udtName,
+			&col.DatabaseType,
+			&col.Comment,
+			&col.Generated,
+			&col.Identity,
+			&col.Domain,
+			&col.Collation,
+			&col.AutoIncrement,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		if mapper != nil {
-			col.Type = mapper.MapType(dataType, udtName, charMaxLength, numericPrecision, numericScale)
-		} else {
-			col.Type = MapPostgreSQLTypeToDBML(dataType, udtName, charMaxLength, numericPrecision, numericScale)
-		}
+		col.Type = mapColumnType(mapper, dataType, udtName, charMaxLength, numericPrecision, numericScale)
 		col.Nullable = isNullable == "YES"
 		if columnDefault.Valid {
 			col.DefaultValue = &columnDefault.String
@@ -245,6 +502,13 @@ func getColumns(db *sql.DB, schemaName, tableName string, mapper TypeMapper) ([]
 	return columns, rows.Err()
 }
 
+func mapColumnType(mapper TypeMapper, dataType, udtName string, charMaxLength, numericPrecision, numericScale sql.NullInt64) string {
+	if mapper != nil {
+		return mapper.MapType(dataType, udtName, charMaxLength, numericPrecision, numericScale)
+	}
+	return MapPostgreSQLTypeToDBML(dataType, udtName, charMaxLength, numericPrecision, numericScale)
+}
+
 func getPrimaryKeys(db *sql.DB, schemaName, tableName string) ([]string, error) {
 	query := `
 		SELECT column_name
@@ -276,22 +540,50 @@ func getPrimaryKeys(db *sql.DB, schemaName, tableName string) ([]string, error)
 	return primaryKeys, rows.Err()
 }
 
-func getIndexes(db *sql.DB, schemaName, tableName string) ([]schema.Index, error) {
+// dropIndexInclude removes INCLUDE columns from every index in s.
+func dropIndexInclude(s *schema.Schema) {
+	for i := range s.Tables {
+		for j := range s.Tables[i].Indexes {
+			s.Tables[i].Indexes[j].Include = nil
+		}
+	}
+}
+
+// getIndexes returns the table's indexes. Index expressions are listed among
+// the columns in backticks, as DBML writes them.
+func getIndexes(db *sql.DB, schemaName, tableName string, o *options) ([]schema.Index, error) {
+	// indkey lists key columns first, then the indnkeyatts..indnatts INCLUDE
+	// columns, each group in index definition order. Expressions have
+	// attnum 0 and no attribute.
+	keyCount := indexKeyCountSQL(o, "idx")
 	query := `
 		SELECT
-			i.indexname,
-			array_agg(a.attname ORDER BY array_position(idx.indkey::int[], a.attnum)) as columns,
-			i.indexdef LIKE '%UNIQUE%' as is_unique
-		FROM pg_indexes i
-		JOIN pg_class c ON c.relname = i.tablename
+			ic.relname,
+			array_agg(` + indexKeySQL("k", "a", "idx") + ` ORDER BY k.ord) FILTER (WHERE k.ord <= ` + keyCount + `) as columns,
+			array_agg(a.attname ORDER BY k.ord) FILTER (WHERE k.ord > ` + keyCount + ` AND a.attname IS NOT NULL) as include,
+			idx.indisunique,
+			am.amname,
+			EXISTS (
+				SELECT 1 FROM pg_constraint con
+				WHERE con.conrelid = c.oid AND con.conindid = idx.indexrelid AND con.contype = 'u'
+			) as is_constraint,
+			COALESCE(pg_get_expr(idx.indpred, idx.indrelid), '') as predicate,
+			` + tablespaceSQL("ic") + ` as tablespace
+		FROM pg_index idx
+		JOIN pg_class c ON c.oid = idx.indrelid
 		JOIN pg_namespace n ON n.oid = c.relnamespace
-		JOIN pg_class ic ON ic.relname = i.indexname
-		JOIN pg_index idx ON idx.indexrelid = ic.oid AND idx.indrelid = c.oid
-		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(idx.indkey)
-		WHERE n.nspname = $1 AND i.tablename = $2
+		JOIN pg_class ic ON ic.oid = idx.indexrelid
+		JOIN pg_am am ON am.oid = ic.relam
+		CROSS JOIN LATERAL unnest(idx.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+		WHERE n.nspname = $1 AND c.relname = $2
 			AND NOT idx.indisprimary
-		GROUP BY i.indexname, i.indexdef
-		ORDER BY i.indexname
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_constraint con
+				WHERE con.conrelid = c.oid AND con.conindid = idx.indexrelid AND con.contype = 'x'
+			)
+		GROUP BY ic.relname, ic.reltablespace, idx.indexrelid, idx.indisunique, ` + keyCount + `, c.oid, am.amname, predicate
+		ORDER BY ic.relname
 	`
 
 	rows, err := db.Query(query, schemaName, tableName)
@@ -303,44 +595,51 @@ func getIndexes(db *sql.DB, schemaName, tableName string) ([]schema.Index, error
 	var indexes []schema.Index
 	for rows.Next() {
 		var index schema.Index
-		var columnsArray string
-		var isUnique bool
-
-		err := rows.Scan(&index.Name, &columnsArray, &isUnique)
+		var method string
+		err := rows.Scan(&index.Name, pq.Array(&index.Columns), pq.Array(&index.Include), &index.Unique, &method, &index.UniqueConstraint, &index.Where, &index.Tablespace)
 		if err != nil {
 			return nil, err
 		}
-
-		columnsArray = strings.Trim(columnsArray, "{}")
-		index.Columns = strings.Split(columnsArray, ",")
-		index.Unique = isUnique
-
+		index.Type = indexType(method)
 		indexes = append(indexes, index)
 	}
 
 	return indexes, rows.Err()
 }
 
-func getForeignKeys(db *sql.DB, schemaName, tableName string) ([]schema.Reference, error) {
+// indexKeySQL returns an expression naming an index key: the column name for
+// a column, or the expression in backticks, as DBML writes it, for an
+// expression. key is the unnested indkey with attnum and ord, attribute its
+// pg_attribute row, and index the pg_index row.
+func indexKeySQL(key, attribute, index string) string {
+	return fmt.Sprintf("CASE WHEN %[1]s.attnum = 0 THEN '`' || pg_get_indexdef(%[3]s.indexrelid, %[1]s.ord::int, true) || '`' ELSE %[2]s.attname::text END",
+		key, attribute, index)
+}
+
+// indexType returns the Index.Type for an access method, which is empty for
+// the default, btree.
+func indexType(method string) string {
+	if method == "btree" {
+		return ""
+	}
+	return method
+}
+
+func getCheckConstraints(db *sql.DB, schemaName, tableName string) ([]schema.CheckConstraint, error) {
 	query := `
-		SELECT DISTINCT
-			kcu1.column_name,
-			kcu2.table_schema AS foreign_table_schema,
-			kcu2.table_name AS foreign_table_name,
-			kcu2.column_name AS foreign_column_name,
-			rc.delete_rule,
-			rc.update_rule,
-			kcu1.ordinal_position
-		FROM information_schema.referential_constraints rc
-		JOIN information_schema.key_column_usage kcu1
-			ON kcu1.constraint_name = rc.constraint_name
-			AND kcu1.table_schema = rc.constraint_schema
-		JOIN information_schema.key_column_usage kcu2
-			ON kcu2.constraint_name = rc.unique_constraint_name
-			AND kcu2.table_schema = rc.unique_constraint_schema
-			AND kcu2.ordinal_position = kcu1.ordinal_position
-		WHERE kcu1.table_schema = $1 AND kcu1.table_name = $2
-		ORDER BY kcu1.ordinal_position
+		SELECT
+			con.conname,
+			ARRAY(
+				SELECT a.attname FROM pg_attribute a
+				WHERE a.attrelid = con.conrelid AND a.attnum = ANY(con.conkey)
+				ORDER BY a.attnum
+			) as columns,
+			pg_get_expr(con.conbin, con.conrelid) as expression
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2 AND con.contype = 'c'
+		ORDER BY con.conname
 	`
 
 	rows, err := db.Query(query, schemaName, tableName)
@@ -349,57 +648,180 @@ func getForeignKeys(db *sql.DB, schemaName, tableName string) ([]schema.Referenc
 	}
 	defer rows.Close()
 
-	referenceMap := make(map[string]schema.Reference)
+	var checks []schema.CheckConstraint
 	for rows.Next() {
-		var ref schema.Reference
-		var fromColumn, toColumn string
-		var ordinalPosition int
+		var check schema.CheckConstraint
+		if err := rows.Scan(&check.Name, pq.Array(&check.Columns), &check.Expression); err != nil {
+			return nil, err
+		}
+		if len(check.Columns) == 0 {
+			check.Columns = nil
+		}
+		checks = append(checks, check)
+	}
 
-		err := rows.Scan(
-			&fromColumn,
-			&ref.ToSchema,
-			&ref.ToTable,
-			&toColumn,
-			&ref.OnDelete,
-			&ref.OnUpdate,
-			&ordinalPosition,
-		)
-		if err != nil {
+	return checks, rows.Err()
+}
+
+// getUniqueConstraints lists a table's UNIQUE constraints, sorted by name,
+// each with its columns in constraint order.
+func getUniqueConstraints(db *sql.DB, schemaName, tableName string) ([]schema.UniqueConstraint, error) {
+	query := `
+		SELECT tc.constraint_name, kcu.column_name
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = tc.constraint_schema
+			AND kcu.constraint_name = tc.constraint_name
+			AND kcu.table_schema = tc.table_schema
+			AND kcu.table_name = tc.table_name
+		WHERE tc.table_schema = $1 AND tc.table_name = $2 AND tc.constraint_type = 'UNIQUE'
+		ORDER BY tc.constraint_name, kcu.ordinal_position
+	`
+
+	rows, err := db.Query(query, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Each row is one column; the table is part of the join, so rows with
+	// the same constraint name belong to one constraint
+	var uniques []schema.UniqueConstraint
+	for rows.Next() {
+		var name, column string
+		if err := rows.Scan(&name, &column); err != nil {
 			return nil, err
 		}
+		if n := len(uniques); n > 0 && uniques[n-1].Name == name {
+			uniques[n-1].Columns = append(uniques[n-1].Columns, column)
+			continue
+		}
+		uniques = append(uniques, schema.UniqueConstraint{Name: name, Columns: []string{column}})
+	}
 
-		ref.FromTable = tableName
-		ref.FromSchema = schemaName
-		ref.FromColumns = []string{fromColumn}
-		ref.ToColumns = []string{toColumn}
+	return uniques, rows.Err()
+}
 
-		key := fmt.Sprintf("%s.%s.%s->%s.%s.%s",
-			schemaName, tableName, fromColumn,
-			ref.ToSchema, ref.ToTable, toColumn)
+// getExclusionConstraints lists a table's EXCLUDE constraints. Their
+// indexes are left out of getIndexes, since the constraint describes them.
+func getExclusionConstraints(db *sql.DB, schemaName, tableName string) ([]schema.ExclusionConstraint, error) {
+	query := `
+		SELECT con.conname, pg_get_constraintdef(con.oid, true)
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2 AND con.contype = 'x'
+		ORDER BY con.conname
+	`
 
-		if existing, exists := referenceMap[key]; exists {
-			if ref.OnDelete != "NO ACTION" && ref.OnDelete != "" && existing.OnDelete == "NO ACTION" {
-				existing.OnDelete = ref.OnDelete
-			}
-			if ref.OnUpdate != "NO ACTION" && ref.OnUpdate != "" && existing.OnUpdate == "NO ACTION" {
-				existing.OnUpdate = ref.OnUpdate
-			}
-			referenceMap[key] = existing
-		} else {
-			referenceMap[key] = ref
+	rows, err := db.Query(query, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exclusions []schema.ExclusionConstraint
+	for rows.Next() {
+		var exclusion schema.ExclusionConstraint
+		if err := rows.Scan(&exclusion.Name, &exclusion.Definition); err != nil {
+			return nil, err
 		}
+		exclusions = append(exclusions, exclusion)
 	}
 
-	var keys []string
-	for key := range referenceMap {
-		keys = append(keys, key)
+	return exclusions, rows.Err()
+}
+
+// getForeignKeys returns the foreign keys of a table, one per constraint with
+// its columns in key order, sorted by constraint name. Columns are read from
+// the constraint's conkey and confkey, since constraint names are only unique
+// per table.
+func getForeignKeys(db *sql.DB, schemaName, tableName string) ([]schema.Reference, error) {
+	query := `
+		SELECT
+			con.oid,
+			con.conname,
+			a.attname,
+			fn.nspname,
+			fc.relname,
+			fa.attname,
+			` + referentialActionSQL("con.confdeltype") + `,
+			` + referentialActionSQL("con.confupdtype") + `,
+			CASE con.confmatchtype WHEN 'f' THEN 'FULL' WHEN 'p' THEN 'PARTIAL' ELSE '' END,
+			con.condeferrable,
+			con.condeferred
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_class fc ON fc.oid = con.confrelid
+		JOIN pg_namespace fn ON fn.oid = fc.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY k(attnum, fattnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute fa ON fa.attrelid = con.confrelid AND fa.attnum = k.fattnum
+		WHERE n.nspname = $1 AND c.relname = $2 AND con.contype = 'f'
+		ORDER BY con.conname, con.oid, k.ord
+	`
+
+	rows, err := db.Query(query, schemaName, tableName)
+	if err != nil {
+		return nil, err
 	}
-	sort.Strings(keys)
+	defer rows.Close()
 
-	var references []schema.Reference
-	for _, key := range keys {
-		references = append(references, referenceMap[key])
+	var keyRows []foreignKeyRow
+	for rows.Next() {
+		row := foreignKeyRow{ref: schema.Reference{FromTable: tableName, FromSchema: schemaName}}
+		err := rows.Scan(
+			&row.oid,
+			&row.ref.Name,
+			&row.fromColumn,
+			&row.ref.ToSchema,
+			&row.ref.ToTable,
+			&row.toColumn,
+			&row.ref.OnDelete,
+			&row.ref.OnUpdate,
+			&row.ref.Match,
+			&row.ref.Deferrable,
+			&row.ref.InitiallyDeferred,
+		)
+		if err != nil {
+			return nil, err
+		}
+		keyRows = append(keyRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return references, rows.Err()
+	return mergeForeignKeyRows(keyRows), nil
+}
+
+// foreignKeyRow is one column pair of a foreign key constraint: the
+// constraint's oid, its reference without columns, and the columns.
+type foreignKeyRow struct {
+	oid        int64
+	ref        schema.Reference
+	fromColumn string
+	toColumn   string
+}
+
+// mergeForeignKeyRows turns rows, grouped by constraint with columns in key
+// order, into one reference per constraint. Rows belong to the same
+// constraint only if their oids match, since two constraints can share a
+// name.
+func mergeForeignKeyRows(rows []foreignKeyRow) []schema.Reference {
+	var references []schema.Reference
+	for i, row := range rows {
+		if i > 0 && rows[i-1].oid == row.oid {
+			last := &references[len(references)-1]
+			last.FromColumns = append(last.FromColumns, row.fromColumn)
+			last.ToColumns = append(last.ToColumns, row.toColumn)
+			continue
+		}
+		ref := row.ref
+		ref.FromColumns = []string{row.fromColumn}
+		ref.ToColumns = []string{row.toColumn}
+		references = append(references, ref)
+	}
+	return references
 }
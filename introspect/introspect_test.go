@@ -0,0 +1,106 @@
+package introspect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestExcludeSchemas(t *testing.T) {
+	result := excludeSchemas([]string{"auth", "public", "storage", "billing"}, []string{"auth", "storage", "missing"})
+
+	expected := []string{"public", "billing"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("excludeSchemas() = %v, want %v", result, expected)
+	}
+}
+
+func TestDropIndexInclude(t *testing.T) {
+	s := &schema.Schema{Tables: []schema.Table{{
+		Name:    "orders",
+		Indexes: []schema.Index{{Name: "idx_orders_customer", Columns: []string{"customer_id"}, Include: []string{"total"}}},
+	}}}
+
+	dropIndexInclude(s)
+
+	if s.Tables[0].Indexes[0].Include != nil {
+		t.Errorf("Expected INCLUDE columns to be dropped, got %v", s.Tables[0].Indexes[0].Include)
+	}
+	if len(s.Tables[0].Indexes[0].Columns) != 1 {
+		t.Errorf("Expected key columns to be kept, got %v", s.Tables[0].Indexes[0].Columns)
+	}
+}
+
+func TestViewKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"none", nil, nil},
+		{"views", []Option{WithViews()}, []string{"v", "m"}},
+		{"materialized only", []Option{WithMaterializedViews()}, []string{"m"}},
+		{"views without materialized", []Option{WithViews(), WithoutMaterializedViews()}, []string{"v"}},
+		{"excluded", []Option{WithMaterializedViews(), WithoutMaterializedViews()}, nil},
+	}
+
+	for _, tt := range tests {
+		o := &options{}
+		for _, opt := range tt.opts {
+			opt(o)
+		}
+		if got := o.viewKinds(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: viewKinds() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveDomains(t *testing.T) {
+	defaultValue := "'unknown'::text"
+	s := &schema.Schema{
+		Domains: []schema.Domain{{Name: "email_address", Schema: "billing", NotNull: true, DefaultValue: &defaultValue}},
+		Tables: []schema.Table{{
+			Name: "customers",
+			Columns: []schema.Column{
+				{Name: "email", Type: "varchar(320)", Nullable: true, Domain: "billing.email_address"},
+				{Name: "name", Type: "text", Nullable: true},
+			},
+		}},
+	}
+
+	resolveDomains(s)
+
+	email := s.Tables[0].Columns[0]
+	if email.Nullable || email.DefaultValue == nil || *email.DefaultValue != defaultValue {
+		t.Errorf("Expected email to take the domain's NOT NULL and default, got %+v", email)
+	}
+	if name := s.Tables[0].Columns[1]; !name.Nullable || name.DefaultValue != nil {
+		t.Errorf("Expected name to be left alone, got %+v", name)
+	}
+}
+
+func TestMergeForeignKeyRows(t *testing.T) {
+	posts := schema.Reference{Name: "fk_user", FromSchema: "public", FromTable: "posts", ToSchema: "public", ToTable: "users"}
+	accounts := schema.Reference{Name: "fk_account", FromSchema: "public", FromTable: "posts", ToSchema: "billing", ToTable: "accounts"}
+	rows := []foreignKeyRow{
+		{oid: 10, ref: accounts, fromColumn: "account_id", toColumn: "id"},
+		{oid: 10, ref: accounts, fromColumn: "region", toColumn: "region"},
+		// Two constraints named fk_user, such as on two tables in one schema
+		{oid: 20, ref: posts, fromColumn: "user_id", toColumn: "id"},
+		{oid: 21, ref: posts, fromColumn: "user_id", toColumn: "id"},
+	}
+
+	refs := mergeForeignKeyRows(rows)
+	if len(refs) != 3 {
+		t.Fatalf("Expected 3 references, got %d: %+v", len(refs), refs)
+	}
+	if !reflect.DeepEqual(refs[0].FromColumns, []string{"account_id", "region"}) || !reflect.DeepEqual(refs[0].ToColumns, []string{"id", "region"}) {
+		t.Errorf("Expected a composite reference, got %+v", refs[0])
+	}
+	for _, ref := range refs[1:] {
+		if !reflect.DeepEqual(ref.FromColumns, []string{"user_id"}) || !reflect.DeepEqual(ref.ToColumns, []string{"id"}) {
+			t.Errorf("Expected fk_user to stay a single-column reference, got %+v", ref)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SchemaChangeChannel is the LISTEN/NOTIFY channel the event trigger
+// installed by InstallEventTrigger notifies after every DDL command. The
+// payload is the command tag, such as "ALTER TABLE".
+const SchemaChangeChannel = "dbml_schema_change"
+
+// installEventTriggerSQL uses EXECUTE PROCEDURE rather than EXECUTE FUNCTION
+// so it also runs on PostgreSQL 10. ddl_command_end fires for DROP and
+// COMMENT as well as CREATE and ALTER.
+var installEventTriggerSQL = []string{
+	`CREATE OR REPLACE FUNCTION public.dbml_notify_schema_change() RETURNS event_trigger
+	LANGUAGE plpgsql AS $$
+	BEGIN
+		PERFORM pg_notify('` + SchemaChangeChannel + `', tg_tag);
+	END;
+	$$`,
+	`DROP EVENT TRIGGER IF EXISTS dbml_schema_change`,
+	`CREATE EVENT TRIGGER dbml_schema_change ON ddl_command_end
+	EXECUTE PROCEDURE public.dbml_notify_schema_change()`,
+}
+
+var uninstallEventTriggerSQL = []string{
+	`DROP EVENT TRIGGER IF EXISTS dbml_schema_change`,
+	`DROP FUNCTION IF EXISTS public.dbml_notify_schema_change()`,
+}
+
+// InstallEventTrigger installs the dbml_schema_change event trigger, which
+// notifies SchemaChangeChannel when a DDL command completes. Notifications are
+// delivered when the transaction commits, so a migration produces its
+// notifications all at once. Creating event triggers requires superuser
+// privileges. Installing is idempotent.
+func InstallEventTrigger(ctx context.Context, db *sql.DB) error {
+	if err := execInTx(ctx, db, installEventTriggerSQL); err != nil {
+		return fmt.Errorf("failed to install event trigger: %w", err)
+	}
+	return nil
+}
+
+// UninstallEventTrigger removes the event trigger and function installed by
+// InstallEventTrigger, if they exist.
+func UninstallEventTrigger(ctx context.Context, db *sql.DB) error {
+	if err := execInTx(ctx, db, uninstallEventTriggerSQL); err != nil {
+		return fmt.Errorf("failed to uninstall event trigger: %w", err)
+	}
+	return nil
+}
+
+// InstallEventTriggerSQL returns the script InstallEventTrigger runs, for
+// review or to run by hand.
+func InstallEventTriggerSQL() string {
+	return script(installEventTriggerSQL)
+}
+
+// UninstallEventTriggerSQL returns the script UninstallEventTrigger runs.
+func UninstallEventTriggerSQL() string {
+	return script(uninstallEventTriggerSQL)
+}
+
+// script wraps statements in the transaction they are executed in.
+func script(statements []string) string {
+	var b strings.Builder
+	b.WriteString("BEGIN;\n\n")
+	for _, stmt := range statements {
+		b.WriteString(strings.ReplaceAll(stmt, "\n\t", "\n"))
+		b.WriteString(";\n\n")
+	}
+	b.WriteString("COMMIT;\n")
+	return b.String()
+}
+
+func execInTx(ctx context.Context, db *sql.DB, statements []string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
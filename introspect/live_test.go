@@ -0,0 +1,32 @@
+package introspect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInstallEventTriggerSQL(t *testing.T) {
+	script := InstallEventTriggerSQL()
+
+	for _, expected := range []string{
+		"BEGIN;\n",
+		"CREATE OR REPLACE FUNCTION public.dbml_notify_schema_change() RETURNS event_trigger\nLANGUAGE plpgsql AS $$\nBEGIN\n\tPERFORM pg_notify('dbml_schema_change', tg_tag);\nEND;\n$$;\n",
+		"CREATE EVENT TRIGGER dbml_schema_change ON ddl_command_end\nEXECUTE PROCEDURE public.dbml_notify_schema_change();\n",
+		"COMMIT;\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected script to contain %q, got:\n%s", expected, script)
+		}
+	}
+}
+
+func TestUninstallEventTriggerSQL(t *testing.T) {
+	script := UninstallEventTriggerSQL()
+
+	if !strings.Contains(script, "DROP FUNCTION IF EXISTS public.dbml_notify_schema_change();\n") {
+		t.Errorf("Expected script to drop the function, got:\n%s", script)
+	}
+	if strings.Index(script, "DROP EVENT TRIGGER") > strings.Index(script, "DROP FUNCTION") {
+		t.Errorf("Expected the trigger to be dropped before its function, got:\n%s", script)
+	}
+}
@@ -0,0 +1,112 @@
+package introspect
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Phase identifies a step of the introspection process.
+type Phase string
+
+// Introspection phases reported to a MetricsCollector.
+const (
+	PhaseTables      Phase = "tables"
+	PhaseEnums       Phase = "enums"
+	PhaseColumns     Phase = "columns"
+	PhasePrimaryKeys Phase = "primary_keys"
+	PhaseIndexes     Phase = "indexes"
+	PhaseForeignKeys Phase = "foreign_keys"
+	PhaseUniques     Phase = "unique_constraints"
+	PhaseChecks      Phase = "checks"
+	PhaseExclusions  Phase = "exclusions"
+	PhaseSingleQuery Phase = "single_query"
+	PhaseStatistics  Phase = "statistics"
+	PhaseMigrations  Phase = "migrations"
+	PhaseViews       Phase = "views"
+	PhaseSequences   Phase = "sequences"
+	PhasePartitions  Phase = "partitions"
+	PhaseInheritance Phase = "inheritance"
+	PhaseDomains     Phase = "domains"
+	PhaseRowSecurity Phase = "row_security"
+	PhaseTriggers    Phase = "triggers"
+	PhaseFunctions   Phase = "functions"
+	PhaseExtensions  Phase = "extensions"
+	PhaseRanges      Phase = "ranges"
+	PhasePostGIS     Phase = "postgis"
+	PhasePrivileges  Phase = "privileges"
+)
+
+// MetricsCollector receives timing information about introspection.
+// RecordPhase is called once per query, so phases that run per table
+// are reported many times.
+type MetricsCollector interface {
+	RecordPhase(phase Phase, duration time.Duration, rows int)
+}
+
+// PhaseStats aggregates the measurements recorded for a single phase.
+type PhaseStats struct {
+	// Phase is the introspection phase these statistics describe.
+	Phase Phase
+	// Calls is the number of queries executed for the phase.
+	Calls int
+	// Duration is the total time spent in the phase.
+	Duration time.Duration
+	// Rows is the total number of rows returned by the phase.
+	Rows int
+}
+
+// Metrics is a MetricsCollector that aggregates measurements per phase.
+// It is safe for concurrent use.
+type Metrics struct {
+	mu     sync.Mutex
+	phases map[Phase]*PhaseStats
+}
+
+// NewMetrics creates an empty Metrics collector.
+func NewMetrics() *Metrics {
+	return &Metrics{phases: make(map[Phase]*PhaseStats)}
+}
+
+// RecordPhase implements MetricsCollector.
+func (m *Metrics) RecordPhase(phase Phase, duration time.Duration, rows int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.phases == nil {
+		m.phases = make(map[Phase]*PhaseStats)
+	}
+	stats, ok := m.phases[phase]
+	if !ok {
+		stats = &PhaseStats{Phase: phase}
+		m.phases[phase] = stats
+	}
+	stats.Calls++
+	stats.Duration += duration
+	stats.Rows += rows
+}
+
+// Phases returns the aggregated statistics, slowest phase first.
+func (m *Metrics) Phases() []PhaseStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]PhaseStats, 0, len(m.phases))
+	for _, stats := range m.phases {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Duration != result[j].Duration {
+			return result[i].Duration > result[j].Duration
+		}
+		return result[i].Phase < result[j].Phase
+	})
+	return result
+}
+
+func (o *options) recordPhase(phase Phase, start time.Time, rows int) {
+	if o.metrics == nil {
+		return
+	}
+	o.metrics.RecordPhase(phase, time.Since(start), rows)
+}
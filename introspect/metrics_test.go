@@ -0,0 +1,32 @@
+package introspect
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricsAggregatesPhases(t *testing.T) {
+	m := NewMetrics()
+	m.RecordPhase(PhaseColumns, 2*time.Millisecond, 5)
+	m.RecordPhase(PhaseColumns, 3*time.Millisecond, 4)
+	m.RecordPhase(PhaseTables, time.Millisecond, 2)
+
+	phases := m.Phases()
+	if len(phases) != 2 {
+		t.Fatalf("Expected 2 phases, got %d", len(phases))
+	}
+
+	columns := phases[0]
+	if columns.Phase != PhaseColumns {
+		t.Fatalf("Expected slowest phase to be columns, got %s", columns.Phase)
+	}
+	if columns.Calls != 2 || columns.Rows != 9 || columns.Duration != 5*time.Millisecond {
+		t.Errorf("Unexpected column stats: %+v", columns)
+	}
+}
+
+func TestRecordPhaseWithoutCollector(t *testing.T) {
+	o := defaultOptions()
+	// Must not panic when no collector is configured.
+	o.recordPhase(PhaseTables, time.Now(), 1)
+}
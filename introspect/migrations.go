@@ -0,0 +1,74 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// migrationSource describes how a migration tool records its history.
+type migrationSource struct {
+	tool  string
+	table string
+	// query returns the latest applied version as text.
+	query string
+}
+
+// migrationSources lists the supported history tables in the order they are
+// tried. Tables are resolved through the search_path.
+var migrationSources = []migrationSource{
+	{
+		tool:  "goose",
+		table: "goose_db_version",
+		query: `SELECT version_id::text FROM goose_db_version WHERE is_applied ORDER BY id DESC LIMIT 1`,
+	},
+	{
+		tool:  "flyway",
+		table: "flyway_schema_history",
+		query: `SELECT version FROM flyway_schema_history WHERE success AND version IS NOT NULL ORDER BY installed_rank DESC LIMIT 1`,
+	},
+	{
+		// golang-migrate keeps a single row; Rails keeps one row per
+		// migration, with fixed-width timestamps that sort as text.
+		tool:  "schema_migrations",
+		table: "schema_migrations",
+		query: `SELECT MAX(version::text) FROM schema_migrations`,
+	},
+}
+
+// addMigration sets s.Migration from the first migration history table found.
+func addMigration(db *sql.DB, s *schema.Schema, o *options) error {
+	start := time.Now()
+	defer func() {
+		found := 0
+		if s.Migration != nil {
+			found = 1
+		}
+		o.recordPhase(PhaseMigrations, start, found)
+	}()
+
+	for _, source := range migrationSources {
+		var exists bool
+		if err := db.QueryRow(`SELECT to_regclass($1) IS NOT NULL`, source.table).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		var version sql.NullString
+		err := db.QueryRow(source.query).Scan(&version)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to read %s: %w", source.table, err)
+		}
+		if !version.Valid {
+			continue
+		}
+
+		s.Migration = &schema.Migration{Tool: source.tool, Table: source.table, Version: version.String}
+		return nil
+	}
+	return nil
+}
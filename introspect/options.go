@@ -6,8 +6,30 @@ type Option func(*options)
 type options struct {
 	schemas           []string
 	excludeTables     []string
+	excludeSchemas    []string
 	includeAllSchemas bool
+	systemSchemas     bool
 	typeMapper        TypeMapper
+	cacheDir          string
+	singleQuery       bool
+	metrics           MetricsCollector
+	statistics        bool
+	migration         bool
+	indexInclude      bool
+	views             bool
+	materializedViews bool
+	excludeMatViews   bool
+	sequences         bool
+	partitions        bool
+	foreignTables     bool
+	triggers          bool
+	privileges        bool
+	functions         bool
+	annotations       bool
+	requireReadOnly   bool
+	requireStandby    bool
+	excludeDatabases  []string
+	serverVersion     int
 }
 
 func defaultOptions() *options {
@@ -31,6 +53,15 @@ func WithExcludeTables(tables ...string) Option {
 	}
 }
 
+// WithExcludeSchemas skips the named schemas. It is most useful with
+// WithAllSchemas to leave out schemas owned by extensions or hosting
+// platforms.
+func WithExcludeSchemas(schemas ...string) Option {
+	return func(o *options) {
+		o.excludeSchemas = schemas
+	}
+}
+
 // WithAllSchemas includes all non-system schemas in the introspection.
 // This overrides WithSchemas.
 func WithAllSchemas() Option {
@@ -39,6 +70,16 @@ func WithAllSchemas() Option {
 	}
 }
 
+// WithSystemSchemas includes every schema, system catalogs such as
+// pg_catalog and information_schema among them, and keeps the functions
+// extensions install in Schema.Functions. It implies WithAllSchemas.
+func WithSystemSchemas() Option {
+	return func(o *options) {
+		o.includeAllSchemas = true
+		o.systemSchemas = true
+	}
+}
+
 // WithTypeMapper sets a custom type mapper for converting database types to DBML types.
 // If not specified, uses the default PostgreSQL type mapper.
 func WithTypeMapper(mapper TypeMapper) Option {
@@ -55,3 +96,187 @@ func WithTypeMappings(mappings map[string]string) Option {
 		o.typeMapper = NewPostgreSQLTypeMapper(mappings)
 	}
 }
+
+// WithCache stores introspection results in dir and reuses them on later runs
+// while the database catalog is unchanged. Whether the catalog changed is
+// detected with a single cheap aggregate query, so repeated runs against an
+// unchanged database skip the full introspection.
+func WithCache(dir string) Option {
+	return func(o *options) {
+		o.cacheDir = dir
+	}
+}
+
+// WithSingleQuery introspects the whole schema with one query that assembles
+// the result as a JSON document on the server. This trades a heavier query for
+// a single round trip, which is much faster over high-latency connections.
+func WithSingleQuery() Option {
+	return func(o *options) {
+		o.singleQuery = true
+	}
+}
+
+// WithMetrics reports the duration and row count of every introspection phase
+// to collector. Use a *Metrics to aggregate the results.
+func WithMetrics(collector MetricsCollector) Option {
+	return func(o *options) {
+		o.metrics = collector
+	}
+}
+
+// WithStatistics records each table's estimated row count and total size on
+// disk in Table.Statistics. The estimate comes from pg_class.reltuples, so it
+// is only as fresh as the last ANALYZE.
+func WithStatistics() Option {
+	return func(o *options) {
+		o.statistics = true
+	}
+}
+
+// WithViews includes views and materialized views in Schema.Views, with
+// their columns, definitions, and the tables and columns they read from.
+func WithViews() Option {
+	return func(o *options) {
+		o.views = true
+		o.materializedViews = true
+	}
+}
+
+// WithMaterializedViews includes materialized views in Schema.Views like
+// WithViews does, without plain views unless WithViews is also given.
+func WithMaterializedViews() Option {
+	return func(o *options) {
+		o.materializedViews = true
+	}
+}
+
+// WithoutMaterializedViews leaves materialized views out of Schema.Views,
+// even with WithViews or WithMaterializedViews.
+func WithoutMaterializedViews() Option {
+	return func(o *options) {
+		o.excludeMatViews = true
+	}
+}
+
+// WithSequences includes the sequences no column owns in Schema.Sequences,
+// such as sequences applications draw from with nextval(). Sequences behind
+// serial and identity columns are left out, since the column default already
+// shows them.
+func WithSequences() Option {
+	return func(o *options) {
+		o.sequences = true
+	}
+}
+
+// WithPartitions lists the partitions of each partitioned table, with their
+// bounds, in Table.Partitions. Partitions are never introspected as tables of
+// their own; without this option only the parent and its partition key are.
+func WithPartitions() Option {
+	return func(o *options) {
+		o.partitions = true
+	}
+}
+
+// WithForeignTables includes foreign tables, such as those created with
+// postgres_fdw, with the server they read from in Table.Foreign.
+func WithForeignTables() Option {
+	return func(o *options) {
+		o.foreignTables = true
+	}
+}
+
+// WithTriggers lists each table's triggers, with their timing, events, and
+// function, in Table.Triggers. Internal triggers, such as those enforcing
+// foreign keys, are left out.
+func WithTriggers() Option {
+	return func(o *options) {
+		o.triggers = true
+	}
+}
+
+// WithPrivileges lists the privileges granted on each table, grouped by
+// grantee, in Table.Grants. The owner's own privileges are left out, so
+// the grants show who else can read or write the table.
+func WithPrivileges() Option {
+	return func(o *options) {
+		o.privileges = true
+	}
+}
+
+// WithFunctions lists the functions and procedures in the introspected
+// schemas in Schema.Functions, with their arguments, return type, and
+// language. Aggregates, window functions, and functions installed by
+// extensions are left out.
+func WithFunctions() Option {
+	return func(o *options) {
+		o.functions = true
+	}
+}
+
+// viewKinds returns the pg_class relkinds of the views to introspect: "v"
+// for views and "m" for materialized views.
+func (o *options) viewKinds() []string {
+	var kinds []string
+	if o.views {
+		kinds = append(kinds, "v")
+	}
+	if o.materializedViews && !o.excludeMatViews {
+		kinds = append(kinds, "m")
+	}
+	return kinds
+}
+
+// WithAnnotations reads @color(#rrggbb), @group(name), and @deprecated
+// annotations from table and column comments into Table.Color,
+// Schema.TableGroups, and the Deprecated fields, and removes them from the
+// comments. See ParseAnnotations.
+func WithAnnotations() Option {
+	return func(o *options) {
+		o.annotations = true
+	}
+}
+
+// WithIndexInclude keeps the non-key INCLUDE columns of covering indexes in
+// Index.Include. Without it they are left out, so indexes list only their key
+// columns.
+func WithIndexInclude() Option {
+	return func(o *options) {
+		o.indexInclude = true
+	}
+}
+
+// WithMigrationVersion records the latest applied migration in
+// Schema.Migration, read from the history table of goose
+// (goose_db_version), Flyway (flyway_schema_history), or golang-migrate and
+// Rails (schema_migrations), whichever is found first on the search_path.
+func WithMigrationVersion() Option {
+	return func(o *options) {
+		o.migration = true
+	}
+}
+
+// WithRequireReadOnly refuses to introspect unless the connection is
+// read-only, either because default_transaction_read_only is set (see
+// ReadOnlyConnectionString) or because the server is a standby. It
+// guarantees introspection cannot write, whatever the role's privileges.
+func WithRequireReadOnly() Option {
+	return func(o *options) {
+		o.requireReadOnly = true
+	}
+}
+
+// WithRequireStandby refuses to introspect unless the server is a standby in
+// recovery, keeping introspection load off the primary.
+func WithRequireStandby() Option {
+	return func(o *options) {
+		o.requireStandby = true
+	}
+}
+
+// WithExcludeDatabases skips the named databases when introspecting a whole
+// server with Server. Other functions ignore it.
+func WithExcludeDatabases(databases ...string) Option {
+	return func(o *options) {
+		o.excludeDatabases = databases
+	}
+}
@@ -0,0 +1,62 @@
+package introspect
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// partitionsQuery lists the direct partitions of the partitioned tables in
+// the requested schemas.
+const partitionsQuery = `
+	SELECT
+		pn.nspname,
+		p.relname,
+		cn.nspname,
+		c.relname,
+		COALESCE(pg_get_expr(c.relpartbound, c.oid), '')
+	FROM pg_inherits i
+	JOIN pg_class p ON p.oid = i.inhparent
+	JOIN pg_namespace pn ON pn.oid = p.relnamespace
+	JOIN pg_class c ON c.oid = i.inhrelid
+	JOIN pg_namespace cn ON cn.oid = c.relnamespace
+	WHERE pn.nspname = ANY($1) AND p.relkind = 'p'
+	ORDER BY pn.nspname, p.relname, cn.nspname, c.relname
+`
+
+// addPartitions fills in Table.Partitions for the partitioned tables in s.
+// Servers before PostgreSQL 10 have no declarative partitioning.
+func addPartitions(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	if !o.supports(version10) {
+		return nil
+	}
+	start := time.Now()
+	rows, err := db.Query(partitionsQuery, pq.Array(schemaNames))
+	if err != nil {
+		o.recordPhase(PhasePartitions, start, 0)
+		return err
+	}
+	defer rows.Close()
+
+	byName := make(map[string]int)
+	for i, table := range s.Tables {
+		byName[table.Schema+"."+table.Name] = i
+	}
+
+	count := 0
+	for rows.Next() {
+		var parentSchema, parentName string
+		var partition schema.Partition
+		if err := rows.Scan(&parentSchema, &parentName, &partition.Schema, &partition.Name, &partition.Bound); err != nil {
+			return err
+		}
+		count++
+		if i, ok := byName[parentSchema+"."+parentName]; ok {
+			s.Tables[i].Partitions = append(s.Tables[i].Partitions, partition)
+		}
+	}
+	o.recordPhase(PhasePartitions, start, count)
+	return rows.Err()
+}
@@ -0,0 +1,87 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// postgisColumnsQuery lists the geometry and geography columns of the tables
+// and views in the requested schemas with their subtype, such as "PointZ",
+// and SRID. Unlike format_type, PostGIS's catalog views also resolve columns
+// constrained with CHECK constraints instead of a type modifier, and view
+// columns.
+const postgisColumnsQuery = `
+	SELECT f_table_schema, f_table_name, f_geometry_column, 'geometry',
+		postgis_type_name(type, coord_dimension, true), srid
+	FROM geometry_columns
+	WHERE f_table_schema = ANY($1)
+	UNION ALL
+	SELECT f_table_schema, f_table_name, f_geography_column, 'geography',
+		postgis_type_name(type, coord_dimension, true), srid
+	FROM geography_columns
+	WHERE f_table_schema = ANY($1)
+`
+
+// addPostGISTypes fills in the subtype and SRID of the database type of
+// geometry and geography columns that format_type spells without them.
+func addPostGISTypes(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	start := time.Now()
+	rows, err := db.Query(postgisColumnsQuery, pq.Array(schemaNames))
+	if err != nil {
+		o.recordPhase(PhasePostGIS, start, 0)
+		return err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]*schema.Column)
+	for i := range s.Tables {
+		table := &s.Tables[i]
+		for j := range table.Columns {
+			columns[table.Schema+"."+table.Name+"."+table.Columns[j].Name] = &table.Columns[j]
+		}
+	}
+	for i := range s.Views {
+		view := &s.Views[i]
+		for j := range view.Columns {
+			columns[view.Schema+"."+view.Name+"."+view.Columns[j].Name] = &view.Columns[j]
+		}
+	}
+
+	count := 0
+	for rows.Next() {
+		var schemaName, tableName, columnName, baseType string
+		var subtype sql.NullString
+		var srid int
+		if err := rows.Scan(&schemaName, &tableName, &columnName, &baseType, &subtype, &srid); err != nil {
+			return err
+		}
+		count++
+		column, ok := columns[schemaName+"."+tableName+"."+columnName]
+		if !ok || column.DatabaseType != baseType {
+			continue
+		}
+		column.DatabaseType = geometryType(baseType, subtype.String, srid)
+	}
+	o.recordPhase(PhasePostGIS, start, count)
+	return rows.Err()
+}
+
+// geometryType spells a geometry or geography type with its subtype and SRID
+// the way PostGIS's type modifier output does, as in "geometry(Point,4326)".
+// The generic Geometry subtype and SRID 0 mean the column is unconstrained.
+func geometryType(baseType, subtype string, srid int) string {
+	if srid > 0 {
+		if subtype == "" {
+			subtype = "Geometry"
+		}
+		return fmt.Sprintf("%s(%s,%d)", baseType, subtype, srid)
+	}
+	if subtype == "" || subtype == "Geometry" {
+		return baseType
+	}
+	return fmt.Sprintf("%s(%s)", baseType, subtype)
+}
@@ -0,0 +1,24 @@
+package introspect
+
+import "testing"
+
+func TestGeometryType(t *testing.T) {
+	tests := []struct {
+		baseType, subtype string
+		srid              int
+		expected          string
+	}{
+		{"geometry", "Point", 4326, "geometry(Point,4326)"},
+		{"geometry", "MultiPolygonZ", 3857, "geometry(MultiPolygonZ,3857)"},
+		{"geography", "LineString", 0, "geography(LineString)"},
+		{"geometry", "Geometry", 4326, "geometry(Geometry,4326)"},
+		{"geometry", "Geometry", 0, "geometry"},
+		{"geometry", "", 0, "geometry"},
+	}
+
+	for _, tt := range tests {
+		if got := geometryType(tt.baseType, tt.subtype, tt.srid); got != tt.expected {
+			t.Errorf("geometryType(%q, %q, %d) = %q, want %q", tt.baseType, tt.subtype, tt.srid, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package introspect
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// privilegesQuery lists the privileges granted on the tables in the
+// requested schemas, grouped by grantee in the order GRANT lists them. The
+// owner's own privileges are implicit and left out.
+const privilegesQuery = `
+	SELECT
+		g.table_schema,
+		g.table_name,
+		g.grantee,
+		array_agg(DISTINCT g.privilege_type ORDER BY g.privilege_type)
+	FROM information_schema.role_table_grants g
+	JOIN pg_namespace n ON n.nspname = g.table_schema
+	JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = g.table_name
+	WHERE g.table_schema = ANY($1) AND g.grantee <> pg_get_userbyid(c.relowner)
+	GROUP BY g.table_schema, g.table_name, g.grantee
+	ORDER BY g.table_schema, g.table_name, g.grantee
+`
+
+// privilegeOrder is the order GRANT documents its table privileges in.
+var privilegeOrder = []string{"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"}
+
+// addPrivileges fills in Table.Grants for the tables in s.
+func addPrivileges(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	start := time.Now()
+	rows, err := db.Query(privilegesQuery, pq.Array(schemaNames))
+	if err != nil {
+		o.recordPhase(PhasePrivileges, start, 0)
+		return err
+	}
+	defer rows.Close()
+
+	byName := make(map[string]int)
+	for i, table := range s.Tables {
+		byName[table.Schema+"."+table.Name] = i
+	}
+
+	count := 0
+	for rows.Next() {
+		var schemaName, tableName string
+		var grant schema.Grant
+		var privileges []string
+		if err := rows.Scan(&schemaName, &tableName, &grant.Grantee, pq.Array(&privileges)); err != nil {
+			return err
+		}
+		count++
+		grant.Privileges = sortPrivileges(privileges)
+		if i, ok := byName[schemaName+"."+tableName]; ok {
+			s.Tables[i].Grants = append(s.Tables[i].Grants, grant)
+		}
+	}
+	o.recordPhase(PhasePrivileges, start, count)
+	return rows.Err()
+}
+
+// sortPrivileges returns privileges in privilegeOrder, followed by any it
+// does not know in their given order.
+func sortPrivileges(privileges []string) []string {
+	sorted := make([]string, 0, len(privileges))
+	known := make(map[string]bool)
+	for _, privilege := range privilegeOrder {
+		known[privilege] = true
+		for _, p := range privileges {
+			if p == privilege {
+				sorted = append(sorted, p)
+				break
+			}
+		}
+	}
+	for _, p := range privileges {
+		if !known[p] {
+			sorted = append(sorted, p)
+		}
+	}
+	return sorted
+}
@@ -0,0 +1,14 @@
+package introspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortPrivileges(t *testing.T) {
+	got := sortPrivileges([]string{"DELETE", "INSERT", "MAINTAIN", "SELECT", "UPDATE"})
+	expected := []string{"SELECT", "INSERT", "UPDATE", "DELETE", "MAINTAIN"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("sortPrivileges() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,135 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Query describes the result of a SELECT statement as a virtual table named
+// name, so reporting queries and views in progress can be documented next to
+// real tables. name may be schema-qualified; it defaults to the public schema.
+//
+// The statement is prepared and run with LIMIT 0, so no rows are read. Any
+// parameters ($1, $2, ...) are bound to NULL. Column types come from the
+// result's row description, so types the driver does not know, such as
+// enums, are written as text. Query honors WithTypeMapper and
+// WithTypeMappings; other options are ignored.
+func Query(ctx context.Context, db *sql.DB, query, name string, opts ...Option) (schema.Table, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	table := schema.Table{Name: name, Schema: "public", Comment: strings.TrimSpace(query)}
+	if schemaName, tableName, ok := strings.Cut(name, "."); ok {
+		table.Schema, table.Name = schemaName, tableName
+	}
+
+	wrapped := fmt.Sprintf("SELECT * FROM (%s) AS q LIMIT 0", strings.TrimRight(strings.TrimSpace(query), ";"))
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return schema.Table{}, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	inputs, err := countParameters(ctx, conn, wrapped)
+	if err != nil {
+		return schema.Table{}, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, wrapped, make([]any, inputs)...)
+	if err != nil {
+		return schema.Table{}, fmt.Errorf("failed to run query: %w", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return schema.Table{}, fmt.Errorf("failed to read result columns: %w", err)
+	}
+	for i, c := range columnTypes {
+		column := resultColumn(c, o.typeMapper)
+		column.OrdinalPosition = i + 1
+		table.Columns = append(table.Columns, column)
+	}
+
+	return table, rows.Err()
+}
+
+// countParameters prepares query on the driver connection and returns the
+// number of parameters it takes, which database/sql does not expose.
+func countParameters(ctx context.Context, conn *sql.Conn, query string) (int, error) {
+	var inputs int
+	err := conn.Raw(func(driverConn any) error {
+		preparer, ok := driverConn.(driver.ConnPrepareContext)
+		if !ok {
+			return fmt.Errorf("driver does not support prepared statements")
+		}
+		stmt, err := preparer.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		inputs = stmt.NumInput()
+		return stmt.Close()
+	})
+	return inputs, err
+}
+
+// columnType is the part of *sql.ColumnType that resultColumn reads.
+type columnType interface {
+	Name() string
+	DatabaseTypeName() string
+	Length() (int64, bool)
+	DecimalSize() (int64, int64, bool)
+}
+
+// resultColumn converts a result column to a nullable schema.Column, since
+// the nullability of query results is unknown.
+func resultColumn(c columnType, mapper TypeMapper) schema.Column {
+	column := schema.Column{Name: c.Name(), Nullable: true}
+
+	typeName := strings.ToLower(c.DatabaseTypeName())
+	if typeName == "" {
+		column.Type = "text"
+		return column
+	}
+
+	// Array types are named after their element type with a leading
+	// underscore
+	dataType := typeName
+	if strings.HasPrefix(typeName, "_") {
+		dataType = "array"
+		column.DatabaseType = strings.TrimPrefix(typeName, "_") + "[]"
+	} else if typeName == "bpchar" {
+		dataType = "char"
+	}
+
+	var charMaxLength, numericPrecision, numericScale sql.NullInt64
+	switch dataType {
+	case "varchar", "char":
+		// Unconstrained lengths are reported as negative
+		if length, ok := c.Length(); ok && length > 0 {
+			charMaxLength = sql.NullInt64{Int64: length, Valid: true}
+			column.DatabaseType = fmt.Sprintf("%s(%d)", dataType, length)
+		}
+	case "numeric":
+		// Unconstrained precision decodes past numeric's limit of 1000
+		if precision, scale, ok := c.DecimalSize(); ok && precision > 0 && precision <= 1000 {
+			numericPrecision = sql.NullInt64{Int64: precision, Valid: true}
+			numericScale = sql.NullInt64{Int64: scale, Valid: true}
+			column.DatabaseType = fmt.Sprintf("numeric(%d,%d)", precision, scale)
+		}
+	}
+	if column.DatabaseType == "" {
+		column.DatabaseType = dataType
+	}
+
+	column.Type = mapColumnType(mapper, dataType, typeName, charMaxLength, numericPrecision, numericScale)
+	return column
+}
@@ -0,0 +1,50 @@
+package introspect
+
+import "testing"
+
+type fakeColumnType struct {
+	name, typeName   string
+	length           int64
+	precision, scale int64
+}
+
+func (c fakeColumnType) Name() string             { return c.name }
+func (c fakeColumnType) DatabaseTypeName() string { return c.typeName }
+func (c fakeColumnType) Length() (int64, bool)    { return c.length, c.length != 0 }
+func (c fakeColumnType) DecimalSize() (int64, int64, bool) {
+	return c.precision, c.scale, c.precision != 0
+}
+
+func TestResultColumn(t *testing.T) {
+	tests := []struct {
+		column       fakeColumnType
+		expected     string
+		databaseType string
+	}{
+		{fakeColumnType{name: "id", typeName: "INT4"}, "int", "int4"},
+		{fakeColumnType{name: "email", typeName: "VARCHAR", length: 255}, "varchar(255)", "varchar(255)"},
+		{fakeColumnType{name: "note", typeName: "VARCHAR", length: -5}, "varchar", "varchar"},
+		{fakeColumnType{name: "code", typeName: "BPCHAR", length: 2}, "char(2)", "char(2)"},
+		{fakeColumnType{name: "revenue", typeName: "NUMERIC", precision: 12, scale: 2}, "decimal(12,2)", "numeric(12,2)"},
+		{fakeColumnType{name: "ratio", typeName: "NUMERIC", precision: 65535, scale: 65531}, "decimal", "numeric"},
+		{fakeColumnType{name: "month", typeName: "TIMESTAMPTZ"}, "timestamptz", "timestamptz"},
+		{fakeColumnType{name: "tags", typeName: "_TEXT"}, "text[]", "text[]"},
+		{fakeColumnType{name: "ids", typeName: "_INT4"}, "int[]", "int4[]"},
+		{fakeColumnType{name: "mood", typeName: ""}, "text", ""},
+	}
+
+	for _, tt := range tests {
+		column := resultColumn(tt.column, nil)
+		if column.Name != tt.column.name || column.Type != tt.expected || column.DatabaseType != tt.databaseType || !column.Nullable {
+			t.Errorf("resultColumn(%+v) = %+v, want type %q and database type %q", tt.column, column, tt.expected, tt.databaseType)
+		}
+	}
+}
+
+func TestResultColumnTypeMapper(t *testing.T) {
+	mapper := NewPostgreSQLTypeMapper(map[string]string{"int4": "integer"})
+	column := resultColumn(fakeColumnType{name: "id", typeName: "INT4"}, mapper)
+	if column.Type != "integer" {
+		t.Errorf("Expected the custom mapping to apply, got %q", column.Type)
+	}
+}
@@ -0,0 +1,75 @@
+package introspect
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// rangesQuery lists the range types in the requested schemas with their
+// subtype. The built-in range types live in pg_catalog and are mapped by
+// DefaultTypeMappings instead.
+const rangesQuery = `
+	SELECT
+		n.nspname,
+		t.typname,
+		format_type(r.rngsubtype, NULL),
+		COALESCE(obj_description(t.oid, 'pg_type'), '')
+	FROM pg_range r
+	JOIN pg_type t ON t.oid = r.rngtypid
+	JOIN pg_namespace n ON n.oid = t.typnamespace
+	WHERE n.nspname = ANY($1)
+	ORDER BY n.nspname, t.typname
+`
+
+// getRanges returns the range types in schemaNames.
+func getRanges(db *sql.DB, schemaNames []string, o *options) ([]schema.Range, error) {
+	start := time.Now()
+	rows, err := db.Query(rangesQuery, pq.Array(schemaNames))
+	if err != nil {
+		o.recordPhase(PhaseRanges, start, 0)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranges []schema.Range
+	for rows.Next() {
+		var r schema.Range
+		if err := rows.Scan(&r.Schema, &r.Name, &r.Subtype, &r.Comment); err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	o.recordPhase(PhaseRanges, start, len(ranges))
+	return ranges, rows.Err()
+}
+
+// withRangeTypes returns mapper with the range types added as mappings to
+// their own name, so their columns keep the range type instead of being
+// written as text. Like withExtensionTypes, it leaves custom mappings and a
+// TypeMapper set with WithTypeMapper alone.
+func withRangeTypes(mapper TypeMapper, ranges []schema.Range) TypeMapper {
+	var custom map[string]string
+	switch m := mapper.(type) {
+	case nil:
+	case *PostgreSQLTypeMapper:
+		custom = m.CustomMappings
+	default:
+		return mapper
+	}
+	if len(ranges) == 0 {
+		return mapper
+	}
+
+	mappings := make(map[string]string)
+	for _, r := range ranges {
+		mappings[strings.ToLower(r.Name)] = r.Name
+	}
+	for typeName, dbmlType := range custom {
+		mappings[strings.ToLower(typeName)] = dbmlType
+	}
+	return NewPostgreSQLTypeMapper(mappings)
+}
@@ -0,0 +1,40 @@
+package introspect
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestWithRangeTypes(t *testing.T) {
+	mapType := func(mapper TypeMapper, dataType, udtName string) string {
+		return mapColumnType(mapper, dataType, udtName, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+	}
+	ranges := []schema.Range{{Name: "floatrange", Schema: "public", Subtype: "double precision"}}
+
+	mapper := withRangeTypes(nil, ranges)
+	if got := mapType(mapper, "USER-DEFINED", "floatrange"); got != "floatrange" {
+		t.Errorf("Expected floatrange to keep its name, got %q", got)
+	}
+	if got := mapType(mapper, "ARRAY", "_floatrange"); got != "floatrange[]" {
+		t.Errorf("Expected floatrange[] to keep its name, got %q", got)
+	}
+	if got := mapType(mapper, "tstzrange", "tstzrange"); got != "tstzrange" {
+		t.Errorf("Expected built-in ranges to map as before, got %q", got)
+	}
+
+	if mapper := withRangeTypes(nil, nil); mapper != nil {
+		t.Errorf("Expected no mapper without range types, got %+v", mapper)
+	}
+
+	custom := NewPostgreSQLTypeMapper(map[string]string{"floatrange": "text"})
+	if got := mapType(withRangeTypes(custom, ranges), "USER-DEFINED", "floatrange"); got != "text" {
+		t.Errorf("Expected the custom floatrange mapping to win, got %q", got)
+	}
+
+	other := mapperFunc(func(string) string { return "custom" })
+	if got := mapType(withRangeTypes(other, ranges), "USER-DEFINED", "floatrange"); got != "custom" {
+		t.Errorf("Expected a custom TypeMapper to be left alone, got %q", got)
+	}
+}
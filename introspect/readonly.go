@@ -0,0 +1,54 @@
+package introspect
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ReadOnlyConnectionString returns connStr with default_transaction_read_only
+// enabled, so every transaction on connections opened from it is read-only and
+// PostgreSQL rejects any write. It accepts both URL and key=value connection
+// strings.
+func ReadOnlyConnectionString(connStr string) (string, error) {
+	if isConnectionURL(connStr) {
+		u, err := url.Parse(connStr)
+		if err != nil {
+			return "", fmt.Errorf("invalid connection URL: %w", err)
+		}
+		query := u.Query()
+		query.Set("default_transaction_read_only", "on")
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+	return appendConnParam(connStr, "default_transaction_read_only", "on"), nil
+}
+
+// checkReadOnly refuses to continue unless the connection is read-only and,
+// if standby is set, the server is a standby in recovery. Both are checked on
+// a single connection from the pool, so connection strings should set
+// default_transaction_read_only for every connection rather than relying on
+// a session SET.
+func checkReadOnly(db *sql.DB, o *options) error {
+	if o.requireStandby {
+		var inRecovery bool
+		if err := db.QueryRow(`SELECT pg_is_in_recovery()`).Scan(&inRecovery); err != nil {
+			return fmt.Errorf("failed to check recovery status: %w", err)
+		}
+		if !inRecovery {
+			return errors.New("refusing to introspect: server is not a standby")
+		}
+	}
+
+	if o.requireReadOnly {
+		var readOnly string
+		if err := db.QueryRow(`SHOW transaction_read_only`).Scan(&readOnly); err != nil {
+			return fmt.Errorf("failed to check transaction_read_only: %w", err)
+		}
+		if readOnly != "on" {
+			return errors.New("refusing to introspect: connection is not read-only (set default_transaction_read_only or connect to a standby)")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package introspect
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// rowSecurityQuery lists the tables in the requested schemas that have row
+// level security enabled.
+const rowSecurityQuery = `
+	SELECT
+		n.nspname,
+		c.relname,
+		c.relrowsecurity,
+		c.relforcerowsecurity
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = ANY($1) AND c.relkind IN ('r', 'p', 'f') AND c.relrowsecurity
+	ORDER BY n.nspname, c.relname
+`
+
+// policiesQuery lists the row level security policies on the tables in the
+// requested schemas.
+const policiesQuery = `
+	SELECT
+		schemaname,
+		tablename,
+		policyname,
+		cmd,
+		permissive = 'PERMISSIVE',
+		roles::text[],
+		COALESCE(qual, ''),
+		COALESCE(with_check, '')
+	FROM pg_policies
+	WHERE schemaname = ANY($1)
+	ORDER BY schemaname, tablename, policyname
+`
+
+// addRowSecurity fills in the row level security status and policies of the
+// tables in s. Servers before PostgreSQL 9.5 have no row level security.
+func addRowSecurity(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	if !o.supports(version95) {
+		return nil
+	}
+	start := time.Now()
+	byName := make(map[string]int)
+	for i, table := range s.Tables {
+		byName[table.Schema+"."+table.Name] = i
+	}
+
+	count, err := getRowSecurity(db, s, schemaNames, byName)
+	if err == nil {
+		var policies int
+		policies, err = getPolicies(db, s, schemaNames, byName)
+		count += policies
+	}
+	o.recordPhase(PhaseRowSecurity, start, count)
+	return err
+}
+
+func getRowSecurity(db *sql.DB, s *schema.Schema, schemaNames []string, byName map[string]int) (int, error) {
+	rows, err := db.Query(rowSecurityQuery, pq.Array(schemaNames))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var schemaName, tableName string
+		var enabled, forced bool
+		if err := rows.Scan(&schemaName, &tableName, &enabled, &forced); err != nil {
+			return count, err
+		}
+		count++
+		if i, ok := byName[schemaName+"."+tableName]; ok {
+			s.Tables[i].RowSecurity = enabled
+			s.Tables[i].ForceRowSecurity = forced
+		}
+	}
+	return count, rows.Err()
+}
+
+func getPolicies(db *sql.DB, s *schema.Schema, schemaNames []string, byName map[string]int) (int, error) {
+	rows, err := db.Query(policiesQuery, pq.Array(schemaNames))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var schemaName, tableName string
+		var policy schema.Policy
+		err := rows.Scan(&schemaName, &tableName, &policy.Name, &policy.Command, &policy.Permissive,
+			pq.Array(&policy.Roles), &policy.Using, &policy.WithCheck)
+		if err != nil {
+			return count, err
+		}
+		count++
+		if i, ok := byName[schemaName+"."+tableName]; ok {
+			s.Tables[i].Policies = append(s.Tables[i].Policies, policy)
+		}
+	}
+	return count, rows.Err()
+}
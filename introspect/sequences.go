@@ -0,0 +1,155 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// sequencesQuery lists the sequences no column owns. Sequences behind serial
+// columns depend on their column automatically ('a'), and those behind
+// identity columns internally ('i'); both show up as auto-increment columns
+// already.
+const sequencesQuery = `
+	SELECT
+		n.nspname,
+		c.relname,
+		format_type(s.seqtypid, NULL),
+		s.seqstart,
+		s.seqincrement,
+		s.seqmin,
+		s.seqmax,
+		s.seqcycle,
+		COALESCE(obj_description(c.oid, 'pg_class'), '')
+	FROM pg_sequence s
+	JOIN pg_class c ON c.oid = s.seqrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = ANY($1)
+		AND NOT EXISTS (
+			SELECT 1 FROM pg_depend d
+			WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid
+				AND d.refclassid = 'pg_class'::regclass AND d.deptype IN ('a', 'i')
+		)
+	ORDER BY n.nspname, c.relname
+`
+
+// ownedSequenceSQL returns an expression that is true when the column attnum
+// of the relation relid owns a sequence, as serial ('a') and identity ('i')
+// columns do.
+func ownedSequenceSQL(relid, attnum string) string {
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM pg_depend d
+		JOIN pg_class s ON s.oid = d.objid AND s.relkind = 'S'
+		WHERE d.classid = 'pg_class'::regclass AND d.refclassid = 'pg_class'::regclass
+			AND d.refobjid = %s AND d.refobjsubid = %s AND d.deptype IN ('a', 'i'))`, relid, attnum)
+}
+
+// ownedSequencesQuery lists the sequences behind the serial and identity
+// columns of the tables in the requested schemas, with the column each
+// belongs to.
+const ownedSequencesQuery = `
+	SELECT
+		tn.nspname,
+		t.relname,
+		a.attname,
+		n.nspname,
+		c.relname,
+		format_type(s.seqtypid, NULL),
+		s.seqstart,
+		s.seqincrement,
+		s.seqmin,
+		s.seqmax,
+		s.seqcycle,
+		COALESCE(obj_description(c.oid, 'pg_class'), '')
+	FROM pg_sequence s
+	JOIN pg_class c ON c.oid = s.seqrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_depend d ON d.classid = 'pg_class'::regclass AND d.objid = c.oid
+		AND d.refclassid = 'pg_class'::regclass AND d.deptype IN ('a', 'i')
+	JOIN pg_class t ON t.oid = d.refobjid
+	JOIN pg_namespace tn ON tn.oid = t.relnamespace
+	JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = d.refobjsubid
+	WHERE tn.nspname = ANY($1)
+`
+
+// addSequences fills in s.Sequences with the standalone sequences in
+// schemaNames, and Column.Sequence with the sequence behind each serial or
+// identity column. Servers before PostgreSQL 10 keep sequence settings in each
+// sequence rather than pg_sequence, so they are reported as unsupported.
+func addSequences(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	if !o.supports(version10) {
+		s.Diagnostics = append(s.Diagnostics, schema.Diagnostic{
+			Code:    schema.DiagnosticUnsupported,
+			Message: fmt.Sprintf("sequences need PostgreSQL 10 or later, but the server runs %s", formatServerVersion(o.serverVersion)),
+		})
+		return nil
+	}
+	start := time.Now()
+	sequences, err := getSequences(db, schemaNames)
+	o.recordPhase(PhaseSequences, start, len(sequences))
+	if err != nil {
+		return err
+	}
+	s.Sequences = sequences
+
+	start = time.Now()
+	count, err := addOwnedSequences(db, s, schemaNames)
+	o.recordPhase(PhaseSequences, start, count)
+	return err
+}
+
+// addOwnedSequences sets Column.Sequence on the columns that own a sequence
+// and returns how many sequences it read.
+func addOwnedSequences(db *sql.DB, s *schema.Schema, schemaNames []string) (int, error) {
+	rows, err := db.Query(ownedSequencesQuery, pq.Array(schemaNames))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	byName := make(map[string]*schema.Column)
+	for i := range s.Tables {
+		table := &s.Tables[i]
+		for j := range table.Columns {
+			byName[table.Schema+"."+table.Name+"."+table.Columns[j].Name] = &table.Columns[j]
+		}
+	}
+
+	count := 0
+	for rows.Next() {
+		var tableSchema, tableName, columnName string
+		var seq schema.Sequence
+		err := rows.Scan(&tableSchema, &tableName, &columnName, &seq.Schema, &seq.Name, &seq.Type,
+			&seq.Start, &seq.Increment, &seq.MinValue, &seq.MaxValue, &seq.Cycle, &seq.Comment)
+		if err != nil {
+			return count, err
+		}
+		count++
+		if column, ok := byName[tableSchema+"."+tableName+"."+columnName]; ok {
+			column.Sequence = &seq
+		}
+	}
+	return count, rows.Err()
+}
+
+func getSequences(db *sql.DB, schemaNames []string) ([]schema.Sequence, error) {
+	rows, err := db.Query(sequencesQuery, pq.Array(schemaNames))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sequences []schema.Sequence
+	for rows.Next() {
+		var seq schema.Sequence
+		err := rows.Scan(&seq.Schema, &seq.Name, &seq.Type,
+			&seq.Start, &seq.Increment, &seq.MinValue, &seq.MaxValue, &seq.Cycle, &seq.Comment)
+		if err != nil {
+			return nil, err
+		}
+		sequences = append(sequences, seq)
+	}
+	return sequences, rows.Err()
+}
@@ -0,0 +1,117 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Server introspects every database on the server connStr points to. Connect
+// to a maintenance database such as postgres; databases that do not allow
+// connections, templates, and those excluded with WithExcludeDatabases are
+// skipped. Each database is introspected on its own connection with the same
+// options, since PostgreSQL cannot query across databases. Combine the result
+// with schema.MergeDatabases.
+func Server(connStr string, opts ...Option) ([]schema.Database, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	names, err := listDatabases(db)
+	db.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+
+	excluded := make(map[string]bool)
+	for _, name := range o.excludeDatabases {
+		excluded[name] = true
+	}
+
+	var databases []schema.Database
+	for _, name := range names {
+		if excluded[name] {
+			continue
+		}
+		dbConnStr, err := DatabaseConnectionString(connStr, name)
+		if err != nil {
+			return nil, err
+		}
+		s, err := FromConnectionString(dbConnStr, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to introspect database %s: %w", name, err)
+		}
+		databases = append(databases, schema.Database{Name: name, Schema: s})
+	}
+	return databases, nil
+}
+
+// listDatabases returns the databases the current user can connect to.
+func listDatabases(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT datname
+		FROM pg_database
+		WHERE datallowconn AND NOT datistemplate
+			AND has_database_privilege(datname, 'CONNECT')
+		ORDER BY datname
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// DatabaseConnectionString returns connStr pointed at another database on the
+// same server. It accepts both URL and key=value connection strings.
+func DatabaseConnectionString(connStr, database string) (string, error) {
+	if isConnectionURL(connStr) {
+		u, err := url.Parse(connStr)
+		if err != nil {
+			return "", fmt.Errorf("invalid connection URL: %w", err)
+		}
+		u.Path = "/" + database
+		u.RawPath = ""
+		return u.String(), nil
+	}
+	return appendConnParam(connStr, "dbname", database), nil
+}
+
+func isConnectionURL(connStr string) bool {
+	return strings.HasPrefix(connStr, "postgres://") || strings.HasPrefix(connStr, "postgresql://")
+}
+
+// appendConnParam adds key=value to a key=value connection string. Later
+// settings override earlier ones.
+func appendConnParam(connStr, key, value string) string {
+	param := key + "=" + quoteConnValue(value)
+	if strings.TrimSpace(connStr) == "" {
+		return param
+	}
+	return connStr + " " + param
+}
+
+// quoteConnValue quotes a key=value connection string value if needed.
+func quoteConnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
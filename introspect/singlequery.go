@@ -0,0 +1,370 @@
+package introspect
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// singleQuery returns a query for every table in the requested schemas other
+// than partitions, together with its columns, primary keys, indexes, foreign
+// keys, and check constraints, plus the schemas' enum types, as one JSON
+// document.
+func singleQuery(o *options) string {
+	keyCount := indexKeyCountSQL(o, "idx")
+	return `
+	SELECT json_build_object(
+		'enums', (SELECT COALESCE(json_agg(json_build_object(
+				'schema', n.nspname,
+				'name', t.typname,
+				'values', (SELECT json_agg(e.enumlabel ORDER BY e.enumsortorder)
+					FROM pg_enum e
+					WHERE e.enumtypid = t.oid)
+			) ORDER BY n.nspname, t.typname), '[]'::json)
+			FROM pg_type t
+			JOIN pg_namespace n ON n.oid = t.typnamespace
+			WHERE t.typtype = 'e' AND n.nspname = ANY($1)
+		),
+		'tables', (SELECT COALESCE(json_agg(t ORDER BY t.schema, t.name), '[]'::json)
+			FROM (
+				SELECT
+					tbl.table_schema AS schema,
+					tbl.table_name AS name,
+					obj_description(c.oid, 'pg_class') AS comment,
+					NULLIF(` + partitionKeySQL(o, "c") + `, '') AS partition_key,
+					(SELECT fs.srvname FROM pg_foreign_table ft
+						JOIN pg_foreign_server fs ON fs.oid = ft.ftserver
+						WHERE ft.ftrelid = c.oid) AS foreign_server,
+					(SELECT ft.ftoptions FROM pg_foreign_table ft WHERE ft.ftrelid = c.oid) AS foreign_options,
+					` + tablespaceSQL("c") + ` AS tablespace,
+					pg_get_userbyid(c.relowner) AS owner,
+					(SELECT COALESCE(json_agg(json_build_object(
+							'name', col.column_name,
+							'ordinal_position', col.ordinal_position,
+							'data_type', col.data_type,
+							'char_max_length', ` + characterMaximumLengthSQL("col.character_maximum_length", "col.udt_name", "(SELECT a.atttypmod FROM pg_attribute a WHERE a.attrelid = c.oid AND a.attnum = col.ordinal_position)") + `,
+							'numeric_precision', col.numeric_precision,
+							'numeric_scale', col.numeric_scale,
+							'is_nullable', col.is_nullable,
+							'column_default', col.column_default,
+							'udt_name', COALESCE(col.udt_name, col.data_type),
+							'database_type', (SELECT ` + baseTypeSQL("a", "t") + `
+								FROM pg_attribute a
+								JOIN pg_type t ON t.oid = a.atttypid
+								WHERE a.attrelid = c.oid AND a.attnum = col.ordinal_position),
+							'comment', col_description(c.oid, col.ordinal_position),
+							'generated', CASE WHEN col.is_generated = 'ALWAYS' THEN col.generation_expression END,
+							'identity', CASE WHEN col.is_identity = 'YES' THEN col.identity_generation END,
+							'domain', ` + domainSQL("col.domain_schema", "col.domain_name") + `,
+							'collation', ` + collationSQL("col.collation_schema", "col.collation_name") + `,
+							'auto_increment', ` + ownedSequenceSQL("c.oid", "col.ordinal_position") + `
+						) ORDER BY col.ordinal_position), '[]'::json)
+						FROM information_schema.columns col
+						WHERE col.table_schema = tbl.table_schema AND col.table_name = tbl.table_name
+					) AS columns,
+					(SELECT COALESCE(json_agg(a.attname ORDER BY ` + arrayPositionSQL(o, "con.conkey", "a.attnum") + `), '[]'::json)
+						FROM pg_constraint con
+						JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = ANY(con.conkey)
+						WHERE con.conrelid = c.oid AND con.contype = 'p'
+					) AS primary_keys,
+					(SELECT COALESCE(json_agg(json_build_object(
+							'name', ic.relname,
+							'columns', (SELECT json_agg(` + indexKeySQL("k", "a", "idx") + ` ORDER BY k.ord)
+								FROM unnest(idx.indkey::int2[]) WITH ORDINALITY k(attnum, ord)
+								LEFT JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+								WHERE k.ord <= ` + keyCount + `),
+							'include', (SELECT json_agg(a.attname ORDER BY k.ord)
+								FROM unnest(idx.indkey::int2[]) WITH ORDINALITY k(attnum, ord)
+								JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+								WHERE k.ord > ` + keyCount + `),
+							'unique', idx.indisunique,
+							'type', (SELECT amname FROM pg_am WHERE oid = ic.relam),
+							'constraint', EXISTS (
+								SELECT 1 FROM pg_constraint con
+								WHERE con.conrelid = c.oid AND con.conindid = idx.indexrelid AND con.contype = 'u'),
+							'predicate', pg_get_expr(idx.indpred, idx.indrelid),
+							'tablespace', ` + tablespaceSQL("ic") + `
+						) ORDER BY ic.relname), '[]'::json)
+						FROM pg_index idx
+						JOIN pg_class ic ON ic.oid = idx.indexrelid
+						WHERE idx.indrelid = c.oid AND NOT idx.indisprimary
+							AND NOT EXISTS (
+								SELECT 1 FROM pg_constraint con
+								WHERE con.conrelid = c.oid AND con.conindid = idx.indexrelid AND con.contype = 'x')
+					) AS indexes,
+					(SELECT COALESCE(json_agg(json_build_object(
+							'name', con.conname,
+							'to_schema', fn.nspname,
+							'to_table', fc.relname,
+							'from_columns', (SELECT json_agg(a.attname ORDER BY k.ord)
+								FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+								JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum),
+							'to_columns', (SELECT json_agg(a.attname ORDER BY k.ord)
+								FROM unnest(con.confkey) WITH ORDINALITY k(attnum, ord)
+								JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum),
+							'on_delete', ` + referentialActionSQL("con.confdeltype") + `,
+							'on_update', ` + referentialActionSQL("con.confupdtype") + `,
+							'match', CASE con.confmatchtype WHEN 'f' THEN 'FULL' WHEN 'p' THEN 'PARTIAL' ELSE '' END,
+							'deferrable', con.condeferrable,
+							'initially_deferred', con.condeferred
+						) ORDER BY con.conname), '[]'::json)
+						FROM pg_constraint con
+						JOIN pg_class fc ON fc.oid = con.confrelid
+						JOIN pg_namespace fn ON fn.oid = fc.relnamespace
+						WHERE con.conrelid = c.oid AND con.contype = 'f'
+					) AS foreign_keys,
+					(SELECT COALESCE(json_agg(json_build_object(
+							'name', con.conname,
+							'columns', (SELECT json_agg(a.attname ORDER BY a.attnum)
+								FROM pg_attribute a
+								WHERE a.attrelid = con.conrelid AND a.attnum = ANY(con.conkey)),
+							'expression', pg_get_expr(con.conbin, con.conrelid)
+						) ORDER BY con.conname), '[]'::json)
+						FROM pg_constraint con
+						WHERE con.conrelid = c.oid AND con.contype = 'c'
+					) AS checks,
+					(SELECT COALESCE(json_agg(json_build_object(
+							'name', con.conname,
+							'columns', (SELECT json_agg(a.attname ORDER BY k.ord)
+								FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+								JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum)
+						) ORDER BY con.conname), '[]'::json)
+						FROM pg_constraint con
+						WHERE con.conrelid = c.oid AND con.contype = 'u'
+					) AS uniques,
+					(SELECT COALESCE(json_agg(json_build_object(
+							'name', con.conname,
+							'definition', pg_get_constraintdef(con.oid, true)
+						) ORDER BY con.conname), '[]'::json)
+						FROM pg_constraint con
+						WHERE con.conrelid = c.oid AND con.contype = 'x'
+					) AS exclusions
+				FROM information_schema.tables tbl
+				JOIN pg_namespace n ON n.nspname = tbl.table_schema
+				JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = tbl.table_name
+				WHERE tbl.table_schema = ANY($1)
+					AND (tbl.table_type = 'BASE TABLE' OR ($2 AND tbl.table_type = 'FOREIGN'))
+					AND ` + notPartitionSQL(o, "c") + `
+			) t
+		)
+	)
+`
+}
+
+func referentialActionSQL(column string) string {
+	return fmt.Sprintf(`CASE %s
+						WHEN 'r' THEN 'RESTRICT'
+						WHEN 'c' THEN 'CASCADE'
+						WHEN 'n' THEN 'SET NULL'
+						WHEN 'd' THEN 'SET DEFAULT'
+						ELSE 'NO ACTION'
+					END`, column)
+}
+
+type jsonTable struct {
+	Schema         string          `json:"schema"`
+	Name           string          `json:"name"`
+	Columns        []jsonColumn    `json:"columns"`
+	PrimaryKeys    []string        `json:"primary_keys"`
+	Indexes        []jsonIndex     `json:"indexes"`
+	References     []jsonReference `json:"foreign_keys"`
+	Checks         []jsonCheck     `json:"checks"`
+	Exclusions     []jsonExclusion `json:"exclusions"`
+	Uniques        []jsonUnique    `json:"uniques"`
+	Comment        *string         `json:"comment"`
+	PartitionKey   *string         `json:"partition_key"`
+	ForeignServer  *string         `json:"foreign_server"`
+	ForeignOptions []string        `json:"foreign_options"`
+	Tablespace     string          `json:"tablespace"`
+	Owner          string          `json:"owner"`
+}
+
+type jsonColumn struct {
+	Name             string  `json:"name"`
+	OrdinalPosition  int     `json:"ordinal_position"`
+	DataType         string  `json:"data_type"`
+	CharMaxLength    *int64  `json:"char_max_length"`
+	NumericPrecision *int64  `json:"numeric_precision"`
+	NumericScale     *int64  `json:"numeric_scale"`
+	IsNullable       string  `json:"is_nullable"`
+	ColumnDefault    *string `json:"column_default"`
+	UDTName          string  `json:"udt_name"`
+	DatabaseType     string  `json:"database_type"`
+	Comment          *string `json:"comment"`
+	Generated        *string `json:"generated"`
+	Identity         *string `json:"identity"`
+	Domain           string  `json:"domain"`
+	Collation        string  `json:"collation"`
+	AutoIncrement    bool    `json:"auto_increment"`
+}
+
+type jsonDocument struct {
+	Tables []jsonTable   `json:"tables"`
+	Enums  []schema.Enum `json:"enums"`
+}
+
+type jsonIndex struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns"`
+	Include    []string `json:"include"`
+	Unique     bool     `json:"unique"`
+	Type       string   `json:"type"`
+	Constraint bool     `json:"constraint"`
+	Predicate  *string  `json:"predicate"`
+	Tablespace string   `json:"tablespace"`
+}
+
+type jsonCheck struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns"`
+	Expression string   `json:"expression"`
+}
+
+type jsonUnique struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
+type jsonExclusion struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+type jsonReference struct {
+	Name              string   `json:"name"`
+	ToSchema          string   `json:"to_schema"`
+	ToTable           string   `json:"to_table"`
+	FromColumns       []string `json:"from_columns"`
+	ToColumns         []string `json:"to_columns"`
+	OnDelete          string   `json:"on_delete"`
+	OnUpdate          string   `json:"on_update"`
+	Match             string   `json:"match"`
+	Deferrable        bool     `json:"deferrable"`
+	InitiallyDeferred bool     `json:"initially_deferred"`
+}
+
+func introspectSingleQuery(db *sql.DB, schemaNames []string, o *options) (*schema.Schema, error) {
+	if len(schemaNames) == 0 {
+		schemaNames = []string{"public"}
+	}
+
+	start := time.Now()
+	var document []byte
+	if err := db.QueryRow(singleQuery(o), pq.Array(schemaNames), o.foreignTables).Scan(&document); err != nil {
+		return nil, fmt.Errorf("failed to introspect schemas: %w", err)
+	}
+
+	var doc jsonDocument
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode schema document: %w", err)
+	}
+	o.recordPhase(PhaseSingleQuery, start, len(doc.Tables))
+
+	result := &schema.Schema{Enums: doc.Enums}
+	for _, t := range doc.Tables {
+		result.Tables = append(result.Tables, t.toTable(o.typeMapper))
+	}
+
+	return result, nil
+}
+
+// toTable converts t.
+func (t jsonTable) toTable(mapper TypeMapper) schema.Table {
+	table := schema.Table{
+		Name:         t.Name,
+		Schema:       t.Schema,
+		PrimaryKeys:  t.PrimaryKeys,
+		Comment:      stringValue(t.Comment),
+		PartitionKey: stringValue(t.PartitionKey),
+		Tablespace:   t.Tablespace,
+		Owner:        t.Owner,
+	}
+	if t.ForeignServer != nil {
+		table.Foreign = &schema.ForeignTable{Server: *t.ForeignServer, Options: t.ForeignOptions}
+	}
+
+	for _, c := range t.Columns {
+		col := schema.Column{
+			Name:            c.Name,
+			OrdinalPosition: c.OrdinalPosition,
+			Type:            mapColumnType(mapper, c.DataType, c.UDTName, nullInt64(c.CharMaxLength), nullInt64(c.NumericPrecision), nullInt64(c.NumericScale)),
+			Nullable:        c.IsNullable == "YES",
+			DefaultValue:    c.ColumnDefault,
+			DatabaseType:    c.DatabaseType,
+			Comment:         stringValue(c.Comment),
+			Generated:       stringValue(c.Generated),
+			Identity:        stringValue(c.Identity),
+			Domain:          c.Domain,
+			Collation:       c.Collation,
+			AutoIncrement:   c.AutoIncrement,
+		}
+		for _, pk := range t.PrimaryKeys {
+			if col.Name == pk {
+				col.IsPrimaryKey = true
+				break
+			}
+		}
+		table.Columns = append(table.Columns, col)
+	}
+
+	for _, idx := range t.Indexes {
+		table.Indexes = append(table.Indexes, schema.Index{
+			Name:             idx.Name,
+			Columns:          idx.Columns,
+			Include:          idx.Include,
+			Unique:           idx.Unique,
+			UniqueConstraint: idx.Constraint,
+			Type:             indexType(idx.Type),
+			Where:            stringValue(idx.Predicate),
+			Tablespace:       idx.Tablespace,
+		})
+	}
+
+	for _, u := range t.Uniques {
+		table.UniqueConstraints = append(table.UniqueConstraints, schema.UniqueConstraint(u))
+	}
+	for _, c := range t.Checks {
+		table.CheckConstraints = append(table.CheckConstraints, schema.CheckConstraint(c))
+	}
+	for _, e := range t.Exclusions {
+		table.ExclusionConstraints = append(table.ExclusionConstraints, schema.ExclusionConstraint(e))
+	}
+
+	// The query orders foreign keys by constraint name, like getForeignKeys
+	for _, r := range t.References {
+		table.References = append(table.References, schema.Reference{
+			Name:              r.Name,
+			FromTable:         t.Name,
+			FromSchema:        t.Schema,
+			FromColumns:       r.FromColumns,
+			ToTable:           r.ToTable,
+			ToSchema:          r.ToSchema,
+			ToColumns:         r.ToColumns,
+			OnDelete:          r.OnDelete,
+			OnUpdate:          r.OnUpdate,
+			Match:             r.Match,
+			Deferrable:        r.Deferrable,
+			InitiallyDeferred: r.InitiallyDeferred,
+		})
+	}
+
+	return table
+}
+
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,83 @@
+package introspect
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONTableToTable(t *testing.T) {
+	document := `{
+		"schema": "public",
+		"name": "posts",
+		"columns": [
+			{"name": "id", "data_type": "integer", "udt_name": "int4", "is_nullable": "NO", "column_default": "nextval('posts_id_seq'::regclass)", "auto_increment": true},
+			{"name": "title", "data_type": "character varying", "udt_name": "varchar", "char_max_length": 200, "is_nullable": "YES"},
+			{"name": "user_id", "data_type": "integer", "udt_name": "int4", "is_nullable": "NO"}
+		],
+		"primary_keys": ["id"],
+		"indexes": [
+			{"name": "idx_posts_user_id", "columns": ["user_id"], "unique": false, "constraint": false},
+			{"name": "posts_title_key", "columns": ["title"], "unique": true, "constraint": true},
+			{"name": "idx_posts_user_title", "columns": ["user_id"], "include": ["title"], "unique": false, "constraint": false},
+			{"name": "idx_posts_lower_title", "columns": ["` + "`lower((title)::text)`" + `"], "unique": false, "constraint": false}
+		],
+		"foreign_keys": [
+			{"name": "posts_author_fkey", "to_schema": "public", "to_table": "authors", "from_columns": ["user_id", "title"], "to_columns": ["user_id", "pen_name"], "on_delete": "NO ACTION", "on_update": "NO ACTION", "match": "FULL"},
+			{"name": "posts_user_id_fkey", "to_schema": "public", "to_table": "users", "from_columns": ["user_id"], "to_columns": ["id"], "on_delete": "CASCADE", "on_update": "NO ACTION"}
+		],
+		"checks": [
+			{"name": "posts_title_check", "columns": ["title"], "expression": "(length((title)::text) > 0)"}
+		],
+		"uniques": [
+			{"name": "posts_title_key", "columns": ["title"]}
+		],
+		"exclusions": [
+			{"name": "posts_user_id_excl", "definition": "EXCLUDE USING gist (user_id WITH =)"}
+		]
+	}`
+
+	var jt jsonTable
+	if err := json.Unmarshal([]byte(document), &jt); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+
+	table := jt.toTable(nil)
+
+	if len(table.Columns) != 3 {
+		t.Fatalf("Expected 3 columns, got %d", len(table.Columns))
+	}
+	if !table.Columns[0].IsPrimaryKey || table.Columns[0].Type != "int" || !table.Columns[0].AutoIncrement {
+		t.Errorf("Expected id to be an auto-incrementing int primary key, got %+v", table.Columns[0])
+	}
+	if table.Columns[1].Type != "varchar(200)" || !table.Columns[1].Nullable {
+		t.Errorf("Expected title to be a nullable varchar(200), got %+v", table.Columns[1])
+	}
+	if len(table.Indexes) != 4 || table.Indexes[0].Columns[0] != "user_id" || table.Indexes[0].UniqueConstraint {
+		t.Fatalf("Unexpected indexes: %+v", table.Indexes)
+	}
+	if !table.Indexes[1].Unique || !table.Indexes[1].UniqueConstraint {
+		t.Errorf("Expected posts_title_key to back a UNIQUE constraint, got %+v", table.Indexes[1])
+	}
+	if len(table.Indexes[2].Include) != 1 || table.Indexes[2].Include[0] != "title" {
+		t.Errorf("Expected idx_posts_user_title to include title, got %+v", table.Indexes[2])
+	}
+	if columns := table.Indexes[3].Columns; len(columns) != 1 || columns[0] != "`lower((title)::text)`" {
+		t.Errorf("Expected idx_posts_lower_title to keep its expression, got %+v", table.Indexes[3])
+	}
+	if len(table.References) != 2 || table.References[1].Name != "posts_user_id_fkey" || table.References[1].ToTable != "users" || table.References[1].OnDelete != "CASCADE" {
+		t.Fatalf("Unexpected references: %+v", table.References)
+	}
+	if ref := table.References[0]; strings.Join(ref.FromColumns, ",") != "user_id,title" || strings.Join(ref.ToColumns, ",") != "user_id,pen_name" || ref.Match != "FULL" {
+		t.Errorf("Expected the composite foreign key as one reference, got %+v", ref)
+	}
+	if len(table.CheckConstraints) != 1 || table.CheckConstraints[0].Columns[0] != "title" || table.CheckConstraints[0].Expression != "(length((title)::text) > 0)" {
+		t.Errorf("Unexpected check constraints: %+v", table.CheckConstraints)
+	}
+	if len(table.UniqueConstraints) != 1 || table.UniqueConstraints[0].Name != "posts_title_key" || table.UniqueConstraints[0].Columns[0] != "title" {
+		t.Errorf("Unexpected unique constraints: %+v", table.UniqueConstraints)
+	}
+	if len(table.ExclusionConstraints) != 1 || table.ExclusionConstraints[0].Definition != "EXCLUDE USING gist (user_id WITH =)" {
+		t.Errorf("Unexpected exclusion constraints: %+v", table.ExclusionConstraints)
+	}
+}
@@ -0,0 +1,61 @@
+package introspect
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+const statisticsQuery = `
+	SELECT
+		n.nspname,
+		c.relname,
+		GREATEST(c.reltuples, 0)::bigint,
+		pg_total_relation_size(c.oid)
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = ANY($1) AND c.relkind IN ('r', 'p')
+`
+
+// addStatistics fills in Table.Statistics for every table in s.
+func addStatistics(db *sql.DB, s *schema.Schema, o *options) error {
+	seen := make(map[string]bool)
+	var schemaNames []string
+	for _, table := range s.Tables {
+		if !seen[table.Schema] {
+			seen[table.Schema] = true
+			schemaNames = append(schemaNames, table.Schema)
+		}
+	}
+	if len(schemaNames) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	rows, err := db.Query(statisticsQuery, pq.Array(schemaNames))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]*schema.TableStatistics)
+	for rows.Next() {
+		var schemaName, tableName string
+		var stat schema.TableStatistics
+		if err := rows.Scan(&schemaName, &tableName, &stat.RowEstimate, &stat.TotalBytes); err != nil {
+			return err
+		}
+		stats[schemaName+"."+tableName] = &stat
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	o.recordPhase(PhaseStatistics, start, len(stats))
+
+	for i := range s.Tables {
+		s.Tables[i].Statistics = stats[s.Tables[i].Schema+"."+s.Tables[i].Name]
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package introspect
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// triggersQuery lists the user-defined triggers on the tables in the
+// requested schemas. Internal triggers, which implement foreign keys and
+// deferred constraints, are left out.
+const triggersQuery = `
+	SELECT
+		n.nspname,
+		c.relname,
+		t.tgname,
+		t.tgtype,
+		CASE WHEN pn.nspname = 'public' THEN p.proname ELSE pn.nspname || '.' || p.proname END,
+		t.tgenabled <> 'D'
+	FROM pg_trigger t
+	JOIN pg_class c ON c.oid = t.tgrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_proc p ON p.oid = t.tgfoid
+	JOIN pg_namespace pn ON pn.oid = p.pronamespace
+	WHERE n.nspname = ANY($1) AND NOT t.tgisinternal
+	ORDER BY n.nspname, c.relname, t.tgname
+`
+
+// Bits of pg_trigger.tgtype.
+const (
+	triggerTypeRow      = 1 << 0
+	triggerTypeBefore   = 1 << 1
+	triggerTypeInsert   = 1 << 2
+	triggerTypeDelete   = 1 << 3
+	triggerTypeUpdate   = 1 << 4
+	triggerTypeTruncate = 1 << 5
+	triggerTypeInstead  = 1 << 6
+)
+
+// addTriggers fills in Table.Triggers for the tables in s.
+func addTriggers(db *sql.DB, s *schema.Schema, schemaNames []string, o *options) error {
+	start := time.Now()
+	rows, err := db.Query(triggersQuery, pq.Array(schemaNames))
+	if err != nil {
+		o.recordPhase(PhaseTriggers, start, 0)
+		return err
+	}
+	defer rows.Close()
+
+	byName := make(map[string]int)
+	for i, table := range s.Tables {
+		byName[table.Schema+"."+table.Name] = i
+	}
+
+	count := 0
+	for rows.Next() {
+		var schemaName, tableName string
+		var trigger schema.Trigger
+		var tgtype int
+		if err := rows.Scan(&schemaName, &tableName, &trigger.Name, &tgtype, &trigger.Function, &trigger.Enabled); err != nil {
+			return err
+		}
+		count++
+		trigger.Timing, trigger.Events, trigger.ForEach = decodeTriggerType(tgtype)
+		if i, ok := byName[schemaName+"."+tableName]; ok {
+			s.Tables[i].Triggers = append(s.Tables[i].Triggers, trigger)
+		}
+	}
+	o.recordPhase(PhaseTriggers, start, count)
+	return rows.Err()
+}
+
+// decodeTriggerType splits a pg_trigger.tgtype bitmask into the trigger's
+// timing, the events that fire it, and whether it fires for each row or
+// statement.
+func decodeTriggerType(tgtype int) (timing string, events []string, forEach string) {
+	switch {
+	case tgtype&triggerTypeInstead != 0:
+		timing = "INSTEAD OF"
+	case tgtype&triggerTypeBefore != 0:
+		timing = "BEFORE"
+	default:
+		timing = "AFTER"
+	}
+
+	for _, event := range []struct {
+		bit  int
+		name string
+	}{
+		{triggerTypeInsert, "INSERT"},
+		{triggerTypeUpdate, "UPDATE"},
+		{triggerTypeDelete, "DELETE"},
+		{triggerTypeTruncate, "TRUNCATE"},
+	} {
+		if tgtype&event.bit != 0 {
+			events = append(events, event.name)
+		}
+	}
+
+	forEach = "STATEMENT"
+	if tgtype&triggerTypeRow != 0 {
+		forEach = "ROW"
+	}
+	return timing, events, forEach
+}
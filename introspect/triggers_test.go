@@ -0,0 +1,32 @@
+package introspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeTriggerType(t *testing.T) {
+	tests := []struct {
+		tgtype  int
+		timing  string
+		events  []string
+		forEach string
+	}{
+		// AFTER INSERT OR UPDATE FOR EACH ROW
+		{1 | 4 | 16, "AFTER", []string{"INSERT", "UPDATE"}, "ROW"},
+		// BEFORE DELETE FOR EACH ROW
+		{1 | 2 | 8, "BEFORE", []string{"DELETE"}, "ROW"},
+		// AFTER TRUNCATE FOR EACH STATEMENT
+		{32, "AFTER", []string{"TRUNCATE"}, "STATEMENT"},
+		// INSTEAD OF INSERT FOR EACH ROW
+		{1 | 4 | 64, "INSTEAD OF", []string{"INSERT"}, "ROW"},
+	}
+
+	for _, tt := range tests {
+		timing, events, forEach := decodeTriggerType(tt.tgtype)
+		if timing != tt.timing || !reflect.DeepEqual(events, tt.events) || forEach != tt.forEach {
+			t.Errorf("decodeTriggerType(%d) = %s %v %s, want %s %v %s",
+				tt.tgtype, timing, events, forEach, tt.timing, tt.events, tt.forEach)
+		}
+	}
+}
@@ -12,7 +12,8 @@ type TypeMapper interface {
 	// MapType converts a database column type to a DBML type string.
 	// dataType is the base data type (e.g., "integer", "varchar")
 	// udtName is the user-defined type name for custom types
-	// charMaxLength, numericPrecision, numericScale provide type modifiers
+	// charMaxLength, numericPrecision, numericScale provide type modifiers;
+	// for pgvector types charMaxLength is the dimension
 	MapType(dataType, udtName string, charMaxLength, numericPrecision, numericScale sql.NullInt64) string
 }
 
@@ -50,6 +51,11 @@ func (m *PostgreSQLTypeMapper) MapType(dataType, udtName string, charMaxLength,
 			return mapped
 		}
 	}
+	// Map array elements with the custom mappings too
+	if strings.EqualFold(dataType, "array") {
+		elementType, elementName := arrayElement(udtName)
+		return m.MapType(elementType, elementName, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}) + "[]"
+	}
 	// Fall back to default implementation
 	return MapPostgreSQLTypeToDBML(dataType, udtName, charMaxLength, numericPrecision, numericScale)
 }
@@ -89,11 +95,94 @@ var DefaultTypeMappings = map[string]string{
 	"json":                        "json",
 	"jsonb":                       "jsonb",
 	"bytea":                       "binary",
+	"bit":                         "bit",
+	"bit varying":                 "varbit",
+	"varbit":                      "varbit",
+	"inet":                        "inet",
+	"cidr":                        "cidr",
+	"macaddr":                     "macaddr",
+	"macaddr8":                    "macaddr8",
+	"money":                       "money",
+	"interval":                    "interval",
+	"xml":                         "xml",
+	"tsvector":                    "tsvector",
+	"tsquery":                     "tsquery",
+	"int4range":                   "int4range",
+	"int8range":                   "int8range",
+	"numrange":                    "numrange",
+	"tsrange":                     "tsrange",
+	"tstzrange":                   "tstzrange",
+	"daterange":                   "daterange",
+	"int4multirange":              "int4multirange",
+	"int8multirange":              "int8multirange",
+	"nummultirange":               "nummultirange",
+	"tsmultirange":                "tsmultirange",
+	"tstzmultirange":              "tstzmultirange",
+	"datemultirange":              "datemultirange",
+	"point":                       "point",
+	"line":                        "line",
+	"lseg":                        "lseg",
+	"box":                         "box",
+	"path":                        "path",
+	"polygon":                     "polygon",
+	"circle":                      "circle",
+	"vector":                      "vector",
+	"halfvec":                     "halfvec",
+	"sparsevec":                   "sparsevec",
+}
+
+// vectorTypes are the pgvector extension's types, whose type modifier is the
+// number of dimensions.
+var vectorTypes = map[string]bool{
+	"vector":    true,
+	"halfvec":   true,
+	"sparsevec": true,
+}
+
+// characterMaximumLengthSQL returns SQL for a column's character maximum
+// length that reports the dimension of pgvector columns instead, which
+// information_schema leaves NULL.
+func characterMaximumLengthSQL(charMaxLength, udtName, typmod string) string {
+	return fmt.Sprintf("CASE WHEN %s IN ('vector', 'halfvec', 'sparsevec') AND %s > 0 THEN %s ELSE %s END",
+		udtName, typmod, typmod, charMaxLength)
+}
+
+// collationSQL returns SQL spelling a column's collation the way
+// Column.Collation does, or an empty string when name is NULL, which
+// information_schema reports for the default collation.
+func collationSQL(schemaName, name string) string {
+	return fmt.Sprintf("CASE WHEN %[2]s IS NULL THEN '' WHEN %[1]s IN ('pg_catalog', 'public') THEN quote_ident(%[2]s) ELSE quote_ident(%[1]s) || '.' || quote_ident(%[2]s) END",
+		schemaName, name)
+}
+
+// arrayElement returns the data type and type name of the elements of an
+// array type, which PostgreSQL names after its element type with a leading
+// underscore, as in "_int4". Elements that are not built-in types, such as
+// enums, are reported as USER-DEFINED, as they are for scalar columns.
+func arrayElement(udtName string) (dataType, name string) {
+	name = strings.TrimPrefix(strings.ToLower(udtName), "_")
+	if name == "bpchar" {
+		name = "char"
+	}
+	if _, ok := DefaultTypeMappings[name]; ok {
+		return name, name
+	}
+	return "USER-DEFINED", name
 }
 
 // MapPostgreSQLTypeToDBML converts a PostgreSQL data type to its DBML equivalent.
-// It handles varchar lengths, numeric precision/scale, and custom types.
+// It handles varchar lengths, numeric precision/scale, pgvector dimensions,
+// arrays, which are written as their element type followed by "[]", and
+// custom types.
 func MapPostgreSQLTypeToDBML(dataType, udtName string, charMaxLength, numericPrecision, numericScale sql.NullInt64) string {
+	// pgvector types are reported as USER-DEFINED, or by name for views
+	if name := strings.ToLower(udtName); vectorTypes[name] {
+		if charMaxLength.Valid {
+			return fmt.Sprintf("%s(%d)", name, charMaxLength.Int64)
+		}
+		return name
+	}
+
 	switch strings.ToLower(dataType) {
 	case "integer", "int4":
 		return "int"
@@ -142,10 +231,27 @@ func MapPostgreSQLTypeToDBML(dataType, udtName string, charMaxLength, numericPre
 		return "jsonb"
 	case "bytea":
 		return "binary"
+	case "bit":
+		if charMaxLength.Valid {
+			return fmt.Sprintf("bit(%d)", charMaxLength.Int64)
+		}
+		return "bit"
+	case "bit varying", "varbit":
+		if charMaxLength.Valid {
+			return fmt.Sprintf("varbit(%d)", charMaxLength.Int64)
+		}
+		return "varbit"
+	case "inet", "cidr", "macaddr", "macaddr8", "money", "interval", "xml",
+		"tsvector", "tsquery", "point", "line", "lseg", "box", "path", "polygon", "circle":
+		return strings.ToLower(dataType)
+	case "int4range", "int8range", "numrange", "tsrange", "tstzrange", "daterange",
+		"int4multirange", "int8multirange", "nummultirange", "tsmultirange", "tstzmultirange", "datemultirange":
+		return strings.ToLower(dataType)
 	case "user-defined":
 		return NormalizeCustomType(udtName)
 	case "array":
-		return NormalizeCustomType(udtName)
+		elementType, elementName := arrayElement(udtName)
+		return MapPostgreSQLTypeToDBML(elementType, elementName, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}) + "[]"
 	default:
 		return dataType
 	}
@@ -36,7 +36,35 @@ func TestMapPostgreSQLTypeToDBML(t *testing.T) {
 		{"jsonb", "jsonb", "jsonb", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "jsonb"},
 		{"bytea", "bytea", "bytea", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "binary"},
 		{"user-defined", "user-defined", "custom_enum", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "text"},
-		{"array type", "array", "_int4", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "text"},
+		{"bit with length", "bit", "bit", sql.NullInt64{Valid: true, Int64: 8}, sql.NullInt64{}, sql.NullInt64{}, "bit(8)"},
+		{"bit varying with length", "bit varying", "varbit", sql.NullInt64{Valid: true, Int64: 64}, sql.NullInt64{}, sql.NullInt64{}, "varbit(64)"},
+		{"bit varying without length", "bit varying", "varbit", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "varbit"},
+		{"inet", "inet", "inet", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "inet"},
+		{"cidr", "cidr", "cidr", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "cidr"},
+		{"macaddr", "macaddr", "macaddr", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "macaddr"},
+		{"money", "money", "money", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "money"},
+		{"interval", "interval", "interval", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "interval"},
+		{"xml", "xml", "xml", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "xml"},
+		{"macaddr8", "macaddr8", "macaddr8", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "macaddr8"},
+		{"tsvector", "tsvector", "tsvector", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "tsvector"},
+		{"tsquery", "tsquery", "tsquery", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "tsquery"},
+		{"int4range", "int4range", "int4range", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "int4range"},
+		{"tstzrange", "tstzrange", "tstzrange", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "tstzrange"},
+		{"datemultirange", "datemultirange", "datemultirange", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "datemultirange"},
+		{"bit without length", "bit", "bit", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "bit"},
+		{"point", "point", "point", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "point"},
+		{"line", "line", "line", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "line"},
+		{"vector", "user-defined", "vector", sql.NullInt64{Valid: true, Int64: 1536}, sql.NullInt64{}, sql.NullInt64{}, "vector(1536)"},
+		{"halfvec", "user-defined", "halfvec", sql.NullInt64{Valid: true, Int64: 768}, sql.NullInt64{}, sql.NullInt64{}, "halfvec(768)"},
+		{"vector without dimension", "vector", "vector", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "vector"},
+		{"array type", "array", "_int4", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "int[]"},
+		{"varchar array", "ARRAY", "_varchar", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "varchar[]"},
+		{"char array", "ARRAY", "_bpchar", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "char[]"},
+		{"uuid array", "ARRAY", "_uuid", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "uuid[]"},
+		{"inet array", "ARRAY", "_inet", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "inet[]"},
+		{"daterange array", "ARRAY", "_daterange", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "daterange[]"},
+		{"tsvector array", "ARRAY", "_tsvector", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "tsvector[]"},
+		{"custom type array", "ARRAY", "_custom_enum", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "text[]"},
 		{"unknown type", "custom_type", "custom_type", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}, "custom_type"},
 	}
 
@@ -79,6 +107,13 @@ func TestPostgreSQLTypeMapper(t *testing.T) {
 		}
 	})
 
+	t.Run("array element", func(t *testing.T) {
+		result := mapper.MapType("ARRAY", "_citext", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+		if result != "varchar[]" {
+			t.Errorf("Expected 'varchar[]' for citext[], got '%s'", result)
+		}
+	})
+
 	t.Run("case insensitive", func(t *testing.T) {
 		result := mapper.MapType("CITEXT", "citext", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
 		if result != "varchar" {
@@ -0,0 +1,106 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Server versions, as server_version_num reports them, at which the catalogs
+// introspection reads changed.
+const (
+	// minServerVersion is the oldest server introspection supports. Older
+	// servers lack WITH ORDINALITY and aggregate FILTER clauses.
+	minServerVersion = 90400
+	// version95 added array_position and row level security.
+	version95 = 90500
+	// version10 added declarative partitioning, relispartition, and
+	// pg_sequence.
+	version10 = 100000
+	// version11 added procedures, pg_proc.prokind, and INCLUDE index
+	// columns, pg_index.indnkeyatts.
+	version11 = 110000
+)
+
+// getServerVersion returns the server's server_version_num, such as 150004
+// for 15.4.
+func getServerVersion(db *sql.DB) (int, error) {
+	var value string
+	if err := db.QueryRow(`SHOW server_version_num`).Scan(&value); err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid server_version_num %q: %w", value, err)
+	}
+	return version, nil
+}
+
+// formatServerVersion formats a server_version_num as PostgreSQL writes its
+// version: 150004 as "15.4" and 90624 as "9.6.24".
+func formatServerVersion(version int) string {
+	if version >= version10 {
+		return fmt.Sprintf("%d.%d", version/10000, version%10000)
+	}
+	return fmt.Sprintf("%d.%d.%d", version/10000, version/100%100, version%100)
+}
+
+// supports reports whether the server is at least version. A server whose
+// version was not detected is assumed to be current.
+func (o *options) supports(version int) bool {
+	return o.serverVersion == 0 || o.serverVersion >= version
+}
+
+// notPartitionSQL returns a condition excluding partitions, which are tables
+// in pg_class, for the pg_class row relation.
+func notPartitionSQL(o *options, relation string) string {
+	if !o.supports(version10) {
+		return "true"
+	}
+	return fmt.Sprintf("NOT %s.relispartition", relation)
+}
+
+// partitionKeySQL returns SQL for the partition key of the pg_class row
+// relation, or an empty string if it is not partitioned.
+func partitionKeySQL(o *options, relation string) string {
+	if !o.supports(version10) {
+		return "''"
+	}
+	return fmt.Sprintf("COALESCE(pg_get_partkeydef(%s.oid), '')", relation)
+}
+
+// indexKeyCountSQL returns SQL for the number of key columns of the pg_index
+// row index. Before INCLUDE columns, every column was a key column.
+func indexKeyCountSQL(o *options, index string) string {
+	if !o.supports(version11) {
+		return index + ".indnatts"
+	}
+	return index + ".indnkeyatts"
+}
+
+// arrayPositionSQL returns SQL for the position of element in the one
+// dimensional array, counting from 1.
+func arrayPositionSQL(o *options, array, element string) string {
+	if !o.supports(version95) {
+		return fmt.Sprintf("(SELECT i FROM generate_subscripts(%[1]s, 1) i WHERE (%[1]s)[i] = %[2]s)", array, element)
+	}
+	return fmt.Sprintf("array_position(%s, %s)", array, element)
+}
+
+// functionKindSQL returns SQL for whether the pg_proc row proc is a
+// "function" or a "procedure", and functionFilterSQL a condition excluding
+// aggregates and window functions.
+func functionKindSQL(o *options, proc string) string {
+	if !o.supports(version11) {
+		return "'function'"
+	}
+	return fmt.Sprintf("CASE %s.prokind WHEN 'p' THEN 'procedure' ELSE 'function' END", proc)
+}
+
+func functionFilterSQL(o *options, proc string) string {
+	if !o.supports(version11) {
+		return fmt.Sprintf("NOT %[1]s.proisagg AND NOT %[1]s.proiswindow", proc)
+	}
+	return fmt.Sprintf("%s.prokind IN ('f', 'p')", proc)
+}
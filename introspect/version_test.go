@@ -0,0 +1,77 @@
+package introspect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestFormatServerVersion(t *testing.T) {
+	tests := map[int]string{
+		170002: "17.2",
+		150004: "15.4",
+		100000: "10.0",
+		90624:  "9.6.24",
+		90400:  "9.4.0",
+	}
+	for version, want := range tests {
+		if got := formatServerVersion(version); got != want {
+			t.Errorf("formatServerVersion(%d) = %q, want %q", version, got, want)
+		}
+	}
+}
+
+func TestVersionSQL(t *testing.T) {
+	current := &options{serverVersion: 160000}
+	unknown := &options{}
+	old := &options{serverVersion: 90424}
+
+	for _, o := range []*options{current, unknown} {
+		if got := notPartitionSQL(o, "c"); got != "NOT c.relispartition" {
+			t.Errorf("Expected relispartition on %d, got %q", o.serverVersion, got)
+		}
+		if got := indexKeyCountSQL(o, "idx"); got != "idx.indnkeyatts" {
+			t.Errorf("Expected indnkeyatts on %d, got %q", o.serverVersion, got)
+		}
+		if got := arrayPositionSQL(o, "con.conkey", "a.attnum"); got != "array_position(con.conkey, a.attnum)" {
+			t.Errorf("Expected array_position on %d, got %q", o.serverVersion, got)
+		}
+		if got := functionFilterSQL(o, "p"); !strings.Contains(got, "prokind") {
+			t.Errorf("Expected prokind on %d, got %q", o.serverVersion, got)
+		}
+	}
+
+	if got := notPartitionSQL(old, "c"); got != "true" {
+		t.Errorf("Expected no partition filter before 10, got %q", got)
+	}
+	if got := partitionKeySQL(old, "c"); got != "''" {
+		t.Errorf("Expected no partition key before 10, got %q", got)
+	}
+	if got := indexKeyCountSQL(old, "idx"); got != "idx.indnatts" {
+		t.Errorf("Expected indnatts before 11, got %q", got)
+	}
+	if got := arrayPositionSQL(old, "con.conkey", "a.attnum"); strings.Contains(got, "array_position") {
+		t.Errorf("Expected no array_position before 9.5, got %q", got)
+	}
+	for _, query := range []string{singleQuery(old), inheritanceQuery(old), functionsQuery(old)} {
+		for _, missing := range []string{"relispartition", "pg_get_partkeydef", "indnkeyatts", "array_position", "prokind"} {
+			if strings.Contains(query, missing) {
+				t.Errorf("Expected no %s before 9.5, got:\n%s", missing, query)
+			}
+		}
+	}
+}
+
+func TestAddSequencesBefore10(t *testing.T) {
+	s := &schema.Schema{}
+	if err := addSequences(nil, s, []string{"public"}, &options{serverVersion: 90624}); err != nil {
+		t.Fatalf("addSequences returned error: %v", err)
+	}
+	if len(s.Diagnostics) != 1 || s.Diagnostics[0].Code != schema.DiagnosticUnsupported {
+		t.Fatalf("Expected an unsupported diagnostic, got %+v", s.Diagnostics)
+	}
+	if !strings.Contains(s.Diagnostics[0].Message, "9.6.24") {
+		t.Errorf("Expected the diagnostic to name the server version, got %q", s.Diagnostics[0].Message)
+	}
+}
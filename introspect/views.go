@@ -0,0 +1,180 @@
+package introspect
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+const viewsQuery = `
+	SELECT
+		n.nspname,
+		c.relname,
+		c.relkind = 'm',
+		pg_get_viewdef(c.oid, true),
+		COALESCE(obj_description(c.oid, 'pg_class'), '')
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = ANY($1) AND c.relkind::text = ANY($2)
+	ORDER BY n.nspname, c.relname
+`
+
+// viewColumnsQuery reads view columns from pg_attribute, since
+// information_schema.columns leaves out materialized views. The type columns
+// mirror information_schema, resolving domains to their base type bt, so the
+// same type mapping applies.
+var viewColumnsQuery = `
+	SELECT
+		n.nspname,
+		c.relname,
+		a.attname,
+		a.attnum,
+		CASE
+			WHEN bt.typcategory = 'A' THEN 'ARRAY'
+			WHEN bt.typtype = 'e' THEN 'USER-DEFINED'
+			ELSE format_type(bt.oid, NULL)
+		END,
+		` + characterMaximumLengthSQL("information_schema._pg_char_max_length(bt.oid, information_schema._pg_truetypmod(a.*, t.*))", "bt.typname", "information_schema._pg_truetypmod(a.*, t.*)") + `,
+		information_schema._pg_numeric_precision(bt.oid, information_schema._pg_truetypmod(a.*, t.*)),
+		information_schema._pg_numeric_scale(bt.oid, information_schema._pg_truetypmod(a.*, t.*)),
+		NOT a.attnotnull,
+		bt.typname,
+		` + baseTypeSQL("a", "t") + `,
+		COALESCE(col_description(c.oid, a.attnum), ''),
+		` + domainSQL("tn.nspname", "CASE WHEN t.typtype = 'd' THEN t.typname END") + `
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+	JOIN pg_type t ON t.oid = a.atttypid
+	JOIN pg_namespace tn ON tn.oid = t.typnamespace
+	JOIN pg_type bt ON bt.oid = information_schema._pg_truetypid(a.*, t.*)
+	WHERE n.nspname = ANY($1) AND c.relkind::text = ANY($2)
+	ORDER BY n.nspname, c.relname, a.attnum
+`
+
+// viewSourcesQuery finds what each view reads from the dependencies of its
+// rewrite rule, which name every table and column the query touches.
+const viewSourcesQuery = `
+	SELECT DISTINCT
+		vn.nspname,
+		v.relname,
+		sn.nspname,
+		s.relname,
+		COALESCE(sa.attname, '')
+	FROM pg_class v
+	JOIN pg_namespace vn ON vn.oid = v.relnamespace
+	JOIN pg_rewrite r ON r.ev_class = v.oid
+	JOIN pg_depend d ON d.classid = 'pg_rewrite'::regclass AND d.objid = r.oid
+		AND d.refclassid = 'pg_class'::regclass
+	JOIN pg_class s ON s.oid = d.refobjid AND s.oid <> v.oid
+	JOIN pg_namespace sn ON sn.oid = s.relnamespace
+	LEFT JOIN pg_attribute sa ON sa.attrelid = s.oid AND sa.attnum = d.refobjsubid AND d.refobjsubid > 0
+	WHERE vn.nspname = ANY($1) AND v.relkind::text = ANY($2)
+		AND s.relkind IN ('r', 'p', 'v', 'm', 'f')
+	ORDER BY 1, 2, 3, 4, 5
+`
+
+// addViews fills in s.Views with the views in schemaNames whose relkind is
+// one of kinds.
+func addViews(db *sql.DB, s *schema.Schema, schemaNames, kinds []string, o *options) error {
+	start := time.Now()
+	views, err := getViews(db, schemaNames, kinds, o.typeMapper)
+	o.recordPhase(PhaseViews, start, len(views))
+	if err != nil {
+		return err
+	}
+	s.Views = views
+	return nil
+}
+
+func getViews(db *sql.DB, schemaNames, kinds []string, mapper TypeMapper) ([]schema.View, error) {
+	rows, err := db.Query(viewsQuery, pq.Array(schemaNames), pq.Array(kinds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []schema.View
+	byName := make(map[string]int)
+	for rows.Next() {
+		var view schema.View
+		if err := rows.Scan(&view.Schema, &view.Name, &view.Materialized, &view.Definition, &view.Comment); err != nil {
+			return nil, err
+		}
+		byName[view.Schema+"."+view.Name] = len(views)
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(views) == 0 {
+		return nil, nil
+	}
+
+	if err := addViewColumns(db, schemaNames, kinds, mapper, views, byName); err != nil {
+		return nil, err
+	}
+	if err := addViewSources(db, schemaNames, kinds, views, byName); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func addViewColumns(db *sql.DB, schemaNames, kinds []string, mapper TypeMapper, views []schema.View, byName map[string]int) error {
+	rows, err := db.Query(viewColumnsQuery, pq.Array(schemaNames), pq.Array(kinds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, viewName, dataType, udtName string
+		var charMaxLength, numericPrecision, numericScale sql.NullInt64
+		var col schema.Column
+		err := rows.Scan(&schemaName, &viewName, &col.Name, &col.OrdinalPosition, &dataType,
+			&charMaxLength, &numericPrecision, &numericScale,
+			&col.Nullable, &udtName, &col.DatabaseType, &col.Comment, &col.Domain)
+		if err != nil {
+			return err
+		}
+		col.Type = mapColumnType(mapper, dataType, udtName, charMaxLength, numericPrecision, numericScale)
+
+		if i, ok := byName[schemaName+"."+viewName]; ok {
+			views[i].Columns = append(views[i].Columns, col)
+		}
+	}
+	return rows.Err()
+}
+
+func addViewSources(db *sql.DB, schemaNames, kinds []string, views []schema.View, byName map[string]int) error {
+	rows, err := db.Query(viewSourcesQuery, pq.Array(schemaNames), pq.Array(kinds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var viewSchema, viewName, sourceSchema, sourceTable, column string
+		if err := rows.Scan(&viewSchema, &viewName, &sourceSchema, &sourceTable, &column); err != nil {
+			return err
+		}
+		i, ok := byName[viewSchema+"."+viewName]
+		if !ok {
+			continue
+		}
+
+		// Rows are sorted, so a new source always follows the last one
+		sources := views[i].Sources
+		if n := len(sources); n == 0 || sources[n-1].Schema != sourceSchema || sources[n-1].Table != sourceTable {
+			sources = append(sources, schema.ViewSource{Schema: sourceSchema, Table: sourceTable})
+		}
+		if column != "" {
+			last := &sources[len(sources)-1]
+			last.Columns = append(last.Columns, column)
+		}
+		views[i].Sources = sources
+	}
+	return rows.Err()
+}
@@ -0,0 +1,129 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+)
+
+// DefaultNamingPattern matches lower snake_case identifiers.
+const DefaultNamingPattern = `^[a-z][a-z0-9_]*$`
+
+// Config controls which rules run and which findings are reported.
+//
+// A configuration file is JSON:
+//
+//	{
+//	  "rules": {"varchar-length": "off", "fk-index": "error"},
+//	  "suppress": ["primary-key:public.audit_log", "naming:*.*.legacyID"],
+//	  "naming_pattern": "^[a-z][a-z0-9_]*$"
+//	}
+type Config struct {
+	// Rules overrides rule severities by rule name. Use Off to disable a rule.
+	Rules map[string]Severity `json:"rules"`
+	// Suppress lists "rule:location" patterns. The location is
+	// schema.table or schema.table.column and may use path.Match wildcards;
+	// the rule may be "*".
+	Suppress []string `json:"suppress"`
+	// NamingPattern is the regular expression table and column names must
+	// match for the naming rule.
+	NamingPattern string `json:"naming_pattern"`
+
+	naming *regexp.Regexp
+}
+
+// DefaultConfig returns a configuration running every rule at its default
+// severity.
+func DefaultConfig() *Config {
+	return &Config{NamingPattern: DefaultNamingPattern}
+}
+
+// LoadConfig reads a JSON configuration file. Unset fields keep their
+// defaults.
+func LoadConfig(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint config: %w", err)
+	}
+
+	config := DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse lint config %s: %w", filename, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid lint config %s: %w", filename, err)
+	}
+	return config, nil
+}
+
+// Validate checks that rule names, suppressions, and the naming pattern are
+// well formed.
+func (c *Config) Validate() error {
+	known := make(map[string]bool)
+	for _, rule := range Rules() {
+		known[rule.Name] = true
+	}
+	for name := range c.Rules {
+		if !known[name] {
+			return fmt.Errorf("unknown rule %q", name)
+		}
+	}
+
+	for _, pattern := range c.Suppress {
+		rule, location, ok := splitSuppression(pattern)
+		if !ok {
+			return fmt.Errorf("suppression %q must have the form rule:location", pattern)
+		}
+		if rule != "*" && !known[rule] {
+			return fmt.Errorf("suppression %q names unknown rule %q", pattern, rule)
+		}
+		if _, err := path.Match(location, ""); err != nil {
+			return fmt.Errorf("suppression %q: %w", pattern, err)
+		}
+	}
+
+	if _, err := c.namingPattern(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Config) namingPattern() (*regexp.Regexp, error) {
+	if c.naming != nil {
+		return c.naming, nil
+	}
+	pattern := c.NamingPattern
+	if pattern == "" {
+		pattern = DefaultNamingPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming pattern: %w", err)
+	}
+	c.naming = re
+	return re, nil
+}
+
+func (c *Config) suppressed(f Finding) bool {
+	for _, pattern := range c.Suppress {
+		rule, location, ok := splitSuppression(pattern)
+		if !ok || (rule != "*" && rule != f.Rule) {
+			continue
+		}
+		if matched, _ := path.Match(location, f.Location()); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func splitSuppression(pattern string) (rule, location string, ok bool) {
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == ':' {
+			return pattern[:i], pattern[i+1:], i > 0 && i < len(pattern)-1
+		}
+	}
+	return "", "", false
+}
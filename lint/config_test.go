@@ -0,0 +1,58 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "lint.json")
+	content := `{
+  "rules": {"varchar-length": "off", "fk-index": "error"},
+  "suppress": ["primary-key:public.audit_log"]
+}`
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(filename)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+
+	if config.Rules["varchar-length"] != Off || config.Rules["fk-index"] != Error {
+		t.Errorf("Unexpected rule severities: %v", config.Rules)
+	}
+	if config.NamingPattern != DefaultNamingPattern {
+		t.Errorf("Expected default naming pattern, got %q", config.NamingPattern)
+	}
+	if len(config.Suppress) != 1 {
+		t.Errorf("Expected one suppression, got %v", config.Suppress)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		errMsg string
+	}{
+		{"unknown rule", Config{Rules: map[string]Severity{"nope": Error}}, `unknown rule "nope"`},
+		{"malformed suppression", Config{Suppress: []string{"public.users"}}, "must have the form rule:location"},
+		{"suppression for unknown rule", Config{Suppress: []string{"nope:public.users"}}, `unknown rule "nope"`},
+		{"bad naming pattern", Config{NamingPattern: "("}, "invalid naming pattern"},
+	}
+
+	for _, tt := range tests {
+		err := tt.config.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.errMsg, err)
+		}
+	}
+
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("Expected default config to be valid, got %v", err)
+	}
+}
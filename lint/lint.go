@@ -0,0 +1,172 @@
+// Package lint checks schema definitions against design rules.
+//
+// Each rule inspects a Schema and reports findings with a severity. Rules can
+// be disabled or given a different severity, and individual findings can be
+// suppressed for specific tables or columns, through Config.
+//
+// Basic usage:
+//
+//	findings := lint.Lint(s, lint.DefaultConfig())
+//	for _, f := range findings {
+//	    fmt.Println(f)
+//	}
+//	if lint.HasErrors(findings) {
+//	    os.Exit(1)
+//	}
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Severity indicates how serious a finding is.
+type Severity int
+
+const (
+	// Off disables a rule.
+	Off Severity = iota
+	// Info findings are suggestions.
+	Info
+	// Warning findings should be reviewed.
+	Warning
+	// Error findings fail the lint run.
+	Error
+)
+
+// String returns the lower-case name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case Off:
+		return "off"
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// ParseSeverity parses a severity name such as "warning" or "off".
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "off":
+		return Off, nil
+	case "info":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	default:
+		return Off, fmt.Errorf("unknown severity %q (expected off, info, warning, or error)", name)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// Finding is a single rule violation.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Schema   string   `json:"schema"`
+	Table    string   `json:"table"`
+	Column   string   `json:"column,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Location returns the qualified table or column the finding refers to.
+func (f Finding) Location() string {
+	location := f.Schema + "." + f.Table
+	if f.Column != "" {
+		location += "." + f.Column
+	}
+	return location
+}
+
+// String formats the finding as "severity location: message [rule]".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s %s: %s [%s]", f.Severity, f.Location(), f.Message, f.Rule)
+}
+
+// Rule is a named check over a schema.
+type Rule struct {
+	// Name identifies the rule in configuration and output.
+	Name string
+	// Description explains what the rule checks.
+	Description string
+	// Severity is used when the configuration does not override it.
+	Severity Severity
+	// Check returns the rule's findings. Severity is filled in by Lint.
+	Check func(s *schema.Schema, config *Config) []Finding
+}
+
+// Lint runs every enabled rule against the schema and returns the findings
+// that are not suppressed, sorted by location and rule.
+func Lint(s *schema.Schema, config *Config) []Finding {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	var findings []Finding
+	for _, rule := range Rules() {
+		severity := rule.Severity
+		if override, ok := config.Rules[rule.Name]; ok {
+			severity = override
+		}
+		if severity == Off {
+			continue
+		}
+
+		for _, f := range rule.Check(s, config) {
+			f.Rule = rule.Name
+			f.Severity = severity
+			if !config.suppressed(f) {
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Location() != findings[j].Location() {
+			return findings[i].Location() < findings[j].Location()
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
+
+// HasErrors reports whether any finding has Error severity.
+func HasErrors(findings []Finding) bool {
+	return Max(findings) >= Error
+}
+
+// Max returns the highest severity among findings, or Off if there are none.
+func Max(findings []Finding) Severity {
+	max := Off
+	for _, f := range findings {
+		if f.Severity > max {
+			max = f.Severity
+		}
+	}
+	return max
+}
@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func testSchema() *schema.Schema {
+	return &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:        "users",
+				Schema:      "public",
+				Columns:     []schema.Column{{Name: "id", Type: "int"}, {Name: "displayName", Type: "varchar", DatabaseType: "character varying"}},
+				PrimaryKeys: []string{"id"},
+			},
+			{
+				Name:   "audit_log",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "user_id", Type: "int"},
+					{Name: "actor_id", Type: "int"},
+				},
+				Indexes: []schema.Index{{Name: "idx_audit_log_actor", Columns: []string{"actor_id", "user_id"}}},
+				References: []schema.Reference{
+					{FromTable: "audit_log", FromSchema: "public", FromColumns: []string{"user_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+					{FromTable: "audit_log", FromSchema: "public", FromColumns: []string{"actor_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+		},
+	}
+}
+
+func TestLint(t *testing.T) {
+	findings := Lint(testSchema(), DefaultConfig())
+
+	expected := []string{
+		"error public.audit_log: table has no primary key [primary-key]",
+		"warning public.audit_log.user_id: foreign key (user_id) to public.users has no covering index [fk-index]",
+		"warning public.users.displayName: column name does not match ^[a-z][a-z0-9_]*$ [naming]",
+		"info public.users.displayName: varchar column has no length limit; use text or declare a length [varchar-length]",
+	}
+
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %d: %v", len(expected), len(findings), findings)
+	}
+	for i, e := range expected {
+		if findings[i].String() != e {
+			t.Errorf("Finding %d = %q, want %q", i, findings[i].String(), e)
+		}
+	}
+
+	if !HasErrors(findings) {
+		t.Errorf("Expected HasErrors to be true")
+	}
+}
+
+func TestLintConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.Rules = map[string]Severity{"primary-key": Off, "varchar-length": Error}
+	config.Suppress = []string{"naming:public.users.*", "fk-index:*.audit_log.user_id"}
+
+	findings := Lint(testSchema(), config)
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d: %v", len(findings), findings)
+	}
+	if findings[0].Rule != "varchar-length" || findings[0].Severity != Error {
+		t.Errorf("Expected varchar-length error, got %v", findings[0])
+	}
+}
+
+func TestLintNamingPattern(t *testing.T) {
+	config := DefaultConfig()
+	config.NamingPattern = `^[a-zA-Z_]+$`
+
+	for _, f := range Lint(testSchema(), config) {
+		if f.Rule == "naming" {
+			t.Errorf("Expected no naming findings with a permissive pattern, got %v", f)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Severity
+		wantErr  bool
+	}{
+		{"off", Off, false},
+		{"Info", Info, false},
+		{"warn", Warning, false},
+		{"error", Error, false},
+		{"fatal", Off, true},
+	}
+
+	for _, tt := range tests {
+		result, err := ParseSeverity(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSeverity(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if result != tt.expected {
+			t.Errorf("ParseSeverity(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Rules returns the built-in rules in the order they run.
+func Rules() []Rule {
+	return []Rule{
+		{
+			Name:        "primary-key",
+			Description: "Every table has a primary key",
+			Severity:    Error,
+			Check:       checkPrimaryKey,
+		},
+		{
+			Name:        "fk-index",
+			Description: "Foreign key columns are covered by an index",
+			Severity:    Warning,
+			Check:       checkForeignKeyIndex,
+		},
+		{
+			Name:        "naming",
+			Description: "Table and column names match the naming pattern",
+			Severity:    Warning,
+			Check:       checkNaming,
+		},
+		{
+			Name:        "varchar-length",
+			Description: "varchar columns declare a length",
+			Severity:    Info,
+			Check:       checkVarcharLength,
+		},
+	}
+}
+
+func checkPrimaryKey(s *schema.Schema, _ *Config) []Finding {
+	var findings []Finding
+	for _, table := range s.Tables {
+		if len(table.PrimaryKeys) == 0 {
+			findings = append(findings, Finding{
+				Schema:  table.Schema,
+				Table:   table.Name,
+				Message: "table has no primary key",
+			})
+		}
+	}
+	return findings
+}
+
+// checkForeignKeyIndex reports foreign keys whose columns are not the leading
+// columns of the primary key or of any index, which makes deletes on the
+// referenced table scan the referencing table.
+func checkForeignKeyIndex(s *schema.Schema, _ *Config) []Finding {
+	var findings []Finding
+	for _, table := range s.Tables {
+		for _, ref := range table.References {
+			if covered(ref.FromColumns, table.PrimaryKeys) {
+				continue
+			}
+			indexed := false
+			for _, index := range table.Indexes {
+				if covered(ref.FromColumns, index.Columns) {
+					indexed = true
+					break
+				}
+			}
+			if indexed {
+				continue
+			}
+
+			f := Finding{
+				Schema:  table.Schema,
+				Table:   table.Name,
+				Message: fmt.Sprintf("foreign key (%s) to %s.%s has no covering index", strings.Join(ref.FromColumns, ", "), ref.ToSchema, ref.ToTable),
+			}
+			if len(ref.FromColumns) == 1 {
+				f.Column = ref.FromColumns[0]
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// covered reports whether columns, in any order, form a prefix of keys.
+func covered(columns, keys []string) bool {
+	if len(columns) == 0 || len(keys) < len(columns) {
+		return false
+	}
+	prefix := make(map[string]bool)
+	for _, key := range keys[:len(columns)] {
+		prefix[key] = true
+	}
+	for _, column := range columns {
+		if !prefix[column] {
+			return false
+		}
+	}
+	return true
+}
+
+func checkNaming(s *schema.Schema, config *Config) []Finding {
+	pattern, err := config.namingPattern()
+	if err != nil {
+		return nil
+	}
+
+	var findings []Finding
+	for _, table := range s.Tables {
+		if !pattern.MatchString(table.Name) {
+			findings = append(findings, Finding{
+				Schema:  table.Schema,
+				Table:   table.Name,
+				Message: fmt.Sprintf("table name does not match %s", pattern),
+			})
+		}
+		for _, column := range table.Columns {
+			if !pattern.MatchString(column.Name) {
+				findings = append(findings, Finding{
+					Schema:  table.Schema,
+					Table:   table.Name,
+					Column:  column.Name,
+					Message: fmt.Sprintf("column name does not match %s", pattern),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func checkVarcharLength(s *schema.Schema, _ *Config) []Finding {
+	var findings []Finding
+	for _, table := range s.Tables {
+		for _, column := range table.Columns {
+			typ := strings.ToLower(column.DatabaseType)
+			if typ == "" {
+				typ = strings.ToLower(column.Type)
+			}
+			if typ == "varchar" || typ == "character varying" {
+				findings = append(findings, Finding{
+					Schema:  table.Schema,
+					Table:   table.Name,
+					Column:  column.Name,
+					Message: "varchar column has no length limit; use text or declare a length",
+				})
+			}
+		}
+	}
+	return findings
+}
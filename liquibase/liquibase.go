@@ -0,0 +1,280 @@
+// Package liquibase renders schema definitions as Liquibase changelogs.
+//
+// The changelog recreates the current schema: one changeset per enum type,
+// table, index, and foreign key, in an order that applies cleanly to an
+// empty database. Changelogs can be written as XML or YAML.
+//
+// Basic usage:
+//
+//	output, err := liquibase.GenerateXML(s)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("db.changelog-master.xml", output, 0644)
+package liquibase
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/ddl"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Author is the author recorded on every generated changeset.
+const Author = "dbml"
+
+// GenerateXML converts a Schema into a Liquibase XML changelog.
+func GenerateXML(s *schema.Schema) ([]byte, error) {
+	return renderXML(changeSets(s)), nil
+}
+
+// GenerateYAML converts a Schema into a Liquibase YAML changelog.
+func GenerateYAML(s *schema.Schema) ([]byte, error) {
+	return renderYAML(changeSets(s)), nil
+}
+
+// attr is a change attribute. Typed attributes (booleans and numbers) are
+// written without quotes in YAML.
+type attr struct {
+	key   string
+	value string
+	typed bool
+}
+
+// node is a Liquibase change element such as createTable or column.
+type node struct {
+	name     string
+	attrs    []attr
+	text     string
+	children []node
+}
+
+type changeSet struct {
+	id      string
+	changes []node
+}
+
+func changeSets(s *schema.Schema) []changeSet {
+	tables := make([]schema.Table, len(s.Tables))
+	copy(tables, s.Tables)
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Schema != tables[j].Schema {
+			return tables[i].Schema < tables[j].Schema
+		}
+		return tables[i].Name < tables[j].Name
+	})
+
+	enums := make([]schema.Enum, len(s.Enums))
+	copy(enums, s.Enums)
+	sort.Slice(enums, func(i, j int) bool {
+		if enums[i].Schema != enums[j].Schema {
+			return enums[i].Schema < enums[j].Schema
+		}
+		return enums[i].Name < enums[j].Name
+	})
+
+	var sets []changeSet
+
+	// Liquibase has no enum change type, so enums are created with raw SQL.
+	for _, enum := range enums {
+		values := make([]string, len(enum.Values))
+		for i, value := range enum.Values {
+			values[i] = ddl.QuoteLiteral(value)
+		}
+		sets = append(sets, changeSet{
+			id: "create-type-" + qualifiedID(enum.Name, enum.Schema),
+			changes: []node{{
+				name: "sql",
+				text: fmt.Sprintf("CREATE TYPE %s AS ENUM (%s)", ddl.QualifiedName(enum.Name, enum.Schema), strings.Join(values, ", ")),
+			}},
+		})
+	}
+
+	for _, table := range tables {
+		sets = append(sets, changeSet{
+			id:      "create-table-" + qualifiedID(table.Name, table.Schema),
+			changes: []node{createTable(table)},
+		})
+	}
+
+	for _, table := range tables {
+		indexes := make([]schema.Index, len(table.Indexes))
+		copy(indexes, table.Indexes)
+		sort.Slice(indexes, func(i, j int) bool {
+			return indexes[i].Name < indexes[j].Name
+		})
+		for _, index := range indexes {
+			if index.UniqueConstraint {
+				sets = append(sets, changeSet{
+					id:      "add-unique-constraint-" + qualifiedID(index.Name, table.Schema),
+					changes: []node{addUniqueConstraint(table, index)},
+				})
+				continue
+			}
+			sets = append(sets, changeSet{
+				id:      "create-index-" + qualifiedID(index.Name, table.Schema),
+				changes: []node{createIndex(table, index)},
+			})
+		}
+	}
+
+	for _, table := range tables {
+		for _, ref := range table.References {
+			name := foreignKeyName(ref)
+			sets = append(sets, changeSet{
+				id:      "add-foreign-key-" + qualifiedID(name, ref.FromSchema),
+				changes: []node{addForeignKey(ref, name)},
+			})
+		}
+	}
+
+	return sets
+}
+
+func qualifiedID(name, schemaName string) string {
+	if schemaName != "" && schemaName != "public" {
+		return schemaName + "." + name
+	}
+	return name
+}
+
+func schemaAttr(key, schemaName string) []attr {
+	if schemaName == "" {
+		return nil
+	}
+	return []attr{{key: key, value: schemaName}}
+}
+
+func createTable(table schema.Table) node {
+	n := node{
+		name:  "createTable",
+		attrs: append(schemaAttr("schemaName", table.Schema), attr{key: "tableName", value: table.Name}),
+	}
+
+	primaryKeys := make(map[string]bool)
+	for _, name := range table.PrimaryKeys {
+		primaryKeys[name] = true
+	}
+
+	for _, column := range table.Columns {
+		n.children = append(n.children, columnNode(column, primaryKeys[column.Name] || column.IsPrimaryKey))
+	}
+	return n
+}
+
+func columnNode(column schema.Column, primaryKey bool) node {
+	typ := column.DatabaseType
+	if typ == "" {
+		typ = ddl.PostgresType(column.Type)
+	}
+
+	n := node{
+		name:  "column",
+		attrs: []attr{{key: "name", value: column.Name}, {key: "type", value: typ}},
+	}
+
+	if column.AutoIncrement {
+		n.attrs = append(n.attrs, attr{key: "autoIncrement", value: "true", typed: true})
+	} else if column.DefaultValue != nil {
+		n.attrs = append(n.attrs, defaultAttr(*column.DefaultValue))
+	}
+
+	var constraints []attr
+	if primaryKey {
+		constraints = append(constraints, attr{key: "primaryKey", value: "true", typed: true})
+	}
+	if !column.Nullable || primaryKey {
+		constraints = append(constraints, attr{key: "nullable", value: "false", typed: true})
+	}
+	if len(constraints) > 0 {
+		n.children = append(n.children, node{name: "constraints", attrs: constraints})
+	}
+	return n
+}
+
+var (
+	numberLiteral = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	stringLiteral = regexp.MustCompile(`^'((?:[^']|'')*)'(::[\w ]+)?$`)
+)
+
+// defaultAttr picks the Liquibase default attribute matching the kind of
+// default expression.
+func defaultAttr(value string) attr {
+	lower := strings.ToLower(value)
+	switch {
+	case lower == "true" || lower == "false":
+		return attr{key: "defaultValueBoolean", value: lower, typed: true}
+	case numberLiteral.MatchString(value):
+		return attr{key: "defaultValueNumeric", value: value, typed: true}
+	}
+	if m := stringLiteral.FindStringSubmatch(value); m != nil {
+		return attr{key: "defaultValue", value: strings.ReplaceAll(m[1], "''", "'")}
+	}
+	return attr{key: "defaultValueComputed", value: value}
+}
+
+func createIndex(table schema.Table, index schema.Index) node {
+	n := node{
+		name: "createIndex",
+		attrs: append(schemaAttr("schemaName", table.Schema),
+			attr{key: "tableName", value: table.Name},
+			attr{key: "indexName", value: index.Name}),
+	}
+	if index.Unique {
+		n.attrs = append(n.attrs, attr{key: "unique", value: "true", typed: true})
+	}
+
+	for _, column := range index.Columns {
+		c := node{name: "column", attrs: []attr{{key: "name", value: strings.Trim(column, "`")}}}
+		if strings.HasPrefix(column, "`") {
+			c.attrs = append(c.attrs, attr{key: "computed", value: "true", typed: true})
+		}
+		n.children = append(n.children, c)
+	}
+	return n
+}
+
+func addUniqueConstraint(table schema.Table, index schema.Index) node {
+	return node{
+		name: "addUniqueConstraint",
+		attrs: append(schemaAttr("schemaName", table.Schema),
+			attr{key: "tableName", value: table.Name},
+			attr{key: "columnNames", value: strings.Join(index.Columns, ",")},
+			attr{key: "constraintName", value: index.Name}),
+	}
+}
+
+func addForeignKey(ref schema.Reference, name string) node {
+	var attrs []attr
+	attrs = append(attrs, schemaAttr("baseTableSchemaName", ref.FromSchema)...)
+	attrs = append(attrs,
+		attr{key: "baseTableName", value: ref.FromTable},
+		attr{key: "baseColumnNames", value: strings.Join(ref.FromColumns, ",")},
+		attr{key: "constraintName", value: name})
+	attrs = append(attrs, schemaAttr("referencedTableSchemaName", ref.ToSchema)...)
+	attrs = append(attrs,
+		attr{key: "referencedTableName", value: ref.ToTable},
+		attr{key: "referencedColumnNames", value: strings.Join(ref.ToColumns, ",")})
+	if ref.OnDelete != "" && ref.OnDelete != "NO ACTION" {
+		attrs = append(attrs, attr{key: "onDelete", value: ref.OnDelete})
+	}
+	if ref.OnUpdate != "" && ref.OnUpdate != "NO ACTION" {
+		attrs = append(attrs, attr{key: "onUpdate", value: ref.OnUpdate})
+	}
+	if ref.Deferrable {
+		attrs = append(attrs, attr{key: "deferrable", value: "true"})
+		if ref.InitiallyDeferred {
+			attrs = append(attrs, attr{key: "initiallyDeferred", value: "true"})
+		}
+	}
+	return node{name: "addForeignKeyConstraint", attrs: attrs}
+}
+
+// foreignKeyName returns PostgreSQL's default constraint name for a foreign
+// key, since introspection does not record constraint names.
+func foreignKeyName(ref schema.Reference) string {
+	return fmt.Sprintf("%s_%s_fkey", ref.FromTable, strings.Join(ref.FromColumns, "_"))
+}
@@ -0,0 +1,141 @@
+package liquibase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func testSchema() *schema.Schema {
+	seq := "nextval('users_id_seq'::regclass)"
+	now := "now()"
+	active := "'active'::character varying"
+	return &schema.Schema{
+		Enums: []schema.Enum{{Name: "role", Schema: "public", Values: []string{"admin", "member"}}},
+		Tables: []schema.Table{
+			{
+				Name:   "users",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "id", Type: "int", IsPrimaryKey: true, DefaultValue: &seq, AutoIncrement: true, DatabaseType: "integer"},
+					{Name: "email", Type: "varchar(255)", DatabaseType: "character varying(255)"},
+					{Name: "status", Type: "varchar(20)", Nullable: true, DefaultValue: &active},
+					{Name: "created_at", Type: "timestamp", DefaultValue: &now},
+				},
+				PrimaryKeys: []string{"id"},
+				Indexes:     []schema.Index{{Name: "users_email_key", Columns: []string{"email"}, Unique: true}},
+			},
+			{
+				Name:   "posts",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "id", Type: "bigint", IsPrimaryKey: true},
+					{Name: "author_id", Type: "int"},
+				},
+				PrimaryKeys: []string{"id"},
+				References: []schema.Reference{
+					{FromTable: "posts", FromSchema: "public", FromColumns: []string{"author_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}, OnDelete: "CASCADE"},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateXML(t *testing.T) {
+	output, err := GenerateXML(testSchema())
+	if err != nil {
+		t.Fatalf("GenerateXML returned error: %v", err)
+	}
+	result := string(output)
+
+	expected := []string{
+		`<changeSet id="create-type-role" author="dbml">`,
+		`<sql>CREATE TYPE role AS ENUM (&#39;admin&#39;, &#39;member&#39;)</sql>`,
+		`<createTable schemaName="public" tableName="users">`,
+		`<column name="id" type="integer" autoIncrement="true">`,
+		`<constraints primaryKey="true" nullable="false"/>`,
+		`<column name="email" type="character varying(255)">`,
+		`<column name="status" type="varchar(20)" defaultValue="active"/>`,
+		`<column name="created_at" type="timestamp" defaultValueComputed="now()">`,
+		`<createIndex schemaName="public" tableName="users" indexName="users_email_key" unique="true">`,
+		`<addForeignKeyConstraint baseTableSchemaName="public" baseTableName="posts" baseColumnNames="author_id" constraintName="posts_author_id_fkey" referencedTableSchemaName="public" referencedTableName="users" referencedColumnNames="id" onDelete="CASCADE"/>`,
+		`</databaseChangeLog>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(result, e) {
+			t.Errorf("Expected output to contain %s, got:\n%s", e, result)
+		}
+	}
+
+	// Types, tables, indexes, then foreign keys
+	order := []string{"create-type-role", "create-table-posts", "create-table-users", "create-index-users_email_key", "add-foreign-key-posts_author_id_fkey"}
+	last := -1
+	for _, id := range order {
+		pos := strings.Index(result, `id="`+id+`"`)
+		if pos <= last {
+			t.Errorf("Expected changeset %s after previous changesets", id)
+		}
+		last = pos
+	}
+}
+
+func TestGenerateUniqueConstraint(t *testing.T) {
+	s := testSchema()
+	s.Tables[0].Indexes[0].UniqueConstraint = true
+
+	output, err := GenerateXML(s)
+	if err != nil {
+		t.Fatalf("GenerateXML returned error: %v", err)
+	}
+	result := string(output)
+
+	expected := `<addUniqueConstraint schemaName="public" tableName="users" columnNames="email" constraintName="users_email_key"/>`
+	if !strings.Contains(result, expected) {
+		t.Errorf("Expected output to contain %s, got:\n%s", expected, result)
+	}
+	if strings.Contains(result, "<createIndex") {
+		t.Errorf("Expected no createIndex for a UNIQUE constraint, got:\n%s", result)
+	}
+}
+
+func TestGenerateYAML(t *testing.T) {
+	output, err := GenerateYAML(testSchema())
+	if err != nil {
+		t.Fatalf("GenerateYAML returned error: %v", err)
+	}
+	result := string(output)
+
+	expected := []string{
+		"databaseChangeLog:\n  - changeSet:\n      id: create-type-role\n      author: dbml\n      changes:\n        - sql:\n            sql: \"CREATE TYPE role AS ENUM ('admin', 'member')\"\n",
+		"        - createTable:\n            schemaName: public\n            tableName: users\n            columns:\n              - column:\n                  name: id\n                  type: integer\n                  autoIncrement: true\n                  constraints:\n                    primaryKey: true\n                    nullable: false\n",
+		"                  name: status\n                  type: varchar(20)\n                  defaultValue: active\n",
+		"                  defaultValueComputed: now()\n",
+		"            indexName: users_email_key\n            unique: true\n            columns:\n              - column:\n                  name: email\n",
+		"            onDelete: CASCADE\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(result, e) {
+			t.Errorf("Expected output to contain:\n%s\ngot:\n%s", e, result)
+		}
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"users", "users"},
+		{"character varying(255)", "character varying(255)"},
+		{"true", `"true"`},
+		{"123", `"123"`},
+		{"a: b", `"a: b"`},
+		{"it's <fine>", `"it's <fine>"`},
+	}
+
+	for _, tt := range tests {
+		if result := yamlString(tt.input); result != tt.expected {
+			t.Errorf("yamlString(%q) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package liquibase
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<databaseChangeLog
+    xmlns="http://www.liquibase.org/xml/ns/dbchangelog"
+    xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+    xsi:schemaLocation="http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-latest.xsd">
+`
+
+func renderXML(sets []changeSet) []byte {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+
+	for _, set := range sets {
+		fmt.Fprintf(&b, "\n    <changeSet id=\"%s\" author=\"%s\">\n", escapeXML(set.id), Author)
+		for _, change := range set.changes {
+			writeXMLNode(&b, change, "        ")
+		}
+		b.WriteString("    </changeSet>\n")
+	}
+
+	b.WriteString("\n</databaseChangeLog>\n")
+	return []byte(b.String())
+}
+
+func writeXMLNode(b *strings.Builder, n node, indent string) {
+	b.WriteString(indent + "<" + n.name)
+	for _, a := range n.attrs {
+		fmt.Fprintf(b, " %s=\"%s\"", a.key, escapeXML(a.value))
+	}
+
+	switch {
+	case n.text != "":
+		fmt.Fprintf(b, ">%s</%s>\n", escapeXML(n.text), n.name)
+	case len(n.children) > 0:
+		b.WriteString(">\n")
+		for _, child := range n.children {
+			writeXMLNode(b, child, indent+"    ")
+		}
+		fmt.Fprintf(b, "%s</%s>\n", indent, n.name)
+	default:
+		b.WriteString("/>\n")
+	}
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func renderYAML(sets []changeSet) []byte {
+	var b strings.Builder
+	b.WriteString("databaseChangeLog:\n")
+
+	for _, set := range sets {
+		b.WriteString("  - changeSet:\n")
+		fmt.Fprintf(&b, "      id: %s\n", yamlString(set.id))
+		fmt.Fprintf(&b, "      author: %s\n", Author)
+		b.WriteString("      changes:\n")
+		for _, change := range set.changes {
+			fmt.Fprintf(&b, "        - %s:\n", change.name)
+			writeYAMLBody(&b, change, "            ")
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// writeYAMLBody writes a node's attributes and children as a YAML mapping.
+// Repeated column children are grouped under a "columns" list, as in
+// Liquibase's YAML format.
+func writeYAMLBody(b *strings.Builder, n node, indent string) {
+	for _, a := range n.attrs {
+		value := yamlString(a.value)
+		if a.typed {
+			value = a.value
+		}
+		fmt.Fprintf(b, "%s%s: %s\n", indent, a.key, value)
+	}
+
+	if n.text != "" {
+		fmt.Fprintf(b, "%s%s: %s\n", indent, n.name, yamlString(n.text))
+	}
+
+	wroteColumns := false
+	for _, child := range n.children {
+		if child.name == "column" {
+			if !wroteColumns {
+				fmt.Fprintf(b, "%scolumns:\n", indent)
+				wroteColumns = true
+			}
+			fmt.Fprintf(b, "%s  - column:\n", indent)
+			writeYAMLBody(b, child, indent+"      ")
+			continue
+		}
+		fmt.Fprintf(b, "%s%s:\n", indent, child.name)
+		writeYAMLBody(b, child, indent+"  ")
+	}
+}
+
+var plainScalar = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.()\- ]*$`)
+
+// yamlString returns s as a YAML scalar, quoting it when a plain scalar
+// would be ambiguous.
+func yamlString(s string) string {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+	default:
+		if plainScalar.MatchString(s) && !strings.HasSuffix(s, " ") {
+			return s
+		}
+	}
+	// JSON strings are valid double-quoted YAML scalars.
+	var quoted strings.Builder
+	encoder := json.NewEncoder(&quoted)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(quoted.String(), "\n")
+}
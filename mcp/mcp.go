@@ -0,0 +1,163 @@
+// Package mcp implements a Model Context Protocol server that lets AI
+// assistants query database structure through live introspection.
+//
+// The server speaks JSON-RPC 2.0 over newline-delimited stdio, as described by
+// the MCP stdio transport. It exposes the following tools:
+//
+//   - list_tables         - names of all introspected tables
+//   - get_schema          - the full schema rendered as DBML
+//   - get_table           - columns, keys, and indexes of a single table
+//   - find_relationships  - foreign keys from and to a table
+//
+// Basic usage:
+//
+//	srv := mcp.New(db, mcp.WithIntrospectOptions(introspect.WithAllSchemas()))
+//	if err := srv.Serve(os.Stdin, os.Stdout); err != nil {
+//	    log.Fatal(err)
+//	}
+package mcp
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/lucasefe/dbml/introspect"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// ProtocolVersion is the MCP protocol revision implemented by the server.
+const ProtocolVersion = "2024-11-05"
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithIntrospectOptions sets the options used for every introspection.
+func WithIntrospectOptions(opts ...introspect.Option) Option {
+	return func(s *Server) {
+		s.introspectOptions = opts
+	}
+}
+
+// WithServerInfo overrides the name and version reported to clients.
+func WithServerInfo(name, version string) Option {
+	return func(s *Server) {
+		s.name = name
+		s.version = version
+	}
+}
+
+// Server answers MCP requests using a live database connection.
+type Server struct {
+	introspectOptions []introspect.Option
+	name              string
+	version           string
+
+	// load returns the current schema. It introspects the database
+	// unless replaced in tests.
+	load func() (*schema.Schema, error)
+}
+
+// New creates a Server that introspects db on every tool call.
+func New(db *sql.DB, opts ...Option) *Server {
+	s := &Server{name: "dbml", version: "1.0.0"}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.load = func() (*schema.Schema, error) {
+		return introspect.Database(db, s.introspectOptions...)
+	}
+	return s
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Serve reads requests from r and writes responses to w until r is exhausted.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := encoder.Encode(response{
+				JSONRPC: "2.0",
+				ID:      json.RawMessage("null"),
+				Error:   &rpcError{Code: codeParseError, Message: err.Error()},
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Notifications carry no ID and never receive a response.
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		result, rpcErr := s.handle(req)
+		if err := encoder.Encode(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (s *Server) handle(req request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": toolDefinitions}, nil
+	case "tools/call":
+		var params struct {
+			Name      string            `json:"name"`
+			Arguments map[string]string `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return s.callTool(params.Name, params.Arguments)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func newTestServer() *Server {
+	s := New(nil)
+	s.load = func() (*schema.Schema, error) {
+		return &schema.Schema{
+			Tables: []schema.Table{
+				{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}},
+				{
+					Name:    "posts",
+					Schema:  "public",
+					Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}, {Name: "user_id", Type: "int"}},
+					References: []schema.Reference{
+						{FromTable: "posts", FromSchema: "public", FromColumns: []string{"user_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+					},
+				},
+			},
+		}, nil
+	}
+	return s
+}
+
+func roundTrip(t *testing.T, s *Server, requests ...string) []response {
+	t.Helper()
+
+	var out bytes.Buffer
+	if err := s.Serve(strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatalf("Serve returned error: %v", err)
+	}
+
+	var responses []response
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp response
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func resultText(t *testing.T, resp response) string {
+	t.Helper()
+
+	data, _ := json.Marshal(resp.Result)
+	var result toolResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Failed to decode tool result: %v", err)
+	}
+	if len(result.Content) == 0 {
+		t.Fatalf("Tool result has no content")
+	}
+	return result.Content[0].Text
+}
+
+func TestInitializeAndNotifications(t *testing.T) {
+	responses := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+
+	if len(responses) != 2 {
+		t.Fatalf("Expected 2 responses (notifications get none), got %d", len(responses))
+	}
+	if responses[0].Error != nil {
+		t.Errorf("initialize returned error: %v", responses[0].Error)
+	}
+	if !strings.Contains(string(mustMarshal(responses[1].Result)), "find_relationships") {
+		t.Errorf("tools/list missing find_relationships tool")
+	}
+}
+
+func TestGetSchemaTool(t *testing.T) {
+	responses := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_schema"}}`,
+	)
+
+	if text := resultText(t, responses[0]); !strings.Contains(text, "Ref: posts.user_id > users.id") {
+		t.Errorf("get_schema output missing reference: %s", text)
+	}
+}
+
+func TestFindRelationshipsTool(t *testing.T) {
+	responses := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"find_relationships","arguments":{"table":"users"}}}`,
+	)
+
+	var relationships map[string][]schema.Reference
+	if err := json.Unmarshal([]byte(resultText(t, responses[0])), &relationships); err != nil {
+		t.Fatalf("Failed to decode relationships: %v", err)
+	}
+	if len(relationships["referenced_by"]) != 1 || relationships["referenced_by"][0].FromTable != "posts" {
+		t.Errorf("Expected users to be referenced by posts, got %+v", relationships)
+	}
+}
+
+func TestUnknownTable(t *testing.T) {
+	responses := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_table","arguments":{"table":"missing"}}}`,
+	)
+
+	if text := resultText(t, responses[0]); !strings.Contains(text, "table not found") {
+		t.Errorf("Expected table not found error, got %s", text)
+	}
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/schema"
+)
+
+type tool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	InputSchema inputSchema `json:"inputSchema"`
+}
+
+type inputSchema struct {
+	Type       string              `json:"type"`
+	Properties map[string]property `json:"properties"`
+	Required   []string            `json:"required,omitempty"`
+}
+
+type property struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+var tableArgument = map[string]property{
+	"table": {Type: "string", Description: `Table name, optionally schema-qualified (e.g. "users" or "auth.users")`},
+}
+
+var toolDefinitions = []tool{
+	{
+		Name:        "list_tables",
+		Description: "List the schema-qualified names of all tables in the database.",
+		InputSchema: inputSchema{Type: "object", Properties: map[string]property{}},
+	},
+	{
+		Name:        "get_schema",
+		Description: "Return the complete database schema as DBML, including tables, columns, indexes, and references.",
+		InputSchema: inputSchema{Type: "object", Properties: map[string]property{}},
+	},
+	{
+		Name:        "get_table",
+		Description: "Return the columns, primary keys, indexes, and foreign keys of a single table as JSON.",
+		InputSchema: inputSchema{Type: "object", Properties: tableArgument, Required: []string{"table"}},
+	},
+	{
+		Name:        "find_relationships",
+		Description: "Return the foreign keys pointing from a table to other tables and from other tables to it.",
+		InputSchema: inputSchema{Type: "object", Properties: tableArgument, Required: []string{"table"}},
+	},
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+func textResult(text string) toolResult {
+	return toolResult{Content: []content{{Type: "text", Text: text}}}
+}
+
+func errorResult(err error) toolResult {
+	return toolResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true}
+}
+
+func jsonResult(v interface{}) toolResult {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(string(data))
+}
+
+// callTool runs a tool. Failures are reported inside the result so the
+// assistant can see them, as the MCP specification recommends.
+func (s *Server) callTool(name string, args map[string]string) (interface{}, *rpcError) {
+	var run func(*schema.Schema, map[string]string) (toolResult, error)
+	switch name {
+	case "list_tables":
+		run = listTables
+	case "get_schema":
+		run = getSchema
+	case "get_table":
+		run = getTable
+	case "find_relationships":
+		run = findRelationships
+	default:
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", name)}
+	}
+
+	current, err := s.load()
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to introspect database: %w", err)), nil
+	}
+
+	result, err := run(current, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	return result, nil
+}
+
+func listTables(s *schema.Schema, _ map[string]string) (toolResult, error) {
+	names := make([]string, 0, len(s.Tables))
+	for _, table := range s.Tables {
+		names = append(names, table.Schema+"."+table.Name)
+	}
+	sort.Strings(names)
+	return textResult(strings.Join(names, "\n")), nil
+}
+
+func getSchema(s *schema.Schema, _ map[string]string) (toolResult, error) {
+	output, err := generator.Generate(s)
+	if err != nil {
+		return toolResult{}, err
+	}
+	return textResult(string(output)), nil
+}
+
+func getTable(s *schema.Schema, args map[string]string) (toolResult, error) {
+	table, err := findTable(s, args["table"])
+	if err != nil {
+		return toolResult{}, err
+	}
+	return jsonResult(table), nil
+}
+
+func findRelationships(s *schema.Schema, args map[string]string) (toolResult, error) {
+	table, err := findTable(s, args["table"])
+	if err != nil {
+		return toolResult{}, err
+	}
+
+	outgoing := table.References
+	if outgoing == nil {
+		outgoing = []schema.Reference{}
+	}
+	incoming := []schema.Reference{}
+	for _, other := range s.Tables {
+		for _, ref := range other.References {
+			if ref.ToTable == table.Name && ref.ToSchema == table.Schema {
+				incoming = append(incoming, ref)
+			}
+		}
+	}
+
+	return jsonResult(map[string][]schema.Reference{
+		"references":    outgoing,
+		"referenced_by": incoming,
+	}), nil
+}
+
+// findTable looks up a table by "name" or "schema.name". Unqualified names
+// must be unambiguous across schemas.
+func findTable(s *schema.Schema, name string) (*schema.Table, error) {
+	if name == "" {
+		return nil, fmt.Errorf("missing required argument: table")
+	}
+
+	schemaName, tableName := "", name
+	if i := strings.Index(name, "."); i >= 0 {
+		schemaName, tableName = name[:i], name[i+1:]
+	}
+
+	var matches []*schema.Table
+	for i := range s.Tables {
+		table := &s.Tables[i]
+		if table.Name == tableName && (schemaName == "" || table.Schema == schemaName) {
+			matches = append(matches, table)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("table not found: %s", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("table name %s is ambiguous; qualify it with a schema", name)
+	}
+}
@@ -0,0 +1,133 @@
+// Package notify tells a team when a database schema changes.
+//
+// A Notifier delivers an Event describing a change. NewWebhook posts the event
+// as JSON to any HTTP endpoint; NewSlack posts a readable message to a Slack
+// incoming webhook.
+//
+// Basic usage:
+//
+//	event := notify.NewEvent(before, after)
+//	if err := notify.NewSlack(url).Notify(ctx, event); err != nil {
+//	    log.Print(err)
+//	}
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lucasefe/dbml/diff"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Event describes a schema change.
+type Event struct {
+	// Database identifies the watched database, e.g. its host and name.
+	Database string `json:"database,omitempty"`
+	// PreviousFingerprint and Fingerprint identify the schema before and
+	// after the change.
+	PreviousFingerprint string `json:"previous_fingerprint"`
+	Fingerprint         string `json:"fingerprint"`
+	// DetectedAt is when the change was noticed.
+	DetectedAt time.Time `json:"detected_at"`
+	// Changes summarizes what changed.
+	Changes *diff.Summary `json:"changes"`
+}
+
+// NewEvent describes the change from old to new, detected now.
+func NewEvent(old, new *schema.Schema) Event {
+	return Event{
+		PreviousFingerprint: old.Fingerprint(),
+		Fingerprint:         new.Fingerprint(),
+		DetectedAt:          time.Now().UTC(),
+		Changes:             diff.Compare(old, new),
+	}
+}
+
+// Notifier delivers events.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Option configures a webhook notifier.
+type Option func(*options)
+
+type options struct {
+	client *http.Client
+}
+
+// WithHTTPClient sets the client used to deliver notifications. The default
+// client times out after 10 seconds.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{client: &http.Client{Timeout: 10 * time.Second}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook returns a Notifier that POSTs each event as JSON to url.
+func NewWebhook(url string, opts ...Option) Notifier {
+	return &webhook{url: url, client: newOptions(opts).client}
+}
+
+func (w *webhook) Notify(ctx context.Context, event Event) error {
+	return post(ctx, w.client, w.url, event)
+}
+
+type slack struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlack returns a Notifier that posts each event as a message to a Slack
+// incoming webhook URL.
+func NewSlack(url string, opts ...Option) Notifier {
+	return &slack{url: url, client: newOptions(opts).client}
+}
+
+func (s *slack) Notify(ctx context.Context, event Event) error {
+	return post(ctx, s.client, s.url, map[string]string{"text": SlackMessage(event)})
+}
+
+// post sends payload as JSON and treats any non-2xx response as an error.
+func post(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification rejected with status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func testEvent() Event {
+	old := &schema.Schema{Tables: []schema.Table{{Name: "users", Schema: "public"}}}
+	new := &schema.Schema{Tables: []schema.Table{
+		{Name: "users", Schema: "public"},
+		{Name: "orders", Schema: "public"},
+	}}
+	event := NewEvent(old, new)
+	event.Database = "localhost/app"
+	return event
+}
+
+func TestNewEvent(t *testing.T) {
+	event := testEvent()
+
+	if event.PreviousFingerprint == event.Fingerprint {
+		t.Error("Expected fingerprints to differ")
+	}
+	if len(event.Changes.AddedTables) != 1 || event.Changes.AddedTables[0] != "orders" {
+		t.Errorf("Unexpected changes: %+v", event.Changes)
+	}
+	if event.DetectedAt.IsZero() {
+		t.Error("Expected DetectedAt to be set")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var received Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected JSON content type, got %s", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	event := testEvent()
+	if err := NewWebhook(srv.URL).Notify(context.Background(), event); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+
+	if received.Fingerprint != event.Fingerprint || received.Database != "localhost/app" {
+		t.Errorf("Unexpected payload: %+v", received)
+	}
+	if received.Changes == nil || len(received.Changes.AddedTables) != 1 {
+		t.Errorf("Expected changes in payload, got %+v", received.Changes)
+	}
+}
+
+func TestWebhookError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such hook", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	err := NewWebhook(srv.URL).Notify(context.Background(), testEvent())
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "no such hook") {
+		t.Errorf("Expected 404 error with body, got %v", err)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	var payload map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	if err := NewSlack(srv.URL).Notify(context.Background(), testEvent()); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+
+	if !strings.Contains(payload["text"], "+ table orders") {
+		t.Errorf("Expected change list in Slack text, got %q", payload["text"])
+	}
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSlackLines caps the change list so large migrations stay readable.
+const maxSlackLines = 30
+
+// SlackMessage formats an event as Slack mrkdwn text.
+func SlackMessage(event Event) string {
+	var b strings.Builder
+
+	b.WriteString(":warning: *Database schema changed*")
+	if event.Database != "" {
+		fmt.Fprintf(&b, " on `%s`", event.Database)
+	}
+	b.WriteString("\n")
+
+	lines := strings.Split(strings.TrimSuffix(event.Changes.String(), "\n"), "\n")
+	if event.Changes.Empty() {
+		lines = []string{"Comments or other details changed."}
+	}
+	omitted := 0
+	if len(lines) > maxSlackLines {
+		omitted = len(lines) - maxSlackLines
+		lines = lines[:maxSlackLines]
+	}
+
+	b.WriteString("```\n")
+	b.WriteString(escape(strings.Join(lines, "\n")))
+	b.WriteString("\n```")
+	if omitted > 0 {
+		fmt.Fprintf(&b, "\n…and %d more changes", omitted)
+	}
+	fmt.Fprintf(&b, "\nFingerprint `%s` → `%s`", short(event.PreviousFingerprint), short(event.Fingerprint))
+	return b.String()
+}
+
+// escape replaces the characters Slack treats as control sequences.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func short(fingerprint string) string {
+	if len(fingerprint) > 12 {
+		return fingerprint[:12]
+	}
+	return fingerprint
+}
@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestSlackMessage(t *testing.T) {
+	message := SlackMessage(testEvent())
+
+	expected := []string{
+		"*Database schema changed* on `localhost/app`",
+		"```\n+ table orders\n```",
+		"Fingerprint `",
+	}
+	for _, e := range expected {
+		if !strings.Contains(message, e) {
+			t.Errorf("Expected message to contain %q, got:\n%s", e, message)
+		}
+	}
+}
+
+func TestSlackMessageTruncates(t *testing.T) {
+	new := &schema.Schema{}
+	for i := 0; i < maxSlackLines+5; i++ {
+		new.Tables = append(new.Tables, schema.Table{Name: fmt.Sprintf("t%02d", i), Schema: "public"})
+	}
+
+	message := SlackMessage(NewEvent(&schema.Schema{}, new))
+
+	if !strings.Contains(message, "and 5 more changes") {
+		t.Errorf("Expected truncation notice, got:\n%s", message)
+	}
+	if strings.Contains(message, fmt.Sprintf("t%02d", maxSlackLines)) {
+		t.Error("Expected lines past the limit to be omitted")
+	}
+}
+
+func TestSlackMessageEscapes(t *testing.T) {
+	old := &schema.Schema{Tables: []schema.Table{{Name: "a", Schema: "public", Columns: []schema.Column{{Name: "c", Type: "int"}}}}}
+	new := &schema.Schema{Tables: []schema.Table{{Name: "a", Schema: "public", Columns: []schema.Column{{Name: "c", Type: "bigint"}}}}}
+
+	message := SlackMessage(NewEvent(old, new))
+
+	if !strings.Contains(message, "int -&gt; bigint") {
+		t.Errorf("Expected > to be escaped, got:\n%s", message)
+	}
+}
+
+func TestSlackMessageWithoutStructuralChanges(t *testing.T) {
+	old := &schema.Schema{Tables: []schema.Table{{Name: "a", Schema: "public"}}}
+	new := &schema.Schema{Tables: []schema.Table{{Name: "a", Schema: "public", Comment: "now documented"}}}
+
+	message := SlackMessage(NewEvent(old, new))
+
+	if !strings.Contains(message, "Comments or other details changed.") {
+		t.Errorf("Expected fallback line, got:\n%s", message)
+	}
+}
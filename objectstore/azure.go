@@ -0,0 +1,42 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type azureStore struct {
+	endpoint  string
+	container string
+	prefix    string
+	sas       string
+	client    *http.Client
+}
+
+func newAzure(account, container, prefix string, o *options) (*azureStore, error) {
+	sas := strings.TrimPrefix(o.getenv("AZURE_STORAGE_SAS_TOKEN"), "?")
+	if sas == "" {
+		return nil, fmt.Errorf("Azure Blob uploads need AZURE_STORAGE_SAS_TOKEN")
+	}
+	return &azureStore{
+		endpoint:  "https://" + account + ".blob.core.windows.net",
+		container: container,
+		prefix:    prefix,
+		sas:       sas,
+		client:    o.client,
+	}, nil
+}
+
+func (a *azureStore) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	blobURL := a.endpoint + "/" + a.container + "/" + escapePath(objectKey(a.prefix, key)) + "?" + a.sas
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, blobURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Version", "2021-08-06")
+	return send(a.client, req, content)
+}
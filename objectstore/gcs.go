@@ -0,0 +1,80 @@
+package objectstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// metadataTokenURL serves access tokens for the attached service account on
+// Google Cloud, including GKE with Workload Identity.
+const metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+type gcsStore struct {
+	bucket   string
+	prefix   string
+	token    string
+	endpoint string
+	tokenURL string
+	client   *http.Client
+}
+
+func newGCS(bucket, prefix string, o *options) *gcsStore {
+	return &gcsStore{
+		bucket:   bucket,
+		prefix:   prefix,
+		token:    o.getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+		endpoint: "https://storage.googleapis.com",
+		tokenURL: metadataTokenURL,
+		client:   o.client,
+	}
+}
+
+func (g *gcsStore) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	objectURL := g.endpoint + "/" + g.bucket + "/" + escapePath(objectKey(g.prefix, key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType)
+	return send(g.client, req, content)
+}
+
+// accessToken returns the configured token, or fetches one from the metadata
+// server. Tokens from the metadata server are cached there, so they are
+// requested for every upload rather than tracked here.
+func (g *gcsStore) accessToken(ctx context.Context) (string, error) {
+	if g.token != "" {
+		return g.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Google Cloud access token (set GOOGLE_OAUTH_ACCESS_TOKEN outside Google Cloud): %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get Google Cloud access token: metadata server returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode Google Cloud access token: %w", err)
+	}
+	return body.AccessToken, nil
+}
@@ -0,0 +1,147 @@
+// Package objectstore uploads files to cloud object storage without
+// depending on the cloud providers' SDKs.
+//
+// A Store is opened from a target URI:
+//
+//   - s3://bucket/prefix - Amazon S3 or a compatible service. Credentials come
+//     from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN; the
+//     region from AWS_REGION (default us-east-1). Set AWS_ENDPOINT_URL to use
+//     an S3-compatible service such as MinIO.
+//   - gs://bucket/prefix - Google Cloud Storage. The access token comes from
+//     GOOGLE_OAUTH_ACCESS_TOKEN or, on Google Cloud, the metadata server.
+//   - azblob://account/container/prefix - Azure Blob Storage, authorized by
+//     the shared access signature in AZURE_STORAGE_SAS_TOKEN.
+//
+// Basic usage:
+//
+//	store, err := objectstore.Open("s3://docs/schemas/prod")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = store.Put(ctx, "schema.dbml", content, "text/plain")
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Store writes objects under a fixed prefix.
+type Store interface {
+	// Put writes content to the object named key, relative to the store's
+	// prefix, replacing any existing object.
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+}
+
+// Option configures a Store.
+type Option func(*options)
+
+type options struct {
+	client *http.Client
+	getenv func(string) string
+}
+
+// WithHTTPClient sets the client used for uploads. The default client times
+// out after one minute.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// withEnv replaces os.Getenv, for tests.
+func withEnv(getenv func(string) string) Option {
+	return func(o *options) {
+		o.getenv = getenv
+	}
+}
+
+// Open returns the Store for a target URI.
+func Open(target string, opts ...Option) (Store, error) {
+	o := &options{
+		client: &http.Client{Timeout: time.Minute},
+		getenv: os.Getenv,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage target %q: %w", target, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid storage target %q: missing bucket", target)
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "s3":
+		return newS3(u.Host, prefix, o)
+	case "gs":
+		return newGCS(u.Host, prefix, o), nil
+	case "azblob":
+		container, rest, _ := strings.Cut(prefix, "/")
+		if container == "" {
+			return nil, fmt.Errorf("invalid storage target %q: expected azblob://account/container/prefix", target)
+		}
+		return newAzure(u.Host, container, rest, o)
+	default:
+		return nil, fmt.Errorf("unsupported storage target %q (expected s3://, gs://, or azblob://)", target)
+	}
+}
+
+// objectKey joins the store prefix and key.
+func objectKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
+
+// escapePath percent-encodes each segment of an object key.
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// send performs req and treats any non-2xx response as an error.
+func send(client *http.Client, req *http.Request, content []byte) error {
+	req.Body = io.NopCloser(bytes.NewReader(content))
+	req.ContentLength = int64(len(content))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// url.Error repeats the URL, query string and all
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to upload %s: %w", redact(req.URL), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("upload to %s failed with status %d: %s", redact(req.URL), resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
+
+// redact drops the query string, which may hold a shared access signature.
+func redact(u *url.URL) string {
+	clean := *u
+	clean.RawQuery = ""
+	return clean.Redacted()
+}
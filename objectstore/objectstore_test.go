@@ -0,0 +1,151 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func env(values map[string]string) Option {
+	return withEnv(func(name string) string { return values[name] })
+}
+
+func TestOpen(t *testing.T) {
+	awsEnv := env(map[string]string{"AWS_ACCESS_KEY_ID": "id", "AWS_SECRET_ACCESS_KEY": "secret", "AZURE_STORAGE_SAS_TOKEN": "?sv=1&sig=x"})
+
+	tests := []struct {
+		target  string
+		wantErr string
+	}{
+		{target: "s3://docs/schemas/prod"},
+		{target: "gs://docs"},
+		{target: "azblob://account/container/prefix"},
+		{target: "azblob://account", wantErr: "expected azblob://account/container/prefix"},
+		{target: "ftp://host/path", wantErr: "unsupported storage target"},
+		{target: "s3:///path", wantErr: "missing bucket"},
+	}
+
+	for _, tt := range tests {
+		_, err := Open(tt.target, awsEnv)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("Open(%s) returned error: %v", tt.target, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Open(%s) error = %v, want %q", tt.target, err, tt.wantErr)
+		}
+	}
+}
+
+func TestOpenRequiresCredentials(t *testing.T) {
+	if _, err := Open("s3://docs", env(nil)); err == nil {
+		t.Error("Expected error without AWS credentials")
+	}
+	if _, err := Open("azblob://account/container", env(nil)); err == nil {
+		t.Error("Expected error without a SAS token")
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	if result := escapePath("prod schemas/v1/schema #1.dbml"); result != "prod%20schemas/v1/schema%20%231.dbml" {
+		t.Errorf("Unexpected escaped path: %s", result)
+	}
+}
+
+// recorder captures the last request made to a test server.
+type recorder struct {
+	method string
+	path   string
+	query  string
+	header http.Header
+	body   string
+}
+
+func newServer(t *testing.T, status int) (*httptest.Server, *recorder) {
+	rec := &recorder{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*rec = recorder{method: r.Method, path: r.URL.EscapedPath(), query: r.URL.RawQuery, header: r.Header, body: string(body)}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, rec
+}
+
+func TestAzurePut(t *testing.T) {
+	srv, rec := newServer(t, http.StatusCreated)
+
+	store, err := Open("azblob://account/docs/prod", env(map[string]string{"AZURE_STORAGE_SAS_TOKEN": "?sv=2021&sig=abc"}))
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	store.(*azureStore).endpoint = srv.URL
+
+	if err := store.Put(context.Background(), "schema.dbml", []byte("Table users {}"), "text/plain"); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+
+	if rec.method != http.MethodPut || rec.path != "/docs/prod/schema.dbml" || rec.query != "sv=2021&sig=abc" {
+		t.Errorf("Unexpected request: %s %s?%s", rec.method, rec.path, rec.query)
+	}
+	if rec.header.Get("X-Ms-Blob-Type") != "BlockBlob" || rec.body != "Table users {}" {
+		t.Errorf("Unexpected headers or body: %v %q", rec.header, rec.body)
+	}
+}
+
+func TestUploadErrorHidesSignature(t *testing.T) {
+	srv, _ := newServer(t, http.StatusForbidden)
+
+	store, _ := Open("azblob://account/docs", env(map[string]string{"AZURE_STORAGE_SAS_TOKEN": "sig=topsecret"}))
+	store.(*azureStore).endpoint = srv.URL
+
+	err := store.Put(context.Background(), "schema.dbml", nil, "text/plain")
+	if err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Fatalf("Expected 403 error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "topsecret") {
+		t.Errorf("Error leaks the SAS token: %v", err)
+	}
+}
+
+func TestGCSPutWithToken(t *testing.T) {
+	srv, rec := newServer(t, http.StatusOK)
+
+	store, _ := Open("gs://docs/prod", env(map[string]string{"GOOGLE_OAUTH_ACCESS_TOKEN": "ya29.token"}))
+	store.(*gcsStore).endpoint = srv.URL
+
+	if err := store.Put(context.Background(), "schema.json", []byte("{}"), "application/json"); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+
+	if rec.path != "/docs/prod/schema.json" || rec.header.Get("Authorization") != "Bearer ya29.token" {
+		t.Errorf("Unexpected request: %s %v", rec.path, rec.header)
+	}
+	if rec.header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected content type: %s", rec.header.Get("Content-Type"))
+	}
+}
+
+func TestGCSPutWithMetadataToken(t *testing.T) {
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			t.Error("Expected Metadata-Flavor header")
+		}
+		w.Write([]byte(`{"access_token": "from-metadata", "expires_in": 3599, "token_type": "Bearer"}`))
+	}))
+	defer metadata.Close()
+	srv, rec := newServer(t, http.StatusOK)
+
+	store, _ := Open("gs://docs", env(nil))
+	store.(*gcsStore).endpoint = srv.URL
+	store.(*gcsStore).tokenURL = metadata.URL
+
+	if err := store.Put(context.Background(), "schema.dbml", nil, "text/plain"); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if rec.header.Get("Authorization") != "Bearer from-metadata" {
+		t.Errorf("Expected metadata token, got %q", rec.header.Get("Authorization"))
+	}
+}
@@ -0,0 +1,218 @@
+// Package openapi renders schema definitions as OpenAPI 3 component schemas.
+//
+// Each table becomes an entry under components.schemas with one property per
+// column, carrying the JSON type, format, nullability, and the column comment
+// as its description. API teams whose resources mirror tables can reference
+// these components instead of maintaining them by hand.
+//
+// Basic usage:
+//
+//	output, err := openapi.Generate(s)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("components.json", output, 0644)
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Version is the OpenAPI version declared by generated documents.
+const Version = "3.0.3"
+
+// Document is the top level of a generated OpenAPI document. It declares no
+// paths; it exists to carry the component schemas.
+type Document struct {
+	OpenAPI    string     `json:"openapi"`
+	Info       Info       `json:"info"`
+	Paths      struct{}   `json:"paths"`
+	Components Components `json:"components"`
+}
+
+// Info is the document's info object.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds the component schemas keyed by name.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is an OpenAPI schema object. Only the fields needed to describe
+// table rows are supported.
+type Schema struct {
+	Type        string     `json:"type,omitempty"`
+	Format      string     `json:"format,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Nullable    bool       `json:"nullable,omitempty"`
+	MaxLength   *int       `json:"maxLength,omitempty"`
+	Enum        []string   `json:"enum,omitempty"`
+	Items       *Schema    `json:"items,omitempty"`
+	Properties  Properties `json:"properties,omitempty"`
+	Required    []string   `json:"required,omitempty"`
+}
+
+// Property is a named schema inside an object schema.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties lists an object's properties. It marshals as a JSON object that
+// keeps the properties in column order.
+type Properties []Property
+
+// MarshalJSON implements json.Marshaler.
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, property := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(property.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(property.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Generate converts a Schema into an OpenAPI document in JSON.
+func Generate(s *schema.Schema) ([]byte, error) {
+	doc := Build(s)
+	output, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	return append(output, '\n'), nil
+}
+
+// Build converts a Schema into an OpenAPI document.
+// Component names are the qualified table names, with "public" omitted.
+func Build(s *schema.Schema) *Document {
+	enums := make(map[string][]string)
+	for _, enum := range s.Enums {
+		enums[enum.Schema+"."+enum.Name] = enum.Values
+		if enum.Schema == "public" || enum.Schema == "" {
+			enums[enum.Name] = enum.Values
+		}
+	}
+
+	doc := &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: "Database schema", Version: "1.0.0"},
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+
+	for _, table := range s.Tables {
+		object := &Schema{Type: "object", Description: table.Comment}
+		for _, column := range table.Columns {
+			property := columnSchema(column, enums)
+			property.Description = column.Comment
+			if column.Nullable && !column.IsPrimaryKey {
+				property.Nullable = true
+			} else {
+				object.Required = append(object.Required, column.Name)
+			}
+			object.Properties = append(object.Properties, Property{Name: column.Name, Schema: property})
+		}
+		doc.Components.Schemas[ComponentName(table)] = object
+	}
+
+	return doc
+}
+
+var invalidComponentChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// ComponentName returns the components.schemas key for a table.
+func ComponentName(table schema.Table) string {
+	name := generator.GetQualifiedTableName(table.Name, table.Schema)
+	return invalidComponentChars.ReplaceAllString(name, "_")
+}
+
+var lengthPattern = regexp.MustCompile(`\((\d+)\)$`)
+
+func columnSchema(column schema.Column, enums map[string][]string) *Schema {
+	if values, ok := enums[column.DatabaseType]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	typ := column.DatabaseType
+	if typ == "" {
+		typ = column.Type
+	}
+	return typeSchema(strings.ToLower(typ), enums)
+}
+
+func typeSchema(typ string, enums map[string][]string) *Schema {
+	if strings.HasSuffix(typ, "[]") {
+		element := strings.TrimSuffix(typ, "[]")
+		items := &Schema{Type: "string", Enum: enums[element]}
+		if items.Enum == nil {
+			items = typeSchema(element, enums)
+		}
+		return &Schema{Type: "array", Items: items}
+	}
+
+	base := typ
+	if i := strings.Index(typ, "("); i >= 0 {
+		// "timestamp(3) with time zone" becomes "timestamp with time zone"
+		base = typ[:i] + typ[strings.Index(typ, ")")+1:]
+	}
+
+	switch base {
+	case "int", "integer", "int4", "smallint", "int2", "serial", "smallserial":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "bigint", "int8", "bigserial":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "boolean", "bool":
+		return &Schema{Type: "boolean"}
+	case "real", "float", "float4":
+		return &Schema{Type: "number", Format: "float"}
+	case "double", "double precision", "float8":
+		return &Schema{Type: "number", Format: "double"}
+	case "numeric", "decimal", "money":
+		return &Schema{Type: "number"}
+	case "uuid":
+		return &Schema{Type: "string", Format: "uuid"}
+	case "date":
+		return &Schema{Type: "string", Format: "date"}
+	case "timestamp", "timestamptz", "timestamp without time zone", "timestamp with time zone":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "bytea", "binary":
+		return &Schema{Type: "string", Format: "byte"}
+	case "json", "jsonb":
+		// Any JSON value: leave the type open.
+		return &Schema{}
+	case "varchar", "character varying", "char", "character", "bpchar":
+		result := &Schema{Type: "string"}
+		if m := lengthPattern.FindStringSubmatch(typ); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				result.MaxLength = &n
+			}
+		}
+		return result
+	default:
+		return &Schema{Type: "string"}
+	}
+}
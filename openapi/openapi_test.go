@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestGenerate(t *testing.T) {
+	seq := "nextval('users_id_seq'::regclass)"
+	s := &schema.Schema{
+		Enums: []schema.Enum{{Name: "role", Schema: "public", Values: []string{"admin", "member"}}},
+		Tables: []schema.Table{
+			{
+				Name:    "users",
+				Schema:  "public",
+				Comment: "Registered users",
+				Columns: []schema.Column{
+					{Name: "id", Type: "bigint", IsPrimaryKey: true, DefaultValue: &seq, DatabaseType: "bigint"},
+					{Name: "email", Type: "varchar(255)", DatabaseType: "character varying(255)", Comment: "Login address"},
+					{Name: "role", Type: "text", DatabaseType: "role"},
+					{Name: "external_id", Type: "uuid", Nullable: true},
+					{Name: "tags", Type: "text[]", Nullable: true, DatabaseType: "text[]"},
+					{Name: "created_at", Type: "timestamptz", DatabaseType: "timestamp(3) with time zone"},
+				},
+				PrimaryKeys: []string{"id"},
+			},
+			{
+				Name:    "sessions",
+				Schema:  "auth",
+				Columns: []schema.Column{{Name: "data", Type: "jsonb"}},
+			},
+		},
+	}
+
+	output, err := Generate(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(output, &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if doc.OpenAPI != Version {
+		t.Errorf("Expected openapi %s, got %s", Version, doc.OpenAPI)
+	}
+	if _, ok := doc.Components.Schemas["auth.sessions"]; !ok {
+		t.Errorf("Expected component for auth.sessions, got %v", doc.Components.Schemas)
+	}
+
+	result := string(output)
+	expected := []string{
+		`"description": "Registered users"`,
+		`"id": {
+            "type": "integer",
+            "format": "int64"
+          }`,
+		`"email": {
+            "type": "string",
+            "description": "Login address",
+            "maxLength": 255
+          }`,
+		`"role": {
+            "type": "string",
+            "enum": [
+              "admin",
+              "member"
+            ]
+          }`,
+		`"external_id": {
+            "type": "string",
+            "format": "uuid",
+            "nullable": true
+          }`,
+		`"tags": {
+            "type": "array",
+            "nullable": true,
+            "items": {
+              "type": "string"
+            }
+          }`,
+		`"created_at": {
+            "type": "string",
+            "format": "date-time"
+          }`,
+		`"required": [
+          "id",
+          "email",
+          "role",
+          "created_at"
+        ]`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(result, e) {
+			t.Errorf("Expected output to contain:\n%s\n\nGot:\n%s", e, result)
+		}
+	}
+
+	// Properties keep column order
+	if strings.Index(result, `"id": {`) > strings.Index(result, `"email": {`) {
+		t.Errorf("Expected properties in column order")
+	}
+}
+
+func TestComponentName(t *testing.T) {
+	tests := []struct {
+		table    schema.Table
+		expected string
+	}{
+		{schema.Table{Name: "users", Schema: "public"}, "users"},
+		{schema.Table{Name: "sessions", Schema: "auth"}, "auth.sessions"},
+		{schema.Table{Name: "order items", Schema: "public"}, "order_items"},
+	}
+
+	for _, tt := range tests {
+		if result := ComponentName(tt.table); result != tt.expected {
+			t.Errorf("ComponentName(%s.%s) = %s, want %s", tt.table.Schema, tt.table.Name, result, tt.expected)
+		}
+	}
+}
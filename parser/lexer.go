@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNewline
+	tokIdent   // bare word or number
+	tokString  // '...' or '''...'''
+	tokQuoted  // "..."
+	tokExpr    // `...`
+	tokPunct   // single punctuation character
+	tokComment // // or /* */ comment
+)
+
+type token struct {
+	kind tokenKind
+	// text is the token's value. Quotes are removed from strings, quoted
+	// identifiers, and expressions.
+	text string
+	line int
+	// start and end are byte offsets of the raw token in the source.
+	start, end int
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+func (t token) isPunct(text string) bool {
+	return t.is(tokPunct, text)
+}
+
+// isKeyword reports whether t is a bare identifier matching keyword
+// case-insensitively.
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, keyword)
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	i := 0
+
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			tokens = append(tokens, token{kind: tokNewline, text: "\n", line: line, start: i, end: i + 1})
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			j := i
+			for j < len(src) && src[j] != '\n' {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokComment, text: src[i:j], line: line, start: i, end: j})
+			i = j
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			comment := src[i : i+2+end+2]
+			tokens = append(tokens, token{kind: tokComment, text: comment, line: line, start: i, end: i + len(comment)})
+			line += strings.Count(comment, "\n")
+			i += len(comment)
+		case strings.HasPrefix(src[i:], "'''"):
+			end := strings.Index(src[i+3:], "'''")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			raw := src[i : i+3+end+3]
+			tokens = append(tokens, token{kind: tokString, text: dedent(raw[3 : len(raw)-3]), line: line, start: i, end: i + len(raw)})
+			line += strings.Count(raw, "\n")
+			i += len(raw)
+		case c == '\'' || c == '"' || c == '`':
+			kind := map[byte]tokenKind{'\'': tokString, '"': tokQuoted, '`': tokExpr}[c]
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' && j+1 < len(src) && kind != tokExpr {
+					j++
+					if src[j] == '\n' {
+						line++
+					}
+					b.WriteByte(unescape(src[j]))
+					continue
+				}
+				if src[j] == '\n' {
+					line++
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated %q", line, string(c))
+			}
+			tokens = append(tokens, token{kind: kind, text: b.String(), line: line, start: i, end: j + 1})
+			i = j + 1
+		case isIdentChar(c):
+			j := i
+			for j < len(src) && isIdentChar(src[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[i:j], line: line, start: i, end: j})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokPunct, text: string(c), line: line, start: i, end: i + 1})
+			i++
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, line: line, start: len(src), end: len(src)})
+	return tokens, nil
+}
+
+// unescape returns the character a backslash escape stands for: a line
+// break, carriage return, or tab for \n, \r, and \t, and the escaped
+// character itself otherwise, as in \' and \\.
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	}
+	return c
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '#' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// dedent removes the common leading indentation from a multi-line string,
+// along with a leading and trailing blank line.
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestLexEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`'a\nb'`, "a\nb"},
+		{`'a\r\nb'`, "a\r\nb"},
+		{`'a\tb'`, "a\tb"},
+		{`'it\'s'`, "it's"},
+		{`'back\\slash'`, `back\slash`},
+		{`"say \"hi\""`, `say "hi"`},
+		{"`a\\nb`", `a\nb`},
+	}
+	for _, tt := range tests {
+		tokens, err := lex(tt.input)
+		if err != nil {
+			t.Fatalf("lex(%s) returned error: %v", tt.input, err)
+		}
+		if tokens[0].text != tt.expected {
+			t.Errorf("lex(%s) = %q, want %q", tt.input, tokens[0].text, tt.expected)
+		}
+	}
+}
+
+// TestLexQuoteRoundTrip lexes the single-quoted notes the generator writes
+// back into the text they were written from.
+func TestLexQuoteRoundTrip(t *testing.T) {
+	comments := []string{
+		"line one\nline two",
+		`C:\temp\new`,
+		"it's 'quoted'",
+		"tab\tseparated",
+		"ends with a backslash\\",
+	}
+	for _, comment := range comments {
+		s := &schema.Schema{Tables: []schema.Table{{
+			Name:    "notes",
+			Schema:  "public",
+			Columns: []schema.Column{{Name: "body", Type: "text", Nullable: true, Comment: comment}},
+		}}}
+		output, err := generator.Generate(s, generator.WithNotes())
+		if err != nil {
+			t.Fatalf("Generate returned error: %v", err)
+		}
+		parsed, err := Parse(output)
+		if err != nil {
+			t.Fatalf("Parse returned error: %v\n%s", err, output)
+		}
+		if got := parsed.Tables[0].Columns[0].Comment; got != comment {
+			t.Errorf("Expected %q to survive the round trip, got %q from:\n%s", comment, got, output)
+		}
+	}
+}
@@ -0,0 +1,1065 @@
+// Package parser reads DBML documents into schema definitions.
+//
+// It understands the subset of DBML needed to describe a relational schema:
+// tables with columns, column settings, and index blocks, standalone and
+// inline references, enums, and table groups. Blocks that have no
+// representation in the schema package (such as Project) are skipped by
+// Parse; ParseDocument keeps their source text, and that of Enum blocks.
+//
+// Basic usage:
+//
+//	s, err := parser.Parse(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// defaultSchema is assumed for tables written without a schema prefix,
+// matching how the generator omits "public".
+const defaultSchema = "public"
+
+// Parse converts a DBML document into a Schema.
+func Parse(data []byte) (*schema.Schema, error) {
+	doc, err := ParseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Schema, nil
+}
+
+// Document is a parsed DBML document.
+type Document struct {
+	// Schema holds the tables, references, and table groups.
+	Schema *schema.Schema
+	// Blocks holds the source text of top-level blocks the schema cannot
+	// represent, such as Project and Note, in document order. Enum blocks
+	// are read into Schema.Enums and also kept here, with their comments.
+	Blocks []string
+	// Dropped lists what neither Schema nor Blocks keep, such as comments
+	// outside those blocks and settings the schema has no field for, each
+	// prefixed with its line.
+	Dropped []string
+}
+
+// ParseDocument converts a DBML document into a Schema, keeping the blocks
+// Parse skips.
+func ParseDocument(data []byte) (*Document, error) {
+	src := string(data)
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments are set aside, and only dropped if they are not in a block
+	// kept verbatim
+	var comments []token
+	code := tokens[:0:0]
+	for _, t := range tokens {
+		if t.kind == tokComment {
+			comments = append(comments, t)
+		} else {
+			code = append(code, t)
+		}
+	}
+
+	p := &parser{src: src, tokens: code, aliases: make(map[string]tableName)}
+	if err := p.parseDocument(); err != nil {
+		return nil, err
+	}
+
+	s, err := p.build()
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, c := range comments {
+		if !p.inBlock(c) {
+			dropped = append(dropped, fmt.Sprintf("line %d: comment %s", c.line, firstLine(c.text)))
+		}
+	}
+	dropped = append(dropped, p.dropped...)
+	return &Document{Schema: s, Blocks: p.blocks, Dropped: dropped}, nil
+}
+
+// ParseString is a convenience wrapper around Parse for string input.
+func ParseString(s string) (*schema.Schema, error) {
+	return Parse([]byte(s))
+}
+
+type tableName struct {
+	schema string
+	name   string
+}
+
+// endpoint is one side of a relationship: a table and its columns.
+type endpoint struct {
+	table   tableName
+	columns []string
+}
+
+type relationship struct {
+	name     string
+	from, to endpoint
+	kind     string
+	settings map[string]string
+	line     int
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+
+	tables        []*schema.Table
+	aliases       map[string]tableName
+	relationships []relationship
+	groups        []group
+	enums         []schema.Enum
+	blocks        []string
+	// spans holds the source offsets of blocks.
+	spans [][2]int
+	// dropped lists what is read but not kept, for Document.Dropped.
+	dropped []string
+	// uniqueColumns lists the columns of the table being read that have the
+	// unique setting.
+	uniqueColumns []string
+}
+
+// drop records a setting or element on line that the schema cannot hold.
+func (p *parser) drop(line int, what string) {
+	p.dropped = append(p.dropped, fmt.Sprintf("line %d: %s", line, what))
+}
+
+// inBlock reports whether t is inside a block kept verbatim.
+func (p *parser) inBlock(t token) bool {
+	for _, span := range p.spans {
+		if t.start >= span[0] && t.end <= span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// firstLine returns the first line of s, for messages about multi-line
+// source text.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// group is a TableGroup whose table names are resolved once every alias is
+// known.
+type group struct {
+	name   string
+	tables [][]string
+	line   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) skipNewlines() {
+	for p.peek().kind == tokNewline {
+		p.next()
+	}
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", t.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) expectPunct(text string) (token, error) {
+	t := p.next()
+	if !t.isPunct(text) {
+		return t, p.errorf(t, "expected %q, found %q", text, t.text)
+	}
+	return t, nil
+}
+
+// name reads an identifier, either bare or double-quoted.
+func (p *parser) name() (string, error) {
+	t := p.next()
+	if t.kind != tokIdent && t.kind != tokQuoted {
+		return "", p.errorf(t, "expected name, found %q", t.text)
+	}
+	return t.text, nil
+}
+
+// qualifiedName reads one or more dot-separated names.
+func (p *parser) qualifiedName() ([]string, error) {
+	first, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{first}
+	for p.peek().isPunct(".") && (p.peekAt(1).kind == tokIdent || p.peekAt(1).kind == tokQuoted) {
+		p.next()
+		part, _ := p.name()
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+func (p *parser) parseDocument() error {
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		switch {
+		case t.kind == tokEOF:
+			return nil
+		case t.isKeyword("Table"):
+			if err := p.parseTable(); err != nil {
+				return err
+			}
+		case t.isKeyword("Ref"):
+			if err := p.parseRef(); err != nil {
+				return err
+			}
+		case t.isKeyword("TableGroup"):
+			if err := p.parseTableGroup(); err != nil {
+				return err
+			}
+		case t.isKeyword("Enum"):
+			if err := p.parseEnum(); err != nil {
+				return err
+			}
+		case t.kind == tokIdent:
+			// Project, Note, and any other named block.
+			start := t.start
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+			end := p.tokens[p.pos-1].end
+			p.blocks = append(p.blocks, p.src[start:end])
+			p.spans = append(p.spans, [2]int{start, end})
+		default:
+			return p.errorf(t, "unexpected %q", t.text)
+		}
+	}
+}
+
+// skipBlock consumes a top-level element up to and including its closing brace.
+func (p *parser) skipBlock() error {
+	start := p.peek()
+	for !p.peek().isPunct("{") {
+		if p.peek().kind == tokEOF {
+			return p.errorf(start, "expected block after %q", start.text)
+		}
+		p.next()
+	}
+	return p.skipBalanced("{", "}")
+}
+
+func (p *parser) skipBalanced(open, close string) error {
+	start, err := p.expectPunct(open)
+	if err != nil {
+		return err
+	}
+	depth := 1
+	for depth > 0 {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return p.errorf(start, "unterminated %q", open)
+		case t.isPunct(open):
+			depth++
+		case t.isPunct(close):
+			depth--
+		}
+	}
+	return nil
+}
+
+// parseEnum reads "Enum name { value ... }". Settings on values, such as
+// notes, have no representation in the schema; the block's source text is
+// kept for them.
+func (p *parser) parseEnum() error {
+	start := p.next() // Enum
+
+	parts, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	name := toTableName(parts)
+	enum := schema.Enum{Name: name.name, Schema: name.schema}
+
+	if _, err := p.expectPunct("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		switch {
+		case t.isPunct("}"):
+			end := p.next().end
+			p.enums = append(p.enums, enum)
+			p.blocks = append(p.blocks, p.src[start.start:end])
+			p.spans = append(p.spans, [2]int{start.start, end})
+			return nil
+		case t.kind == tokEOF:
+			return p.errorf(t, "unterminated enum %s", name.name)
+		default:
+			value, err := p.name()
+			if err != nil {
+				return err
+			}
+			enum.Values = append(enum.Values, value)
+			if p.peek().isPunct("[") {
+				if _, err := p.parseSettings(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// parseTableGroup reads "TableGroup name { table ... }".
+func (p *parser) parseTableGroup() error {
+	start := p.next() // TableGroup
+
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	g := group{name: name, line: start.line}
+
+	// Settings such as color have no representation in the schema
+	if p.peek().isPunct("[") {
+		settings, err := p.parseSettings()
+		if err != nil {
+			return err
+		}
+		for _, s := range settings {
+			p.drop(s.line, fmt.Sprintf("table group %s setting %s", name, s.key))
+		}
+	}
+
+	if _, err := p.expectPunct("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		switch {
+		case t.isPunct("}"):
+			p.next()
+			p.groups = append(p.groups, g)
+			return nil
+		case t.kind == tokEOF:
+			return p.errorf(t, "unterminated table group %s", name)
+		case t.isKeyword("Note") && (p.peekAt(1).isPunct(":") || p.peekAt(1).isPunct("{")):
+			if _, err := p.parseNote(); err != nil {
+				return err
+			}
+			p.drop(t.line, fmt.Sprintf("table group %s note", name))
+		default:
+			parts, err := p.qualifiedName()
+			if err != nil {
+				return err
+			}
+			g.tables = append(g.tables, parts)
+		}
+	}
+}
+
+func toTableName(parts []string) tableName {
+	if len(parts) == 1 {
+		return tableName{schema: defaultSchema, name: parts[0]}
+	}
+	return tableName{schema: strings.Join(parts[:len(parts)-1], "."), name: parts[len(parts)-1]}
+}
+
+func (p *parser) parseTable() error {
+	p.next() // Table
+
+	parts, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	name := toTableName(parts)
+	table := &schema.Table{Name: name.name, Schema: name.schema}
+	p.uniqueColumns = nil
+
+	if p.peek().isKeyword("as") {
+		p.next()
+		alias, err := p.name()
+		if err != nil {
+			return err
+		}
+		p.aliases[alias] = name
+	}
+
+	if p.peek().isPunct("[") {
+		settings, err := p.parseSettings()
+		if err != nil {
+			return err
+		}
+		for _, s := range settings {
+			switch strings.ToLower(s.key) {
+			case "note":
+				table.Comment = s.text()
+			case "headercolor":
+				table.Color = s.text()
+			default:
+				p.drop(s.line, fmt.Sprintf("table %s setting %s", name.name, s.key))
+			}
+		}
+	}
+
+	if _, err := p.expectPunct("{"); err != nil {
+		return err
+	}
+
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		switch {
+		case t.isPunct("}"):
+			p.next()
+			addUniqueIndexes(table, p.uniqueColumns)
+			p.tables = append(p.tables, table)
+			return nil
+		case t.kind == tokEOF:
+			return p.errorf(t, "unterminated table %s", table.Name)
+		case t.isKeyword("indexes") && p.peekAt(1).isPunct("{"):
+			if err := p.parseIndexes(table); err != nil {
+				return err
+			}
+		case t.isKeyword("Note") && (p.peekAt(1).isPunct(":") || p.peekAt(1).isPunct("{")):
+			note, err := p.parseNote()
+			if err != nil {
+				return err
+			}
+			table.Comment = note
+		default:
+			if err := p.parseColumn(table); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// addUniqueIndexes adds a unique index for each of columns, written with the
+// unique column setting, unless the indexes block already has one.
+func addUniqueIndexes(table *schema.Table, columns []string) {
+	for _, column := range columns {
+		indexed := false
+		for _, index := range table.Indexes {
+			if index.Unique && len(index.Columns) == 1 && index.Columns[0] == column {
+				indexed = true
+			}
+		}
+		if !indexed {
+			table.Indexes = append(table.Indexes, schema.Index{Columns: []string{column}, Unique: true})
+		}
+	}
+}
+
+// parseNote consumes a "Note: '...'" or "Note { '...' }" element.
+func (p *parser) parseNote() (string, error) {
+	p.next() // Note
+	if p.peek().isPunct(":") {
+		p.next()
+		t := p.next()
+		if t.kind != tokString {
+			return "", p.errorf(t, "expected note string, found %q", t.text)
+		}
+		return t.text, nil
+	}
+
+	if _, err := p.expectPunct("{"); err != nil {
+		return "", err
+	}
+	p.skipNewlines()
+	t := p.next()
+	if t.kind != tokString {
+		return "", p.errorf(t, "expected note string, found %q", t.text)
+	}
+	p.skipNewlines()
+	if _, err := p.expectPunct("}"); err != nil {
+		return "", err
+	}
+	return t.text, nil
+}
+
+func (p *parser) parseColumn(table *schema.Table) error {
+	nameToken := p.peek()
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+
+	typ, err := p.columnType()
+	if err != nil {
+		return err
+	}
+
+	column := schema.Column{Name: name, Type: typ, Nullable: true, OrdinalPosition: len(table.Columns) + 1}
+
+	if p.peek().isPunct("[") {
+		settings, err := p.parseSettings()
+		if err != nil {
+			return err
+		}
+		for _, s := range settings {
+			switch strings.ToLower(s.key) {
+			case "pk", "primary key":
+				column.IsPrimaryKey = true
+				column.Nullable = false
+				table.PrimaryKeys = append(table.PrimaryKeys, name)
+			case "not null":
+				column.Nullable = false
+			case "null":
+				column.Nullable = true
+			case "increment":
+				value := fmt.Sprintf("nextval('%s_%s_seq'::regclass)", table.Name, name)
+				column.DefaultValue = &value
+				column.AutoIncrement = true
+			case "default":
+				value := s.value
+				column.DefaultValue = &value
+			case "note":
+				column.Comment = s.text()
+			case "ref":
+				rel, err := p.inlineRef(s, tableName{schema: table.Schema, name: table.Name}, name)
+				if err != nil {
+					return err
+				}
+				p.relationships = append(p.relationships, rel)
+			case "unique":
+				p.uniqueColumns = append(p.uniqueColumns, name)
+			default:
+				p.drop(s.line, fmt.Sprintf("column %s.%s setting %s", table.Name, name, s.key))
+			}
+		}
+	}
+
+	if t := p.peek(); t.kind != tokNewline && !t.isPunct("}") && t.kind != tokEOF {
+		return p.errorf(nameToken, "unexpected %q after column %s", t.text, name)
+	}
+
+	table.Columns = append(table.Columns, column)
+	return nil
+}
+
+// columnType reads the raw type text following a column name, such as
+// "varchar(255)", "decimal(10, 2)", "int[]", or "auth.role".
+func (p *parser) columnType() (string, error) {
+	first := p.peek()
+	if first.kind == tokQuoted {
+		p.next()
+		return first.text, nil
+	}
+	if first.kind != tokIdent {
+		return "", p.errorf(first, "expected column type, found %q", first.text)
+	}
+
+	start, end := first.start, first.end
+	p.next()
+	depth := 0
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokEOF || t.kind == tokNewline:
+			return strings.TrimSpace(p.src[start:end]), nil
+		case t.isPunct("(") || (t.isPunct("[") && p.peekAt(1).isPunct("]")):
+			depth++
+		case t.isPunct(")") || (t.isPunct("]") && depth > 0):
+			depth--
+		case depth == 0 && (t.isPunct("[") || t.isPunct("}")):
+			return strings.TrimSpace(p.src[start:end]), nil
+		case depth == 0 && t.kind != tokPunct:
+			// A second bare word ends the type unless it is part of a
+			// qualified name such as schema.type.
+			prev := p.tokens[p.pos-1]
+			if !prev.isPunct(".") {
+				return strings.TrimSpace(p.src[start:end]), nil
+			}
+		}
+		end = t.end
+		p.next()
+	}
+}
+
+type setting struct {
+	key   string
+	value string
+	// raw holds the tokens of the value for settings such as ref whose
+	// structure matters.
+	raw []token
+	// kind is the token kind of a single-token value.
+	kind tokenKind
+	// line is the line the setting starts on.
+	line int
+}
+
+// text returns the unquoted value of a string setting such as note.
+func (s setting) text() string {
+	if s.kind == tokString {
+		return s.raw[0].text
+	}
+	return s.value
+}
+
+// parseSettings reads a bracketed, comma-separated settings list.
+func (p *parser) parseSettings() ([]setting, error) {
+	open, err := p.expectPunct("[")
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []setting
+	for {
+		for p.peek().kind == tokNewline {
+			p.next()
+		}
+		if p.peek().isPunct("]") {
+			p.next()
+			return settings, nil
+		}
+
+		var keyParts []string
+		for {
+			t := p.peek()
+			if t.kind == tokEOF {
+				return nil, p.errorf(open, "unterminated settings")
+			}
+			if t.isPunct(":") || t.isPunct(",") || t.isPunct("]") {
+				break
+			}
+			keyParts = append(keyParts, p.next().text)
+		}
+
+		s := setting{key: strings.Join(keyParts, " "), line: p.peek().line}
+		if p.peek().isPunct(":") {
+			p.next()
+			var raw []token
+			depth := 0
+			for {
+				t := p.peek()
+				if t.kind == tokEOF {
+					return nil, p.errorf(open, "unterminated settings")
+				}
+				if depth == 0 && (t.isPunct(",") || t.isPunct("]")) {
+					break
+				}
+				if t.isPunct("(") || t.isPunct("[") {
+					depth++
+				} else if t.isPunct(")") || t.isPunct("]") {
+					depth--
+				}
+				raw = append(raw, p.next())
+			}
+			s.raw = raw
+			if len(raw) == 1 {
+				s.kind = raw[0].kind
+				s.value = raw[0].text
+				if raw[0].kind == tokString {
+					s.value = "'" + strings.ReplaceAll(raw[0].text, "'", "''") + "'"
+				}
+			} else if len(raw) > 1 {
+				s.value = strings.TrimSpace(p.src[raw[0].start:raw[len(raw)-1].end])
+			}
+		}
+		settings = append(settings, s)
+
+		if p.peek().isPunct(",") {
+			p.next()
+		}
+	}
+}
+
+func (p *parser) parseIndexes(table *schema.Table) error {
+	p.next() // indexes
+	if _, err := p.expectPunct("{"); err != nil {
+		return err
+	}
+
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		if t.isPunct("}") {
+			p.next()
+			return nil
+		}
+		if t.kind == tokEOF {
+			return p.errorf(t, "unterminated indexes block")
+		}
+
+		var columns []string
+		if t.isPunct("(") {
+			p.next()
+			for !p.peek().isPunct(")") {
+				c := p.next()
+				switch c.kind {
+				case tokIdent, tokQuoted:
+					columns = append(columns, c.text)
+				case tokExpr:
+					columns = append(columns, "`"+c.text+"`")
+				case tokPunct:
+					if c.text != "," {
+						return p.errorf(c, "unexpected %q in index", c.text)
+					}
+				case tokEOF:
+					return p.errorf(t, "unterminated index column list")
+				}
+			}
+			p.next()
+		} else {
+			c := p.next()
+			switch c.kind {
+			case tokIdent, tokQuoted:
+				columns = append(columns, c.text)
+			case tokExpr:
+				columns = append(columns, "`"+c.text+"`")
+			default:
+				return p.errorf(c, "expected index column, found %q", c.text)
+			}
+		}
+
+		index := schema.Index{Columns: columns}
+		isPrimaryKey := false
+		if p.peek().isPunct("[") {
+			settings, err := p.parseSettings()
+			if err != nil {
+				return err
+			}
+			for _, s := range settings {
+				switch strings.ToLower(s.key) {
+				case "unique":
+					index.Unique = true
+				case "name":
+					index.Name = unquoteSetting(s)
+				case "type":
+					if t := strings.ToLower(unquoteSetting(s)); t != "btree" {
+						index.Type = t
+					}
+				case "note":
+					parseIndexNote(&index, unquoteSetting(s))
+				case "pk":
+					isPrimaryKey = true
+				default:
+					p.drop(s.line, fmt.Sprintf("index setting %s in table %s", s.key, table.Name))
+				}
+			}
+		}
+
+		if isPrimaryKey {
+			table.PrimaryKeys = columns
+			for i := range table.Columns {
+				for _, c := range columns {
+					if table.Columns[i].Name == c {
+						table.Columns[i].IsPrimaryKey = true
+						table.Columns[i].Nullable = false
+					}
+				}
+			}
+			continue
+		}
+
+		table.Indexes = append(table.Indexes, index)
+	}
+}
+
+// parseIndexNote reads the constraint marker, access method, covering-index
+// columns, partial-index predicate, and tablespace from an index note of the
+// form "UNIQUE constraint; USING hnsw; INCLUDE (a, b); WHERE (c IS NULL);
+// TABLESPACE fast", as written by the generator.
+func parseIndexNote(index *schema.Index, note string) {
+	for _, part := range strings.Split(note, "; ") {
+		if part == "UNIQUE constraint" {
+			index.Unique = true
+			index.UniqueConstraint = true
+		} else if method, ok := strings.CutPrefix(part, "USING "); ok {
+			index.Type = method
+		} else if columns := includeColumns(part); columns != nil {
+			index.Include = columns
+		} else if predicate, ok := strings.CutPrefix(part, "WHERE "); ok {
+			index.Where = predicate
+		} else if tablespace, ok := strings.CutPrefix(part, "TABLESPACE "); ok {
+			index.Tablespace = tablespace
+		}
+	}
+}
+
+// includeColumns reads the covering-index columns from "INCLUDE (a, b)".
+func includeColumns(note string) []string {
+	list, ok := strings.CutPrefix(note, "INCLUDE (")
+	if !ok || !strings.HasSuffix(list, ")") {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(list, ")"), ", ")
+}
+
+func unquoteSetting(s setting) string {
+	if len(s.raw) == 1 {
+		return s.raw[0].text
+	}
+	return s.value
+}
+
+// parseRef reads "Ref [name]: a.b > c.d [settings]" or the block form
+// "Ref [name] { a.b > c.d [settings] }".
+func (p *parser) parseRef() error {
+	p.next() // Ref
+	var name string
+	if p.peek().kind == tokIdent || p.peek().kind == tokQuoted {
+		name = p.next().text
+	}
+
+	if p.peek().isPunct(":") {
+		p.next()
+		return p.parseRelationship(name)
+	}
+
+	if _, err := p.expectPunct("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.peek().isPunct("}") {
+			p.next()
+			return nil
+		}
+		if p.peek().kind == tokEOF {
+			return p.errorf(p.peek(), "unterminated Ref block")
+		}
+		if err := p.parseRelationship(name); err != nil {
+			return err
+		}
+	}
+}
+
+// parseRelationship reads "a.b > c.d [settings]" of a Ref named name, or an
+// unnamed one if name is empty.
+func (p *parser) parseRelationship(name string) error {
+	line := p.peek().line
+	from, err := p.parseEndpoint()
+	if err != nil {
+		return err
+	}
+
+	kind, err := p.relationKind()
+	if err != nil {
+		return err
+	}
+
+	to, err := p.parseEndpoint()
+	if err != nil {
+		return err
+	}
+
+	rel := relationship{name: name, from: from, to: to, kind: kind, line: line, settings: map[string]string{}}
+	if p.peek().isPunct("[") {
+		settings, err := p.parseSettings()
+		if err != nil {
+			return err
+		}
+		for _, s := range settings {
+			key := strings.ToLower(s.key)
+			if key != "delete" && key != "update" {
+				p.drop(s.line, "ref setting "+s.key)
+				continue
+			}
+			rel.settings[key] = s.value
+		}
+	}
+
+	p.relationships = append(p.relationships, rel)
+	return nil
+}
+
+func (p *parser) relationKind() (string, error) {
+	t := p.next()
+	switch {
+	case t.isPunct("<") && p.peek().isPunct(">"):
+		p.next()
+		return "<>", nil
+	case t.isPunct("<"), t.isPunct(">"), t.isPunct("-"):
+		return t.text, nil
+	default:
+		return "", p.errorf(t, "expected relationship type, found %q", t.text)
+	}
+}
+
+// parseEndpoint reads "table.column", "schema.table.column", or
+// "table.(a, b)".
+func (p *parser) parseEndpoint() (endpoint, error) {
+	var parts []string
+	first, err := p.name()
+	if err != nil {
+		return endpoint{}, err
+	}
+	parts = append(parts, first)
+
+	for p.peek().isPunct(".") {
+		p.next()
+		if p.peek().isPunct("(") {
+			p.next()
+			var columns []string
+			for !p.peek().isPunct(")") {
+				t := p.next()
+				if t.kind == tokIdent || t.kind == tokQuoted {
+					columns = append(columns, t.text)
+				} else if !t.isPunct(",") {
+					return endpoint{}, p.errorf(t, "unexpected %q in column list", t.text)
+				}
+			}
+			p.next()
+			return endpoint{table: p.resolveTable(parts), columns: columns}, nil
+		}
+		part, err := p.name()
+		if err != nil {
+			return endpoint{}, err
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) < 2 {
+		return endpoint{}, p.errorf(p.peek(), "expected table.column, found %q", first)
+	}
+	return endpoint{table: p.resolveTable(parts[:len(parts)-1]), columns: []string{parts[len(parts)-1]}}, nil
+}
+
+func (p *parser) resolveTable(parts []string) tableName {
+	if len(parts) == 1 {
+		if name, ok := p.aliases[parts[0]]; ok {
+			return name
+		}
+	}
+	return toTableName(parts)
+}
+
+// inlineRef converts a column's "ref: > table.column" setting.
+func (p *parser) inlineRef(s setting, table tableName, column string) (relationship, error) {
+	saved := p.pos
+	savedTokens := p.tokens
+	defer func() {
+		p.pos = saved
+		p.tokens = savedTokens
+	}()
+
+	if len(s.raw) == 0 {
+		return relationship{}, fmt.Errorf("empty ref setting on column %s", column)
+	}
+	p.tokens = append(append([]token{}, s.raw...), token{kind: tokEOF, line: s.raw[0].line})
+	p.pos = 0
+
+	kind, err := p.relationKind()
+	if err != nil {
+		return relationship{}, err
+	}
+	to, err := p.parseEndpoint()
+	if err != nil {
+		return relationship{}, err
+	}
+
+	return relationship{
+		from:     endpoint{table: table, columns: []string{column}},
+		to:       to,
+		kind:     kind,
+		line:     s.raw[0].line,
+		settings: map[string]string{},
+	}, nil
+}
+
+// build attaches relationships to tables as references and returns the schema.
+func (p *parser) build() (*schema.Schema, error) {
+	result := &schema.Schema{}
+	byName := make(map[tableName]*schema.Table)
+	for _, t := range p.tables {
+		byName[tableName{schema: t.Schema, name: t.Name}] = t
+	}
+
+	for _, rel := range p.relationships {
+		// The foreign key lives on the "many" side; "<" points the other way.
+		// One-to-one and many-to-many refs keep their type
+		from, to := rel.from, rel.to
+		relationship := ""
+		switch rel.kind {
+		case "<":
+			from, to = to, from
+		case "-", "<>":
+			relationship = rel.kind
+		}
+		if len(from.columns) != len(to.columns) {
+			return nil, fmt.Errorf("line %d: relationship column counts do not match", rel.line)
+		}
+
+		table, ok := byName[from.table]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown table %s", rel.line, qualified(from.table))
+		}
+		if _, ok := byName[to.table]; !ok {
+			return nil, fmt.Errorf("line %d: unknown table %s", rel.line, qualified(to.table))
+		}
+
+		table.References = append(table.References, schema.Reference{
+			Name:         rel.name,
+			FromTable:    from.table.name,
+			FromSchema:   from.table.schema,
+			FromColumns:  from.columns,
+			ToTable:      to.table.name,
+			ToSchema:     to.table.schema,
+			ToColumns:    to.columns,
+			Relationship: relationship,
+			OnDelete:     referentialAction(rel.settings["delete"]),
+			OnUpdate:     referentialAction(rel.settings["update"]),
+		})
+	}
+
+	for _, t := range p.tables {
+		result.Tables = append(result.Tables, *t)
+	}
+	result.Enums = p.enums
+
+	for _, g := range p.groups {
+		tableGroup := schema.TableGroup{Name: g.name}
+		for _, parts := range g.tables {
+			name := p.resolveTable(parts)
+			if _, ok := byName[name]; !ok {
+				return nil, fmt.Errorf("line %d: unknown table %s in table group %s", g.line, qualified(name), g.name)
+			}
+			tableGroup.Tables = append(tableGroup.Tables, schema.TableName{Schema: name.schema, Name: name.name})
+		}
+		result.TableGroups = append(result.TableGroups, tableGroup)
+	}
+	return result, nil
+}
+
+func qualified(name tableName) string {
+	return name.schema + "." + name.name
+}
+
+// referentialAction converts a DBML action such as "set null" to the
+// upper-case form used by introspection.
+func referentialAction(value string) string {
+	if value == "" {
+		return "NO ACTION"
+	}
+	return strings.ToUpper(value)
+}
@@ -0,0 +1,439 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestParse(t *testing.T) {
+	input := `
+// Example schema
+Project demo {
+  database_type: 'PostgreSQL'
+}
+
+Table users as U [headercolor: #3498DB] {
+  id int [pk, increment]
+  email varchar(255) [not null, unique]
+  balance decimal(10, 2) [default: 0]
+  tags text[]
+  status "user status" [default: 'active', note: 'Account status']
+
+  indexes {
+    (email) [unique, name: 'idx_users_email']
+    ` + "`lower(email)`" + `
+  }
+
+  Note: 'Registered users'
+}
+
+Table auth.sessions {
+  id uuid [pk]
+  user_id int [not null, ref: > U.id]
+}
+
+Table posts {
+  id int [pk]
+  user_id int
+}
+
+Ref fk_posts_user: posts.user_id > users.id [delete: cascade, update: set null]
+`
+
+	s, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if len(s.Tables) != 3 {
+		t.Fatalf("Expected 3 tables, got %d", len(s.Tables))
+	}
+
+	users := s.Tables[0]
+	if users.Name != "users" || users.Schema != "public" {
+		t.Errorf("Unexpected table name: %s.%s", users.Schema, users.Name)
+	}
+	if len(users.Columns) != 5 {
+		t.Fatalf("Expected 5 columns, got %d", len(users.Columns))
+	}
+	if !users.Columns[0].IsPrimaryKey || !users.Columns[0].AutoIncrement {
+		t.Errorf("Expected id to be an auto-incrementing primary key, got %+v", users.Columns[0])
+	}
+	if users.Columns[1].Nullable {
+		t.Errorf("Expected email to be not null")
+	}
+	if users.Columns[2].Type != "decimal(10, 2)" || *users.Columns[2].DefaultValue != "0" {
+		t.Errorf("Unexpected balance column: %+v", users.Columns[2])
+	}
+	if users.Columns[3].Type != "text[]" {
+		t.Errorf("Expected array type text[], got %s", users.Columns[3].Type)
+	}
+	if users.Columns[4].Type != "user status" || *users.Columns[4].DefaultValue != "'active'" {
+		t.Errorf("Unexpected status column: %+v", users.Columns[4])
+	}
+	if users.Columns[4].Comment != "Account status" {
+		t.Errorf("Expected column note as comment, got %q", users.Columns[4].Comment)
+	}
+	if users.Comment != "Registered users" {
+		t.Errorf("Expected table note as comment, got %q", users.Comment)
+	}
+	if len(users.Indexes) != 2 || users.Indexes[0].Name != "idx_users_email" || !users.Indexes[0].Unique {
+		t.Errorf("Unexpected indexes: %+v", users.Indexes)
+	}
+
+	sessions := s.Tables[1]
+	if sessions.Schema != "auth" || len(sessions.References) != 1 {
+		t.Fatalf("Expected auth.sessions with one reference, got %+v", sessions)
+	}
+	if ref := sessions.References[0]; ref.ToTable != "users" || ref.ToSchema != "public" {
+		t.Errorf("Inline ref did not resolve alias: %+v", ref)
+	}
+
+	posts := s.Tables[2]
+	if len(posts.References) != 1 {
+		t.Fatalf("Expected one reference on posts, got %d", len(posts.References))
+	}
+	if ref := posts.References[0]; ref.OnDelete != "CASCADE" || ref.OnUpdate != "SET NULL" {
+		t.Errorf("Unexpected referential actions: %+v", ref)
+	}
+	if ref := posts.References[0]; ref.Name != "fk_posts_user" {
+		t.Errorf("Expected the Ref name as the constraint name, got %q", ref.Name)
+	}
+}
+
+func TestParseCompositeKeys(t *testing.T) {
+	input := `
+Table order_items {
+  order_id int
+  product_id int
+
+  indexes {
+    (order_id, product_id) [pk]
+  }
+}
+
+Table orders {
+  id int [pk]
+}
+
+Ref {
+  orders.id < order_items.order_id
+}
+`
+
+	s, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	items := s.Tables[0]
+	if len(items.PrimaryKeys) != 2 || !items.Columns[0].IsPrimaryKey || !items.Columns[1].IsPrimaryKey {
+		t.Errorf("Expected composite primary key, got %+v", items)
+	}
+	if len(items.Indexes) != 0 {
+		t.Errorf("Primary key index should not be kept as a regular index")
+	}
+	if len(items.References) != 1 || items.References[0].ToTable != "orders" {
+		t.Errorf("Expected '<' reference to be stored on order_items, got %+v", items.References)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unterminated table", "Table users {\n  id int\n"},
+		{"unknown ref table", "Table users {\n  id int\n}\nRef: posts.user_id > users.id\n"},
+		{"bad relationship", "Table users {\n  id int\n}\nRef: users.id = users.id\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseString(tt.input); err == nil {
+				t.Errorf("Expected error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	defaultVal := "now()"
+	original := &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:   "users",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "created_at", Type: "timestamp", DefaultValue: &defaultVal},
+					{Name: "id", Type: "int", IsPrimaryKey: true},
+				},
+				PrimaryKeys: []string{"id"},
+				Indexes: []schema.Index{
+					{Name: "idx", Columns: []string{"created_at"}, Include: []string{"id"}},
+					{Name: "idx_hash", Columns: []string{"id"}, Type: "hash"},
+					{Name: "idx_brin", Columns: []string{"created_at"}, Include: []string{"id"}, Type: "brin"},
+					{Name: "idx_recent", Columns: []string{"id", "created_at"}, Where: "(created_at > '2024-01-01'::date)", Tablespace: "fast"},
+					{Name: "users_id_created_at_key", Columns: []string{"id", "created_at"}, Unique: true, UniqueConstraint: true},
+				},
+			},
+			{
+				Name:    "posts",
+				Schema:  "blog",
+				Columns: []schema.Column{{Name: "user_id", Type: "int"}},
+				References: []schema.Reference{
+					{Name: "posts_user_id_fkey", FromTable: "posts", FromSchema: "blog", FromColumns: []string{"user_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "NO ACTION"},
+				},
+			},
+		},
+	}
+
+	first, err := generator.Generate(original)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	parsed, err := Parse(first)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v\n%s", err, first)
+	}
+
+	second, err := generator.Generate(parsed)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	if string(first) != string(second) {
+		t.Errorf("Round trip changed output:\n%s\n---\n%s", first, second)
+	}
+
+	if include := parsed.Tables[1].Indexes[0].Include; len(include) != 1 || include[0] != "id" {
+		t.Errorf("Expected INCLUDE columns to survive the round trip, got %v", include)
+	}
+	types := make(map[string]bool)
+	for _, index := range parsed.Tables[1].Indexes {
+		types[index.Type] = true
+		if index.Name == "users_id_created_at_key" && !index.UniqueConstraint {
+			t.Errorf("Expected the UNIQUE constraint to survive the round trip, got %+v", index)
+		}
+	}
+	where := false
+	for _, index := range parsed.Tables[1].Indexes {
+		where = where || (index.Where == "(created_at > '2024-01-01'::date)" && index.Tablespace == "fast")
+	}
+	if !where {
+		t.Errorf("Expected the partial index predicate and tablespace to survive the round trip, got %+v", parsed.Tables[1].Indexes)
+	}
+	if !types["hash"] || !types["brin"] {
+		t.Errorf("Expected index types to survive the round trip, got %+v", parsed.Tables[1].Indexes)
+	}
+}
+
+func TestRoundTripNoteBlock(t *testing.T) {
+	comment := "Blog posts\n\nDrafts have no published_at:\n  see publish()"
+	original := &schema.Schema{
+		Tables: []schema.Table{{
+			Name:    "posts",
+			Schema:  "public",
+			Comment: comment,
+			Columns: []schema.Column{{Name: "id", Type: "int"}},
+		}},
+	}
+
+	output, err := generator.Generate(original, generator.WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if !strings.Contains(string(output), "  Note {\n    '''\n    Blog posts\n\n") {
+		t.Errorf("Expected a Note block, got:\n%s", output)
+	}
+
+	parsed, err := Parse(output)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v\n%s", err, output)
+	}
+	if got := parsed.Tables[0].Comment; got != comment {
+		t.Errorf("Expected the comment to survive the round trip, got %q", got)
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	input := `Project shop {
+  database_type: 'PostgreSQL'
+}
+
+Table users [headercolor: #3498db] {
+  id int [pk, note: 'Account owner']
+  Note: 'Registered users'
+}
+
+Table orders {
+  id int [pk]
+}
+
+TableGroup commerce {
+  users
+  orders
+}
+`
+
+	doc, err := ParseDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseDocument returned error: %v", err)
+	}
+
+	if len(doc.Blocks) != 1 || !strings.HasPrefix(doc.Blocks[0], "Project shop {") || !strings.HasSuffix(doc.Blocks[0], "}") {
+		t.Errorf("Expected the Project block to be kept verbatim, got %q", doc.Blocks)
+	}
+
+	groups := doc.Schema.TableGroups
+	if len(groups) != 1 || groups[0].Name != "commerce" || len(groups[0].Tables) != 2 {
+		t.Fatalf("Expected one table group with two tables, got %+v", groups)
+	}
+	if groups[0].Tables[0] != (schema.TableName{Schema: "public", Name: "users"}) {
+		t.Errorf("Expected group table public.users, got %+v", groups[0].Tables[0])
+	}
+
+	formatted, err := generator.Generate(doc.Schema, generator.WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{"Note: 'Registered users'", "note: 'Account owner'", "TableGroup commerce {", "Table users [headercolor: #3498db] {"} {
+		if !strings.Contains(string(formatted), want) {
+			t.Errorf("Expected formatted output to contain %q:\n%s", want, formatted)
+		}
+	}
+
+	reparsed, err := Parse(formatted)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v\n%s", err, formatted)
+	}
+	again, err := generator.Generate(reparsed, generator.WithNotes())
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if string(formatted) != string(again) {
+		t.Errorf("Formatting is not idempotent:\n%s\n---\n%s", formatted, again)
+	}
+}
+
+func TestParseEnums(t *testing.T) {
+	input := `Enum status {
+  active [note: 'Visible']
+  "on hold"
+}
+
+Enum billing.plan {
+  free
+  paid
+}
+
+Table users {
+  state status
+}
+`
+
+	doc, err := ParseDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseDocument returned error: %v", err)
+	}
+
+	expected := []schema.Enum{
+		{Name: "status", Schema: "public", Values: []string{"active", "on hold"}},
+		{Name: "plan", Schema: "billing", Values: []string{"free", "paid"}},
+	}
+	if !reflect.DeepEqual(doc.Schema.Enums, expected) {
+		t.Errorf("Expected enums %+v, got %+v", expected, doc.Schema.Enums)
+	}
+	if len(doc.Blocks) != 2 || !strings.HasPrefix(doc.Blocks[0], "Enum status {") || !strings.HasSuffix(doc.Blocks[1], "}") {
+		t.Errorf("Expected the Enum blocks to be kept verbatim, got %q", doc.Blocks)
+	}
+	if len(doc.Dropped) != 0 {
+		t.Errorf("Expected nothing dropped, got %q", doc.Dropped)
+	}
+}
+
+func TestParseTableGroupUnknownTable(t *testing.T) {
+	if _, err := ParseString("Table users {\n  id int\n}\nTableGroup g {\n  posts\n}\n"); err == nil {
+		t.Error("Expected error for a table group naming an unknown table")
+	}
+}
+
+func TestParseRelationshipTypes(t *testing.T) {
+	input := `Table users {
+  id int [pk]
+}
+
+Table profiles {
+  user_id int [pk]
+}
+
+Ref: profiles.user_id - users.id
+Ref: users.id <> profiles.user_id
+Ref: users.id < profiles.user_id
+`
+
+	s, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	output, err := generator.GenerateString(s)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, want := range []string{
+		"Ref: profiles.user_id - users.id\n",
+		"Ref: users.id <> profiles.user_id\n",
+		"Ref: profiles.user_id > users.id\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestParseDocumentDropped(t *testing.T) {
+	input := `// Shop schema
+Enum status {
+  active // kept with the block
+}
+
+Table users [color: #fff] {
+  id int [pk, check: ` + "`id > 0`" + `]
+  email varchar [unique]
+}
+
+TableGroup people [color: #000] {
+  users
+  Note: 'Everyone'
+}
+
+Ref: users.id > users.id [color: #fff]
+`
+
+	doc, err := ParseDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseDocument returned error: %v", err)
+	}
+
+	expected := []string{
+		"line 1: comment // Shop schema",
+		"line 6: table users setting color",
+		"line 7: column users.id setting check",
+		"line 11: table group people setting color",
+		"line 13: table group people note",
+		"line 16: ref setting color",
+	}
+	if strings.Join(doc.Dropped, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected dropped:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(doc.Dropped, "\n"))
+	}
+
+	users := doc.Schema.Tables[0]
+	if len(users.Indexes) != 1 || !users.Indexes[0].Unique || users.Indexes[0].Columns[0] != "email" {
+		t.Errorf("Expected the unique setting as a unique index, got %+v", users.Indexes)
+	}
+}
@@ -0,0 +1,180 @@
+// Package pii flags columns that are likely to hold personal or otherwise
+// sensitive data.
+//
+// Detection is a heuristic over column names: each Pattern pairs a category
+// such as "email" or "ssn" with a regular expression. The first pattern that
+// matches a column decides its category. Findings can be written as a JSON
+// report or tagged onto column comments so they appear in generated output.
+//
+// Basic usage:
+//
+//	detector, err := pii.NewDetector(pii.DefaultPatterns()...)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	findings := detector.Detect(s)
+//	tagged := pii.Tag(s, findings)
+package pii
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Pattern maps column names to a PII category.
+type Pattern struct {
+	// Category names the kind of data, such as "email" or "phone".
+	Category string `json:"category"`
+	// Pattern is a regular expression matched against column names,
+	// case-insensitively.
+	Pattern string `json:"pattern"`
+}
+
+// DefaultPatterns returns the built-in patterns. More specific categories
+// come first, so "email_address" is an email rather than an address and
+// "ip_address" is not a postal address.
+func DefaultPatterns() []Pattern {
+	return []Pattern{
+		{Category: "email", Pattern: `e_?mail`},
+		{Category: "ip_address", Pattern: `(^|_)ip(_?addr(ess)?)?($|_)|remote_addr`},
+		{Category: "ssn", Pattern: `(^|_)ssn($|_)|social_?security|national_?id|(^|_)tax_?id($|_)`},
+		{Category: "phone", Pattern: `phone|mobile_?(number|no)|(^|_)fax($|_)`},
+		{Category: "date_of_birth", Pattern: `(^|_)dob($|_)|birth_?(date|day)|date_?of_?birth|birthday`},
+		{Category: "address", Pattern: `address|street|(^|_)city($|_)|zip_?code|postal_?code|postcode`},
+		{Category: "name", Pattern: `^(first|last|middle|full|given|family|legal)_?name$|^surname$`},
+		{Category: "payment_card", Pattern: `card_?number|credit_?card|(^|_)cvv($|_)|(^|_)pan($|_)`},
+		{Category: "passport", Pattern: `passport`},
+		{Category: "credential", Pattern: `password|passwd|secret|api_?key|(^|_)token($|_)`},
+	}
+}
+
+// LoadPatterns reads a JSON array of patterns from a file.
+func LoadPatterns(filename string) ([]Pattern, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PII patterns: %w", err)
+	}
+
+	var patterns []Pattern
+	if err := json.Unmarshal(data, &patterns); err != nil {
+		return nil, fmt.Errorf("failed to parse PII patterns %s: %w", filename, err)
+	}
+	return patterns, nil
+}
+
+// Finding is a column flagged as likely PII.
+type Finding struct {
+	Schema   string `json:"schema"`
+	Table    string `json:"table"`
+	Column   string `json:"column"`
+	Category string `json:"category"`
+}
+
+// Detector matches columns against patterns.
+type Detector struct {
+	categories []string
+	patterns   []*regexp.Regexp
+}
+
+// NewDetector compiles the patterns. Patterns are tried in order.
+func NewDetector(patterns ...Pattern) (*Detector, error) {
+	d := &Detector{}
+	for _, p := range patterns {
+		re, err := regexp.Compile("(?i)" + p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for category %s: %w", p.Category, err)
+		}
+		d.categories = append(d.categories, p.Category)
+		d.patterns = append(d.patterns, re)
+	}
+	return d, nil
+}
+
+// Category returns the category of the first pattern matching the column
+// name, or "" if none match.
+func (d *Detector) Category(columnName string) string {
+	for i, re := range d.patterns {
+		if re.MatchString(columnName) {
+			return d.categories[i]
+		}
+	}
+	return ""
+}
+
+// Detect returns a finding for every column whose name matches a pattern.
+// Foreign key columns are skipped: they hold references, and the referenced
+// column is flagged instead.
+func (d *Detector) Detect(s *schema.Schema) []Finding {
+	var findings []Finding
+	for _, table := range s.Tables {
+		foreignKeys := make(map[string]bool)
+		for _, ref := range table.References {
+			for _, column := range ref.FromColumns {
+				foreignKeys[column] = true
+			}
+		}
+
+		for _, column := range table.Columns {
+			if foreignKeys[column.Name] {
+				continue
+			}
+			if category := d.Category(column.Name); category != "" {
+				findings = append(findings, Finding{
+					Schema:   table.Schema,
+					Table:    table.Name,
+					Column:   column.Name,
+					Category: category,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// Tag returns a copy of the schema whose flagged columns have "PII: category"
+// appended to their comments.
+func Tag(s *schema.Schema, findings []Finding) *schema.Schema {
+	categories := make(map[string]string)
+	for _, f := range findings {
+		categories[f.Schema+"."+f.Table+"."+f.Column] = f.Category
+	}
+
+	result := *s
+	result.Tables = make([]schema.Table, len(s.Tables))
+	for i, table := range s.Tables {
+		columns := make([]schema.Column, len(table.Columns))
+		copy(columns, table.Columns)
+		for j := range columns {
+			category, ok := categories[table.Schema+"."+table.Name+"."+columns[j].Name]
+			if !ok {
+				continue
+			}
+			tag := "PII: " + category
+			if columns[j].Comment == "" {
+				columns[j].Comment = tag
+			} else if !strings.Contains(columns[j].Comment, tag) {
+				columns[j].Comment += "\n" + tag
+			}
+		}
+		table.Columns = columns
+		result.Tables[i] = table
+	}
+	return &result
+}
+
+// Report renders findings as an indented JSON array.
+func Report(findings []Finding) ([]byte, error) {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	data, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PII report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,149 @@
+package pii
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestCategory(t *testing.T) {
+	detector, err := NewDetector(DefaultPatterns()...)
+	if err != nil {
+		t.Fatalf("NewDetector returned error: %v", err)
+	}
+
+	tests := []struct {
+		column   string
+		expected string
+	}{
+		{"email", "email"},
+		{"email_address", "email"},
+		{"ip_address", "ip_address"},
+		{"last_login_ip", "ip_address"},
+		{"billing_address", "address"},
+		{"zip_code", "address"},
+		{"ssn", "ssn"},
+		{"phone_number", "phone"},
+		{"dob", "date_of_birth"},
+		{"birth_date", "date_of_birth"},
+		{"first_name", "name"},
+		{"password_hash", "credential"},
+		{"api_key", "credential"},
+		{"id", ""},
+		{"created_at", ""},
+		{"description", ""},
+		{"zip", ""},
+		{"company_name", ""},
+		{"tokens_used", ""},
+	}
+
+	for _, tt := range tests {
+		if result := detector.Category(tt.column); result != tt.expected {
+			t.Errorf("Category(%s) = %q, want %q", tt.column, result, tt.expected)
+		}
+	}
+}
+
+func testSchema() *schema.Schema {
+	return &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:   "users",
+				Schema: "public",
+				Columns: []schema.Column{
+					{Name: "id", Type: "int"},
+					{Name: "email", Type: "varchar(255)", Comment: "Login address"},
+					{Name: "phone", Type: "varchar(20)"},
+				},
+			},
+			{
+				Name:    "orders",
+				Schema:  "public",
+				Columns: []schema.Column{{Name: "email", Type: "int"}},
+				References: []schema.Reference{
+					{FromTable: "orders", FromSchema: "public", FromColumns: []string{"email"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"email"}},
+				},
+			},
+		},
+	}
+}
+
+func TestDetectAndTag(t *testing.T) {
+	detector, err := NewDetector(DefaultPatterns()...)
+	if err != nil {
+		t.Fatalf("NewDetector returned error: %v", err)
+	}
+
+	s := testSchema()
+	findings := detector.Detect(s)
+
+	expected := []Finding{
+		{Schema: "public", Table: "users", Column: "email", Category: "email"},
+		{Schema: "public", Table: "users", Column: "phone", Category: "phone"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %v", len(expected), findings)
+	}
+	for i := range expected {
+		if findings[i] != expected[i] {
+			t.Errorf("Finding %d = %+v, want %+v", i, findings[i], expected[i])
+		}
+	}
+
+	tagged := Tag(s, findings)
+	if got := tagged.Tables[0].Columns[1].Comment; got != "Login address\nPII: email" {
+		t.Errorf("Unexpected email comment: %q", got)
+	}
+	if got := tagged.Tables[0].Columns[2].Comment; got != "PII: phone" {
+		t.Errorf("Unexpected phone comment: %q", got)
+	}
+	if s.Tables[0].Columns[1].Comment != "Login address" {
+		t.Errorf("Tag modified the original schema")
+	}
+}
+
+func TestLoadPatterns(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "patterns.json")
+	if err := os.WriteFile(filename, []byte(`[{"category": "employee_id", "pattern": "^emp_no$"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	patterns, err := LoadPatterns(filename)
+	if err != nil {
+		t.Fatalf("LoadPatterns returned error: %v", err)
+	}
+
+	detector, err := NewDetector(patterns...)
+	if err != nil {
+		t.Fatalf("NewDetector returned error: %v", err)
+	}
+	if detector.Category("EMP_NO") != "employee_id" {
+		t.Errorf("Expected custom pattern to match case-insensitively")
+	}
+
+	if _, err := NewDetector(Pattern{Category: "bad", Pattern: "("}); err == nil {
+		t.Errorf("Expected error for invalid pattern")
+	}
+}
+
+func TestReport(t *testing.T) {
+	data, err := Report(nil)
+	if err != nil {
+		t.Fatalf("Report returned error: %v", err)
+	}
+	if string(data) != "[]\n" {
+		t.Errorf("Expected empty array, got %q", data)
+	}
+
+	data, err = Report([]Finding{{Schema: "public", Table: "users", Column: "email", Category: "email"}})
+	if err != nil {
+		t.Fatalf("Report returned error: %v", err)
+	}
+	var decoded []Finding
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded) != 1 {
+		t.Errorf("Expected one decoded finding, got %v (%v)", decoded, err)
+	}
+}
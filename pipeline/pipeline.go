@@ -0,0 +1,320 @@
+// Package pipeline runs user-supplied middleware around generation.
+//
+// Schema middleware runs after introspection and may change the schema (for
+// example to redact tables); output middleware runs after rendering and may
+// change the bytes (for example to add a license header). Middleware runs in
+// the order it was added.
+//
+// Basic usage:
+//
+//	p := pipeline.New(
+//	    pipeline.WithSchemaMiddleware(pipeline.RedactTables("secrets", "audit_*")),
+//	    pipeline.WithOutputMiddleware(pipeline.Header("// Copyright Example Corp.\n")),
+//	)
+//	content, err := p.Render(s, "dbml")
+//
+// Middleware can also live in a separate executable; see Plugin.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/lucasefe/dbml/render"
+	"github.com/lucasefe/dbml/schema"
+	"github.com/lucasefe/dbml/sink"
+)
+
+// SchemaMiddleware transforms a schema after introspection.
+type SchemaMiddleware func(s *schema.Schema) (*schema.Schema, error)
+
+// OutputMiddleware transforms rendered output. format is the name of the
+// format that produced content, such as "dbml" or "sql".
+type OutputMiddleware func(content []byte, format string) ([]byte, error)
+
+// Option configures a Pipeline.
+type Option func(*Pipeline)
+
+// WithSchemaMiddleware appends schema middleware to the pipeline.
+func WithSchemaMiddleware(m ...SchemaMiddleware) Option {
+	return func(p *Pipeline) {
+		p.schema = append(p.schema, m...)
+	}
+}
+
+// WithOutputMiddleware appends output middleware to the pipeline.
+func WithOutputMiddleware(m ...OutputMiddleware) Option {
+	return func(p *Pipeline) {
+		p.output = append(p.output, m...)
+	}
+}
+
+// Pipeline holds the middleware applied around rendering.
+type Pipeline struct {
+	schema []SchemaMiddleware
+	output []OutputMiddleware
+}
+
+// New creates a Pipeline.
+func New(opts ...Option) *Pipeline {
+	p := &Pipeline{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Schema runs the schema middleware over s.
+func (p *Pipeline) Schema(s *schema.Schema) (*schema.Schema, error) {
+	for i, m := range p.schema {
+		result, err := m(s)
+		if err != nil {
+			return nil, fmt.Errorf("schema middleware %d failed: %w", i+1, err)
+		}
+		if result == nil {
+			return nil, fmt.Errorf("schema middleware %d returned no schema", i+1)
+		}
+		s = result
+	}
+	return s, nil
+}
+
+// Output runs the output middleware over content rendered as format.
+func (p *Pipeline) Output(content []byte, format string) ([]byte, error) {
+	for i, m := range p.output {
+		result, err := m(content, format)
+		if err != nil {
+			return nil, fmt.Errorf("output middleware %d failed: %w", i+1, err)
+		}
+		content = result
+	}
+	return content, nil
+}
+
+// Render runs the schema middleware, renders the result with the format
+// registered in package render, and runs the output middleware.
+func (p *Pipeline) Render(s *schema.Schema, format string) ([]byte, error) {
+	s, err := p.Schema(s)
+	if err != nil {
+		return nil, err
+	}
+	content, err := render.Bytes(s, format)
+	if err != nil {
+		return nil, err
+	}
+	return p.Output(content, format)
+}
+
+// Write renders s like Render and writes the result to dst under the
+// format's conventional file name (see sink.FileName).
+func (p *Pipeline) Write(ctx context.Context, dst sink.Sink, s *schema.Schema, format string) error {
+	content, err := p.Render(s, format)
+	if err != nil {
+		return err
+	}
+	return dst.Write(ctx, sink.FileName(format), content)
+}
+
+// RedactTables returns schema middleware that removes tables whose name or
+// schema-qualified name matches one of the patterns (path.Match syntax), along
+// with foreign keys that reference them and their table group entries.
+func RedactTables(patterns ...string) SchemaMiddleware {
+	return func(s *schema.Schema) (*schema.Schema, error) {
+		redacted := make(map[string]bool)
+		for _, table := range s.Tables {
+			for _, pattern := range patterns {
+				nameMatch, err := path.Match(pattern, table.Name)
+				if err != nil {
+					return nil, fmt.Errorf("invalid table pattern %q: %w", pattern, err)
+				}
+				qualifiedMatch, _ := path.Match(pattern, table.Schema+"."+table.Name)
+				if nameMatch || qualifiedMatch {
+					redacted[table.Schema+"."+table.Name] = true
+				}
+			}
+		}
+
+		result := *s
+		result.Tables = nil
+		for _, table := range s.Tables {
+			if redacted[table.Schema+"."+table.Name] {
+				continue
+			}
+			var refs []schema.Reference
+			for _, ref := range table.References {
+				if !redacted[ref.ToSchema+"."+ref.ToTable] {
+					refs = append(refs, ref)
+				}
+			}
+			table.References = refs
+			result.Tables = append(result.Tables, table)
+		}
+
+		result.TableGroups = nil
+		for _, group := range s.TableGroups {
+			var tables []schema.TableName
+			for _, t := range group.Tables {
+				if !redacted[t.Schema+"."+t.Name] {
+					tables = append(tables, t)
+				}
+			}
+			if len(tables) > 0 {
+				group.Tables = tables
+				result.TableGroups = append(result.TableGroups, group)
+			}
+		}
+		return &result, nil
+	}
+}
+
+// OmitDefaults returns schema middleware that removes the default values of
+// columns whose name, table-qualified name, or default expression matches one
+// of the patterns (path.Match syntax), such as "created_at", "*.updated_at",
+// or "now()". Defaults such as timestamps clutter every table of a diagram
+// while saying little about the design.
+func OmitDefaults(patterns ...string) SchemaMiddleware {
+	return func(s *schema.Schema) (*schema.Schema, error) {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid default pattern %q: %w", pattern, err)
+			}
+		}
+
+		result := *s
+		result.Tables = make([]schema.Table, len(s.Tables))
+		for i, table := range s.Tables {
+			columns := make([]schema.Column, len(table.Columns))
+			for j, column := range table.Columns {
+				if column.DefaultValue != nil && matchesDefault(patterns, table.Name, column) {
+					column.DefaultValue = nil
+				}
+				columns[j] = column
+			}
+			table.Columns = columns
+			result.Tables[i] = table
+		}
+		return &result, nil
+	}
+}
+
+func matchesDefault(patterns []string, tableName string, column schema.Column) bool {
+	for _, pattern := range patterns {
+		for _, name := range []string{column.Name, tableName + "." + column.Name, *column.DefaultValue} {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RenameSchemas returns schema middleware that renames database schemas in
+// the output, such as tenant_template to tenant, without touching the
+// database. aliases maps original names to new ones; schemas not in it keep
+// their names. Tables, references, inheritance, views, enums, sequences, and
+// table groups are renamed consistently.
+func RenameSchemas(aliases map[string]string) SchemaMiddleware {
+	rename := func(name string) string {
+		if alias, ok := aliases[name]; ok {
+			return alias
+		}
+		return name
+	}
+
+	return func(s *schema.Schema) (*schema.Schema, error) {
+		result := *s
+
+		result.Tables = make([]schema.Table, len(s.Tables))
+		for i, table := range s.Tables {
+			table.Schema = rename(table.Schema)
+			refs := make([]schema.Reference, len(table.References))
+			for j, ref := range table.References {
+				ref.FromSchema = rename(ref.FromSchema)
+				ref.ToSchema = rename(ref.ToSchema)
+				refs[j] = ref
+			}
+			table.References = refs
+			partitions := make([]schema.Partition, len(table.Partitions))
+			for j, partition := range table.Partitions {
+				partition.Schema = rename(partition.Schema)
+				partitions[j] = partition
+			}
+			table.Partitions = partitions
+			if table.Inherits != nil {
+				parents := make([]schema.TableName, len(table.Inherits))
+				for j, parent := range table.Inherits {
+					parents[j] = schema.TableName{Schema: rename(parent.Schema), Name: parent.Name}
+				}
+				table.Inherits = parents
+			}
+			result.Tables[i] = table
+		}
+
+		result.Views = make([]schema.View, len(s.Views))
+		for i, view := range s.Views {
+			view.Schema = rename(view.Schema)
+			sources := make([]schema.ViewSource, len(view.Sources))
+			for j, source := range view.Sources {
+				source.Schema = rename(source.Schema)
+				sources[j] = source
+			}
+			view.Sources = sources
+			result.Views[i] = view
+		}
+
+		result.Enums = make([]schema.Enum, len(s.Enums))
+		for i, enum := range s.Enums {
+			enum.Schema = rename(enum.Schema)
+			result.Enums[i] = enum
+		}
+
+		result.Sequences = make([]schema.Sequence, len(s.Sequences))
+		for i, seq := range s.Sequences {
+			seq.Schema = rename(seq.Schema)
+			result.Sequences[i] = seq
+		}
+
+		result.Domains = make([]schema.Domain, len(s.Domains))
+		for i, domain := range s.Domains {
+			domain.Schema = rename(domain.Schema)
+			result.Domains[i] = domain
+		}
+
+		result.Ranges = make([]schema.Range, len(s.Ranges))
+		for i, r := range s.Ranges {
+			r.Schema = rename(r.Schema)
+			result.Ranges[i] = r
+		}
+
+		result.Functions = make([]schema.Function, len(s.Functions))
+		for i, function := range s.Functions {
+			function.Schema = rename(function.Schema)
+			result.Functions[i] = function
+		}
+
+		result.TableGroups = make([]schema.TableGroup, len(s.TableGroups))
+		for i, group := range s.TableGroups {
+			tables := make([]schema.TableName, len(group.Tables))
+			for j, t := range group.Tables {
+				tables[j] = schema.TableName{Schema: rename(t.Schema), Name: t.Name}
+			}
+			group.Tables = tables
+			result.TableGroups[i] = group
+		}
+		return &result, nil
+	}
+}
+
+// Header returns output middleware that prepends header to the output of
+// every format. The header is written verbatim, so it should use comment
+// syntax valid for the formats it is applied to.
+func Header(header string) OutputMiddleware {
+	if header != "" && !strings.HasSuffix(header, "\n") {
+		header += "\n"
+	}
+	return func(content []byte, format string) ([]byte, error) {
+		return append([]byte(header), content...), nil
+	}
+}
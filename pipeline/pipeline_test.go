@@ -0,0 +1,216 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+	"github.com/lucasefe/dbml/sink"
+)
+
+func testSchema() *schema.Schema {
+	return &schema.Schema{Tables: []schema.Table{
+		{
+			Name:   "users",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "id", Type: "int"},
+				{Name: "secret_id", Type: "int"},
+			},
+			References: []schema.Reference{{
+				FromTable: "users", FromSchema: "public", FromColumns: []string{"secret_id"},
+				ToTable: "secrets", ToSchema: "vault", ToColumns: []string{"id"},
+			}},
+		},
+		{Name: "secrets", Schema: "vault", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+		{Name: "audit_log", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}},
+	}}
+}
+
+func TestRender(t *testing.T) {
+	p := New(
+		WithSchemaMiddleware(RedactTables("vault.*", "audit_*")),
+		WithOutputMiddleware(Header("// Copyright Example Corp.")),
+	)
+
+	content, err := p.Render(testSchema(), "dbml")
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	result := string(content)
+	if !strings.HasPrefix(result, "// Copyright Example Corp.\n") {
+		t.Errorf("Expected header first, got:\n%s", result)
+	}
+	if !strings.Contains(result, "Table users") {
+		t.Errorf("Expected users table, got:\n%s", result)
+	}
+	for _, unexpected := range []string{"secrets", "audit_log", "Ref"} {
+		if strings.Contains(result, unexpected) {
+			t.Errorf("Expected %q to be redacted, got:\n%s", unexpected, result)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	p := New(WithOutputMiddleware(Header("-- generated")))
+
+	if err := p.Write(context.Background(), sink.Directory(dir), testSchema(), "sqlc"); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "schema.sql"))
+	if err != nil {
+		t.Fatalf("Expected schema.sql to be written: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "-- generated") {
+		t.Errorf("Expected output middleware to run, got:\n%s", content)
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	appendTag := func(tag string) OutputMiddleware {
+		return func(content []byte, format string) ([]byte, error) {
+			calls = append(calls, tag+":"+format)
+			return append(content, tag...), nil
+		}
+	}
+
+	p := New(WithOutputMiddleware(appendTag("a")), WithOutputMiddleware(appendTag("b")))
+	content, err := p.Output([]byte(">"), "sql")
+	if err != nil {
+		t.Fatalf("Output returned error: %v", err)
+	}
+
+	if string(content) != ">ab" {
+		t.Errorf("Expected middleware to run in order, got %q", content)
+	}
+	if strings.Join(calls, ",") != "a:sql,b:sql" {
+		t.Errorf("Unexpected calls: %v", calls)
+	}
+}
+
+func TestSchemaMiddlewareErrors(t *testing.T) {
+	failing := New(WithSchemaMiddleware(func(s *schema.Schema) (*schema.Schema, error) {
+		return nil, errors.New("boom")
+	}))
+	if _, err := failing.Schema(testSchema()); err == nil || !strings.Contains(err.Error(), "schema middleware 1 failed: boom") {
+		t.Errorf("Expected wrapped error, got %v", err)
+	}
+
+	empty := New(WithSchemaMiddleware(func(s *schema.Schema) (*schema.Schema, error) {
+		return nil, nil
+	}))
+	if _, err := empty.Schema(testSchema()); err == nil {
+		t.Error("Expected error when middleware returns no schema")
+	}
+}
+
+func TestRedactTablesGroups(t *testing.T) {
+	s := testSchema()
+	s.TableGroups = []schema.TableGroup{
+		{Name: "app", Tables: []schema.TableName{{Schema: "public", Name: "users"}, {Schema: "public", Name: "audit_log"}}},
+		{Name: "vault", Tables: []schema.TableName{{Schema: "vault", Name: "secrets"}}},
+	}
+
+	result, err := RedactTables("vault.*", "audit_*")(s)
+	if err != nil {
+		t.Fatalf("RedactTables returned error: %v", err)
+	}
+	if len(result.TableGroups) != 1 || len(result.TableGroups[0].Tables) != 1 || result.TableGroups[0].Tables[0].Name != "users" {
+		t.Errorf("Expected only app with users to remain, got %+v", result.TableGroups)
+	}
+}
+
+func TestRedactTablesInvalidPattern(t *testing.T) {
+	if _, err := RedactTables("[")(testSchema()); err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if _, err := New().Render(testSchema(), "nope"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestRenameSchemas(t *testing.T) {
+	s := testSchema()
+	s.TableGroups = []schema.TableGroup{{Name: "all", Tables: []schema.TableName{{Schema: "vault", Name: "secrets"}}}}
+	s.Views = []schema.View{{Name: "active_users", Schema: "public", Sources: []schema.ViewSource{{Schema: "public", Table: "users"}}}}
+
+	result, err := RenameSchemas(map[string]string{"public": "core"})(s)
+	if err != nil {
+		t.Fatalf("RenameSchemas returned error: %v", err)
+	}
+
+	if result.Tables[0].Schema != "core" || result.Tables[1].Schema != "vault" {
+		t.Errorf("Expected core and vault, got %s and %s", result.Tables[0].Schema, result.Tables[1].Schema)
+	}
+	if ref := result.Tables[0].References[0]; ref.FromSchema != "core" || ref.ToSchema != "vault" {
+		t.Errorf("Expected reference from core to vault, got %s to %s", ref.FromSchema, ref.ToSchema)
+	}
+	if result.Views[0].Schema != "core" || result.Views[0].Sources[0].Schema != "core" {
+		t.Errorf("Expected view and source in core, got %+v", result.Views[0])
+	}
+	if result.TableGroups[0].Tables[0].Schema != "vault" {
+		t.Errorf("Expected unaliased schema to be kept, got %+v", result.TableGroups[0])
+	}
+	if s.Tables[0].Schema != "public" || s.Tables[0].References[0].FromSchema != "public" {
+		t.Errorf("Original schema was modified")
+	}
+
+	content, err := New(WithSchemaMiddleware(RenameSchemas(map[string]string{"public": "core"}))).Render(testSchema(), "dbml")
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if !strings.Contains(string(content), "Ref: core.users.secret_id > vault.secrets.id") {
+		t.Errorf("Expected aliased reference, got:\n%s", content)
+	}
+}
+
+func TestOmitDefaults(t *testing.T) {
+	now, zero, uuid := "now()", "0", "gen_random_uuid()"
+	s := &schema.Schema{Tables: []schema.Table{
+		{
+			Name:   "orders",
+			Schema: "public",
+			Columns: []schema.Column{
+				{Name: "id", Type: "uuid", DefaultValue: &uuid},
+				{Name: "placed_at", Type: "timestamp", DefaultValue: &now},
+				{Name: "updated_at", Type: "timestamp"},
+				{Name: "total", Type: "int", DefaultValue: &zero},
+			},
+		},
+		{Name: "items", Schema: "public", Columns: []schema.Column{{Name: "quantity", Type: "int", DefaultValue: &zero}}},
+	}}
+
+	result, err := OmitDefaults("now()", "items.quantity")(s)
+	if err != nil {
+		t.Fatalf("OmitDefaults returned error: %v", err)
+	}
+
+	orders := result.Tables[0].Columns
+	if orders[0].DefaultValue == nil || orders[3].DefaultValue == nil {
+		t.Errorf("Expected unmatched defaults to be kept, got %+v", orders)
+	}
+	if orders[1].DefaultValue != nil {
+		t.Errorf("Expected the now() default to be omitted, got %q", *orders[1].DefaultValue)
+	}
+	if result.Tables[1].Columns[0].DefaultValue != nil {
+		t.Errorf("Expected the items.quantity default to be omitted")
+	}
+	if s.Tables[0].Columns[1].DefaultValue == nil {
+		t.Errorf("Original schema was modified")
+	}
+
+	if _, err := OmitDefaults("[")(s); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
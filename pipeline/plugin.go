@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/lucasefe/dbml/convert"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// PluginTimeout bounds how long a plugin may run for one stage.
+const PluginTimeout = 30 * time.Second
+
+// Plugin runs middleware as an external executable, so it can be written in
+// any language. The command is run once per stage with the stage name as its
+// last argument:
+//
+//   - "schema": stdin is the schema as JSON (the dbml json format), and stdout
+//     must be the transformed schema as JSON.
+//   - "output": stdin is the rendered output, and stdout is the transformed
+//     output. The format name is passed in the DBML_FORMAT environment
+//     variable.
+//
+// A non-zero exit status fails generation; anything written to stderr is
+// included in the error.
+type Plugin struct {
+	// Path is the executable to run.
+	Path string
+	// Args are passed before the stage name.
+	Args []string
+}
+
+// ParsePlugin splits a command line such as "./redact --strict" into a
+// Plugin. Arguments are separated by whitespace; quoting is not supported.
+func ParsePlugin(command string) (Plugin, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return Plugin{}, fmt.Errorf("empty plugin command")
+	}
+	return Plugin{Path: fields[0], Args: fields[1:]}, nil
+}
+
+// SchemaMiddleware returns middleware that runs the plugin's schema stage.
+func (p Plugin) SchemaMiddleware() SchemaMiddleware {
+	return func(s *schema.Schema) (*schema.Schema, error) {
+		input, err := convert.Encode(s, convert.JSON)
+		if err != nil {
+			return nil, err
+		}
+		output, err := p.run("schema", input, nil)
+		if err != nil {
+			return nil, err
+		}
+		result, err := convert.Decode(output, convert.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s returned an invalid schema: %w", p.Path, err)
+		}
+		return result, nil
+	}
+}
+
+// OutputMiddleware returns middleware that runs the plugin's output stage.
+func (p Plugin) OutputMiddleware() OutputMiddleware {
+	return func(content []byte, format string) ([]byte, error) {
+		return p.run("output", content, []string{"DBML_FORMAT=" + format})
+	}
+}
+
+func (p Plugin) run(stage string, input []byte, env []string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), PluginTimeout)
+	defer cancel()
+
+	args := append(append([]string(nil), p.Args...), stage)
+	cmd := exec.CommandContext(ctx, p.Path, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return nil, fmt.Errorf("plugin %s failed in %s stage: %w: %s", p.Path, stage, err, detail)
+		}
+		return nil, fmt.Errorf("plugin %s failed in %s stage: %w", p.Path, stage, err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// TestHelperPlugin is not a real test: it is the plugin executable used by
+// the tests below, run as a subprocess of the test binary.
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("DBML_HELPER_PLUGIN") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	input, _ := io.ReadAll(os.Stdin)
+	switch stage := os.Args[len(os.Args)-1]; stage {
+	case "schema":
+		var s schema.Schema
+		json.Unmarshal(input, &s)
+		for i := range s.Tables {
+			s.Tables[i].Comment = "checked"
+		}
+		json.NewEncoder(os.Stdout).Encode(s)
+	case "output":
+		fmt.Printf("-- %s\n%s", os.Getenv("DBML_FORMAT"), input)
+	default:
+		fmt.Fprintf(os.Stderr, "unsupported stage %s", stage)
+		os.Exit(2)
+	}
+}
+
+func helperPlugin(t *testing.T) Plugin {
+	t.Setenv("DBML_HELPER_PLUGIN", "1")
+	return Plugin{Path: os.Args[0], Args: []string{"-test.run=^TestHelperPlugin$", "--"}}
+}
+
+func TestPluginSchemaStage(t *testing.T) {
+	result, err := helperPlugin(t).SchemaMiddleware()(testSchema())
+	if err != nil {
+		t.Fatalf("SchemaMiddleware returned error: %v", err)
+	}
+	if len(result.Tables) != 3 || result.Tables[0].Comment != "checked" {
+		t.Errorf("Unexpected schema from plugin: %+v", result.Tables)
+	}
+}
+
+func TestPluginOutputStage(t *testing.T) {
+	result, err := helperPlugin(t).OutputMiddleware()([]byte("CREATE TABLE users ();\n"), "sql")
+	if err != nil {
+		t.Fatalf("OutputMiddleware returned error: %v", err)
+	}
+	if string(result) != "-- sql\nCREATE TABLE users ();\n" {
+		t.Errorf("Unexpected output from plugin: %q", result)
+	}
+}
+
+func TestPluginFailure(t *testing.T) {
+	plugin := helperPlugin(t)
+	_, err := plugin.run("bogus", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "unsupported stage bogus") {
+		t.Errorf("Expected error with stderr detail, got %v", err)
+	}
+}
+
+func TestParsePlugin(t *testing.T) {
+	plugin, err := ParsePlugin("  ./redact --strict  ")
+	if err != nil {
+		t.Fatalf("ParsePlugin returned error: %v", err)
+	}
+	if plugin.Path != "./redact" || len(plugin.Args) != 1 || plugin.Args[0] != "--strict" {
+		t.Errorf("Unexpected plugin: %+v", plugin)
+	}
+
+	if _, err := ParsePlugin("   "); err == nil {
+		t.Error("Expected error for empty command")
+	}
+}
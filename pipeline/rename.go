@@ -0,0 +1,142 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Renames maps physical table and column names to the names documentation
+// should show.
+type Renames struct {
+	// Tables maps "table" or "schema.table" to a new table name. Views can
+	// be renamed the same way.
+	Tables map[string]string `json:"tables"`
+	// Columns maps "table.column" or "schema.table.column" to a new column
+	// name. Keys use the original table name.
+	Columns map[string]string `json:"columns"`
+}
+
+// LoadRenames reads Renames from a JSON file such as:
+//
+//	{"tables": {"usr_tbl": "users"}, "columns": {"usr_tbl.fname": "first_name"}}
+func LoadRenames(filename string) (Renames, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Renames{}, fmt.Errorf("failed to read renames: %w", err)
+	}
+
+	var r Renames
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Renames{}, fmt.Errorf("failed to parse renames %s: %w", filename, err)
+	}
+	return r, nil
+}
+
+// Rename returns schema middleware that renames tables and columns in the
+// output without touching the database. Schema-qualified keys win over bare
+// ones. Primary keys, indexes, references, view sources, and table groups
+// follow the renames, so refs stay consistent.
+func Rename(r Renames) SchemaMiddleware {
+	return func(s *schema.Schema) (*schema.Schema, error) {
+		for key := range r.Columns {
+			if !strings.Contains(key, ".") {
+				return nil, fmt.Errorf("invalid column rename %q (expected table.column)", key)
+			}
+		}
+
+		table := func(schemaName, name string) string {
+			if renamed, ok := r.Tables[schemaName+"."+name]; ok {
+				return renamed
+			}
+			if renamed, ok := r.Tables[name]; ok {
+				return renamed
+			}
+			return name
+		}
+		column := func(schemaName, tableName, name string) string {
+			if renamed, ok := r.Columns[schemaName+"."+tableName+"."+name]; ok {
+				return renamed
+			}
+			if renamed, ok := r.Columns[tableName+"."+name]; ok {
+				return renamed
+			}
+			return name
+		}
+		columns := func(schemaName, tableName string, names []string) []string {
+			if names == nil {
+				return nil
+			}
+			renamed := make([]string, len(names))
+			for i, name := range names {
+				renamed[i] = column(schemaName, tableName, name)
+			}
+			return renamed
+		}
+		columnList := func(schemaName, tableName string, list []schema.Column) []schema.Column {
+			renamed := make([]schema.Column, len(list))
+			for i, c := range list {
+				c.Name = column(schemaName, tableName, c.Name)
+				renamed[i] = c
+			}
+			return renamed
+		}
+
+		result := *s
+
+		result.Tables = make([]schema.Table, len(s.Tables))
+		for i, t := range s.Tables {
+			t.Columns = columnList(t.Schema, t.Name, t.Columns)
+			t.PrimaryKeys = columns(t.Schema, t.Name, t.PrimaryKeys)
+
+			indexes := make([]schema.Index, len(t.Indexes))
+			for j, index := range t.Indexes {
+				index.Columns = columns(t.Schema, t.Name, index.Columns)
+				index.Include = columns(t.Schema, t.Name, index.Include)
+				indexes[j] = index
+			}
+			t.Indexes = indexes
+
+			refs := make([]schema.Reference, len(t.References))
+			for j, ref := range t.References {
+				ref.FromColumns = columns(ref.FromSchema, ref.FromTable, ref.FromColumns)
+				ref.ToColumns = columns(ref.ToSchema, ref.ToTable, ref.ToColumns)
+				ref.FromTable = table(ref.FromSchema, ref.FromTable)
+				ref.ToTable = table(ref.ToSchema, ref.ToTable)
+				refs[j] = ref
+			}
+			t.References = refs
+
+			t.Name = table(t.Schema, t.Name)
+			result.Tables[i] = t
+		}
+
+		result.Views = make([]schema.View, len(s.Views))
+		for i, v := range s.Views {
+			v.Columns = columnList(v.Schema, v.Name, v.Columns)
+			sources := make([]schema.ViewSource, len(v.Sources))
+			for j, source := range v.Sources {
+				source.Columns = columns(source.Schema, source.Table, source.Columns)
+				source.Table = table(source.Schema, source.Table)
+				sources[j] = source
+			}
+			v.Sources = sources
+			v.Name = table(v.Schema, v.Name)
+			result.Views[i] = v
+		}
+
+		result.TableGroups = make([]schema.TableGroup, len(s.TableGroups))
+		for i, group := range s.TableGroups {
+			tables := make([]schema.TableName, len(group.Tables))
+			for j, t := range group.Tables {
+				tables[j] = schema.TableName{Schema: t.Schema, Name: table(t.Schema, t.Name)}
+			}
+			group.Tables = tables
+			result.TableGroups[i] = group
+		}
+		return &result, nil
+	}
+}
@@ -0,0 +1,75 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestRename(t *testing.T) {
+	s := testSchema()
+	s.Tables[0].PrimaryKeys = []string{"id"}
+	s.Tables[0].Indexes = []schema.Index{{Name: "users_secret_idx", Columns: []string{"secret_id"}}}
+	s.TableGroups = []schema.TableGroup{{Name: "all", Tables: []schema.TableName{{Schema: "vault", Name: "secrets"}}}}
+
+	r := Renames{
+		Tables:  map[string]string{"vault.secrets": "credentials", "secrets": "ignored"},
+		Columns: map[string]string{"users.secret_id": "credential_id", "vault.secrets.id": "credential_pk"},
+	}
+	result, err := Rename(r)(s)
+	if err != nil {
+		t.Fatalf("Rename returned error: %v", err)
+	}
+
+	if result.Tables[1].Name != "credentials" {
+		t.Errorf("Expected the schema-qualified rename to win, got %s", result.Tables[1].Name)
+	}
+	if result.Tables[0].Columns[1].Name != "credential_id" || result.Tables[0].Indexes[0].Columns[0] != "credential_id" {
+		t.Errorf("Expected secret_id to be renamed in columns and indexes, got %+v", result.Tables[0])
+	}
+	if result.Tables[1].Columns[0].Name != "credential_pk" {
+		t.Errorf("Expected secrets.id to be renamed, got %s", result.Tables[1].Columns[0].Name)
+	}
+	if result.TableGroups[0].Tables[0].Name != "credentials" {
+		t.Errorf("Expected the table group to follow the rename, got %+v", result.TableGroups[0])
+	}
+	if s.Tables[1].Name != "secrets" || s.Tables[0].Columns[1].Name != "secret_id" {
+		t.Errorf("Original schema was modified")
+	}
+
+	content, err := New(WithSchemaMiddleware(Rename(r))).Render(testSchema(), "dbml")
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if !strings.Contains(string(content), "Ref: users.credential_id > vault.credentials.credential_pk") {
+		t.Errorf("Expected renamed reference, got:\n%s", content)
+	}
+}
+
+func TestRenameInvalidColumnKey(t *testing.T) {
+	if _, err := Rename(Renames{Columns: map[string]string{"fname": "first_name"}})(testSchema()); err == nil {
+		t.Error("Expected error for column key without a table")
+	}
+}
+
+func TestLoadRenames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "renames.json")
+	if err := os.WriteFile(path, []byte(`{"tables": {"usr_tbl": "users"}, "columns": {"usr_tbl.fname": "first_name"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := LoadRenames(path)
+	if err != nil {
+		t.Fatalf("LoadRenames returned error: %v", err)
+	}
+	if r.Tables["usr_tbl"] != "users" || r.Columns["usr_tbl.fname"] != "first_name" {
+		t.Errorf("Unexpected renames %+v", r)
+	}
+
+	if _, err := LoadRenames(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,43 @@
+package preview
+
+import "html/template"
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>dbml preview</title>
+<style>
+  body { margin: 0; font-family: system-ui, sans-serif; color: #0f172a; display: flex; height: 100vh; }
+  main { flex: 1; overflow: auto; background: #f8fafc; }
+  aside { width: 32rem; max-width: 40vw; display: flex; flex-direction: column; border-left: 1px solid #cbd5e1; }
+  header { padding: 0.5rem 1rem; border-bottom: 1px solid #cbd5e1; font-size: 0.875rem; display: flex; justify-content: space-between; }
+  pre { flex: 1; margin: 0; padding: 1rem; overflow: auto; font-size: 12px; background: #ffffff; }
+  #status { color: #64748b; }
+  .ref:hover { stroke: #1d4ed8; stroke-width: 3; }
+</style>
+</head>
+<body>
+<main>{{.Diagram}}</main>
+<aside>
+  <header><strong>{{.Tables}} tables</strong><span id="status">live</span></header>
+  <pre>{{.DBML}}</pre>
+</aside>
+<script>
+  const fingerprint = {{.Fingerprint}};
+  const status = document.getElementById("status");
+  setInterval(async () => {
+    try {
+      const response = await fetch("/fingerprint", {cache: "no-store"});
+      if (!response.ok) throw new Error(response.statusText);
+      const current = await response.text();
+      if (current !== fingerprint) location.reload();
+      status.textContent = "live";
+    } catch (err) {
+      status.textContent = "disconnected";
+    }
+  }, {{.PollMillis}});
+</script>
+</body>
+</html>
+`))
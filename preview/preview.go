@@ -0,0 +1,201 @@
+// Package preview serves a live diagram of a database schema for local
+// exploration.
+//
+// The page shows the schema as an SVG diagram next to its DBML source and
+// polls the server for changes, reloading when the schema's fingerprint
+// changes. Everything is rendered locally; nothing is sent to third-party
+// services.
+//
+// Basic usage:
+//
+//	p := preview.New(db, preview.WithRefreshInterval(5*time.Second))
+//	log.Fatal(http.ListenAndServe("localhost:8081", p.Handler()))
+//
+// The server provides the following endpoints:
+//
+//   - GET /             - the preview page
+//   - GET /diagram.svg  - the diagram on its own
+//   - GET /schema.dbml  - the DBML source
+//   - GET /fingerprint  - the current schema fingerprint, polled by the page
+package preview
+
+import (
+	"bytes"
+	"database/sql"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lucasefe/dbml/diagram"
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/introspect"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// DefaultRefreshInterval is how often the schema is re-introspected while
+// the page is open, unless WithRefreshInterval is used.
+const DefaultRefreshInterval = 5 * time.Second
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithRefreshInterval sets how often the database is re-introspected while
+// a page is polling for changes.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.interval = interval
+	}
+}
+
+// WithIntrospectOptions sets the options used for every introspection.
+func WithIntrospectOptions(opts ...introspect.Option) Option {
+	return func(s *Server) {
+		s.introspectOptions = opts
+	}
+}
+
+// WithGeneratorOptions sets the options used to generate the DBML source.
+func WithGeneratorOptions(opts ...generator.Option) Option {
+	return func(s *Server) {
+		s.generatorOptions = opts
+	}
+}
+
+// Server serves the preview page for a database.
+type Server struct {
+	db                *sql.DB
+	interval          time.Duration
+	introspectOptions []introspect.Option
+	generatorOptions  []generator.Option
+
+	mu       sync.Mutex
+	cached   *schema.Schema
+	cachedAt time.Time
+}
+
+// New creates a Server that introspects db.
+func New(db *sql.DB, opts ...Option) *Server {
+	s := &Server{db: db, interval: DefaultRefreshInterval}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Handler returns the HTTP handler serving all endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handlePage)
+	mux.HandleFunc("/diagram.svg", s.handleDiagram)
+	mux.HandleFunc("/schema.dbml", s.handleDBML)
+	mux.HandleFunc("/fingerprint", s.handleFingerprint)
+	return mux
+}
+
+// ListenAndServe serves the handler on addr.
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// Schema returns the current schema, introspecting the database when the
+// cached copy is older than the refresh interval.
+func (s *Server) Schema() (*schema.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.cachedAt) < s.interval {
+		return s.cached, nil
+	}
+
+	result, err := introspect.Database(s.db, s.introspectOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cached = result
+	s.cachedAt = time.Now()
+	return result, nil
+}
+
+type pageData struct {
+	Diagram     template.HTML
+	DBML        string
+	Fingerprint string
+	Tables      int
+	PollMillis  int64
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	result, err := s.Schema()
+	if err != nil {
+		http.Error(w, "failed to introspect database: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	source, err := generator.Generate(result, s.generatorOptions...)
+	if err != nil {
+		http.Error(w, "failed to generate DBML: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var body bytes.Buffer
+	err = page.Execute(&body, pageData{
+		// The diagram escapes all schema text, so it is safe to inline
+		Diagram:     template.HTML(diagram.SVG(result)),
+		DBML:        string(source),
+		Fingerprint: result.Fingerprint(),
+		Tables:      len(result.Tables),
+		PollMillis:  s.interval.Milliseconds(),
+	})
+	if err != nil {
+		http.Error(w, "failed to render page: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(body.Bytes())
+}
+
+func (s *Server) handleDiagram(w http.ResponseWriter, r *http.Request) {
+	result, err := s.Schema()
+	if err != nil {
+		http.Error(w, "failed to introspect database: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write(diagram.SVG(result))
+}
+
+func (s *Server) handleDBML(w http.ResponseWriter, r *http.Request) {
+	result, err := s.Schema()
+	if err != nil {
+		http.Error(w, "failed to introspect database: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	source, err := generator.Generate(result, s.generatorOptions...)
+	if err != nil {
+		http.Error(w, "failed to generate DBML: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(source)
+}
+
+func (s *Server) handleFingerprint(w http.ResponseWriter, r *http.Request) {
+	result, err := s.Schema()
+	if err != nil {
+		http.Error(w, "failed to introspect database: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write([]byte(result.Fingerprint()))
+}
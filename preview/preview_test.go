@@ -0,0 +1,106 @@
+package preview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/schema"
+)
+
+func newCachedServer(opts ...Option) *Server {
+	s := New(nil, append(opts, WithRefreshInterval(time.Hour))...)
+	s.cached = &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Comment: "Accounts", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}},
+			{Name: "<script>", Schema: "public"},
+		},
+	}
+	s.cachedAt = time.Now()
+	return s
+}
+
+func get(s *Server, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestPage(t *testing.T) {
+	s := newCachedServer()
+	rec := get(s, "/")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	expected := []string{
+		"<svg",
+		`id="table-users"`,
+		"Table users {",
+		"2 tables",
+		`const fingerprint = "` + s.cached.Fingerprint() + `"`,
+		"3600000",
+	}
+	for _, e := range expected {
+		if !strings.Contains(body, e) {
+			t.Errorf("Expected page to contain %q", e)
+		}
+	}
+	if strings.Contains(body, "<script>\"") || strings.Count(body, "<script>") != 1 {
+		t.Error("Expected table names to be escaped in the page")
+	}
+}
+
+func TestPageNotFound(t *testing.T) {
+	if rec := get(newCachedServer(), "/nope"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+}
+
+func TestDiagram(t *testing.T) {
+	rec := get(newCachedServer(), "/diagram.svg")
+
+	if got := rec.Header().Get("Content-Type"); got != "image/svg+xml" {
+		t.Errorf("Expected SVG content type, got %s", got)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "<svg") {
+		t.Errorf("Expected SVG document, got %s", rec.Body.String())
+	}
+}
+
+func TestDBML(t *testing.T) {
+	rec := get(newCachedServer(), "/schema.dbml")
+
+	if !strings.Contains(rec.Body.String(), "Table users {") {
+		t.Errorf("Expected DBML, got %s", rec.Body.String())
+	}
+}
+
+func TestGeneratorOptions(t *testing.T) {
+	if body := get(newCachedServer(), "/schema.dbml").Body.String(); strings.Contains(body, "Accounts") {
+		t.Errorf("Expected no notes by default, got %s", body)
+	}
+
+	s := newCachedServer(WithGeneratorOptions(generator.WithNotes()))
+	for _, path := range []string{"/", "/schema.dbml"} {
+		if body := get(s, path).Body.String(); !strings.Contains(body, "Note: &#39;Accounts&#39;") && !strings.Contains(body, "Note: 'Accounts'") {
+			t.Errorf("Expected %s to use the generator options, got %s", path, body)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	s := newCachedServer()
+	rec := get(s, "/fingerprint")
+
+	if rec.Body.String() != s.cached.Fingerprint() {
+		t.Errorf("Expected fingerprint %s, got %s", s.cached.Fingerprint(), rec.Body.String())
+	}
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Error("Expected fingerprint responses not to be cached")
+	}
+}
@@ -0,0 +1,66 @@
+package render
+
+import (
+	"io"
+
+	"github.com/lucasefe/dbml/atlas"
+	"github.com/lucasefe/dbml/convert"
+	"github.com/lucasefe/dbml/ddl"
+	"github.com/lucasefe/dbml/diagram"
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/liquibase"
+	"github.com/lucasefe/dbml/openapi"
+	"github.com/lucasefe/dbml/schema"
+)
+
+func init() {
+	Register("dbml", fromBytes(func(s *schema.Schema) ([]byte, error) {
+		return generator.Generate(s)
+	}))
+	Register("sql", fromBytes(func(s *schema.Schema) ([]byte, error) {
+		return ddl.Generate(s)
+	}))
+	Register("sqlc", fromBytes(func(s *schema.Schema) ([]byte, error) {
+		statements, err := ddl.Generate(s)
+		if err != nil {
+			return nil, err
+		}
+		header := "-- Code generated by dbml from a live database. DO NOT EDIT.\n" +
+			"-- Regenerate with: dbml --format sqlc\n\n"
+		return append([]byte(header), statements...), nil
+	}))
+	Register("flyway", fromBytes(func(s *schema.Schema) ([]byte, error) {
+		statements, err := ddl.Generate(s, ddl.WithDependencyOrder())
+		if err != nil {
+			return nil, err
+		}
+		header := "-- Flyway baseline generated by dbml from a live database.\n" +
+			"-- Existing databases: flyway baseline -baselineVersion=1\n\n"
+		return append([]byte(header), statements...), nil
+	}))
+	Register("json", fromBytes(func(s *schema.Schema) ([]byte, error) {
+		return convert.Encode(s, convert.JSON)
+	}))
+	Register("atlas", fromBytes(atlas.Generate))
+	Register("liquibase", fromBytes(liquibase.GenerateXML))
+	Register("liquibase-yaml", fromBytes(liquibase.GenerateYAML))
+	Register("openapi", fromBytes(openapi.Generate))
+	Register("svg", fromBytes(func(s *schema.Schema) ([]byte, error) {
+		return diagram.SVG(s), nil
+	}))
+	Register("mermaid", fromBytes(func(s *schema.Schema) ([]byte, error) {
+		return diagram.Mermaid(s), nil
+	}))
+}
+
+// fromBytes adapts the generators' []byte-returning functions.
+func fromBytes(generate func(*schema.Schema) ([]byte, error)) Renderer {
+	return Func(func(s *schema.Schema, w io.Writer) error {
+		content, err := generate(s)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	})
+}
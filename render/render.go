@@ -0,0 +1,102 @@
+// Package render turns schemas into output formats through a registry of
+// named renderers.
+//
+// The built-in formats (dbml, sql, sqlc, flyway, json, openapi, atlas,
+// liquibase, liquibase-yaml, and svg) are registered automatically. Other packages
+// can add formats by registering a Renderer, typically from an init function,
+// and the dbml CLI accepts any registered name for --format:
+//
+//	func init() {
+//	    render.Register("csv", render.Func(func(s *schema.Schema, w io.Writer) error {
+//	        ...
+//	    }))
+//	}
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Renderer writes a schema in some output format.
+type Renderer interface {
+	Render(s *schema.Schema, w io.Writer) error
+}
+
+// Func adapts an ordinary function to the Renderer interface.
+type Func func(s *schema.Schema, w io.Writer) error
+
+// Render calls f(s, w).
+func (f Func) Render(s *schema.Schema, w io.Writer) error {
+	return f(s, w)
+}
+
+var (
+	mu        sync.RWMutex
+	renderers = make(map[string]Renderer)
+)
+
+// Register makes a renderer available under name. It panics if name is
+// empty, r is nil, or the name is already registered.
+func Register(name string, r Renderer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if name == "" {
+		panic("render: Register with empty name")
+	}
+	if r == nil {
+		panic("render: Register renderer is nil")
+	}
+	if _, dup := renderers[name]; dup {
+		panic("render: Register called twice for format " + name)
+	}
+	renderers[name] = r
+}
+
+// Lookup returns the renderer registered under name.
+func Lookup(name string) (Renderer, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	r, ok := renderers[name]
+	return r, ok
+}
+
+// Formats returns the registered format names, sorted.
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render renders s with the renderer registered under format.
+func Render(s *schema.Schema, format string, w io.Writer) error {
+	r, ok := Lookup(format)
+	if !ok {
+		return fmt.Errorf("unknown format %q (expected one of: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return r.Render(s, w)
+}
+
+// Bytes renders s with the renderer registered under format and returns the
+// output.
+func Bytes(s *schema.Schema, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Render(s, format, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
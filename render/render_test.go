@@ -0,0 +1,104 @@
+package render
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func testSchema() *schema.Schema {
+	return &schema.Schema{Tables: []schema.Table{{
+		Name:        "users",
+		Schema:      "public",
+		PrimaryKeys: []string{"id"},
+		Columns:     []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}},
+	}}}
+}
+
+func TestBuiltinFormats(t *testing.T) {
+	expected := map[string]string{
+		"dbml":           "Table users",
+		"sql":            "CREATE TABLE",
+		"sqlc":           "DO NOT EDIT",
+		"flyway":         "Flyway baseline",
+		"json":           `"users"`,
+		"openapi":        `"openapi"`,
+		"atlas":          `table "users"`,
+		"liquibase":      "<databaseChangeLog",
+		"liquibase-yaml": "databaseChangeLog:",
+		"svg":            "<svg",
+	}
+
+	for format, want := range expected {
+		content, err := Bytes(testSchema(), format)
+		if err != nil {
+			t.Errorf("Bytes(%s) returned error: %v", format, err)
+			continue
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected %s output to contain %q, got:\n%s", format, want, content)
+		}
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := Bytes(testSchema(), "nope")
+	if err == nil || !strings.Contains(err.Error(), `unknown format "nope"`) || !strings.Contains(err.Error(), "dbml") {
+		t.Errorf("Expected unknown format error listing formats, got %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-names", Func(func(s *schema.Schema, w io.Writer) error {
+		for _, table := range s.Tables {
+			io.WriteString(w, table.Name+"\n")
+		}
+		return nil
+	}))
+	defer unregister("test-names")
+
+	content, err := Bytes(testSchema(), "test-names")
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if string(content) != "users\n" {
+		t.Errorf("Unexpected output: %q", content)
+	}
+
+	found := false
+	for _, name := range Formats() {
+		found = found || name == "test-names"
+	}
+	if !found {
+		t.Errorf("Expected Formats() to include test-names, got %v", Formats())
+	}
+}
+
+func TestRegisterErrorPropagates(t *testing.T) {
+	Register("test-fail", Func(func(s *schema.Schema, w io.Writer) error {
+		return errors.New("boom")
+	}))
+	defer unregister("test-fail")
+
+	if _, err := Bytes(testSchema(), "test-fail"); err == nil || err.Error() != "boom" {
+		t.Errorf("Expected renderer error, got %v", err)
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic when registering a duplicate format")
+		}
+	}()
+	Register("dbml", Func(func(s *schema.Schema, w io.Writer) error { return nil }))
+}
+
+func unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(renderers, name)
+}
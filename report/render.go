@@ -0,0 +1,119 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteText writes the report as aligned plain text.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%-20s %8s %8s %8s %8s\n", "SCHEMA", "TABLES", "COLUMNS", "INDEXES", "FKS")
+	for _, s := range r.Schemas {
+		fmt.Fprintf(&b, "%-20s %8d %8d %8d %8d\n", s.Name, s.Tables, s.Columns, s.Indexes, s.ForeignKeys)
+	}
+	fmt.Fprintf(&b, "%-20s %8d %8d %8d %8d\n", "TOTAL", r.Totals.Tables, r.Totals.Columns, r.Totals.Indexes, r.Totals.ForeignKeys)
+
+	if len(r.Largest) > 0 {
+		b.WriteString("\nLargest tables:\n")
+		for _, t := range r.Largest {
+			fmt.Fprintf(&b, "  %-40s %10s %12d rows\n", t.Table, FormatBytes(t.TotalBytes), t.RowEstimate)
+		}
+	}
+
+	b.WriteString("\nWidest tables:\n")
+	for _, t := range r.Widest {
+		fmt.Fprintf(&b, "  %-40s %4d columns\n", t.Table, t.Columns)
+	}
+
+	fmt.Fprintf(&b, "\nTables without comments (%d):\n", len(r.Uncommented))
+	for _, name := range r.Uncommented {
+		fmt.Fprintf(&b, "  %s\n", name)
+	}
+
+	if len(r.Functions) > 0 {
+		fmt.Fprintf(&b, "\nFunctions (%d):\n", len(r.Functions))
+		for _, f := range r.Functions {
+			fmt.Fprintf(&b, "  %s(%s)", f.Name, f.Arguments)
+			if f.Returns != "" {
+				fmt.Fprintf(&b, " -> %s", f.Returns)
+			}
+			fmt.Fprintf(&b, " [%s]\n", f.Language)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes the report as a Markdown document.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Schema Report\n\n")
+	b.WriteString("| Schema | Tables | Columns | Indexes | Foreign keys |\n")
+	b.WriteString("|--------|-------:|--------:|--------:|-------------:|\n")
+	for _, s := range r.Schemas {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d |\n", markdownEscape(s.Name), s.Tables, s.Columns, s.Indexes, s.ForeignKeys)
+	}
+	fmt.Fprintf(&b, "| **Total** | %d | %d | %d | %d |\n", r.Totals.Tables, r.Totals.Columns, r.Totals.Indexes, r.Totals.ForeignKeys)
+
+	if len(r.Largest) > 0 {
+		b.WriteString("\n## Largest Tables\n\n")
+		b.WriteString("| Table | Size | Rows (estimate) |\n")
+		b.WriteString("|-------|-----:|----------------:|\n")
+		for _, t := range r.Largest {
+			fmt.Fprintf(&b, "| %s | %s | %d |\n", markdownEscape(t.Table), FormatBytes(t.TotalBytes), t.RowEstimate)
+		}
+	}
+
+	b.WriteString("\n## Widest Tables\n\n")
+	b.WriteString("| Table | Columns |\n")
+	b.WriteString("|-------|--------:|\n")
+	for _, t := range r.Widest {
+		fmt.Fprintf(&b, "| %s | %d |\n", markdownEscape(t.Table), t.Columns)
+	}
+
+	fmt.Fprintf(&b, "\n## Tables Without Comments (%d)\n\n", len(r.Uncommented))
+	if len(r.Uncommented) == 0 {
+		b.WriteString("Every table has a comment.\n")
+	}
+	for _, name := range r.Uncommented {
+		fmt.Fprintf(&b, "- %s\n", markdownEscape(name))
+	}
+
+	if len(r.Functions) > 0 {
+		fmt.Fprintf(&b, "\n## Functions (%d)\n\n", len(r.Functions))
+		b.WriteString("| Name | Kind | Arguments | Returns | Language |\n")
+		b.WriteString("|------|------|-----------|---------|----------|\n")
+		for _, f := range r.Functions {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", markdownEscape(f.Name), f.Kind,
+				markdownEscape(f.Arguments), markdownEscape(f.Returns), f.Language)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// FormatBytes formats a byte count with a binary unit, e.g. "1.5 MiB", like
+// schema.FormatBytes.
+func FormatBytes(n int64) string {
+	return schema.FormatBytes(n)
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "_", `\_`, "*", `\*`).Replace(s)
+}
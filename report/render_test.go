@@ -0,0 +1,85 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	var b strings.Builder
+	if err := Build(testSchema(), 0).WriteText(&b); err != nil {
+		t.Fatalf("WriteText returned error: %v", err)
+	}
+
+	expected := []string{
+		"auth                        1        4        0        0",
+		"TOTAL                       3        9        1        1",
+		"Largest tables:\n  events",
+		"3.0 GiB",
+		"Tables without comments (2):\n  auth.sessions\n  events\n",
+		"Functions (1):\n  auth.expire(before timestamp) [sql]\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(b.String(), e) {
+			t.Errorf("Expected text to contain %q, got:\n%s", e, b.String())
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := Build(testSchema(), 0).WriteMarkdown(&b); err != nil {
+		t.Fatalf("WriteMarkdown returned error: %v", err)
+	}
+
+	expected := []string{
+		"# Schema Report",
+		"| public | 2 | 5 | 1 | 1 |",
+		"| **Total** | 3 | 9 | 1 | 1 |",
+		"## Largest Tables",
+		"| users | 2.0 MiB | 1000 |",
+		"| auth.sessions | 4 |",
+		"- events\n",
+		"## Functions (1)",
+		"| auth.expire | procedure | before timestamp |  | sql |",
+	}
+	for _, e := range expected {
+		if !strings.Contains(b.String(), e) {
+			t.Errorf("Expected Markdown to contain %q, got:\n%s", e, b.String())
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b strings.Builder
+	if err := Build(testSchema(), 0).WriteJSON(&b); err != nil {
+		t.Fatalf("WriteJSON returned error: %v", err)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if decoded.Totals.Tables != 3 || len(decoded.Schemas) != 2 {
+		t.Errorf("Unexpected decoded report: %+v", decoded)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+	}
+
+	for _, tt := range tests {
+		if result := FormatBytes(tt.input); result != tt.expected {
+			t.Errorf("FormatBytes(%d) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+}
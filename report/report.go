@@ -0,0 +1,156 @@
+// Package report summarizes a schema for architecture reviews.
+//
+// A Report counts tables, columns, indexes, and foreign keys per schema, ranks
+// the largest and widest tables, lists tables without comments, and lists the
+// functions and procedures when they were introspected. It can be written as
+// plain text, Markdown, or JSON.
+//
+// Basic usage:
+//
+//	r := report.Build(s, 10)
+//	r.WriteMarkdown(os.Stdout)
+package report
+
+import (
+	"sort"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Report is a summary of a schema.
+type Report struct {
+	// Totals sums the counts across every schema.
+	Totals Counts `json:"totals"`
+	// Schemas holds the counts for each database schema, sorted by name.
+	Schemas []SchemaCounts `json:"schemas"`
+	// Largest lists the tables using the most disk space, largest first.
+	// It is empty unless the schema was introspected with statistics.
+	Largest []TableSize `json:"largest"`
+	// Widest lists the tables with the most columns, widest first.
+	Widest []TableWidth `json:"widest"`
+	// Uncommented lists the tables without a comment.
+	Uncommented []string `json:"uncommented"`
+	// Functions lists the functions and procedures, sorted by name. It is
+	// empty unless the schema was introspected with functions.
+	Functions []FunctionSignature `json:"functions"`
+}
+
+// Counts tallies the objects in a schema.
+type Counts struct {
+	Tables      int `json:"tables"`
+	Columns     int `json:"columns"`
+	Indexes     int `json:"indexes"`
+	ForeignKeys int `json:"foreign_keys"`
+}
+
+// SchemaCounts tallies the objects in one database schema.
+type SchemaCounts struct {
+	Name string `json:"name"`
+	Counts
+}
+
+// TableSize describes a table's disk usage.
+type TableSize struct {
+	Table       string `json:"table"`
+	RowEstimate int64  `json:"row_estimate"`
+	TotalBytes  int64  `json:"total_bytes"`
+}
+
+// TableWidth describes a table's column count.
+type TableWidth struct {
+	Table   string `json:"table"`
+	Columns int    `json:"columns"`
+}
+
+// FunctionSignature describes a function or procedure.
+type FunctionSignature struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Arguments string `json:"arguments"`
+	Returns   string `json:"returns"`
+	Language  string `json:"language"`
+}
+
+// Build summarizes the schema, listing at most top tables in each ranking
+// (every table if top is not positive).
+func Build(s *schema.Schema, top int) *Report {
+	r := &Report{
+		Schemas:     []SchemaCounts{},
+		Largest:     []TableSize{},
+		Widest:      []TableWidth{},
+		Uncommented: []string{},
+		Functions:   []FunctionSignature{},
+	}
+
+	bySchema := make(map[string]*SchemaCounts)
+	for _, table := range s.Tables {
+		counts, ok := bySchema[table.Schema]
+		if !ok {
+			counts = &SchemaCounts{Name: table.Schema}
+			bySchema[table.Schema] = counts
+		}
+		counts.Tables++
+		counts.Columns += len(table.Columns)
+		counts.Indexes += len(table.Indexes)
+		counts.ForeignKeys += len(table.References)
+
+		name := generator.GetQualifiedTableName(table.Name, table.Schema)
+		if table.Statistics != nil {
+			r.Largest = append(r.Largest, TableSize{
+				Table:       name,
+				RowEstimate: table.Statistics.RowEstimate,
+				TotalBytes:  table.Statistics.TotalBytes,
+			})
+		}
+		r.Widest = append(r.Widest, TableWidth{Table: name, Columns: len(table.Columns)})
+		if table.Comment == "" {
+			r.Uncommented = append(r.Uncommented, name)
+		}
+	}
+
+	for _, counts := range bySchema {
+		r.Schemas = append(r.Schemas, *counts)
+		r.Totals.Tables += counts.Tables
+		r.Totals.Columns += counts.Columns
+		r.Totals.Indexes += counts.Indexes
+		r.Totals.ForeignKeys += counts.ForeignKeys
+	}
+	sort.Slice(r.Schemas, func(i, j int) bool { return r.Schemas[i].Name < r.Schemas[j].Name })
+
+	sort.SliceStable(r.Largest, func(i, j int) bool {
+		if r.Largest[i].TotalBytes != r.Largest[j].TotalBytes {
+			return r.Largest[i].TotalBytes > r.Largest[j].TotalBytes
+		}
+		return r.Largest[i].Table < r.Largest[j].Table
+	})
+	sort.SliceStable(r.Widest, func(i, j int) bool {
+		if r.Widest[i].Columns != r.Widest[j].Columns {
+			return r.Widest[i].Columns > r.Widest[j].Columns
+		}
+		return r.Widest[i].Table < r.Widest[j].Table
+	})
+	sort.Strings(r.Uncommented)
+
+	for _, function := range s.Functions {
+		r.Functions = append(r.Functions, FunctionSignature{
+			Name:      generator.GetQualifiedTableName(function.Name, function.Schema),
+			Kind:      function.Kind,
+			Arguments: function.Arguments,
+			Returns:   function.Returns,
+			Language:  function.Language,
+		})
+	}
+	sort.SliceStable(r.Functions, func(i, j int) bool { return r.Functions[i].Name < r.Functions[j].Name })
+
+	if top > 0 {
+		if len(r.Largest) > top {
+			r.Largest = r.Largest[:top]
+		}
+		if len(r.Widest) > top {
+			r.Widest = r.Widest[:top]
+		}
+	}
+
+	return r
+}
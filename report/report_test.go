@@ -0,0 +1,72 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func testSchema() *schema.Schema {
+	return &schema.Schema{
+		Tables: []schema.Table{
+			{
+				Name:       "users",
+				Schema:     "public",
+				Comment:    "Registered users",
+				Columns:    []schema.Column{{Name: "id"}, {Name: "email"}, {Name: "name"}},
+				Indexes:    []schema.Index{{Name: "users_email_key"}},
+				Statistics: &schema.TableStatistics{RowEstimate: 1000, TotalBytes: 2 << 20},
+			},
+			{
+				Name:       "events",
+				Schema:     "public",
+				Columns:    []schema.Column{{Name: "id"}, {Name: "user_id"}},
+				References: []schema.Reference{{FromTable: "events", ToTable: "users"}},
+				Statistics: &schema.TableStatistics{RowEstimate: 500000, TotalBytes: 3 << 30},
+			},
+			{
+				Name:    "sessions",
+				Schema:  "auth",
+				Columns: []schema.Column{{Name: "id"}, {Name: "user_id"}, {Name: "token"}, {Name: "expires_at"}},
+			},
+		},
+		Functions: []schema.Function{
+			{Name: "expire", Schema: "auth", Kind: "procedure", Arguments: "before timestamp", Language: "sql"},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	r := Build(testSchema(), 2)
+
+	if r.Totals != (Counts{Tables: 3, Columns: 9, Indexes: 1, ForeignKeys: 1}) {
+		t.Errorf("Unexpected totals: %+v", r.Totals)
+	}
+
+	if len(r.Schemas) != 2 || r.Schemas[0].Name != "auth" || r.Schemas[1].Name != "public" {
+		t.Fatalf("Expected auth and public schemas, got %+v", r.Schemas)
+	}
+	if r.Schemas[1].Tables != 2 || r.Schemas[1].Columns != 5 {
+		t.Errorf("Unexpected public counts: %+v", r.Schemas[1])
+	}
+
+	if len(r.Largest) != 2 || r.Largest[0].Table != "events" || r.Largest[1].Table != "users" {
+		t.Errorf("Unexpected largest tables: %+v", r.Largest)
+	}
+
+	if len(r.Widest) != 2 || r.Widest[0].Table != "auth.sessions" || r.Widest[1].Table != "users" {
+		t.Errorf("Unexpected widest tables: %+v", r.Widest)
+	}
+
+	if len(r.Uncommented) != 2 || r.Uncommented[0] != "auth.sessions" || r.Uncommented[1] != "events" {
+		t.Errorf("Unexpected uncommented tables: %v", r.Uncommented)
+	}
+}
+
+func TestBuildWithoutStatistics(t *testing.T) {
+	r := Build(&schema.Schema{Tables: []schema.Table{{Name: "users", Schema: "public"}}}, 0)
+
+	if r.Largest == nil || len(r.Largest) != 0 {
+		t.Errorf("Expected empty largest list without statistics, got %v", r.Largest)
+	}
+}
@@ -0,0 +1,61 @@
+package sample
+
+import (
+	"strings"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+// maxValueLength caps each value in an annotation so long text and JSON
+// columns do not swamp the comment.
+const maxValueLength = 40
+
+// Annotate returns a copy of s with each table's sample appended to its
+// comment as a pipe-separated table, so outputs that carry comments show it.
+func Annotate(s *schema.Schema, samples []TableSample) *schema.Schema {
+	byTable := make(map[string]TableSample)
+	for _, sample := range samples {
+		byTable[sample.Schema+"."+sample.Table] = sample
+	}
+
+	result := *s
+	result.Tables = make([]schema.Table, len(s.Tables))
+	for i, table := range s.Tables {
+		if sample, ok := byTable[table.Schema+"."+table.Name]; ok && len(sample.Rows) > 0 {
+			if table.Comment == "" {
+				table.Comment = format(sample)
+			} else {
+				table.Comment += "\n\n" + format(sample)
+			}
+		}
+		result.Tables[i] = table
+	}
+	return &result
+}
+
+// format renders a sample as a heading followed by one line per row.
+func format(sample TableSample) string {
+	var b strings.Builder
+	b.WriteString("Sample data:\n")
+	b.WriteString(strings.Join(sample.Columns, " | "))
+	for _, row := range sample.Rows {
+		values := make([]string, len(row))
+		for i, v := range row {
+			values[i] = formatValue(v)
+		}
+		b.WriteString("\n")
+		b.WriteString(strings.Join(values, " | "))
+	}
+	return b.String()
+}
+
+func formatValue(v *string) string {
+	if v == nil {
+		return "NULL"
+	}
+	value := strings.Join(strings.Fields(*v), " ")
+	if len([]rune(value)) > maxValueLength {
+		value = string([]rune(value)[:maxValueLength-3]) + "..."
+	}
+	return value
+}
@@ -0,0 +1,53 @@
+package sample
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestAnnotate(t *testing.T) {
+	s := &schema.Schema{Tables: []schema.Table{
+		{Name: "users", Schema: "public", Comment: "Registered users"},
+		{Name: "orders", Schema: "public"},
+		{Name: "events", Schema: "public"},
+	}}
+	samples := []TableSample{
+		{
+			Schema:  "public",
+			Table:   "users",
+			Columns: []string{"id", "bio"},
+			Rows: [][]*string{
+				{strPtr("1"), strPtr("line one\nline two")},
+				{strPtr("2"), nil},
+			},
+		},
+		{
+			Schema:  "public",
+			Table:   "orders",
+			Columns: []string{"id", "notes"},
+			Rows:    [][]*string{{strPtr("7"), strPtr(strings.Repeat("x", 100))}},
+		},
+		{Schema: "public", Table: "events", Columns: []string{"id"}, Rows: [][]*string{}},
+	}
+
+	result := Annotate(s, samples)
+
+	expected := "Registered users\n\nSample data:\nid | bio\n1 | line one line two\n2 | NULL"
+	if result.Tables[0].Comment != expected {
+		t.Errorf("Unexpected users comment:\n%s", result.Tables[0].Comment)
+	}
+
+	if !strings.HasSuffix(result.Tables[1].Comment, "7 | "+strings.Repeat("x", 37)+"...") {
+		t.Errorf("Expected long value to be truncated, got:\n%s", result.Tables[1].Comment)
+	}
+
+	if result.Tables[2].Comment != "" {
+		t.Errorf("Expected empty sample to leave comment alone, got %q", result.Tables[2].Comment)
+	}
+
+	if s.Tables[0].Comment != "Registered users" {
+		t.Error("Annotate modified the input schema")
+	}
+}
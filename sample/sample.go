@@ -0,0 +1,192 @@
+// Package sample captures example rows from each table so generated
+// documentation can show what the data actually looks like.
+//
+// Sampling reads real data, so it is opt-in and can be limited to an allow
+// list of columns or exclude columns by pattern. Samples can be written as a
+// companion JSON file or appended to table comments with Annotate.
+//
+// Basic usage:
+//
+//	samples, err := sample.Capture(db, s, sample.WithRows(3), sample.WithExcludeColumns("*password*"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	annotated := sample.Annotate(s, samples)
+package sample
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// TableSample holds example rows from one table.
+type TableSample struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	// Columns lists the sampled columns in table order.
+	Columns []string `json:"columns"`
+	// Rows holds each row's values as text, in Columns order. NULL values
+	// are nil.
+	Rows [][]*string `json:"rows"`
+}
+
+// Option configures sampling.
+type Option func(*options)
+
+type options struct {
+	rows    int
+	include []string
+	exclude []string
+}
+
+// DefaultRows is the number of rows sampled per table unless WithRows is used.
+const DefaultRows = 5
+
+// WithRows sets the number of rows sampled per table.
+func WithRows(n int) Option {
+	return func(o *options) {
+		o.rows = n
+	}
+}
+
+// WithColumns samples only columns matching one of the patterns. Patterns use
+// path.Match syntax and are matched against the column name and against
+// "table.column".
+func WithColumns(patterns ...string) Option {
+	return func(o *options) {
+		o.include = patterns
+	}
+}
+
+// WithExcludeColumns never samples columns matching one of the patterns.
+// Exclusions take precedence over WithColumns.
+func WithExcludeColumns(patterns ...string) Option {
+	return func(o *options) {
+		o.exclude = patterns
+	}
+}
+
+// Capture samples rows from every table in s. Tables with no selected columns
+// are skipped.
+func Capture(db *sql.DB, s *schema.Schema, opts ...Option) ([]TableSample, error) {
+	o := &options{rows: DefaultRows}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if err := validate(o); err != nil {
+		return nil, err
+	}
+
+	var samples []TableSample
+	for _, table := range s.Tables {
+		columns := selectColumns(table, o)
+		if len(columns) == 0 {
+			continue
+		}
+
+		rows, err := queryRows(db, table, columns, o.rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample %s.%s: %w", table.Schema, table.Name, err)
+		}
+		samples = append(samples, TableSample{
+			Schema:  table.Schema,
+			Table:   table.Name,
+			Columns: columns,
+			Rows:    rows,
+		})
+	}
+	return samples, nil
+}
+
+func validate(o *options) error {
+	if o.rows <= 0 {
+		return fmt.Errorf("invalid sample size %d: must be positive", o.rows)
+	}
+	for _, pattern := range append(append([]string(nil), o.include...), o.exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid column pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// selectColumns returns the columns of table allowed by the include and
+// exclude patterns.
+func selectColumns(table schema.Table, o *options) []string {
+	var columns []string
+	for _, column := range table.Columns {
+		if len(o.include) > 0 && !matchAny(o.include, table.Name, column.Name) {
+			continue
+		}
+		if matchAny(o.exclude, table.Name, column.Name) {
+			continue
+		}
+		columns = append(columns, column.Name)
+	}
+	return columns
+}
+
+func matchAny(patterns []string, table, column string) bool {
+	for _, pattern := range patterns {
+		for _, name := range []string{column, table + "." + column} {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sampleQuery builds the query reading n rows of columns as text.
+func sampleQuery(table schema.Table, columns []string, n int) string {
+	selected := make([]string, len(columns))
+	for i, column := range columns {
+		selected[i] = pq.QuoteIdentifier(column) + "::text"
+	}
+	return fmt.Sprintf("SELECT %s FROM %s.%s LIMIT %d",
+		strings.Join(selected, ", "), pq.QuoteIdentifier(table.Schema), pq.QuoteIdentifier(table.Name), n)
+}
+
+func queryRows(db *sql.DB, table schema.Table, columns []string, n int) ([][]*string, error) {
+	rows, err := db.Query(sampleQuery(table, columns, n))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := [][]*string{}
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+
+		row := make([]*string, len(columns))
+		for i, v := range values {
+			if v.Valid {
+				s := v.String
+				row[i] = &s
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// Encode renders samples as an indented JSON array.
+func Encode(samples []TableSample) ([]byte, error) {
+	if samples == nil {
+		samples = []TableSample{}
+	}
+	return json.MarshalIndent(samples, "", "  ")
+}
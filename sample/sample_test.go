@@ -0,0 +1,122 @@
+package sample
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func usersTable() schema.Table {
+	return schema.Table{
+		Name:   "users",
+		Schema: "public",
+		Columns: []schema.Column{
+			{Name: "id"},
+			{Name: "email"},
+			{Name: "password_hash"},
+			{Name: "created_at"},
+		},
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  options
+		expected []string
+	}{
+		{
+			name:     "all columns",
+			expected: []string{"id", "email", "password_hash", "created_at"},
+		},
+		{
+			name:     "exclude by column name",
+			options:  options{exclude: []string{"password*"}},
+			expected: []string{"id", "email", "created_at"},
+		},
+		{
+			name:     "include by qualified name",
+			options:  options{include: []string{"users.id", "users.email"}},
+			expected: []string{"id", "email"},
+		},
+		{
+			name:     "exclude wins over include",
+			options:  options{include: []string{"*"}, exclude: []string{"users.email"}},
+			expected: []string{"id", "password_hash", "created_at"},
+		},
+		{
+			name:     "nothing selected",
+			options:  options{include: []string{"orders.*"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := selectColumns(usersTable(), &tt.options)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("selectColumns() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validate(&options{rows: 0}); err == nil {
+		t.Error("Expected error for zero rows")
+	}
+	if err := validate(&options{rows: 5, exclude: []string{"["}}); err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+	if err := validate(&options{rows: 5, include: []string{"users.*"}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestSampleQuery(t *testing.T) {
+	table := schema.Table{Name: "Order Items", Schema: "sales"}
+	query := sampleQuery(table, []string{"id", "Note"}, 3)
+
+	expected := `SELECT "id"::text, "Note"::text FROM "sales"."Order Items" LIMIT 3`
+	if query != expected {
+		t.Errorf("sampleQuery() = %s, want %s", query, expected)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	email := "ada@example.com"
+	samples := []TableSample{{
+		Schema:  "public",
+		Table:   "users",
+		Columns: []string{"id", "email"},
+		Rows:    [][]*string{{strPtr("1"), &email}, {strPtr("2"), nil}},
+	}}
+
+	data, err := Encode(samples)
+	if err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	if !strings.Contains(string(data), `null`) || !strings.Contains(string(data), `"ada@example.com"`) {
+		t.Errorf("Unexpected JSON:\n%s", data)
+	}
+
+	var decoded []TableSample
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0].Rows[1][1] != nil {
+		t.Errorf("Unexpected decoded samples: %+v", decoded)
+	}
+
+	empty, err := Encode(nil)
+	if err != nil || string(empty) != "[]" {
+		t.Errorf("Encode(nil) = %s, %v, want []", empty, err)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,51 @@
+package schema
+
+import "strings"
+
+// TypeFallback reports whether column was written as text, or text[] for an
+// array, for want of a DBML equivalent of its database type, such as
+// PostGIS's geometry. Enum columns are not fallbacks, since they are written
+// with their Enum block.
+func (s *Schema) TypeFallback(column Column) bool {
+	if column.Type != "text" && column.Type != "text[]" || column.DatabaseType == "" || column.DatabaseType == column.Type {
+		return false
+	}
+	_, isEnum := s.ColumnEnum(column.DatabaseType)
+	return !isEnum
+}
+
+// GenerationClause returns the GENERATED ALWAYS AS (...) STORED clause of a
+// generated column, or an empty string for ordinary columns. The expression
+// is parenthesized unless it already is.
+func (c Column) GenerationClause() string {
+	if c.Generated == "" {
+		return ""
+	}
+	expression := c.Generated
+	if !parenthesized(expression) {
+		expression = "(" + expression + ")"
+	}
+	return "GENERATED ALWAYS AS " + expression + " STORED"
+}
+
+// parenthesized reports whether s is wrapped in one pair of parentheses, as
+// in "(a + b)" but not "(a) + (b)".
+func parenthesized(s string) bool {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return false
+	}
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 && i < len(s)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
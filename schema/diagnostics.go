@@ -0,0 +1,54 @@
+package schema
+
+import "fmt"
+
+// Diagnostic codes identify the kinds of issues reported in Diagnostics.
+const (
+	// DiagnosticTypeFallback reports a column whose database type has no
+	// DBML equivalent and was written as text.
+	DiagnosticTypeFallback = "type-fallback"
+	// DiagnosticExpressionIndex reported an index on expressions, which were
+	// not introspected.
+	//
+	// Deprecated: index expressions are introspected into Index.Columns, so
+	// this code is no longer reported.
+	DiagnosticExpressionIndex = "expression-index"
+	// DiagnosticUnsupported reports a construct an output format cannot
+	// represent and leaves out.
+	DiagnosticUnsupported = "unsupported"
+)
+
+// Diagnostic is a non-fatal issue found while introspecting or generating a
+// schema, such as something the output silently leaves out.
+type Diagnostic struct {
+	// Code identifies the kind of issue (e.g., DiagnosticTypeFallback).
+	Code string
+	// Schema is the database schema of the object concerned.
+	Schema string
+	// Table is the table, view, or type concerned, or empty if the issue
+	// concerns the whole schema.
+	Table string
+	// Object is the column or index concerned, or empty if the issue
+	// concerns the table itself.
+	Object string
+	// Message describes the issue.
+	Message string
+}
+
+// String formats the diagnostic as "schema.table.object: message (code)".
+func (d Diagnostic) String() string {
+	name := d.Schema
+	for _, part := range []string{d.Table, d.Object} {
+		if part == "" {
+			continue
+		}
+		if name != "" {
+			name += "."
+		}
+		name += part
+	}
+	if name == "" {
+		return fmt.Sprintf("%s (%s)", d.Message, d.Code)
+	}
+	return fmt.Sprintf("%s: %s (%s)", name, d.Message, d.Code)
+}
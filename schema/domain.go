@@ -0,0 +1,17 @@
+package schema
+
+// ColumnDomain returns the domain type of a column, given its Domain.
+func (s *Schema) ColumnDomain(name string) (Domain, bool) {
+	if name == "" {
+		return Domain{}, false
+	}
+	for _, domain := range s.Domains {
+		if name == domain.Schema+"."+domain.Name {
+			return domain, true
+		}
+		if name == domain.Name && (domain.Schema == "" || domain.Schema == "public") {
+			return domain, true
+		}
+	}
+	return Domain{}, false
+}
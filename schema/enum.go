@@ -0,0 +1,19 @@
+package schema
+
+import "strings"
+
+// ColumnEnum returns the enum type of a column, given its DatabaseType as
+// PostgreSQL spells it: the enum name, qualified with its schema unless that
+// is public, and followed by [] for arrays of the enum.
+func (s *Schema) ColumnEnum(databaseType string) (Enum, bool) {
+	name := strings.TrimSuffix(databaseType, "[]")
+	for _, enum := range s.Enums {
+		if name == enum.Schema+"."+enum.Name {
+			return enum, true
+		}
+		if name == enum.Name && (enum.Schema == "" || enum.Schema == "public") {
+			return enum, true
+		}
+	}
+	return Enum{}, false
+}
@@ -1,6 +1,7 @@
 package schema
 
-// FilterTables removes tables from the schema that match the exclude list.
+// FilterTables removes tables and views from the schema that match the
+// exclude list, along with their diagnostics.
 // It returns a new Schema with the filtered tables; the original is not modified.
 func FilterTables(s *Schema, excludeTables []string) *Schema {
 	excludeMap := make(map[string]bool)
@@ -15,5 +16,23 @@ func FilterTables(s *Schema, excludeTables []string) *Schema {
 		}
 	}
 
-	return &Schema{Tables: filteredTables}
+	var filteredViews []View
+	for _, view := range s.Views {
+		if !excludeMap[view.Name] {
+			filteredViews = append(filteredViews, view)
+		}
+	}
+
+	var filteredDiagnostics []Diagnostic
+	for _, d := range s.Diagnostics {
+		if d.Table == "" || !excludeMap[d.Table] {
+			filteredDiagnostics = append(filteredDiagnostics, d)
+		}
+	}
+
+	result := *s
+	result.Tables = filteredTables
+	result.Views = filteredViews
+	result.Diagnostics = filteredDiagnostics
+	return &result
 }
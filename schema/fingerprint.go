@@ -0,0 +1,244 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// ChecksumOption configures Checksum.
+type ChecksumOption func(*checksumOptions)
+
+type checksumOptions struct {
+	withoutComments bool
+}
+
+// WithoutComments leaves table, column, and view comments out of the
+// checksum, along with the header colors and deprecation read from comment
+// annotations, so editing documentation does not change it.
+func WithoutComments() ChecksumOption {
+	return func(o *checksumOptions) {
+		o.withoutComments = true
+	}
+}
+
+// Checksum returns a stable hash of the schema's structure: the hex-encoded
+// SHA-256 of the schema's JSON encoding in a canonical order. Tables, enums,
+// and views are sorted by schema and name, indexes and table groups by name,
+// the tables in each group by schema and name, and references by their
+// content, so two schemas with the same content produce the same checksum
+// regardless of the order in which they were introspected. Column order is
+// preserved because it is significant. Table statistics, the migration
+// version, and the server version are always ignored since they change
+// without the structure changing.
+//
+// The checksum changes when any other field changes, including comments
+// unless WithoutComments is given. New fields added to the schema package
+// change it too, so compare checksums computed by the same version.
+func (s *Schema) Checksum(opts ...ChecksumOption) string {
+	o := &checksumOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	canonical := canonicalize(s)
+	if o.withoutComments {
+		withoutComments(canonical)
+	}
+
+	// Marshaling plain structs, slices, strings, and bools cannot fail.
+	data, _ := json.Marshal(canonical)
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Fingerprint returns the schema's Checksum, comments included.
+func (s *Schema) Fingerprint() string {
+	return s.Checksum()
+}
+
+// withoutComments clears the comments of a canonicalized schema. The tables
+// and views are already copies, but their columns are shared with the
+// original schema and must be copied before they are changed.
+func withoutComments(s *Schema) {
+	for i := range s.Tables {
+		table := &s.Tables[i]
+		table.Comment = ""
+		table.Color = ""
+		table.Deprecated = false
+		table.Columns = uncommentedColumns(table.Columns)
+	}
+	for i := range s.Views {
+		s.Views[i].Comment = ""
+		s.Views[i].Columns = uncommentedColumns(s.Views[i].Columns)
+	}
+}
+
+func uncommentedColumns(columns []Column) []Column {
+	result := make([]Column, len(columns))
+	for i, column := range columns {
+		column.Comment = ""
+		column.Deprecated = false
+		result[i] = column
+	}
+	return result
+}
+
+func canonicalize(s *Schema) *Schema {
+	tables := make([]Table, len(s.Tables))
+	copy(tables, s.Tables)
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Schema != tables[j].Schema {
+			return tables[i].Schema < tables[j].Schema
+		}
+		return tables[i].Name < tables[j].Name
+	})
+
+	for i := range tables {
+		tables[i].Statistics = nil
+
+		indexes := make([]Index, len(tables[i].Indexes))
+		copy(indexes, tables[i].Indexes)
+		sort.Slice(indexes, func(a, b int) bool {
+			return indexes[a].Name < indexes[b].Name
+		})
+		tables[i].Indexes = indexes
+
+		references := make([]Reference, len(tables[i].References))
+		copy(references, tables[i].References)
+		sort.Slice(references, func(a, b int) bool {
+			return referenceKey(references[a]) < referenceKey(references[b])
+		})
+		tables[i].References = references
+
+		checks := make([]CheckConstraint, len(tables[i].CheckConstraints))
+		copy(checks, tables[i].CheckConstraints)
+		sort.Slice(checks, func(a, b int) bool {
+			return checks[a].Name < checks[b].Name
+		})
+		tables[i].CheckConstraints = checks
+
+		exclusions := make([]ExclusionConstraint, len(tables[i].ExclusionConstraints))
+		copy(exclusions, tables[i].ExclusionConstraints)
+		sort.Slice(exclusions, func(a, b int) bool {
+			return exclusions[a].Name < exclusions[b].Name
+		})
+		tables[i].ExclusionConstraints = exclusions
+
+		policies := make([]Policy, len(tables[i].Policies))
+		copy(policies, tables[i].Policies)
+		sort.Slice(policies, func(a, b int) bool {
+			return policies[a].Name < policies[b].Name
+		})
+		tables[i].Policies = policies
+
+		triggers := make([]Trigger, len(tables[i].Triggers))
+		copy(triggers, tables[i].Triggers)
+		sort.Slice(triggers, func(a, b int) bool {
+			return triggers[a].Name < triggers[b].Name
+		})
+		tables[i].Triggers = triggers
+
+		grants := make([]Grant, len(tables[i].Grants))
+		copy(grants, tables[i].Grants)
+		sort.Slice(grants, func(a, b int) bool {
+			return grants[a].Grantee < grants[b].Grantee
+		})
+		tables[i].Grants = grants
+	}
+
+	enums := make([]Enum, len(s.Enums))
+	copy(enums, s.Enums)
+	sort.Slice(enums, func(i, j int) bool {
+		if enums[i].Schema != enums[j].Schema {
+			return enums[i].Schema < enums[j].Schema
+		}
+		return enums[i].Name < enums[j].Name
+	})
+
+	views := make([]View, len(s.Views))
+	copy(views, s.Views)
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Schema != views[j].Schema {
+			return views[i].Schema < views[j].Schema
+		}
+		return views[i].Name < views[j].Name
+	})
+
+	sequences := make([]Sequence, len(s.Sequences))
+	copy(sequences, s.Sequences)
+	sort.Slice(sequences, func(i, j int) bool {
+		if sequences[i].Schema != sequences[j].Schema {
+			return sequences[i].Schema < sequences[j].Schema
+		}
+		return sequences[i].Name < sequences[j].Name
+	})
+
+	domains := make([]Domain, len(s.Domains))
+	copy(domains, s.Domains)
+	sort.Slice(domains, func(i, j int) bool {
+		if domains[i].Schema != domains[j].Schema {
+			return domains[i].Schema < domains[j].Schema
+		}
+		return domains[i].Name < domains[j].Name
+	})
+
+	ranges := make([]Range, len(s.Ranges))
+	copy(ranges, s.Ranges)
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].Schema != ranges[j].Schema {
+			return ranges[i].Schema < ranges[j].Schema
+		}
+		return ranges[i].Name < ranges[j].Name
+	})
+
+	functions := make([]Function, len(s.Functions))
+	copy(functions, s.Functions)
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Schema != functions[j].Schema {
+			return functions[i].Schema < functions[j].Schema
+		}
+		if functions[i].Name != functions[j].Name {
+			return functions[i].Name < functions[j].Name
+		}
+		return functions[i].Arguments < functions[j].Arguments
+	})
+
+	groups := make([]TableGroup, len(s.TableGroups))
+	copy(groups, s.TableGroups)
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	for i := range groups {
+		members := make([]TableName, len(groups[i].Tables))
+		copy(members, groups[i].Tables)
+		sort.Slice(members, func(a, b int) bool {
+			if members[a].Schema != members[b].Schema {
+				return members[a].Schema < members[b].Schema
+			}
+			return members[a].Name < members[b].Name
+		})
+		groups[i].Tables = members
+	}
+
+	result := *s
+	result.Tables = tables
+	result.Enums = enums
+	result.Views = views
+	result.Sequences = sequences
+	result.Domains = domains
+	result.Ranges = ranges
+	result.Functions = functions
+	result.TableGroups = groups
+	result.Migration = nil
+	result.ServerVersion = 0
+	result.Diagnostics = nil
+	return &result
+}
+
+func referenceKey(ref Reference) string {
+	data, _ := json.Marshal(ref)
+	return string(data)
+}
@@ -0,0 +1,104 @@
+package schema
+
+import "testing"
+
+func TestFingerprintStable(t *testing.T) {
+	a := &Schema{
+		Tables: []Table{
+			{Name: "users", Schema: "public", Columns: []Column{{Name: "id", Type: "int"}}},
+			{Name: "posts", Schema: "public", Columns: []Column{{Name: "id", Type: "int"}}},
+		},
+	}
+	b := &Schema{
+		Tables: []Table{
+			{Name: "posts", Schema: "public", Columns: []Column{{Name: "id", Type: "int"}}},
+			{Name: "users", Schema: "public", Columns: []Column{{Name: "id", Type: "int"}}},
+		},
+	}
+
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("Expected fingerprints to match regardless of table order")
+	}
+
+	if a.Tables[0].Name != "users" {
+		t.Errorf("Fingerprint modified the original schema")
+	}
+}
+
+func TestFingerprintDetectsChanges(t *testing.T) {
+	a := &Schema{
+		Tables: []Table{
+			{Name: "users", Schema: "public", Columns: []Column{{Name: "id", Type: "int"}}},
+		},
+	}
+	b := &Schema{
+		Tables: []Table{
+			{Name: "users", Schema: "public", Columns: []Column{{Name: "id", Type: "bigint"}}},
+		},
+	}
+
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Errorf("Expected fingerprints to differ when a column type changes")
+	}
+}
+
+func TestFingerprintIgnoresVolatileData(t *testing.T) {
+	a := &Schema{
+		Tables: []Table{
+			{Name: "users", Schema: "public", Columns: []Column{{Name: "id", Type: "int"}}},
+		},
+	}
+	b := &Schema{
+		Tables: []Table{
+			{
+				Name:       "users",
+				Schema:     "public",
+				Columns:    []Column{{Name: "id", Type: "int"}},
+				Statistics: &TableStatistics{RowEstimate: 10, TotalBytes: 8192},
+			},
+		},
+		Migration:     &Migration{Tool: "goose", Table: "goose_db_version", Version: "42"},
+		ServerVersion: 160002,
+	}
+
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("Expected statistics, migration, and server versions not to affect the fingerprint")
+	}
+}
+
+func TestChecksumWithoutComments(t *testing.T) {
+	a := &Schema{
+		Tables: []Table{
+			{Name: "users", Schema: "public", Comment: "People", Columns: []Column{{Name: "id", Type: "int", Comment: "Key"}}},
+		},
+	}
+	b := &Schema{
+		Tables: []Table{
+			{Name: "users", Schema: "public", Color: "#ff0000", Columns: []Column{{Name: "id", Type: "int", Deprecated: true}}},
+		},
+	}
+
+	if a.Checksum() == b.Checksum() {
+		t.Errorf("Expected checksums to differ when comments differ")
+	}
+	if a.Checksum(WithoutComments()) != b.Checksum(WithoutComments()) {
+		t.Errorf("Expected checksums without comments to match")
+	}
+	if a.Tables[0].Columns[0].Comment != "Key" {
+		t.Errorf("Checksum modified the original schema")
+	}
+	if a.Fingerprint() != a.Checksum() {
+		t.Errorf("Expected Fingerprint to equal Checksum")
+	}
+}
+
+func TestChecksumIgnoresGroupOrder(t *testing.T) {
+	users := TableName{Schema: "public", Name: "users"}
+	orders := TableName{Schema: "public", Name: "orders"}
+	a := &Schema{TableGroups: []TableGroup{{Name: "b", Tables: []TableName{users, orders}}, {Name: "a"}}}
+	b := &Schema{TableGroups: []TableGroup{{Name: "a"}, {Name: "b", Tables: []TableName{orders, users}}}}
+
+	if a.Checksum() != b.Checksum() {
+		t.Errorf("Expected checksums to match regardless of group order")
+	}
+}
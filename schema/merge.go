@@ -0,0 +1,107 @@
+package schema
+
+// Database is the schema of one database on a server.
+type Database struct {
+	// Name is the database name.
+	Name string
+	// Schema is the database's introspected schema.
+	Schema *Schema
+}
+
+// MergeDatabases combines the schemas of several databases into one.
+// PostgreSQL schema names repeat across databases, so every schema is renamed
+// after its database: public becomes the database name, and any other schema
+// becomes database_schema. Tables, views, enums, sequences, references, and
+// diagnostics
+// are renamed consistently, and each database's tables form a TableGroup
+// named after it. Existing groups are kept, prefixed with the database name.
+// Migration versions are per database and are dropped. The server version is
+// kept when every database reports the same one.
+func MergeDatabases(databases []Database) *Schema {
+	result := &Schema{}
+	for i, db := range databases {
+		if i == 0 {
+			result.ServerVersion = db.Schema.ServerVersion
+		} else if db.Schema.ServerVersion != result.ServerVersion {
+			result.ServerVersion = 0
+		}
+		rename := func(schemaName string) string {
+			if schemaName == "" || schemaName == "public" {
+				return db.Name
+			}
+			return db.Name + "_" + schemaName
+		}
+
+		group := TableGroup{Name: db.Name}
+		for _, table := range db.Schema.Tables {
+			table.Schema = rename(table.Schema)
+			references := make([]Reference, len(table.References))
+			for i, ref := range table.References {
+				ref.FromSchema = rename(ref.FromSchema)
+				ref.ToSchema = rename(ref.ToSchema)
+				references[i] = ref
+			}
+			table.References = references
+			partitions := make([]Partition, len(table.Partitions))
+			for i, partition := range table.Partitions {
+				partition.Schema = rename(partition.Schema)
+				partitions[i] = partition
+			}
+			table.Partitions = partitions
+			if table.Inherits != nil {
+				parents := make([]TableName, len(table.Inherits))
+				for i, parent := range table.Inherits {
+					parents[i] = TableName{Schema: rename(parent.Schema), Name: parent.Name}
+				}
+				table.Inherits = parents
+			}
+			result.Tables = append(result.Tables, table)
+			group.Tables = append(group.Tables, TableName{Schema: table.Schema, Name: table.Name})
+		}
+		for _, view := range db.Schema.Views {
+			view.Schema = rename(view.Schema)
+			sources := make([]ViewSource, len(view.Sources))
+			for i, source := range view.Sources {
+				source.Schema = rename(source.Schema)
+				sources[i] = source
+			}
+			view.Sources = sources
+			result.Views = append(result.Views, view)
+		}
+		for _, enum := range db.Schema.Enums {
+			enum.Schema = rename(enum.Schema)
+			result.Enums = append(result.Enums, enum)
+		}
+		for _, seq := range db.Schema.Sequences {
+			seq.Schema = rename(seq.Schema)
+			result.Sequences = append(result.Sequences, seq)
+		}
+		for _, domain := range db.Schema.Domains {
+			domain.Schema = rename(domain.Schema)
+			result.Domains = append(result.Domains, domain)
+		}
+		for _, r := range db.Schema.Ranges {
+			r.Schema = rename(r.Schema)
+			result.Ranges = append(result.Ranges, r)
+		}
+		for _, function := range db.Schema.Functions {
+			function.Schema = rename(function.Schema)
+			result.Functions = append(result.Functions, function)
+		}
+		for _, d := range db.Schema.Diagnostics {
+			d.Schema = rename(d.Schema)
+			result.Diagnostics = append(result.Diagnostics, d)
+		}
+		if len(group.Tables) > 0 {
+			result.TableGroups = append(result.TableGroups, group)
+		}
+		for _, g := range db.Schema.TableGroups {
+			tables := make([]TableName, len(g.Tables))
+			for i, t := range g.Tables {
+				tables[i] = TableName{Schema: rename(t.Schema), Name: t.Name}
+			}
+			result.TableGroups = append(result.TableGroups, TableGroup{Name: db.Name + "_" + g.Name, Tables: tables})
+		}
+	}
+	return result
+}
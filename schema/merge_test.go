@@ -0,0 +1,59 @@
+package schema
+
+import "testing"
+
+func TestMergeDatabases(t *testing.T) {
+	app := &Schema{
+		Tables: []Table{
+			{Name: "users", Schema: "public"},
+			{
+				Name:   "events",
+				Schema: "audit",
+				References: []Reference{
+					{FromTable: "events", FromSchema: "audit", FromColumns: []string{"user_id"}, ToTable: "users", ToSchema: "public", ToColumns: []string{"id"}},
+				},
+			},
+		},
+		Enums:         []Enum{{Name: "status", Schema: "public"}},
+		Migration:     &Migration{Tool: "goose", Version: "42"},
+		ServerVersion: 160002,
+	}
+	billing := &Schema{
+		Tables:        []Table{{Name: "users", Schema: "public"}},
+		ServerVersion: 160002,
+	}
+
+	merged := MergeDatabases([]Database{{Name: "app", Schema: app}, {Name: "billing", Schema: billing}})
+
+	if len(merged.Tables) != 3 {
+		t.Fatalf("Expected 3 tables, got %d", len(merged.Tables))
+	}
+	if merged.Tables[0].Schema != "app" || merged.Tables[1].Schema != "app_audit" || merged.Tables[2].Schema != "billing" {
+		t.Errorf("Expected schemas app, app_audit, billing, got %s, %s, %s", merged.Tables[0].Schema, merged.Tables[1].Schema, merged.Tables[2].Schema)
+	}
+
+	ref := merged.Tables[1].References[0]
+	if ref.FromSchema != "app_audit" || ref.ToSchema != "app" {
+		t.Errorf("Expected reference from app_audit to app, got %s to %s", ref.FromSchema, ref.ToSchema)
+	}
+	if merged.Enums[0].Schema != "app" {
+		t.Errorf("Expected enum schema app, got %s", merged.Enums[0].Schema)
+	}
+	if merged.Migration != nil {
+		t.Errorf("Expected migration to be dropped")
+	}
+	if merged.ServerVersion != 160002 {
+		t.Errorf("Expected the shared server version, got %d", merged.ServerVersion)
+	}
+
+	if len(merged.TableGroups) != 2 {
+		t.Fatalf("Expected 2 table groups, got %d", len(merged.TableGroups))
+	}
+	if group := merged.TableGroups[0]; group.Name != "app" || len(group.Tables) != 2 || group.Tables[1] != (TableName{Schema: "app_audit", Name: "events"}) {
+		t.Errorf("Unexpected group %+v", group)
+	}
+
+	if app.Tables[1].References[0].FromSchema != "audit" {
+		t.Errorf("Original schema was modified")
+	}
+}
@@ -0,0 +1,24 @@
+package schema
+
+// MatchClause returns "MATCH FULL" or "MATCH PARTIAL" for a foreign key with
+// that match type, or an empty string for MATCH SIMPLE, the default.
+func (r Reference) MatchClause() string {
+	if r.Match == "" {
+		return ""
+	}
+	return "MATCH " + r.Match
+}
+
+// DeferrableClause returns "DEFERRABLE" or "DEFERRABLE INITIALLY DEFERRED"
+// for a deferrable foreign key, or an empty string for one checked
+// immediately, the default.
+func (r Reference) DeferrableClause() string {
+	switch {
+	case !r.Deferrable:
+		return ""
+	case r.InitiallyDeferred:
+		return "DEFERRABLE INITIALLY DEFERRED"
+	default:
+		return "DEFERRABLE"
+	}
+}
@@ -7,6 +7,47 @@ package schema
 type Schema struct {
 	// Tables contains all tables found in the introspected schema(s).
 	Tables []Table
+	// Enums contains the enumerated types defined in the introspected schema(s).
+	Enums []Enum
+	// Views contains the views and materialized views, if they were
+	// introspected.
+	Views []View
+	// Sequences contains the sequences no column owns, such as those used
+	// with nextval() by applications, if they were introspected.
+	Sequences []Sequence
+	// Domains contains the domain types defined in the introspected
+	// schema(s).
+	Domains []Domain
+	// Ranges contains the range types defined in the introspected
+	// schema(s).
+	Ranges []Range
+	// Functions contains the functions and procedures defined in the
+	// introspected schema(s), if they were introspected.
+	Functions []Function
+	// TableGroups contains named groups of tables, such as the tables of
+	// each database when several databases are merged.
+	TableGroups []TableGroup
+	// Migration is the migration state the schema was captured at, or nil
+	// if it is unknown.
+	Migration *Migration
+	// ServerVersion is the server_version_num of the server the schema was
+	// introspected from, such as 150004 for PostgreSQL 15.4, or 0 if it is
+	// unknown.
+	ServerVersion int
+	// Diagnostics lists non-fatal issues found during introspection, such
+	// as types written as text.
+	Diagnostics []Diagnostic
+}
+
+// Migration identifies the latest migration applied to a database, as
+// recorded by a migration tool's history table.
+type Migration struct {
+	// Tool names the migration tool (e.g., "goose", "flyway").
+	Tool string
+	// Table is the history table the version was read from.
+	Table string
+	// Version is the latest applied version.
+	Version string
 }
 
 // Table represents a database table with its columns, primary keys,
@@ -24,6 +65,121 @@ type Table struct {
 	Indexes []Index
 	// References contains foreign key relationships from this table to other tables.
 	References []Reference
+	// UniqueConstraints contains the table's UNIQUE constraints, sorted by
+	// name. Their backing indexes are also in Indexes, with UniqueConstraint
+	// set.
+	UniqueConstraints []UniqueConstraint
+	// CheckConstraints contains the table's CHECK constraints, sorted by name.
+	CheckConstraints []CheckConstraint
+	// ExclusionConstraints contains the table's EXCLUDE constraints, sorted
+	// by name.
+	ExclusionConstraints []ExclusionConstraint
+	// Inherits lists the tables this table inherits from (CREATE TABLE ...
+	// INHERITS), in declaration order, or nil if none.
+	Inherits []TableName
+	// PartitionKey is the partitioning of a partitioned table as PostgreSQL
+	// prints it (e.g., "RANGE (created_at)"), or empty for other tables.
+	PartitionKey string
+	// Partitions lists the partitions of a partitioned table, if they were
+	// introspected. Partitions are not tables of their own in the schema.
+	Partitions []Partition
+	// Tablespace is the tablespace the table is stored in, or empty for the
+	// database's default tablespace.
+	Tablespace string
+	// Owner is the role that owns the table, or empty if unknown.
+	Owner string
+	// Comment is the table's description (COMMENT ON TABLE), or empty if none.
+	Comment string
+	// Color is the header color diagrams draw the table with (e.g.,
+	// "#ff0000"), or empty for the default.
+	Color string
+	// Deprecated indicates the table should no longer be used.
+	Deprecated bool
+	// Statistics holds the table's size, or nil if it was not collected.
+	Statistics *TableStatistics
+	// Foreign describes where a foreign table's data lives, or is nil for
+	// ordinary tables.
+	Foreign *ForeignTable
+	// RowSecurity indicates row level security is enabled on the table
+	// (ALTER TABLE ... ENABLE ROW LEVEL SECURITY).
+	RowSecurity bool
+	// ForceRowSecurity indicates row level security applies to the table's
+	// owner too (ALTER TABLE ... FORCE ROW LEVEL SECURITY).
+	ForceRowSecurity bool
+	// Policies contains the table's row level security policies, sorted by
+	// name.
+	Policies []Policy
+	// Triggers contains the table's triggers, sorted by name, if they were
+	// introspected.
+	Triggers []Trigger
+	// Grants contains the privileges granted on the table to roles other
+	// than its owner, sorted by grantee, if they were introspected.
+	Grants []Grant
+}
+
+// Grant represents the privileges a role holds on a table (GRANT).
+type Grant struct {
+	// Grantee is the role the privileges were granted to, or PUBLIC.
+	Grantee string
+	// Privileges lists the granted privileges, such as SELECT and INSERT,
+	// in the order GRANT documents them.
+	Privileges []string
+}
+
+// Trigger represents a trigger on a table (CREATE TRIGGER).
+type Trigger struct {
+	// Name is the trigger name.
+	Name string
+	// Timing is BEFORE, AFTER, or INSTEAD OF.
+	Timing string
+	// Events lists the events that fire the trigger, in the order INSERT,
+	// UPDATE, DELETE, TRUNCATE.
+	Events []string
+	// ForEach is ROW or STATEMENT.
+	ForEach string
+	// Function is the trigger function, qualified with its schema unless
+	// that is public.
+	Function string
+	// Enabled is false for triggers disabled with ALTER TABLE ... DISABLE
+	// TRIGGER.
+	Enabled bool
+}
+
+// Policy represents a row level security policy (CREATE POLICY).
+type Policy struct {
+	// Name is the policy name.
+	Name string
+	// Command is the command the policy applies to: ALL, SELECT, INSERT,
+	// UPDATE, or DELETE.
+	Command string
+	// Permissive is true for permissive policies, which are combined with
+	// OR, and false for restrictive ones, which are combined with AND.
+	Permissive bool
+	// Roles lists the roles the policy applies to; "public" means all roles.
+	Roles []string
+	// Using is the USING expression rows must satisfy to be visible, or
+	// empty if none.
+	Using string
+	// WithCheck is the WITH CHECK expression new rows must satisfy, or empty
+	// if none.
+	WithCheck string
+}
+
+// ForeignTable describes the remote origin of a foreign table.
+type ForeignTable struct {
+	// Server is the foreign server the table reads from (CREATE SERVER).
+	Server string
+	// Options lists the table's options as "key=value" pairs, such as
+	// "schema_name=public" and "table_name=users" for postgres_fdw.
+	Options []string
+}
+
+// TableStatistics describes how much data a table holds.
+type TableStatistics struct {
+	// RowEstimate is the planner's estimate of the number of rows.
+	RowEstimate int64
+	// TotalBytes is the disk space used by the table, its indexes, and TOAST data.
+	TotalBytes int64
 }
 
 // Column represents a database column within a table.
@@ -38,20 +194,251 @@ type Column struct {
 	DefaultValue *string
 	// IsPrimaryKey indicates whether this column is part of the primary key.
 	IsPrimaryKey bool
+	// OrdinalPosition is the column's 1-based position in its table or view
+	// as declared, or 0 if unknown. Positions of dropped columns are not
+	// reused, so there may be gaps.
+	OrdinalPosition int
+	// DatabaseType is the column's type as spelled by the database
+	// (e.g., "character varying(255)"), or empty if unknown.
+	DatabaseType string
+	// Generated is the expression of a generated column
+	// (GENERATED ALWAYS AS (...) STORED), or empty for ordinary columns.
+	Generated string
+	// Identity is "ALWAYS" or "BY DEFAULT" for identity columns
+	// (GENERATED ... AS IDENTITY), or empty for other columns.
+	Identity string
+	// AutoIncrement indicates the database assigns the column's values from
+	// a sequence the column owns: serial and identity columns. Columns whose
+	// default draws from a shared sequence with nextval() are not
+	// auto-incrementing.
+	AutoIncrement bool
+	// Domain is the domain type of the column, qualified with its schema
+	// unless that is public, or empty for other columns. Type and
+	// DatabaseType describe the domain's base type.
+	Domain string
+	// Sequence is the sequence that generates the values of a serial or
+	// identity column, if sequences were introspected, or nil.
+	Sequence *Sequence
+	// Collation is the column's collation as written after COLLATE, such as
+	// "C" (quoted) or case_insensitive, qualified with its schema unless that
+	// is pg_catalog or public. It is empty when the column uses its type's
+	// default collation.
+	Collation string
+	// Comment is the column's description (COMMENT ON COLUMN), or empty if none.
+	Comment string
+	// Deprecated indicates the column should no longer be used.
+	Deprecated bool
 }
 
 // Index represents a database index on one or more columns.
 type Index struct {
 	// Name is the index name.
 	Name string
-	// Columns lists the column names included in the index.
+	// Columns lists the index's key columns in index definition order.
 	Columns []string
+	// Include lists the non-key columns of a covering index
+	// (CREATE INDEX ... INCLUDE), or nil if there are none.
+	Include []string
 	// Unique indicates whether this is a unique index.
 	Unique bool
+	// UniqueConstraint indicates the index backs a UNIQUE constraint
+	// (ALTER TABLE ... ADD UNIQUE) rather than being created on its own with
+	// CREATE UNIQUE INDEX.
+	UniqueConstraint bool
+	// Type is the index access method, such as "hash", "gin", or "hnsw", or
+	// empty for the default, btree.
+	Type string
+	// Where is the predicate of a partial index (CREATE INDEX ... WHERE) as
+	// PostgreSQL prints it (e.g., "(deleted_at IS NULL)"), or empty for
+	// indexes on every row.
+	Where string
+	// Tablespace is the tablespace the index is stored in, or empty for the
+	// database's default tablespace.
+	Tablespace string
+}
+
+// Partition is one partition of a partitioned table.
+type Partition struct {
+	// Name is the partition's table name without schema qualification.
+	Name string
+	// Schema is the database schema containing the partition.
+	Schema string
+	// Bound is the partition bound as PostgreSQL prints it (e.g.,
+	// "FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')" or "DEFAULT").
+	Bound string
+}
+
+// UniqueConstraint represents a table-level UNIQUE constraint (ALTER TABLE
+// ... ADD CONSTRAINT ... UNIQUE), as opposed to a unique index created with
+// CREATE UNIQUE INDEX.
+type UniqueConstraint struct {
+	// Name is the constraint name, which is also the name of its index.
+	Name string
+	// Columns lists the constrained columns in constraint order.
+	Columns []string
+}
+
+// CheckConstraint represents a CHECK constraint on a table.
+type CheckConstraint struct {
+	// Name is the constraint name.
+	Name string
+	// Columns lists the columns the expression refers to, in table order, or
+	// nil if it refers to none.
+	Columns []string
+	// Expression is the condition rows must satisfy, as PostgreSQL prints it
+	// (e.g., "(price > (0)::numeric)").
+	Expression string
+}
+
+// ExclusionConstraint represents an EXCLUDE constraint on a table, which
+// keeps any two rows from matching on all of its operators, such as two
+// bookings of one room with overlapping time ranges.
+type ExclusionConstraint struct {
+	// Name is the constraint name.
+	Name string
+	// Definition is the constraint as PostgreSQL prints it (e.g.,
+	// "EXCLUDE USING gist (room_id WITH =, during WITH &&)").
+	Definition string
+}
+
+// View represents a view or materialized view.
+type View struct {
+	// Name is the view name without schema qualification.
+	Name string
+	// Schema is the database schema containing this view.
+	Schema string
+	// Materialized indicates a materialized view.
+	Materialized bool
+	// Columns contains the view's columns, ordered by position.
+	Columns []Column
+	// Definition is the view's SELECT statement.
+	Definition string
+	// Comment is the view's description (COMMENT ON VIEW), or empty if none.
+	Comment string
+	// Sources lists the tables and views the view reads from, sorted by
+	// schema and name.
+	Sources []ViewSource
+}
+
+// ViewSource is a table or view that a view reads from.
+type ViewSource struct {
+	// Schema is the database schema containing the source.
+	Schema string
+	// Table is the name of the source table or view.
+	Table string
+	// Columns lists the source columns the view reads, sorted by name.
+	Columns []string
+}
+
+// Sequence represents a sequence that is not owned by a column.
+type Sequence struct {
+	// Name is the sequence name without schema qualification.
+	Name string
+	// Schema is the database schema containing this sequence.
+	Schema string
+	// Type is the sequence's data type: smallint, integer, or bigint.
+	Type string
+	// Start is the first value.
+	Start int64
+	// Increment is added to the current value to get the next one.
+	Increment int64
+	// MinValue is the lowest value the sequence can produce.
+	MinValue int64
+	// MaxValue is the highest value the sequence can produce.
+	MaxValue int64
+	// Cycle indicates the sequence wraps around when it reaches its limit.
+	Cycle bool
+	// Comment is the sequence's description (COMMENT ON SEQUENCE), or empty
+	// if none.
+	Comment string
+}
+
+// Domain represents a domain type: a base type with constraints.
+type Domain struct {
+	// Name is the domain name without schema qualification.
+	Name string
+	// Schema is the database schema containing this domain.
+	Schema string
+	// BaseType is the underlying type as spelled by the database
+	// (e.g., "character varying(320)").
+	BaseType string
+	// NotNull indicates the domain does not allow NULL values.
+	NotNull bool
+	// DefaultValue is the domain's default value expression, or nil if none.
+	DefaultValue *string
+	// CheckConstraints lists the domain's CHECK constraints, whose
+	// expressions refer to the checked value as VALUE.
+	CheckConstraints []CheckConstraint
+	// Comment is the domain's description (COMMENT ON DOMAIN), or empty if
+	// none.
+	Comment string
+}
+
+// Range represents a range type (CREATE TYPE ... AS RANGE).
+type Range struct {
+	// Name is the range type name without schema qualification.
+	Name string
+	// Schema is the database schema containing this range type.
+	Schema string
+	// Subtype is the type of the range's bounds as spelled by the
+	// database (e.g., "double precision").
+	Subtype string
+	// Comment is the range type's description (COMMENT ON TYPE), or empty
+	// if none.
+	Comment string
+}
+
+// Function represents a stored function or procedure.
+type Function struct {
+	// Name is the function name without schema qualification.
+	Name string
+	// Schema is the database schema containing this function.
+	Schema string
+	// Kind is "function" or "procedure".
+	Kind string
+	// Arguments is the argument list as PostgreSQL prints it (e.g.,
+	// "user_id integer, active boolean DEFAULT true").
+	Arguments string
+	// Returns is the return type (e.g., "integer" or "SETOF users"), or
+	// empty for procedures.
+	Returns string
+	// Language is the implementation language (e.g., "plpgsql", "sql").
+	Language string
+	// Comment is the function's description (COMMENT ON FUNCTION), or empty
+	// if none.
+	Comment string
+}
+
+// TableGroup is a named set of tables.
+type TableGroup struct {
+	// Name is the group name.
+	Name string
+	// Tables lists the tables in the group.
+	Tables []TableName
+}
+
+// TableName identifies a table by schema and name.
+type TableName struct {
+	// Schema is the database schema containing the table.
+	Schema string
+	// Name is the table name without schema qualification.
+	Name string
+}
+
+// Enum represents an enumerated type.
+type Enum struct {
+	// Name is the type name without schema qualification.
+	Name string
+	// Schema is the database schema containing this type.
+	Schema string
+	// Values lists the enum labels in declaration order.
+	Values []string
 }
 
 // Reference represents a foreign key relationship between tables.
 type Reference struct {
+	// Name is the foreign key constraint name, or empty if unknown.
+	Name string
 	// FromTable is the table containing the foreign key.
 	FromTable string
 	// FromSchema is the schema of the table containing the foreign key.
@@ -64,8 +451,22 @@ type Reference struct {
 	ToSchema string
 	// ToColumns lists the referenced column names.
 	ToColumns []string
+	// Relationship is the DBML relationship type read from a Ref: "-" for
+	// one-to-one or "<>" for many-to-many. Empty means many-to-one (">"),
+	// as for every introspected foreign key.
+	Relationship string
 	// OnDelete is the referential action on delete (e.g., "CASCADE", "SET NULL").
 	OnDelete string
 	// OnUpdate is the referential action on update.
 	OnUpdate string
+	// Match is the match type of a multi-column foreign key, "FULL" or
+	// "PARTIAL", or empty for MATCH SIMPLE, the default.
+	Match string
+	// Deferrable indicates the constraint check can be deferred to the end
+	// of the transaction (DEFERRABLE).
+	Deferrable bool
+	// InitiallyDeferred indicates a deferrable constraint is checked at the
+	// end of the transaction unless SET CONSTRAINTS says otherwise
+	// (DEFERRABLE INITIALLY DEFERRED).
+	InitiallyDeferred bool
 }
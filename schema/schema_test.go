@@ -27,6 +27,41 @@ func TestFilterTables(t *testing.T) {
 	}
 }
 
+func TestFilterTablesViews(t *testing.T) {
+	s := &Schema{
+		Tables: []Table{{Name: "users", Schema: "public"}},
+		Views:  []View{{Name: "active_users", Schema: "public"}, {Name: "legacy_report", Schema: "public"}},
+	}
+
+	filtered := FilterTables(s, []string{"legacy_report"})
+
+	if len(filtered.Views) != 1 || filtered.Views[0].Name != "active_users" {
+		t.Errorf("Expected only active_users to remain, got %v", filtered.Views)
+	}
+	if len(s.Views) != 2 {
+		t.Errorf("Original schema was modified")
+	}
+}
+
+func TestFilterTablesDiagnostics(t *testing.T) {
+	s := &Schema{
+		Tables: []Table{{Name: "users", Schema: "public"}, {Name: "legacy", Schema: "public"}},
+		Diagnostics: []Diagnostic{
+			{Code: DiagnosticTypeFallback, Schema: "public", Table: "users", Object: "mood", Message: "type mood has no DBML equivalent and was written as text"},
+			{Code: DiagnosticExpressionIndex, Schema: "public", Table: "legacy", Object: "legacy_lower_name", Message: "index has only expressions"},
+		},
+	}
+
+	filtered := FilterTables(s, []string{"legacy"})
+
+	if len(filtered.Diagnostics) != 1 || filtered.Diagnostics[0].Table != "users" {
+		t.Errorf("Expected only the users diagnostic to remain, got %v", filtered.Diagnostics)
+	}
+	if got := filtered.Diagnostics[0].String(); got != "public.users.mood: type mood has no DBML equivalent and was written as text (type-fallback)" {
+		t.Errorf("Unexpected diagnostic string: %s", got)
+	}
+}
+
 func TestFilterTablesEmpty(t *testing.T) {
 	s := &Schema{
 		Tables: []Table{
@@ -57,3 +92,51 @@ func TestFilterTablesOriginalUnmodified(t *testing.T) {
 		t.Errorf("Original schema was modified, expected 2 tables, got %d", len(s.Tables))
 	}
 }
+
+func TestColumnEnum(t *testing.T) {
+	s := &Schema{Enums: []Enum{
+		{Name: "mood", Schema: "public"},
+		{Name: "status", Schema: "billing"},
+	}}
+
+	tests := []struct {
+		databaseType string
+		expected     string
+	}{
+		{"mood", "public.mood"},
+		{"mood[]", "public.mood"},
+		{"public.mood", "public.mood"},
+		{"billing.status", "billing.status"},
+		{"status", ""},
+		{"text", ""},
+	}
+
+	for _, tt := range tests {
+		enum, ok := s.ColumnEnum(tt.databaseType)
+		got := ""
+		if ok {
+			got = enum.Schema + "." + enum.Name
+		}
+		if got != tt.expected {
+			t.Errorf("ColumnEnum(%q) = %q, want %q", tt.databaseType, got, tt.expected)
+		}
+	}
+}
+
+func TestGenerationClause(t *testing.T) {
+	tests := []struct {
+		generated string
+		expected  string
+	}{
+		{"", ""},
+		{"(a + b)", "GENERATED ALWAYS AS (a + b) STORED"},
+		{"a + b", "GENERATED ALWAYS AS (a + b) STORED"},
+		{"(a) + (b)", "GENERATED ALWAYS AS ((a) + (b)) STORED"},
+	}
+
+	for _, tt := range tests {
+		if got := (Column{Generated: tt.generated}).GenerationClause(); got != tt.expected {
+			t.Errorf("GenerationClause(%q) = %q, want %q", tt.generated, got, tt.expected)
+		}
+	}
+}
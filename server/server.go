@@ -0,0 +1,202 @@
+// Package server exposes introspected database schemas over HTTP.
+//
+// Basic usage:
+//
+//	srv := server.New(db,
+//	    server.WithToken(os.Getenv("DBML_TOKEN")),
+//	    server.WithCacheTTL(time.Minute),
+//	    server.WithIntrospectOptions(introspect.WithSchemas("public")),
+//	)
+//	log.Fatal(http.ListenAndServe(":8080", srv.Handler()))
+//
+// The server provides the following endpoints:
+//
+//   - GET /dbml         - the schema rendered as DBML
+//   - GET /schema.json  - the schema as JSON
+//   - GET /health       - database connectivity check (never requires a token)
+//
+// Append ?refresh=true to /dbml or /schema.json to bypass the cache. Both
+// send an ETag and answer If-None-Match with 304 Not Modified.
+package server
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lucasefe/dbml/generator"
+	"github.com/lucasefe/dbml/introspect"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithToken requires requests to carry "Authorization: Bearer <token>".
+// If token is empty, requests are not authenticated.
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithCacheTTL reuses an introspected schema for ttl before introspecting
+// again. If ttl is zero, the database is introspected on every request.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.cacheTTL = ttl
+	}
+}
+
+// WithIntrospectOptions sets the options used for every introspection.
+func WithIntrospectOptions(opts ...introspect.Option) Option {
+	return func(s *Server) {
+		s.introspectOptions = opts
+	}
+}
+
+// Server serves schema documents generated from a live database.
+type Server struct {
+	db                *sql.DB
+	token             string
+	cacheTTL          time.Duration
+	introspectOptions []introspect.Option
+
+	mu       sync.Mutex
+	cached   *schema.Schema
+	cachedAt time.Time
+}
+
+// New creates a Server that introspects db.
+func New(db *sql.DB, opts ...Option) *Server {
+	s := &Server{db: db}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Handler returns the HTTP handler serving all endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.Handle("/dbml", s.authenticate(http.HandlerFunc(s.handleDBML)))
+	mux.Handle("/schema.json", s.authenticate(http.HandlerFunc(s.handleSchemaJSON)))
+	return mux
+}
+
+// ListenAndServe serves the handler on addr.
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// Schema returns the current schema, introspecting the database if the
+// cached copy is missing, expired, or refresh is true.
+func (s *Server) Schema(refresh bool) (*schema.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !refresh && s.cached != nil && s.cacheTTL > 0 && time.Since(s.cachedAt) < s.cacheTTL {
+		return s.cached, nil
+	}
+
+	result, err := introspect.Database(s.db, s.introspectOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cached = result
+	s.cachedAt = time.Now()
+	return result, nil
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(s.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleHealth reports whether the database is reachable. It needs no token,
+// so the error, which can name the host, user, or database, is only logged.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if err := s.db.PingContext(r.Context()); err != nil {
+		log.Printf("health check failed: %v", err)
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleDBML(w http.ResponseWriter, r *http.Request) {
+	result, err := s.Schema(isRefresh(r))
+	if err != nil {
+		http.Error(w, "failed to introspect database: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if notModified(w, r, result) {
+		return
+	}
+
+	output, err := generator.Generate(result)
+	if err != nil {
+		http.Error(w, "failed to generate DBML: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(output)
+}
+
+func (s *Server) handleSchemaJSON(w http.ResponseWriter, r *http.Request) {
+	result, err := s.Schema(isRefresh(r))
+	if err != nil {
+		http.Error(w, "failed to introspect database: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if notModified(w, r, result) {
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// notModified sets the ETag header to the schema's fingerprint and, if the
+// request's If-None-Match lists it, responds 304 Not Modified and returns
+// true.
+func notModified(w http.ResponseWriter, r *http.Request, result *schema.Schema) bool {
+	etag := `"` + result.Fingerprint() + `"`
+	w.Header().Set("ETag", etag)
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+func isRefresh(r *http.Request) bool {
+	value := r.URL.Query().Get("refresh")
+	return value == "true" || value == "1"
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func newCachedServer(opts ...Option) *Server {
+	s := New(nil, append(opts, WithCacheTTL(time.Hour))...)
+	s.cached = &schema.Schema{
+		Tables: []schema.Table{
+			{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int", IsPrimaryKey: true}}},
+		},
+	}
+	s.cachedAt = time.Now()
+	return s
+}
+
+func TestTokenRequired(t *testing.T) {
+	s := newCachedServer(WithToken("secret"))
+
+	req := httptest.NewRequest(http.MethodGet, "/dbml", nil)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/dbml", nil)
+	req.Header.Set("Authorization", "secret")
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a token without the Bearer prefix, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/dbml", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with token, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Table users {") {
+		t.Errorf("Response missing DBML table: %s", rec.Body.String())
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	s := newCachedServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/schema.json", nil)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected JSON content type, got %s", got)
+	}
+	if !strings.Contains(rec.Body.String(), `"Name":"users"`) {
+		t.Errorf("Response missing table: %s", rec.Body.String())
+	}
+}
+
+func TestHealthHidesError(t *testing.T) {
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=secret_user dbname=secret_db sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatalf("sql.Open returned error: %v", err)
+	}
+	defer db.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+	New(db).Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503, got %d", rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"status":"unavailable"}` {
+		t.Errorf("Expected only the status, got %s", body)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	s := newCachedServer()
+
+	for _, path := range []string{"/dbml", "/schema.json"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+
+		etag := rec.Header().Get("ETag")
+		if rec.Code != http.StatusOK || etag == "" {
+			t.Fatalf("Expected 200 with an ETag for %s, got %d %q", path, rec.Code, etag)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("Expected 304 with no body for %s, got %d %q", path, rec.Code, rec.Body.String())
+		}
+
+		req = httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", `"stale"`)
+		rec = httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected 200 for a stale ETag on %s, got %d", path, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type writerSink struct {
+	w io.Writer
+}
+
+// Stdout returns a Sink that writes to standard output.
+func Stdout() Sink {
+	return Writer(os.Stdout)
+}
+
+// Writer returns a Sink that writes every file to w, one after another.
+func Writer(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Write(ctx context.Context, name string, content []byte) error {
+	if _, err := s.w.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+type fileSink struct {
+	path string
+}
+
+// File returns a Sink that writes to the file at path, created with mode 0644.
+// Each write replaces the file.
+func File(path string) Sink {
+	return &fileSink{path: path}
+}
+
+func (s *fileSink) Write(ctx context.Context, name string, content []byte) error {
+	if err := os.WriteFile(s.path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write to file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+type directorySink struct {
+	dir string
+}
+
+// Directory returns a Sink that writes each file into dir under its name,
+// creating dir if needed.
+func Directory(dir string) Sink {
+	return &directorySink{dir: dir}
+}
+
+func (s *directorySink) Write(ctx context.Context, name string, content []byte) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", s.dir, err)
+	}
+	path := filepath.Join(s.dir, filepath.Base(name))
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write to file %s: %w", path, err)
+	}
+	return nil
+}
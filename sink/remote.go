@@ -0,0 +1,99 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lucasefe/dbml/objectstore"
+)
+
+// HTTPOption configures an HTTP sink.
+type HTTPOption func(*httpSink)
+
+// WithHTTPClient sets the client used for requests. The default client times
+// out after one minute.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(s *httpSink) {
+		s.client = client
+	}
+}
+
+// WithHeader adds a header to every request, e.g. for authorization.
+func WithHeader(key, value string) HTTPOption {
+	return func(s *httpSink) {
+		s.header.Add(key, value)
+	}
+}
+
+type httpSink struct {
+	url    string
+	client *http.Client
+	header http.Header
+}
+
+// HTTP returns a Sink that PUTs each file to rawURL. If rawURL ends in "/",
+// the file name is appended to it, so several files can be written.
+func HTTP(rawURL string, opts ...HTTPOption) Sink {
+	s := &httpSink{
+		url:    rawURL,
+		client: &http.Client{Timeout: time.Minute},
+		header: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *httpSink) Write(ctx context.Context, name string, content []byte) error {
+	target := s.url
+	if strings.HasSuffix(target, "/") {
+		target += url.PathEscape(name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range s.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", ContentType(name))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// url.Error repeats the URL, which may carry a token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("upload of %s failed with status %d: %s", name, resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
+
+type storeSink struct {
+	store objectstore.Store
+}
+
+// ObjectStore returns a Sink that uploads each file to store under its name.
+func ObjectStore(store objectstore.Store) Sink {
+	return &storeSink{store: store}
+}
+
+func (s *storeSink) Write(ctx context.Context, name string, content []byte) error {
+	return s.store.Put(ctx, name, content, ContentType(name))
+}
@@ -0,0 +1,118 @@
+// Package sink writes generated output to a destination: standard output, a
+// file, a directory, an HTTP endpoint, or cloud object storage.
+//
+// Every renderer writes through the same Sink interface, so any format can be
+// sent to any destination. Open picks a sink from a target string the way the
+// dbml CLI's --output flag does:
+//
+//   - "" or "-" - standard output
+//   - s3://, gs://, or azblob:// URIs - object storage (see package objectstore)
+//   - http:// or https:// URLs - an HTTP PUT; a URL ending in "/" receives one
+//     PUT per file, named after it
+//   - a path ending in a separator, or an existing directory - a directory
+//   - any other path - a file
+//
+// Basic usage:
+//
+//	dst, err := sink.Open("s3://docs/schemas/prod")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = sink.Render(ctx, dst, s, "dbml") // uploads schema.dbml
+package sink
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasefe/dbml/objectstore"
+	"github.com/lucasefe/dbml/render"
+	"github.com/lucasefe/dbml/schema"
+)
+
+// Sink is a destination for generated files.
+type Sink interface {
+	// Write stores one generated file. name is the file's conventional name,
+	// such as "schema.dbml"; sinks with a single destination, like stdout or
+	// a file, ignore it.
+	Write(ctx context.Context, name string, content []byte) error
+}
+
+// Open returns the Sink for a target string, as described in the package
+// documentation.
+func Open(target string) (Sink, error) {
+	if target == "" || target == "-" {
+		return Stdout(), nil
+	}
+
+	if u, err := url.Parse(target); err == nil {
+		switch u.Scheme {
+		case "s3", "gs", "azblob":
+			store, err := objectstore.Open(target)
+			if err != nil {
+				return nil, err
+			}
+			return ObjectStore(store), nil
+		case "http", "https":
+			return HTTP(target), nil
+		}
+	}
+
+	if strings.HasSuffix(target, "/") || strings.HasSuffix(target, string(filepath.Separator)) {
+		return Directory(target), nil
+	}
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return Directory(target), nil
+	}
+	return File(target), nil
+}
+
+// fileNames maps formats to the names their output is conventionally given.
+var fileNames = map[string]string{
+	"sqlc":           "schema.sql",
+	"flyway":         "V1__baseline.sql",
+	"openapi":        "openapi.json",
+	"atlas":          "schema.hcl",
+	"liquibase":      "changelog.xml",
+	"liquibase-yaml": "changelog.yaml",
+	"mermaid":        "schema.mmd",
+}
+
+// FileName returns the conventional file name for output in format, such as
+// "schema.dbml" for dbml or "changelog.xml" for liquibase.
+func FileName(format string) string {
+	if name, ok := fileNames[format]; ok {
+		return name
+	}
+	return "schema." + format
+}
+
+var contentTypes = map[string]string{
+	".json": "application/json",
+	".sql":  "application/sql",
+	".svg":  "image/svg+xml",
+	".xml":  "application/xml",
+	".yaml": "application/yaml",
+}
+
+// ContentType returns the MIME type for a file name, defaulting to UTF-8
+// plain text.
+func ContentType(name string) string {
+	if contentType, ok := contentTypes[filepath.Ext(name)]; ok {
+		return contentType
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// Render renders the schema in format and writes it to dst under the
+// format's conventional file name.
+func Render(ctx context.Context, dst Sink, s *schema.Schema, format string) error {
+	content, err := render.Bytes(s, format)
+	if err != nil {
+		return err
+	}
+	return dst.Write(ctx, FileName(format), content)
+}
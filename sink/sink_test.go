@@ -0,0 +1,171 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/dbml/schema"
+)
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		target string
+		want   Sink
+	}{
+		{target: "", want: &writerSink{}},
+		{target: "-", want: &writerSink{}},
+		{target: "schema.dbml", want: &fileSink{}},
+		{target: dir, want: &directorySink{}},
+		{target: "out/", want: &directorySink{}},
+		{target: "https://docs.example.com/schema.dbml", want: &httpSink{}},
+	}
+
+	for _, tt := range tests {
+		dst, err := Open(tt.target)
+		if err != nil {
+			t.Errorf("Open(%q) returned error: %v", tt.target, err)
+			continue
+		}
+		if got, want := typeName(dst), typeName(tt.want); got != want {
+			t.Errorf("Open(%q) = %s, want %s", tt.target, got, want)
+		}
+	}
+
+	if _, err := Open("ftp://example.com/schema.dbml"); err != nil {
+		t.Errorf("Expected unknown schemes to be treated as paths, got %v", err)
+	}
+}
+
+func typeName(s Sink) string {
+	switch s.(type) {
+	case *writerSink:
+		return "writer"
+	case *fileSink:
+		return "file"
+	case *directorySink:
+		return "directory"
+	case *httpSink:
+		return "http"
+	case *storeSink:
+		return "object store"
+	}
+	return "unknown"
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"dbml":      "schema.dbml",
+		"sqlc":      "schema.sql",
+		"flyway":    "V1__baseline.sql",
+		"liquibase": "changelog.xml",
+		"csv":       "schema.csv",
+	}
+	for format, want := range tests {
+		if got := FileName(format); got != want {
+			t.Errorf("FileName(%s) = %s, want %s", format, got, want)
+		}
+	}
+}
+
+func TestContentType(t *testing.T) {
+	if got := ContentType("schema.svg"); got != "image/svg+xml" {
+		t.Errorf("Unexpected content type for svg: %s", got)
+	}
+	if got := ContentType("schema.dbml"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Unexpected content type for dbml: %s", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	var buf bytes.Buffer
+	s := &schema.Schema{Tables: []schema.Table{{Name: "users", Schema: "public", Columns: []schema.Column{{Name: "id", Type: "int"}}}}}
+
+	if err := Render(context.Background(), Writer(&buf), s, "dbml"); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Table users") {
+		t.Errorf("Expected rendered DBML, got:\n%s", buf.String())
+	}
+
+	if err := Render(context.Background(), Writer(&buf), s, "nope"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	dst := Directory(dir)
+
+	if err := dst.Write(context.Background(), "schema.dbml", []byte("Table users {}")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "schema.dbml"))
+	if err != nil {
+		t.Fatalf("Failed to read written file: %v", err)
+	}
+	if string(content) != "Table users {}" {
+		t.Errorf("Unexpected content: %s", content)
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.dbml")
+
+	if err := File(path).Write(context.Background(), "schema.dbml", []byte("x")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the file to be written to its own path: %v", err)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	var method, path, contentType, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(b)
+		contentType, auth = r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	dst := HTTP(srv.URL+"/docs/", WithHeader("Authorization", "Bearer secret"))
+	if err := dst.Write(context.Background(), "schema.json", []byte("{}")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	if method != http.MethodPut || path != "/docs/schema.json" || body != "{}" {
+		t.Errorf("Unexpected request: %s %s %q", method, path, body)
+	}
+	if contentType != "application/json" || auth != "Bearer secret" {
+		t.Errorf("Unexpected headers: %s, %s", contentType, auth)
+	}
+
+	if err := HTTP(srv.URL+"/exact.json").Write(context.Background(), "schema.json", nil); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if path != "/exact.json" {
+		t.Errorf("Expected the exact URL to be used, got %s", path)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "read only", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := HTTP(srv.URL).Write(context.Background(), "schema.dbml", nil)
+	if err == nil || !strings.Contains(err.Error(), "status 403: read only") {
+		t.Errorf("Expected status error, got %v", err)
+	}
+}
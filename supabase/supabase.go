@@ -0,0 +1,182 @@
+// Package supabase resolves Supabase projects into PostgreSQL connection
+// strings.
+//
+// Direct connections need only the project ref and database password.
+// Pooled connections go through Supavisor, whose host depends on the
+// project's region, so they are looked up with the Supabase Management API
+// using a personal access token.
+//
+// Basic usage:
+//
+//	connStr, err := supabase.ConnectionString(ctx, "abcdefghijklmnop", password,
+//	    supabase.WithPooler(os.Getenv("SUPABASE_ACCESS_TOKEN")))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	s, err := introspect.FromConnectionString(connStr,
+//	    introspect.WithAllSchemas(),
+//	    introspect.WithExcludeSchemas(supabase.InternalSchemas...))
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DefaultAPIURL is the Supabase Management API.
+const DefaultAPIURL = "https://api.supabase.com"
+
+// InternalSchemas are the schemas Supabase and its bundled extensions manage.
+// They are rarely interesting when documenting an application's own schema.
+var InternalSchemas = []string{
+	"_analytics",
+	"_realtime",
+	"auth",
+	"cron",
+	"extensions",
+	"graphql",
+	"graphql_public",
+	"net",
+	"pgbouncer",
+	"pgsodium",
+	"pgsodium_masks",
+	"realtime",
+	"storage",
+	"supabase_functions",
+	"supabase_migrations",
+	"vault",
+}
+
+var projectRefPattern = regexp.MustCompile(`^[a-z0-9]{20}$`)
+
+// Option configures how a project is resolved.
+type Option func(*options)
+
+type options struct {
+	accessToken string
+	apiURL      string
+	client      *http.Client
+	database    string
+}
+
+// WithPooler connects through the Supavisor connection pooler instead of
+// directly. accessToken is a Supabase personal access token, used to look up
+// the pooler host for the project.
+func WithPooler(accessToken string) Option {
+	return func(o *options) {
+		o.accessToken = accessToken
+	}
+}
+
+// WithAPIURL overrides the Management API URL.
+func WithAPIURL(apiURL string) Option {
+	return func(o *options) {
+		o.apiURL = apiURL
+	}
+}
+
+// WithHTTPClient sets the client used for Management API requests. The
+// default client times out after 10 seconds.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithDatabase connects to a database other than "postgres".
+func WithDatabase(name string) Option {
+	return func(o *options) {
+		o.database = name
+	}
+}
+
+// ConnectionString returns a connection string for the project's database.
+// Connections always require TLS.
+func ConnectionString(ctx context.Context, projectRef, password string, opts ...Option) (string, error) {
+	o := &options{
+		apiURL:   DefaultAPIURL,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		database: "postgres",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if !projectRefPattern.MatchString(projectRef) {
+		return "", fmt.Errorf("invalid Supabase project ref %q: expected 20 lowercase letters and digits", projectRef)
+	}
+	if password == "" {
+		return "", fmt.Errorf("a database password is required for Supabase project %s", projectRef)
+	}
+
+	if o.accessToken == "" {
+		return connectionURL("postgres", password, "db."+projectRef+".supabase.co", 5432, o.database), nil
+	}
+
+	pooler, err := lookupPooler(ctx, projectRef, o)
+	if err != nil {
+		return "", err
+	}
+	return connectionURL(pooler.User, password, pooler.Host, pooler.Port, o.database), nil
+}
+
+func connectionURL(user, password, host string, port int, database string) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(user, password),
+		Host:     host + ":" + strconv.Itoa(port),
+		Path:     "/" + database,
+		RawQuery: "sslmode=require",
+	}
+	return u.String()
+}
+
+// pooler is the part of the Management API pooler configuration needed to
+// connect.
+type pooler struct {
+	DatabaseType string `json:"database_type"`
+	User         string `json:"db_user"`
+	Host         string `json:"db_host"`
+	Port         int    `json:"db_port"`
+}
+
+// lookupPooler fetches the pooler configuration of the project's primary
+// database.
+func lookupPooler(ctx context.Context, projectRef string, o *options) (*pooler, error) {
+	endpoint := o.apiURL + "/v1/projects/" + projectRef + "/config/database/pooler"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Supabase API request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+o.accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Supabase API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("Supabase API returned status %d for project %s: %s", resp.StatusCode, projectRef, detail)
+	}
+
+	var poolers []pooler
+	if err := json.NewDecoder(resp.Body).Decode(&poolers); err != nil {
+		return nil, fmt.Errorf("failed to decode Supabase pooler configuration: %w", err)
+	}
+	for _, p := range poolers {
+		if p.DatabaseType == "PRIMARY" && p.Host != "" {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("no pooler configured for the primary database of project %s", projectRef)
+}